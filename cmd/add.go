@@ -19,6 +19,8 @@ import (
 
 var (
 	addCogName string
+	// addWrittenPath is the cog file the add callback staged
+	addWrittenPath string
 )
 
 var addCmd = &cobra.Command{
@@ -29,19 +31,20 @@ var addCmd = &cobra.Command{
 This command guides you through creating a new cog by specifying:
   - Cog name and file structure
   - Slash commands with descriptions and arguments
-  - Prefix commands for traditional bot interactions
+  - Prefix commands for traditional bot interactions, with aliases and subcommand groups
+  - Hybrid commands that work both as slash and prefix commands
+  - User and message context menu commands
+  - Slash command groups such as /ticket open, nested up to two levels
   - Command argument types and return values
   - Command scopes (guild or global)
+  - Event listeners such as on_member_join with an optional canned action
+  - Scheduled tasks that run on an interval or at times of day
 
 The generated cog will be automatically registered in botbox.conf and include 
 proper Discord.py boilerplate code. It's recommended to use this command instead 
 of manually creating cogs to ensure proper integration.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		_, err := utils.FindBotConf()
-		if err != nil {
-			fmt.Fprintln(os.Stderr, "Current directory is not in a botbox project.")
-			return
-		}
+		requireProject()
 
 		if len(args) > 0 {
 			addCogName = args[0]
@@ -49,102 +52,188 @@ of manually creating cogs to ensure proper integration.`,
 			addCogName = ""
 		}
 
-		if isHeadless(cmd, []string{"commands"}) {
+		if isHeadless(cmd, []string{"commands", "listeners", "tasks"}) {
 			runAddHeadless(cmd, args)
 			return
 		}
 
 		model := utils.AddModel(addCallback, addInitCallback)
+		model.DryRun, _ = cmd.Flags().GetBool("dry-run")
 		utils.CupSleeve(model)
+		if model.DryRun {
+			printDryRun(model.Changes)
+		}
 	},
 }
 
 func runAddHeadless(cmd *cobra.Command, args []string) {
 	if addCogName == "" {
-		fmt.Fprintln(os.Stderr, "Error: a cog name is required when running without the TUI")
-		os.Exit(1)
+		exitWithError(exitUsage, fmt.Errorf("a cog name is required when running without the TUI"))
 	}
 
 	rawCommands, _ := cmd.Flags().GetString("commands")
 	commands, err := parseCommandsInput(rawCommands)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error:", err)
-		os.Exit(1)
+		exitWithError(exitUsage, err)
 	}
 
 	// Validate each command against the ones accepted before it
 	var slashCommands, prefixCommands []utils.CommandInfo
 	for i, command := range commands {
-		// Modal commands only respond through the modal, so their return type is fixed
-		if command.Type == "modal" {
+		if utils.HasFixedReturnType(command.Type) {
 			command.ReturnType = "None"
 		}
 		if err := utils.ValidateCommand(command, commands[:i]); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: command '%s': %v\n", command.Name, err)
-			os.Exit(1)
+			exitWithError(exitUsage, fmt.Errorf("command '%s': %w", command.Name, err))
 		}
-		// Modal commands are app commands, so they live with the slash commands
-		if command.Type == "prefix" {
-			prefixCommands = append(prefixCommands, command)
-		} else {
+		if utils.IsAppCommand(command.Type) {
 			slashCommands = append(slashCommands, command)
+		} else {
+			prefixCommands = append(prefixCommands, command)
 		}
 	}
+	if err := utils.ValidatePrefixGroups(commands); err != nil {
+		exitWithError(exitUsage, err)
+	}
 
 	slashJSON, err := utils.CmdInfoSliceToJSON(slashCommands)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error:", err)
-		os.Exit(1)
+		exitWithError(exitError, err)
 	}
 	prefixJSON, err := utils.CmdInfoSliceToJSON(prefixCommands)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error:", err)
-		os.Exit(1)
+		exitWithError(exitError, err)
+	}
+
+	rawListeners, _ := cmd.Flags().GetString("listeners")
+	listeners, err := parseListenersInput(rawListeners)
+	if err != nil {
+		exitWithError(exitUsage, err)
+	}
+	if err := utils.ValidateListeners(listeners, commands, nil); err != nil {
+		exitWithError(exitUsage, err)
+	}
+	listenerJSON, err := utils.ListenerInfoSliceToJSON(listeners)
+	if err != nil {
+		exitWithError(exitError, err)
+	}
+
+	rawTasks, _ := cmd.Flags().GetString("tasks")
+	tasks, err := parseTasksInput(rawTasks)
+	if err != nil {
+		exitWithError(exitUsage, err)
+	}
+	if err := utils.ValidateTasks(tasks, commands, listeners); err != nil {
+		exitWithError(exitUsage, err)
+	}
+	taskJSON, err := utils.TaskInfoSliceToJSON(tasks)
+	if err != nil {
+		exitWithError(exitError, err)
 	}
 
 	model := utils.AddModel(addCallback, addInitCallback)
 	model.ModelValues.Map["slashCommands"] = &slashJSON
 	model.ModelValues.Map["prefixCommands"] = &prefixJSON
+	model.ModelValues.Map["listeners"] = &listenerJSON
+	model.ModelValues.Map["tasks"] = &taskJSON
+	model.DryRun, _ = cmd.Flags().GetBool("dry-run")
 
-	if utils.PrintErrors(utils.RunHeadless(model)) {
-		os.Exit(1)
+	exitOnErrors(exitError, utils.RunHeadless(model))
+
+	fileBase := strings.ToLower(string(addCogName[0])) + addCogName[1:]
+	if jsonOutput() {
+		printResult(newCogResult(model.Changes, fileBase, model.DryRun))
+		return
+	}
+	if model.DryRun {
+		printDryRun(model.Changes)
+		return
 	}
 
-	rootDir, err := utils.FindBotConf()
-	if err == nil {
-		fileBase := strings.ToLower(string(addCogName[0])) + addCogName[1:]
-		fmt.Println(filepath.Join(rootDir, "src", "cogs", fileBase+".py"))
+	if addWrittenPath != "" {
+		fmt.Println(addWrittenPath)
 	}
 }
 
+// cogResult is the JSON result of add and edit. Files lists what was written, or would be for a dry run
+// whose unified diff is in Diff, and Backup is the backup edit took of the replaced files, left out when none was taken
+type cogResult struct {
+	Cog       utils.CogConfig `json:"cog"`
+	Files     []string        `json:"files"`
+	DryRun    bool            `json:"dry_run"`
+	Diff      string          `json:"diff"`
+	Backup    string          `json:"backup,omitempty"`
+	Preserved []string        `json:"preserved"`
+	Warnings  []string        `json:"warnings"`
+}
+
 /**
- * parseCommandsInput
- * Parses the --commands flag which accepts inline JSON, @path/to/file.json, or - for stdin
- * @param raw {string} - the raw flag value
- * @return []utils.CommandInfo - the parsed commands
- * @return error - any read or parse failure
+ * newCogResult
+ * Builds the JSON result of a cog that was added or edited, reading the cog from the staged botbox.conf
+ * @param changes {*utils.ChangeSet} - the files the command staged
+ * @param fileBase {string} - the cog's file name without .py
+ * @param dryRun {bool} - whether the changes were only previewed
+ * @return cogResult - the result
  **/
-func parseCommandsInput(raw string) ([]utils.CommandInfo, error) {
-	if raw == "" {
-		return nil, nil
+func newCogResult(changes *utils.ChangeSet, fileBase string, dryRun bool) cogResult {
+	rootDir := requireProject()
+	config, err := stagedConfig(changes, rootDir)
+	if err != nil {
+		exitWithError(exitError, err)
+	}
+
+	result := cogResult{Files: changedFiles(changes), DryRun: dryRun, Preserved: []string{}, Warnings: []string{}}
+	if index, found := findProjectCog(config, fileBase); found {
+		result.Cog = config.Cogs[index]
 	}
+	if dryRun {
+		result.Diff = changes.Diff(rootDir)
+	}
+	return result
+}
 
-	var data []byte
+/**
+ * readJSONInput
+ * Reads a JSON flag value given as inline JSON, @path/to/file.json, or - for stdin
+ * @param raw {string} - the raw flag value
+ * @param what {string} - what the flag holds, used in error messages
+ * @return []byte - the JSON to parse
+ * @return error - any read failure
+ **/
+func readJSONInput(raw string, what string) ([]byte, error) {
 	switch {
 	case raw == "-":
 		stdinData, err := io.ReadAll(os.Stdin)
 		if err != nil {
-			return nil, fmt.Errorf("error reading commands from stdin: %w", err)
+			return nil, fmt.Errorf("error reading %s from stdin: %w", what, err)
 		}
-		data = stdinData
+		return stdinData, nil
 	case strings.HasPrefix(raw, "@"):
 		fileData, err := os.ReadFile(strings.TrimPrefix(raw, "@"))
 		if err != nil {
-			return nil, fmt.Errorf("error reading commands file: %w", err)
+			return nil, fmt.Errorf("error reading %s file: %w", what, err)
 		}
-		data = fileData
+		return fileData, nil
 	default:
-		data = []byte(raw)
+		return []byte(raw), nil
+	}
+}
+
+/**
+ * parseCommandsInput
+ * Parses the --commands flag which accepts inline JSON, @path/to/file.json, or - for stdin
+ * @param raw {string} - the raw flag value
+ * @return []utils.CommandInfo - the parsed commands
+ * @return error - any read or parse failure
+ **/
+func parseCommandsInput(raw string) ([]utils.CommandInfo, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	data, err := readJSONInput(raw, "commands")
+	if err != nil {
+		return nil, err
 	}
 
 	var commands []utils.CommandInfo
@@ -154,6 +243,54 @@ func parseCommandsInput(raw string) ([]utils.CommandInfo, error) {
 	return commands, nil
 }
 
+/**
+ * parseListenersInput
+ * Parses the --listeners flag which accepts inline JSON, @path/to/file.json, or - for stdin
+ * @param raw {string} - the raw flag value
+ * @return []utils.ListenerInfo - the parsed listeners
+ * @return error - any read or parse failure
+ **/
+func parseListenersInput(raw string) ([]utils.ListenerInfo, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	data, err := readJSONInput(raw, "listeners")
+	if err != nil {
+		return nil, err
+	}
+
+	var listeners []utils.ListenerInfo
+	if err := json.Unmarshal(data, &listeners); err != nil {
+		return nil, fmt.Errorf("error parsing listeners JSON: %w", err)
+	}
+	return listeners, nil
+}
+
+/**
+ * parseTasksInput
+ * Parses the --tasks flag which accepts inline JSON, @path/to/file.json, or - for stdin
+ * @param raw {string} - the raw flag value
+ * @return []utils.TaskInfo - the parsed tasks
+ * @return error - any read or parse failure
+ **/
+func parseTasksInput(raw string) ([]utils.TaskInfo, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	data, err := readJSONInput(raw, "tasks")
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []utils.TaskInfo
+	if err := json.Unmarshal(data, &tasks); err != nil {
+		return nil, fmt.Errorf("error parsing tasks JSON: %w", err)
+	}
+	return tasks, nil
+}
+
 func addCallback(model *utils.Model) []error {
 	values := model.ModelValues
 	var errors []error
@@ -176,53 +313,49 @@ func addCallback(model *utils.Model) []error {
 	fileBase := strings.ToLower(string(filename[0])) + filename[1:]
 	slashCommandList, _ := utils.JSONToCmdInfoSlice(*values.Map["slashCommands"])
 	prefixCommandList, _ := utils.JSONToCmdInfoSlice(*values.Map["prefixCommands"])
+	listenerList, _ := utils.JSONToListenerInfoSlice(*values.Map["listeners"])
+	taskList, _ := utils.JSONToTaskInfoSlice(*values.Map["tasks"])
 
 	// Prefix commands have no guild scope in Discord, normalizing avoids sync drift
 	for i := range prefixCommandList {
 		prefixCommandList[i].Scope = "global"
 	}
 
-	filePath := filepath.Join(rootDir, "src", "cogs", fileBase+".py")
-	file, err := os.Create(filePath)
+	className := strings.ToUpper(string(filename[0])) + filename[1:]
+
+	// The project's generator renders the cog, commands its library has no template for fail before the cog file is written
+	generator, err := utils.GeneratorFor(config.BotInfo.Library)
 	if err != nil {
-		errors = append(errors, fmt.Errorf("error creating file: %w", err))
+		errors = append(errors, err)
 		return errors
 	}
-	defer file.Close()
-
-	className := strings.ToUpper(string(filename[0])) + filename[1:]
-
-	cogContent, err := utils.RenderTemplate("cog.py.tmpl", utils.CogTemplateData{
-		Author:         config.BotInfo.Author,
-		BotName:        config.BotInfo.Name,
-		BotDescription: config.BotInfo.Description,
-		ClassName:      className,
-		Filename:       filename,
+	filePath := filepath.Join(rootDir, generator.CogsDir(), generator.CogFileName(fileBase))
+	addWrittenPath = filePath
+	cogContent, err := generator.RenderCog(config.BotInfo, utils.CogConfig{
+		Name:           className,
+		File:           filename,
 		SlashCommands:  slashCommandList,
 		PrefixCommands: prefixCommandList,
+		Listeners:      listenerList,
+		Tasks:          taskList,
 	})
 	if err != nil {
-		errors = append(errors, fmt.Errorf("error rendering cog template: %w", err))
+		errors = append(errors, err)
 		return errors
 	}
 
-	_, err = file.WriteString(cogContent)
-	if err != nil {
+	if err := model.Changes.WriteFile(filePath, []byte(cogContent)); err != nil {
 		errors = append(errors, fmt.Errorf("error writing to file: %w", err))
 		return errors
 	}
 
-	err = file.Sync()
-	if err != nil {
-		errors = append(errors, fmt.Errorf("error syncing file: %w", err))
-		return errors
-	}
-
 	cog := utils.CogConfig{
 		Name:           strings.ToUpper(string(filename[0])) + filename[1:],
 		File:           fileBase,
 		SlashCommands:  []utils.CommandInfo{},
 		PrefixCommands: []utils.CommandInfo{},
+		Listeners:      listenerList,
+		Tasks:          taskList,
 	}
 
 	for _, slashCommand := range slashCommandList {
@@ -236,23 +369,13 @@ func addCallback(model *utils.Model) []error {
 	cog.Env = "development"
 	config.Cogs = append(config.Cogs, cog)
 
-	jsonData, err := json.MarshalIndent(config, "", "  ")
-	if err != nil {
-		errors = append(errors, fmt.Errorf("failed to marshal config to JSON: %w", err))
-		return errors
-	}
-
-	confDir, err := utils.FindBotConf()
-	if err != nil {
-		errors = append(errors, fmt.Errorf("error finding botbox config directory: %w", err))
+	if err := utils.StageConfig(model.Changes, rootDir, config); err != nil {
+		errors = append(errors, fmt.Errorf("failed to write updated botbox.conf: %w", err))
 		return errors
 	}
 
-	confPath := filepath.Join(confDir, "botbox.conf")
-
-	err = os.WriteFile(confPath, jsonData, 0644)
-	if err != nil {
-		errors = append(errors, fmt.Errorf("failed to write updated botbox.conf: %w", err))
+	if _, err := utils.UpdateLocaleFiles(model.Changes, rootDir, config, nil, true); err != nil {
+		errors = append(errors, fmt.Errorf("error writing locale files: %w", err))
 		return errors
 	}
 
@@ -296,6 +419,9 @@ func addInitCallback(model *utils.Model, allFormsModels []utils.Values) {
 func init() {
 	rootCmd.AddCommand(addCmd)
 	addCmd.Flags().String("commands", "", "JSON array of commands to generate, accepts inline JSON, @path/to/file.json, or - for stdin")
+	addCmd.Flags().String("listeners", "", "JSON array of event listeners to generate, accepts inline JSON, @path/to/file.json, or - for stdin")
+	addCmd.Flags().String("tasks", "", "JSON array of scheduled tasks to generate, accepts inline JSON, @path/to/file.json, or - for stdin")
+	addCmd.Flags().Bool("dry-run", false, "Print a unified diff of the files the new cog would add or change without writing them")
 }
 
 /*
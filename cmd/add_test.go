@@ -63,6 +63,34 @@ func TestParseCommandsInputMissingFile(t *testing.T) {
 	}
 }
 
+func TestParseListenersInput(t *testing.T) {
+	listeners, err := parseListenersInput(`[{"Event":"on_member_join","Action":"message","ChannelID":"123456789012345678","Content":"Welcome {member.mention}"}]`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(listeners) != 1 || listeners[0].Event != "on_member_join" || listeners[0].Action != "message" {
+		t.Errorf("parsed listener has wrong fields: %+v", listeners)
+	}
+
+	if _, err := parseListenersInput("not json"); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestParseTasksInput(t *testing.T) {
+	tasks, err := parseTasksInput(`[{"Name":"digest","Times":["09:00","21:30"],"Timezone":"America/New_York"}]`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].Name != "digest" || len(tasks[0].Times) != 2 {
+		t.Errorf("parsed task has wrong fields: %+v", tasks)
+	}
+
+	if _, err := parseTasksInput("not json"); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
 /*
 Copyright © 2025 Austin "Choice404" Choi
 
@@ -0,0 +1,133 @@
+/*
+Copyright © 2025 Austin "Choice404" Choi
+See end of file for extended copyright information
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/choice404/botbox/v2/cmd/utils"
+	"github.com/spf13/cobra"
+)
+
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Make the current project match a bot spec",
+	Long: `Make the current Bot Box project match a bot spec, see botbox plan for the
+spec format.
+
+Cogs in the spec but not the project are generated, cogs whose definition
+changed are regenerated keeping the code in their protected regions, and cogs
+missing from the spec are removed along with their files, except the built-in
+admin, cog management, and help cogs which apply never touches. botbox.conf
+and the locale files are rewritten to match. Applying the same spec twice
+changes nothing the second time.
+
+Every file apply replaces or removes is saved in one backup first, restore it
+with botbox restore <id>.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runApply(cmd)
+	},
+}
+
+// applyResult is the JSON result of apply. Files lists what was written, or would be for a dry run whose
+// unified diff is in Diff, and Backup is the backup taken of the replaced files
+type applyResult struct {
+	utils.SpecPlan
+	Files  []string `json:"files"`
+	DryRun bool     `json:"dry_run"`
+	Diff   string   `json:"diff"`
+	Backup string   `json:"backup"`
+}
+
+/**
+ * runApply
+ * Stages the files a spec changes and writes them, or prints them for a dry run
+ * @param cmd {*cobra.Command} - the command holding the flags
+ * @return ...
+ **/
+func runApply(cmd *cobra.Command) {
+	rootDir, plan := loadSpecPlan(cmd)
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	noBackup, _ := cmd.Flags().GetBool("no-backup")
+
+	changes := &utils.ChangeSet{}
+	backupID, err := utils.StageSpec(changes, rootDir, plan, !noBackup && !dryRun)
+	if err != nil {
+		exitWithError(exitError, err)
+	}
+	if !dryRun {
+		if err := changes.Apply(); err != nil {
+			exitWithError(exitError, err)
+		}
+	}
+
+	if jsonOutput() {
+		result := applyResult{SpecPlan: plan, Files: changedFiles(changes), DryRun: dryRun, Backup: backupID}
+		if dryRun {
+			result.Diff = changes.Diff(rootDir)
+		}
+		printResult(result)
+		return
+	}
+
+	specPath, _ := cmd.Flags().GetString("file")
+	if dryRun {
+		printPlan(plan, specPath)
+		printDryRun(changes)
+		return
+	}
+	if plan.Empty() {
+		printPlan(plan, specPath)
+		return
+	}
+	for _, path := range changedFiles(changes) {
+		fmt.Println(path)
+	}
+	if backupID != "" {
+		fmt.Fprintf(os.Stderr, "Backup created: %s, restore it with 'botbox restore %s'\n", backupID, backupID)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(applyCmd)
+	applyCmd.Flags().StringP("file", "f", "", "Bot spec to apply, like bot.yaml")
+	applyCmd.Flags().Bool("no-backup", false, "Skip the backup of the files apply replaces or removes")
+	applyCmd.Flags().Bool("dry-run", false, "Print the plan and a unified diff of the files apply would change without writing them")
+}
+
+/*
+Copyright © 2025 Austin "Choice404" Choi
+
+https://github.com/choice404/botbox
+
+Bot Box
+A discord bot template generator to help create discord bots quickly and easily
+
+This code is licensed under the MIT License.
+
+MIT License
+
+Copyright (c) 2025 Austin Choi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
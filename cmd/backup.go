@@ -0,0 +1,188 @@
+/*
+Copyright © 2025 Austin "Choice404" Choi
+See end of file for extended copyright information
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/choice404/botbox/v2/cmd/utils"
+	"github.com/spf13/cobra"
+)
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Manage the backups of the current Bot Box project",
+	Long: `Manage the backups Bot Box takes before it replaces project files.
+
+botbox edit backs up the cog file and botbox.conf before it regenerates a cog,
+and botbox project upgrade backs up botbox.conf. Backups are kept in
+.botbox/backups with one <id>.json file each, named after the time they were
+taken. The newest 20 backups are kept and older ones are removed.
+
+Use botbox restore <id> to put the files of a backup back.`,
+}
+
+var backupListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the backups of the current project, newest first",
+	Run: func(cmd *cobra.Command, args []string) {
+		runBackupList()
+	},
+}
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore <id>",
+	Short: "Restore the files of a backup",
+	Long: `Restore every file of a backup, such as a cog file together with the
+botbox.conf it was generated from.
+
+The files being replaced are backed up first, so a restore can be undone by
+restoring that backup. Use --dry-run to print the changes as a unified diff
+without writing anything.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		runRestore(args[0], dryRun)
+	},
+}
+
+// backupEntry is one backup in the JSON result of backup list, Files are relative to the project root
+type backupEntry struct {
+	ID      string    `json:"id"`
+	Created time.Time `json:"created"`
+	Reason  string    `json:"reason"`
+	Files   []string  `json:"files"`
+}
+
+// backupListResult is the JSON result of backup list, newest first
+type backupListResult struct {
+	Backups []backupEntry `json:"backups"`
+}
+
+// restoreResult is the JSON result of restore, PreviousBackup holds the files the restore replaced
+type restoreResult struct {
+	Backup         string   `json:"backup"`
+	Files          []string `json:"files"`
+	PreviousBackup string   `json:"previous_backup"`
+	DryRun         bool     `json:"dry_run"`
+	Diff           string   `json:"diff"`
+}
+
+/**
+ * runBackupList
+ * Prints the id, time, reason, and files of every backup of the project
+ * @return ...
+ **/
+func runBackupList() {
+	rootDir := requireProject()
+
+	backups, err := utils.ListBackups(rootDir)
+	if err != nil {
+		exitWithError(exitError, err)
+	}
+	if jsonOutput() {
+		result := backupListResult{Backups: []backupEntry{}}
+		for _, backup := range backups {
+			result.Backups = append(result.Backups, backupEntry{ID: backup.ID, Created: backup.Created, Reason: backup.Reason, Files: backup.SortedFiles()})
+		}
+		printResult(result)
+		return
+	}
+	if len(backups) == 0 {
+		fmt.Fprintln(os.Stderr, "No backups yet")
+		return
+	}
+
+	for _, backup := range backups {
+		fmt.Printf("%s  %s  %s: %s\n", backup.ID, backup.Created.Local().Format("2006-01-02 15:04:05"), backup.Reason, strings.Join(backup.SortedFiles(), ", "))
+	}
+}
+
+/**
+ * runRestore
+ * Writes the files of a backup back into the project, or prints them as a diff for a dry run
+ * @param id {string} - the backup to restore
+ * @param dryRun {bool} - whether to only print the changes
+ * @return ...
+ **/
+func runRestore(id string, dryRun bool) {
+	rootDir := requireProject()
+
+	changes := &utils.ChangeSet{}
+	backup, previousID, err := utils.StageRestore(changes, rootDir, id)
+	if err != nil {
+		exitWithError(exitError, err)
+	}
+
+	if !dryRun {
+		if err := changes.Apply(); err != nil {
+			exitWithError(exitError, err)
+		}
+	}
+	if jsonOutput() {
+		result := restoreResult{Backup: backup.ID, Files: changedFiles(changes), PreviousBackup: previousID, DryRun: dryRun}
+		if dryRun {
+			result.Diff = changes.Diff(rootDir)
+		}
+		printResult(result)
+		return
+	}
+	if dryRun {
+		printDryRun(changes)
+		return
+	}
+
+	if previousID != "" {
+		fmt.Fprintf(os.Stderr, "Replaced files saved in backup %s\n", previousID)
+	}
+	for _, file := range backup.SortedFiles() {
+		fmt.Println(file)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(backupCmd)
+	backupCmd.AddCommand(backupListCmd)
+	rootCmd.AddCommand(restoreCmd)
+
+	restoreCmd.Flags().Bool("dry-run", false, "Print the changes as a unified diff without writing anything")
+}
+
+/*
+Copyright © 2025 Austin "Choice404" Choi
+
+https://github.com/choice404/botbox
+
+Bot Box
+A discord bot template generator to help create discord bots quickly and easily
+
+This code is licensed under the MIT License.
+
+MIT License
+
+Copyright (c) 2025 Austin Choi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
@@ -8,7 +8,6 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 
 	"github.com/choice404/botbox/v2/cmd/utils"
 	"github.com/spf13/cobra"
@@ -44,11 +43,7 @@ var (
 			if globalFlag {
 				configModel = utils.GlobalConfigModel(configCallback, globalConfigInitCallback)
 			} else {
-				_, err := utils.FindBotConf()
-				if err != nil {
-					fmt.Fprintln(os.Stderr, "Current directory is not in a botbox project.")
-					return
-				}
+				requireProject()
 				configModel = utils.LocalConfigModel(configCallback, localConfigInitCallback)
 			}
 			utils.CupSleeve(configModel)
@@ -57,34 +52,29 @@ var (
 )
 
 func runConfigHeadless(globalFlag bool) {
+	var config any
 	if globalFlag {
-		config, err := utils.LoadGlobalConfig()
+		globalConfig, err := utils.LoadGlobalConfig()
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "Error:", err)
-			os.Exit(1)
+			exitWithError(exitError, err)
 		}
-		jsonData, err := json.MarshalIndent(config, "", "  ")
+		config = globalConfig
+	} else {
+		requireProject()
+		localConfig, err := utils.LoadConfig()
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "Error:", err)
-			os.Exit(1)
+			exitWithError(exitError, err)
 		}
-		fmt.Println(string(jsonData))
-		return
+		config = localConfig
 	}
 
-	if _, err := utils.FindBotConf(); err != nil {
-		fmt.Fprintln(os.Stderr, "Current directory is not in a botbox project.")
-		os.Exit(1)
-	}
-	config, err := utils.LoadConfig()
-	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error:", err)
-		os.Exit(1)
+	if jsonOutput() {
+		printResult(config)
+		return
 	}
 	jsonData, err := json.MarshalIndent(config, "", "  ")
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error:", err)
-		os.Exit(1)
+		exitWithError(exitError, err)
 	}
 	fmt.Println(string(jsonData))
 }
@@ -29,8 +29,11 @@ generate a new project directory containing:
   - Environment configuration (.env or doppler.yaml)
   - src/ directory with main.py and initial cogs
 
-The project will include a demo HelloWorld cog and a CogManagement cog for dynamic 
-cog loading/unloading during development.`,
+The cogs come from the project template, picked first or set with --template.
+The default template includes a demo HelloWorld cog and a CogManagement cog for
+dynamic cog loading/unloading during development, see botbox template list for
+the others. User templates in ~/.config/botbox/templates can ask for variables,
+set headlessly with --var name=value.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if isHeadless(cmd, projectValueFlags) {
 			runCreateHeadless(cmd, args)
@@ -62,27 +65,29 @@ cog loading/unloading during development.`,
 	},
 }
 
+// projectResult is the JSON result of create and init
+type projectResult struct {
+	RootDir string   `json:"root_dir"`
+	Files   []string `json:"files"`
+}
+
 func runCreateHeadless(cmd *cobra.Command, args []string) {
 	force, _ := cmd.Flags().GetBool("force")
 
 	if _, err := utils.FindBotConf(); err == nil && !force {
-		fmt.Fprintln(os.Stderr, "Error: the current directory is already in a botbox project, use --force to create one anyway")
-		os.Exit(1)
+		exitWithError(exitUsage, fmt.Errorf("the current directory is already in a botbox project, use --force to create one anyway"))
 	}
 
 	values, err := collectProjectValues(cmd, args)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error:", err)
-		os.Exit(1)
+		exitWithError(exitUsage, err)
 	}
 
 	model := utils.CreateModel(createProjectCallback)
 	applyModelValues(&model, values)
 	setForceValue(&model, force)
 
-	if utils.PrintErrors(utils.RunHeadless(model)) {
-		os.Exit(1)
-	}
+	exitOnErrors(exitError, utils.RunHeadless(model))
 
 	rootDir := values["botName"]
 	if !filepath.IsAbs(rootDir) {
@@ -90,6 +95,10 @@ func runCreateHeadless(cmd *cobra.Command, args []string) {
 			rootDir = filepath.Join(cwd, rootDir)
 		}
 	}
+	if jsonOutput() {
+		printResult(projectResult{RootDir: rootDir, Files: readWrittenFiles(model.ModelValues)})
+		return
+	}
 	fmt.Println(rootDir)
 }
 
@@ -121,7 +130,9 @@ func createProjectCallback(model *utils.Model) []error {
 		return errors
 	}
 
-	if err := utils.CreateProject(rootDir, values, readForceValue(values)); err != nil {
+	written, err := utils.CreateProject(rootDir, values, readForceValue(values))
+	setWrittenFiles(model, written)
+	if err != nil {
 		errors = append(errors, fmt.Errorf("error creating project: %w", err))
 		return errors
 	}
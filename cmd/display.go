@@ -6,8 +6,6 @@ See end of file for extended copyright information
 package cmd
 
 import (
-	"fmt"
-
 	"github.com/choice404/botbox/v2/cmd/utils"
 	"github.com/spf13/cobra"
 )
@@ -30,11 +28,7 @@ var displayCmd = &cobra.Command{
 		if globalFlag {
 			configModel = utils.GlobalConfigModel(configCallback, globalConfigInitCallback)
 		} else {
-			_, err := utils.FindBotConf()
-			if err != nil {
-				fmt.Println("Current directory is not in a botbox project.")
-				return
-			}
+			requireProject()
 			configModel = utils.LocalConfigModel(configCallback, localConfigInitCallback)
 		}
 		utils.CupSleeve(configModel)
@@ -7,7 +7,6 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/choice404/botbox/v2/cmd/utils"
 	"github.com/spf13/cobra"
@@ -50,16 +49,11 @@ func runDockerInit(cmd *cobra.Command) {
 	// This command is flag driven, so existing files are skipped instead of prompting
 	utils.HeadlessMode = true
 
-	rootDir, err := utils.FindBotConf()
-	if err != nil {
-		fmt.Fprintln(os.Stderr, "Current directory is not in a botbox project.")
-		os.Exit(1)
-	}
+	rootDir := requireProject()
 
 	config, err := utils.LoadConfig()
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error:", err)
-		os.Exit(1)
+		exitWithError(exitError, err)
 	}
 
 	force, _ := cmd.Flags().GetBool("force")
@@ -75,12 +69,19 @@ func runDockerInit(cmd *cobra.Command) {
 
 	envProvider := utils.ResolveEnvProvider(config, rootDir)
 
-	written, err := utils.GenerateDockerFiles(rootDir, pythonVersion, envProvider, force)
+	generator, err := utils.GeneratorFor(config.BotInfo.Library)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error:", err)
-		os.Exit(1)
+		exitWithError(exitError, err)
+	}
+	written, err := generator.GenerateDockerFiles(rootDir, pythonVersion, envProvider, force)
+	if err != nil {
+		exitWithError(exitError, err)
 	}
 
+	if jsonOutput() {
+		printResult(filesResult{Files: append([]string{}, written...)})
+		return
+	}
 	for _, path := range written {
 		fmt.Println(path)
 	}
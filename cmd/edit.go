@@ -6,10 +6,10 @@ See end of file for extended copyright information
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/choice404/botbox/v2/cmd/utils"
 	"github.com/spf13/cobra"
@@ -17,12 +17,14 @@ import (
 
 var (
 	editCogName     string
+	editCogFile     string
 	editWrittenPath string
-	editBackupPath  string
+	editBackupID    string
+	editRegenerated utils.RegenerateResult
 )
 
 // Flags that carry edit values, providing any of them implies headless mode
-var editValueFlags = []string{"remove-command", "add-commands", "replace-commands", "env", "no-backup"}
+var editValueFlags = []string{"remove-command", "add-commands", "replace-commands", "remove-listener", "add-listeners", "remove-task", "add-tasks", "env", "no-backup"}
 
 // editOptions carries the headless edit flags after they leave cobra
 type editOptions struct {
@@ -30,8 +32,13 @@ type editOptions struct {
 	addCommands     string
 	replaceCommands string
 	replaceSet      bool
+	removeListeners []string
+	addListeners    string
+	removeTasks     []string
+	addTasks        string
 	env             string
 	noBackup        bool
+	dryRun          bool
 }
 
 var editCmd = &cobra.Command{
@@ -40,29 +47,29 @@ var editCmd = &cobra.Command{
 	Long: `Edit an existing cog (command module) in your Bot Box project.
 
 This command lets you change a cog without recreating it:
-  - Add new slash, prefix, or modal commands
+  - Add new slash, prefix, hybrid, modal, or context menu commands
   - Edit the info, arguments, fields, pages, and responses of a command
   - Remove commands from the cog
+  - Add, change, or remove event listeners and scheduled tasks
   - Switch the cog between the development and production environments
 
-Applying the changes regenerates the cog's .py file from its definition, so
-custom code written inside command bodies is not preserved. A .py.bak copy of
-the previous file is written first unless --no-backup is given.`,
+Applying the changes regenerates the cog's .py file from its definition. Code
+between the "# botbox:begin" and "# botbox:end" markers is protected: command,
+listener, and task bodies you changed by hand are kept as written, and the
+imports and class regions hold any imports and methods of your own. Editing
+fails without touching the file if those markers were damaged. Cogs generated
+before the markers existed are regenerated as a whole. The previous cog file
+and botbox.conf are backed up to .botbox/backups first unless --no-backup is
+given, see botbox backup list and botbox restore.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		_, err := utils.FindBotConf()
-		if err != nil {
-			fmt.Fprintln(os.Stderr, "Current directory is not in a botbox project.")
-			return
-		}
+		requireProject()
 
 		config, err := utils.LoadConfig()
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "Error:", err)
-			return
+			exitWithError(exitError, err)
 		}
 		if len(config.Cogs) == 0 {
-			fmt.Fprintln(os.Stderr, "Error: no cogs available to edit")
-			return
+			exitWithError(exitUsage, fmt.Errorf("no cogs available to edit"))
 		}
 
 		if len(args) > 0 {
@@ -77,23 +84,21 @@ the previous file is written first unless --no-backup is given.`,
 		}
 
 		model := utils.EditModel(editCallback, editInitCallback)
+		model.DryRun, _ = cmd.Flags().GetBool("dry-run")
 		utils.CupSleeve(model)
-		printEditResult()
+		printEditResult(model.Changes, model.DryRun)
 	},
 }
 
 func runEditHeadless(cmd *cobra.Command) {
 	model, err := buildEditHeadlessModel(collectEditOptions(cmd))
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error:", err)
-		os.Exit(1)
+		exitWithError(exitUsage, err)
 	}
 
-	if utils.PrintErrors(utils.RunHeadless(*model)) {
-		os.Exit(1)
-	}
+	exitOnErrors(exitError, utils.RunHeadless(*model))
 
-	printEditResult()
+	printEditResult(model.Changes, model.DryRun)
 }
 
 /**
@@ -107,16 +112,26 @@ func collectEditOptions(cmd *cobra.Command) editOptions {
 	removeCommands, _ := flags.GetStringArray("remove-command")
 	addCommands, _ := flags.GetString("add-commands")
 	replaceCommands, _ := flags.GetString("replace-commands")
+	removeListeners, _ := flags.GetStringArray("remove-listener")
+	addListeners, _ := flags.GetString("add-listeners")
+	removeTasks, _ := flags.GetStringArray("remove-task")
+	addTasks, _ := flags.GetString("add-tasks")
 	env, _ := flags.GetString("env")
 	noBackup, _ := flags.GetBool("no-backup")
+	dryRun, _ := flags.GetBool("dry-run")
 
 	return editOptions{
 		removeCommands:  removeCommands,
 		addCommands:     addCommands,
 		replaceCommands: replaceCommands,
 		replaceSet:      flags.Changed("replace-commands"),
+		removeListeners: removeListeners,
+		addListeners:    addListeners,
+		removeTasks:     removeTasks,
+		addTasks:        addTasks,
 		env:             env,
 		noBackup:        noBackup,
+		dryRun:          dryRun,
 	}
 }
 
@@ -124,6 +139,7 @@ func collectEditOptions(cmd *cobra.Command) editOptions {
  * buildEditHeadlessModel
  * Applies the edit operations to the cog's command set and builds the model to run
  * Operations run in a fixed order, replace first, then removes, then adds
+ * Listener and task removes run before their adds, an added listener or task replaces the one for the same event or name
  * @param opts {editOptions} - the collected headless flags
  * @return *utils.Model - the model ready for RunHeadless
  * @return error - the first validation or parse failure
@@ -157,19 +173,13 @@ func buildEditHeadlessModel(opts editOptions) (*utils.Model, error) {
 		commands = normalizeModalReturns(replaced)
 	}
 
-	// Removes must name a command that exists in the working set
+	// Removes must name a command that exists in the working set, a prefix group takes its subcommands with it
 	for _, name := range opts.removeCommands {
-		removed := false
-		for i, command := range commands {
-			if command.Name == name {
-				commands = append(commands[:i], commands[i+1:]...)
-				removed = true
-				break
-			}
-		}
+		remaining, removed := utils.RemoveCommand(commands, name)
 		if !removed {
 			return nil, fmt.Errorf("command '%s' does not exist in cog '%s'", name, cog.Name)
 		}
+		commands = remaining
 	}
 
 	if opts.addCommands != "" {
@@ -186,19 +196,77 @@ func buildEditHeadlessModel(opts editOptions) (*utils.Model, error) {
 			return nil, fmt.Errorf("command '%s': %v", command.Name, err)
 		}
 	}
+	if err := utils.ValidatePrefixGroups(commands); err != nil {
+		return nil, err
+	}
+
+	listeners := append([]utils.ListenerInfo{}, cog.Listeners...)
+	for _, event := range opts.removeListeners {
+		before := len(listeners)
+		listeners = slices.DeleteFunc(listeners, func(listener utils.ListenerInfo) bool {
+			return listener.Event == event
+		})
+		if len(listeners) == before {
+			return nil, fmt.Errorf("listener '%s' does not exist in cog '%s'", event, cog.Name)
+		}
+	}
+
+	if opts.addListeners != "" {
+		added, err := parseListenersInput(opts.addListeners)
+		if err != nil {
+			return nil, err
+		}
+		for _, listener := range added {
+			listeners = slices.DeleteFunc(listeners, func(existing utils.ListenerInfo) bool {
+				return existing.Event == listener.Event
+			})
+			listeners = append(listeners, listener)
+		}
+	}
+
+	if err := utils.ValidateListeners(listeners, commands, nil); err != nil {
+		return nil, err
+	}
+
+	tasks := append([]utils.TaskInfo{}, cog.Tasks...)
+	for _, name := range opts.removeTasks {
+		before := len(tasks)
+		tasks = slices.DeleteFunc(tasks, func(task utils.TaskInfo) bool {
+			return task.Name == name
+		})
+		if len(tasks) == before {
+			return nil, fmt.Errorf("task '%s' does not exist in cog '%s'", name, cog.Name)
+		}
+	}
+
+	if opts.addTasks != "" {
+		added, err := parseTasksInput(opts.addTasks)
+		if err != nil {
+			return nil, err
+		}
+		for _, task := range added {
+			tasks = slices.DeleteFunc(tasks, func(existing utils.TaskInfo) bool {
+				return existing.Name == task.Name
+			})
+			tasks = append(tasks, task)
+		}
+	}
+
+	if err := utils.ValidateTasks(tasks, commands, listeners); err != nil {
+		return nil, err
+	}
 
 	if opts.env != "" && opts.env != "development" && opts.env != "production" {
 		return nil, fmt.Errorf("env must be development or production")
 	}
 
-	// Modal commands are app commands, so they live with the slash commands
 	slashCommands := []utils.CommandInfo{}
 	prefixCommands := []utils.CommandInfo{}
 	for _, command := range commands {
-		if command.Type == "prefix" {
-			prefixCommands = append(prefixCommands, command)
-		} else {
+		if utils.IsAppCommand(command.Type) {
 			slashCommands = append(slashCommands, command)
+		} else {
+			prefixCommands = append(prefixCommands, command)
 		}
 	}
 
@@ -210,30 +278,40 @@ func buildEditHeadlessModel(opts editOptions) (*utils.Model, error) {
 	if err != nil {
 		return nil, err
 	}
+	listenerJSON, err := utils.ListenerInfoSliceToJSON(listeners)
+	if err != nil {
+		return nil, err
+	}
+	taskJSON, err := utils.TaskInfoSliceToJSON(tasks)
+	if err != nil {
+		return nil, err
+	}
 
 	model := utils.EditModel(editCallback, editInitCallback)
 	*model.ModelValues.Map["cogName"] = cog.Name
 	model.ModelValues.Map["slashCommands"] = &slashJSON
 	model.ModelValues.Map["prefixCommands"] = &prefixJSON
+	model.ModelValues.Map["listeners"] = &listenerJSON
+	model.ModelValues.Map["tasks"] = &taskJSON
 	*model.ModelValues.Map["cogEnv"] = opts.env
 	if opts.noBackup {
 		*model.ModelValues.Map["backup"] = "no"
 	}
+	model.DryRun = opts.dryRun
 
 	return &model, nil
 }
 
 /**
  * normalizeModalReturns
- * Returns a copy of the commands with modal return types pinned to None
+ * Returns a copy of the commands with modal and context menu return types pinned to None
  * @param commands {[]utils.CommandInfo} - the commands to normalize
  * @return []utils.CommandInfo - the normalized copy
  **/
 func normalizeModalReturns(commands []utils.CommandInfo) []utils.CommandInfo {
 	normalized := make([]utils.CommandInfo, 0, len(commands))
 	for _, command := range commands {
-		// Modal commands only respond through the modal, so their return type is fixed
-		if command.Type == "modal" {
+		if utils.HasFixedReturnType(command.Type) {
 			command.ReturnType = "None"
 		}
 		normalized = append(normalized, command)
@@ -260,18 +338,45 @@ func findProjectCog(config utils.Config, name string) (int, bool) {
 
 /**
  * printEditResult
- * Prints the regeneration warning to stderr and the written file path to stdout
+ * Prints the regeneration warning to stderr and the written file path to stdout, or the diff of a dry run.
+ * JSON output prints the edited cog's result instead
+ * @param changes {*utils.ChangeSet} - the files the edit staged
+ * @param dryRun {bool} - whether the changes were only previewed
  * @return ...
  **/
-func printEditResult() {
-	if editWrittenPath == "" {
+func printEditResult(changes *utils.ChangeSet, dryRun bool) {
+	// Nothing was written when the review screen was cancelled
+	if editWrittenPath == "" || (!dryRun && !changes.Applied()) {
+		return
+	}
+	var warnings []string
+	if editRegenerated.Unprotected {
+		warning := "the previous cog file had no protected region markers, custom code in command bodies is not preserved"
+		if editBackupID != "" {
+			warning += ", previous version saved in backup " + editBackupID
+		}
+		warnings = append(warnings, warning)
+	}
+
+	if jsonOutput() {
+		result := newCogResult(changes, editCogFile, dryRun)
+		result.Backup = editBackupID
+		result.Preserved = append(result.Preserved, editRegenerated.Preserved...)
+		result.Warnings = append(result.Warnings, warnings...)
+		printResult(result)
 		return
 	}
-	warning := "regeneration rewrites the cog file from its definition, custom code in command bodies is not preserved"
-	if editBackupPath != "" {
-		warning += ", previous version saved to " + editBackupPath
+
+	for _, warning := range warnings {
+		fmt.Fprintln(os.Stderr, "Warning:", warning)
+	}
+	if len(editRegenerated.Preserved) > 0 {
+		fmt.Fprintln(os.Stderr, "Kept custom code in:", strings.Join(editRegenerated.Preserved, ", "))
+	}
+	if dryRun {
+		printDryRun(changes)
+		return
 	}
-	fmt.Fprintln(os.Stderr, "Warning:", warning)
 	fmt.Println(editWrittenPath)
 }
 
@@ -332,42 +437,51 @@ func editCallback(model *utils.Model) []error {
 	}
 	prefixCommands = normalizedPrefix
 
+	listeners, err := utils.JSONToListenerInfoSlice(*values.Map["listeners"])
+	if err != nil {
+		errors = append(errors, fmt.Errorf("error reading listeners: %w", err))
+		return errors
+	}
+	tasks, err := utils.JSONToTaskInfoSlice(*values.Map["tasks"])
+	if err != nil {
+		errors = append(errors, fmt.Errorf("error reading tasks: %w", err))
+		return errors
+	}
+
 	cog := config.Cogs[cogIndex]
 	cog.SlashCommands = slashCommands
 	cog.PrefixCommands = prefixCommands
+	cog.Listeners = listeners
+	cog.Tasks = tasks
 	if env := *values.Map["cogEnv"]; env != "" {
 		cog.Env = env
 	}
 
-	backup := *values.Map["backup"] != "no"
-	if err := utils.RegenerateCogFile(rootDir, config, cog, backup); err != nil {
+	// A dry run writes nothing, so there is no backup to take or report
+	backup := *values.Map["backup"] != "no" && !model.DryRun
+	regenerated, err := utils.RegenerateCogFile(model.Changes, rootDir, config, config, cog, backup)
+	if err != nil {
 		errors = append(errors, fmt.Errorf("error regenerating cog file: %w", err))
 		return errors
 	}
 
 	config.Cogs[cogIndex] = cog
 
-	jsonData, err := json.MarshalIndent(config, "", "  ")
-	if err != nil {
-		errors = append(errors, fmt.Errorf("failed to marshal config to JSON: %w", err))
+	if err := utils.StageConfig(model.Changes, rootDir, config); err != nil {
+		errors = append(errors, fmt.Errorf("failed to write updated botbox.conf: %w", err))
 		return errors
 	}
 
-	confPath := filepath.Join(rootDir, "botbox.conf")
-	err = os.WriteFile(confPath, jsonData, 0644)
-	if err != nil {
-		errors = append(errors, fmt.Errorf("failed to write updated botbox.conf: %w", err))
+	if _, err := utils.UpdateLocaleFiles(model.Changes, rootDir, config, nil, true); err != nil {
+		errors = append(errors, fmt.Errorf("error writing locale files: %w", err))
 		return errors
 	}
 
 	// The run function prints the result after the tui or headless run finishes
-	editWrittenPath = filepath.Join(rootDir, "src", "cogs", cog.File+".py")
-	editBackupPath = ""
-	if backup {
-		if _, err := os.Stat(editWrittenPath + ".bak"); err == nil {
-			editBackupPath = editWrittenPath + ".bak"
-		}
-	}
+	editCogFile = cog.File
+	editWrittenPath = regenerated.Path
+	editBackupID = regenerated.BackupID
+	editRegenerated = regenerated
 
 	return nil
 }
@@ -424,15 +538,46 @@ func editInitCallback(model *utils.Model, allFormsModels []utils.Values) {
 		}
 		*modelValues.Map["prefixCommands"] = prefixJSON
 	}
+	if *modelValues.Map["listeners"] == "" {
+		listeners := cog.Listeners
+		if listeners == nil {
+			listeners = []utils.ListenerInfo{}
+		}
+		listenerJSON, err := utils.ListenerInfoSliceToJSON(listeners)
+		if err != nil {
+			errors = append(errors, fmt.Errorf("error reading listeners: %w", err))
+			model.HandleError(errors)
+			return
+		}
+		*modelValues.Map["listeners"] = listenerJSON
+	}
+	if *modelValues.Map["tasks"] == "" {
+		tasks := cog.Tasks
+		if tasks == nil {
+			tasks = []utils.TaskInfo{}
+		}
+		taskJSON, err := utils.TaskInfoSliceToJSON(tasks)
+		if err != nil {
+			errors = append(errors, fmt.Errorf("error reading tasks: %w", err))
+			model.HandleError(errors)
+			return
+		}
+		*modelValues.Map["tasks"] = taskJSON
+	}
 }
 
 func init() {
 	rootCmd.AddCommand(editCmd)
-	editCmd.Flags().StringArray("remove-command", nil, "Name of a command to remove from the cog, repeatable")
+	editCmd.Flags().StringArray("remove-command", nil, "Path of a command to remove from the cog such as \"ticket list\", repeatable, a prefix group also loses its subcommands")
 	editCmd.Flags().String("add-commands", "", "JSON array of commands to add, accepts inline JSON, @path/to/file.json, or - for stdin")
 	editCmd.Flags().String("replace-commands", "", "JSON array that replaces every command on the cog, accepts inline JSON, @path/to/file.json, or - for stdin")
+	editCmd.Flags().StringArray("remove-listener", nil, "Event of a listener to remove from the cog, repeatable")
+	editCmd.Flags().String("add-listeners", "", "JSON array of event listeners to add, replacing any for the same event, accepts inline JSON, @path/to/file.json, or - for stdin")
+	editCmd.Flags().StringArray("remove-task", nil, "Name of a scheduled task to remove from the cog, repeatable")
+	editCmd.Flags().String("add-tasks", "", "JSON array of scheduled tasks to add, replacing any with the same name, accepts inline JSON, @path/to/file.json, or - for stdin")
 	editCmd.Flags().String("env", "", "Cog environment: development or production")
-	editCmd.Flags().Bool("no-backup", false, "Skip the .py.bak backup written before the cog file is regenerated")
+	editCmd.Flags().Bool("no-backup", false, "Skip the backup of the cog file and botbox.conf taken before the cog file is regenerated")
+	editCmd.Flags().Bool("dry-run", false, "Print a unified diff of the files the edit would change without writing them")
 }
 
 /*
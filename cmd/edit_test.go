@@ -6,8 +6,10 @@ See end of file for extended copyright information
 package cmd
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
@@ -107,8 +109,10 @@ func runEditForTest(t *testing.T, cogName string, opts editOptions) error {
 	editCogName = cogName
 	t.Cleanup(func() {
 		editCogName = ""
+		editCogFile = ""
 		editWrittenPath = ""
-		editBackupPath = ""
+		editBackupID = ""
+		editRegenerated = utils.RegenerateResult{}
 	})
 
 	model, err := buildEditHeadlessModel(opts)
@@ -147,6 +151,16 @@ func loadEditedCog(t *testing.T) utils.CogConfig {
 	return utils.CogConfig{}
 }
 
+// listTestBackups reads the project's backups, newest first
+func listTestBackups(t *testing.T, project string) []utils.Backup {
+	t.Helper()
+	backups, err := utils.ListBackups(project)
+	if err != nil {
+		t.Fatalf("failed to list backups: %v", err)
+	}
+	return backups
+}
+
 func TestEditHeadlessRemoveCommand(t *testing.T) {
 	project := setupEditProject(t)
 
@@ -165,8 +179,15 @@ func TestEditHeadlessRemoveCommand(t *testing.T) {
 	if len(cog.PrefixCommands) != 1 || cog.PrefixCommands[0].Name != "wave" {
 		t.Errorf("prefix commands = %+v, want only wave", cog.PrefixCommands)
 	}
-	if _, err := os.Stat(filepath.Join(project, "src", "cogs", "greetings.py.bak")); err != nil {
-		t.Errorf("backup file missing: %v", err)
+	backups := listTestBackups(t, project)
+	if len(backups) != 1 {
+		t.Fatalf("backups = %+v, want one", backups)
+	}
+	if files := backups[0].SortedFiles(); !slices.Equal(files, []string{"botbox.conf", "src/cogs/greetings.py"}) {
+		t.Errorf("backed up files = %v, want botbox.conf and the cog file", files)
+	}
+	if !strings.Contains(backups[0].Files["src/cogs/greetings.py"], "async def hello(") {
+		t.Error("backup should hold the cog file from before the edit")
 	}
 }
 
@@ -237,8 +258,241 @@ func TestEditHeadlessNoBackup(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if _, err := os.Stat(filepath.Join(project, "src", "cogs", "greetings.py.bak")); !os.IsNotExist(err) {
-		t.Errorf("backup file should not exist, stat error = %v", err)
+	if backups := listTestBackups(t, project); len(backups) != 0 {
+		t.Errorf("backups = %+v, want none", backups)
+	}
+}
+
+func TestEditHeadlessDryRun(t *testing.T) {
+	project := setupEditProject(t)
+	before := readCogFile(t, project)
+	confBefore, err := os.ReadFile(filepath.Join(project, "botbox.conf"))
+	if err != nil {
+		t.Fatalf("failed to read botbox.conf: %v", err)
+	}
+
+	editCogName = "greetings"
+	t.Cleanup(func() {
+		editCogName = ""
+		editRegenerated = utils.RegenerateResult{}
+	})
+	model, err := buildEditHeadlessModel(editOptions{removeCommands: []string{"wave"}, dryRun: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if errs := utils.RunHeadless(*model); len(errs) > 0 {
+		t.Fatalf("edit callbacks failed: %v", errs)
+	}
+
+	if content := readCogFile(t, project); content != before {
+		t.Error("dry run rewrote the cog file")
+	}
+	confAfter, err := os.ReadFile(filepath.Join(project, "botbox.conf"))
+	if err != nil {
+		t.Fatalf("failed to read botbox.conf: %v", err)
+	}
+	if string(confAfter) != string(confBefore) {
+		t.Error("dry run rewrote botbox.conf")
+	}
+	if backups := listTestBackups(t, project); len(backups) != 0 {
+		t.Errorf("dry run should not write a backup, got %+v", backups)
+	}
+	if editBackupID != "" {
+		t.Errorf("dry run reported backup %q, want none", editBackupID)
+	}
+
+	if model.Changes.Applied() {
+		t.Error("dry run changes should not be applied")
+	}
+	diff := model.Changes.Diff(project)
+	for _, want := range []string{"--- a/src/cogs/greetings.py", "+++ b/botbox.conf", "-    async def wave("} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("dry run diff missing %q:\n%s", want, diff)
+		}
+	}
+	if strings.Contains(diff, ".botbox/backups") {
+		t.Errorf("dry run diff lists a backup that is never written:\n%s", diff)
+	}
+}
+
+// writeCogFile replaces the generated cog file, standing in for hand written changes
+func writeCogFile(t *testing.T, project, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(project, "src", "cogs", "greetings.py"), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write cog file: %v", err)
+	}
+}
+
+func TestEditHeadlessKeepsProtectedRegions(t *testing.T) {
+	project := setupEditProject(t)
+
+	content := readCogFile(t, project)
+	content = strings.Replace(content, "        # botbox:begin body hello\n", "        # botbox:begin body hello\n        target = target.title()\n", 1)
+	content = strings.Replace(content, "    # botbox:begin class\n", "    # botbox:begin class\n    def shout(self, text: str) -> str:\n        return text.upper()\n", 1)
+	writeCogFile(t, project, content)
+
+	wave := editTestWave()
+	wave.Responses = []utils.ResponseInfo{{Type: "message", Content: "waves back"}}
+	replaceJSON, err := utils.CmdInfoSliceToJSON([]utils.CommandInfo{editTestHello(), editTestWizard(), wave})
+	if err != nil {
+		t.Fatalf("failed to marshal commands: %v", err)
+	}
+	if err := runEditForTest(t, "greetings", editOptions{replaceCommands: replaceJSON, replaceSet: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	content = readCogFile(t, project)
+	for _, snippet := range []string{
+		"        target = target.title()\n",
+		"    def shout(self, text: str) -> str:\n",
+		`await ctx.send(f"waves back", ephemeral=False)`,
+	} {
+		if !strings.Contains(content, snippet) {
+			t.Errorf("regenerated cog file is missing %q", snippet)
+		}
+	}
+	if want := []string{"body hello", "class"}; !slices.Equal(editRegenerated.Preserved, want) {
+		t.Errorf("preserved regions = %v, want %v", editRegenerated.Preserved, want)
+	}
+	if editRegenerated.Unprotected {
+		t.Error("a cog file with markers should not be reported as unprotected")
+	}
+}
+
+func TestEditHeadlessDamagedMarkers(t *testing.T) {
+	project := setupEditProject(t)
+
+	damaged := strings.Replace(readCogFile(t, project), "        # botbox:end body wave\n", "", 1)
+	writeCogFile(t, project, damaged)
+
+	editCogName = "greetings"
+	t.Cleanup(func() { editCogName = "" })
+	model, err := buildEditHeadlessModel(editOptions{removeCommands: []string{"hello"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	errs := utils.RunHeadless(*model)
+	if len(errs) == 0 || !strings.Contains(errs[0].Error(), `begins inside region "body wave"`) {
+		t.Fatalf("errors = %v, want the unclosed body wave region reported", errs)
+	}
+
+	if content := readCogFile(t, project); content != damaged {
+		t.Error("a cog file with damaged markers should be left untouched")
+	}
+	if backups := listTestBackups(t, project); len(backups) != 0 {
+		t.Errorf("no backup should be written when regeneration fails, got %+v", backups)
+	}
+	if cog := loadEditedCog(t); len(cog.SlashCommands) != 2 {
+		t.Errorf("botbox.conf should keep both slash commands, got %+v", cog.SlashCommands)
+	}
+}
+
+func TestEditHeadlessListeners(t *testing.T) {
+	project := setupEditProject(t)
+
+	addJSON := `[{"Event":"on_member_join","Action":"role","RoleID":"123456789012345678"},{"Event":"on_ready","Action":"log"}]`
+	if err := runEditForTest(t, "greetings", editOptions{addListeners: addJSON}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if content := readCogFile(t, project); !strings.Contains(content, "async def on_member_join(self, member: discord.Member) -> None:") {
+		t.Error("regenerated cog file should contain the added listener")
+	}
+
+	// An added listener replaces the one for the same event, removes run first
+	replaceJSON := `[{"Event":"on_member_join","Action":"log"}]`
+	if err := runEditForTest(t, "greetings", editOptions{removeListeners: []string{"on_ready"}, addListeners: replaceJSON}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cog := loadEditedCog(t)
+	if len(cog.Listeners) != 1 || cog.Listeners[0] != (utils.ListenerInfo{Event: "on_member_join", Action: "log"}) {
+		t.Errorf("listeners = %+v, want only the on_member_join log listener", cog.Listeners)
+	}
+
+	// The regenerated listeners round trip through sync
+	result, err := utils.SyncCogsWithConfig()
+	if err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	if len(result.UpdatedCogs) > 0 {
+		t.Errorf("sync found changes after a listener edit: %v", result.UpdatedCogs)
+	}
+}
+
+func TestEditHeadlessTasks(t *testing.T) {
+	project := setupEditProject(t)
+
+	addJSON := `[{"Name":"digest","Times":["09:00"],"Timezone":"Europe/Berlin","ChannelID":"123456789012345678","Content":"Good morning"},{"Name":"cleanup","Interval":1,"Unit":"hours"}]`
+	if err := runEditForTest(t, "greetings", editOptions{addTasks: addJSON}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	content := readCogFile(t, project)
+	for _, snippet := range []string{"async def digest(self) -> None:", "self.cleanup.start()", "self.digest.cancel()"} {
+		if !strings.Contains(content, snippet) {
+			t.Errorf("regenerated cog file is missing %s", snippet)
+		}
+	}
+
+	if err := runEditForTest(t, "greetings", editOptions{removeTasks: []string{"digest"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cog := loadEditedCog(t)
+	if len(cog.Tasks) != 1 || cog.Tasks[0].Name != "cleanup" {
+		t.Errorf("tasks = %+v, want only cleanup", cog.Tasks)
+	}
+
+	// The regenerated tasks round trip through sync
+	result, err := utils.SyncCogsWithConfig()
+	if err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	if len(result.UpdatedCogs) > 0 {
+		t.Errorf("sync found changes after a task edit: %v", result.UpdatedCogs)
+	}
+}
+
+func TestEditHeadlessPrefixGroups(t *testing.T) {
+	project := setupEditProject(t)
+
+	addJSON := `[
+		{"Name":"tag","Scope":"guild","Type":"prefix","Description":"Manages tags","InvokeWithoutCommand":true,"Aliases":["t"],"Args":[],"ReturnType":"None"},
+		{"Name":"create","Scope":"guild","Type":"prefix","Description":"Creates a tag","Group":"tag","Aliases":["new"],"ReturnType":"None",
+			"Args":[{"Name":"name","Type":"str","Description":"Tag name"},{"Name":"content","Type":"str","Description":"Tag text","Rest":true}]},
+		{"Name":"share","Scope":"guild","Type":"prefix","Description":"Shares a tag","Group":"tag","Hidden":true,"ReturnType":"None",
+			"Args":[{"Name":"members","Type":"discord.Member","Description":"Who to share with","Greedy":true}]}
+	]`
+	if err := runEditForTest(t, "greetings", editOptions{addCommands: addJSON}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	content := readCogFile(t, project)
+	for _, snippet := range []string{
+		`@commands.group(aliases=["t"], invoke_without_command=True)`,
+		`@tag.command(name="create", aliases=["new"])`,
+		"async def tag_create(self, ctx: commands.Context, name: str, *, content: str) -> None:",
+		`@tag.command(name="share", hidden=True)`,
+		"members: commands.Greedy[discord.Member]",
+	} {
+		if !strings.Contains(content, snippet) {
+			t.Errorf("regenerated cog file is missing %s", snippet)
+		}
+	}
+
+	// The prefix options round trip through sync
+	result, err := utils.SyncCogsWithConfig()
+	if err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	if len(result.UpdatedCogs) > 0 {
+		t.Errorf("sync found changes after adding prefix groups: %v", result.UpdatedCogs)
+	}
+
+	// Removing the group command takes its subcommands with it
+	if err := runEditForTest(t, "greetings", editOptions{removeCommands: []string{"tag"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, command := range loadEditedCog(t).PrefixCommands {
+		if command.Name == "tag" || command.Group == "tag" {
+			t.Errorf("command %s should have been removed with its group", command.Name)
+		}
 	}
 }
 
@@ -254,7 +508,12 @@ func TestEditHeadlessErrors(t *testing.T) {
 		{"unknown cog fails", "ghost", editOptions{removeCommands: []string{"hello"}}},
 		{"duplicate added name fails", "greetings", editOptions{addCommands: `[{"Name":"wave","Scope":"guild","Type":"slash","Description":"d","Args":[],"ReturnType":"None"}]`}},
 		{"bad env fails", "greetings", editOptions{env: "staging"}},
+		{"prefix subcommand without its group fails", "greetings", editOptions{addCommands: `[{"Name":"create","Scope":"guild","Type":"prefix","Description":"d","Group":"tag","Args":[],"ReturnType":"None"}]`}},
 		{"bad json fails", "greetings", editOptions{addCommands: "not json"}},
+		{"unknown remove listener fails", "greetings", editOptions{removeListeners: []string{"on_ready"}}},
+		{"unknown remove task fails", "greetings", editOptions{removeTasks: []string{"digest"}}},
+		{"invalid task fails", "greetings", editOptions{addTasks: `[{"Name":"digest","Interval":1,"Unit":"days"}]`}},
+		{"invalid listener fails", "greetings", editOptions{addListeners: `[{"Event":"on_message","Action":"role","RoleID":"123456789012345678"}]`}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -275,6 +534,51 @@ func TestEditHeadlessMissingCogName(t *testing.T) {
 	}
 }
 
+func TestEditHeadlessLocalizations(t *testing.T) {
+	project := setupEditProject(t)
+
+	addJSON := `[{"Name":"bye","Scope":"guild","Type":"slash","Description":"Says goodbye","ReturnType":"None",
+		"NameLocalizations":{"de":"tschuess"},"DescriptionLocalizations":{"de":"Verabschiedet sich"},
+		"Args":[{"Name":"target","Type":"str","Description":"Who","DescriptionLocalizations":{"fr":"Qui"}}]}]`
+	if err := runEditForTest(t, "greetings", editOptions{addCommands: addJSON}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var german map[string]string
+	data, err := os.ReadFile(filepath.Join(project, "src", "locales", "de.json"))
+	if err != nil {
+		t.Fatalf("failed to read de.json: %v", err)
+	}
+	if err := json.Unmarshal(data, &german); err != nil {
+		t.Fatalf("failed to parse de.json: %v", err)
+	}
+	if german["commands.bye.name"] != "tschuess" || german["commands.bye.description"] != "Verabschiedet sich" {
+		t.Errorf("de.json = %v, want the bye translations", german)
+	}
+	// Untranslated keys are written empty so translators can find them
+	if text, exists := german["commands.bye.params.target.description"]; !exists || text != "" {
+		t.Errorf("de.json target description = %q (exists %v), want an empty key", text, exists)
+	}
+	if _, err := os.Stat(filepath.Join(project, "src", "locales", "fr.json")); err != nil {
+		t.Errorf("fr.json was not written: %v", err)
+	}
+
+	// The translations come back from the locale files, so sync leaves the config as it is
+	result, err := utils.SyncCogsWithConfig()
+	if err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	if len(result.UpdatedCogs) > 0 {
+		t.Errorf("sync updated %v, want no changes", result.UpdatedCogs)
+	}
+	cog := loadEditedCog(t)
+	for _, command := range cog.SlashCommands {
+		if command.Name == "bye" && command.Args[0].DescriptionLocalizations["fr"] != "Qui" {
+			t.Errorf("bye target translations = %v, want fr Qui", command.Args[0].DescriptionLocalizations)
+		}
+	}
+}
+
 func TestEditThenSyncReportsNoChanges(t *testing.T) {
 	setupEditProject(t)
 
@@ -289,8 +593,9 @@ func TestEditThenSyncReportsNoChanges(t *testing.T) {
 		if err != nil {
 			t.Fatalf("sync round %d failed: %v", round, err)
 		}
-		if len(result.Errors) > 0 {
-			t.Fatalf("sync round %d reported errors: %v", round, result.Errors)
+		// Generated code must be fully readable, so even warnings are a failure
+		if len(result.Diagnostics) > 0 {
+			t.Fatalf("sync round %d reported diagnostics: %v", round, result.Diagnostics)
 		}
 		if len(result.AddedCogs) > 0 || len(result.UpdatedCogs) > 0 || len(result.RemovedCogs) > 0 {
 			t.Errorf("sync round %d found changes: added=%v updated=%v removed=%v",
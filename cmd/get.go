@@ -24,7 +24,7 @@ var getCmd = &cobra.Command{
 	Long: `Retrieve a configuration value using dot notation for nested keys.
 
 Local configuration keys (default):
-  - bot.name, bot.description, bot.command_prefix, bot.author, bot.help_style, bot.env_provider
+  - bot.name, bot.description, bot.command_prefix, bot.author, bot.help_style, bot.library, bot.env_provider
 
 Global configuration keys (use -g flag):
   - cli.check_updates, cli.auto_update
@@ -41,6 +41,13 @@ Examples:
 	RunE: runConfigGet,
 }
 
+// configValueResult is the JSON result of config get and config set, Scope is local or global
+type configValueResult struct {
+	Scope string `json:"scope"`
+	Key   string `json:"key"`
+	Value any    `json:"value"`
+}
+
 func runConfigGet(cmd *cobra.Command, args []string) error {
 	key := args[0]
 
@@ -61,10 +68,14 @@ func handleGlobalConfigGet(key string) error {
 	}
 
 	if !isValidGlobalConfigKey(key) {
-		return fmt.Errorf("invalid global config key: %s", key)
+		return withExitCode(exitUsage, fmt.Errorf("invalid global config key: %s", key))
 	}
 
 	value := utils.GetGlobalConfigValue(key)
+	if jsonOutput() {
+		printResult(configValueResult{Scope: "global", Key: key, Value: value})
+		return nil
+	}
 	if getRaw {
 		if value != nil {
 			fmt.Printf("%v\n", value)
@@ -83,11 +94,11 @@ func handleGlobalConfigGet(key string) error {
 func handleLocalConfigGet(key string) error {
 	_, err := utils.FindBotConf()
 	if err != nil {
-		return fmt.Errorf("not in a botbox project: %w", err)
+		return withExitCode(exitNotProject, errNotProject)
 	}
 
 	if !isValidLocalConfigKey(key) {
-		return fmt.Errorf("invalid local config key: %s", key)
+		return withExitCode(exitUsage, fmt.Errorf("invalid local config key: %s", key))
 	}
 
 	value, err := utils.GetLocalConfigValue(key)
@@ -95,6 +106,11 @@ func handleLocalConfigGet(key string) error {
 		return fmt.Errorf("failed to get local config value: %w", err)
 	}
 
+	if jsonOutput() {
+		printResult(configValueResult{Scope: "local", Key: key, Value: value})
+		return nil
+	}
+
 	if getRaw {
 		fmt.Printf("%v\n", value)
 		return nil
@@ -6,15 +6,17 @@ See end of file for extended copyright information
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/choice404/botbox/v2/cmd/utils"
 	"github.com/spf13/cobra"
 )
 
 // Flags that carry project values, providing any of them implies headless mode
-var projectValueFlags = []string{"name", "description", "author", "prefix", "env", "token", "doppler-project", "guild", "doppler-env", "license", "help-style", "docker"}
+var projectValueFlags = []string{"name", "description", "author", "prefix", "env", "token", "doppler-project", "guild", "doppler-env", "license", "help-style", "library", "docker", "template", "var"}
 
 /**
  * registerProjectFlags
@@ -34,18 +36,24 @@ func registerProjectFlags(cmd *cobra.Command) {
 	cmd.Flags().String("doppler-env", "", "Doppler environment name when using --env doppler")
 	cmd.Flags().String("license", "mit", "License type: mit, apache-2.0, gpl-3.0, bsd-3-clause, unlicense, no-license")
 	cmd.Flags().String("help-style", "compact", "How the generated help command formats its output: compact or detailed")
+	cmd.Flags().String("library", utils.DefaultLibrary, "Discord library the generated code is written for: discord.py, py-cord, nextcord, or disnake")
 	cmd.Flags().Bool("docker", false, "Generate Docker files (Dockerfile, docker-compose.yml, .dockerignore)")
+	cmd.Flags().String("template", utils.DefaultProjectTemplate, "Project template to start from, see botbox template list")
+	cmd.Flags().StringArray("var", nil, "Template variable as name=value (repeatable)")
 	cmd.Flags().Bool("force", false, "Overwrite existing files without prompting")
 }
 
 /**
  * isHeadless
- * Decides if a command should run without the tui
+ * Decides if a command should run without the tui, JSON output always does
  * @param cmd {*cobra.Command} - the command being run
  * @param valueFlags {[]string} - flags that imply headless mode when set
  * @return bool - true when the command should skip the tui
  **/
 func isHeadless(cmd *cobra.Command, valueFlags []string) bool {
+	if jsonOutput() {
+		return true
+	}
 	if headless, err := cmd.Flags().GetBool("headless"); err == nil && headless {
 		return true
 	}
@@ -57,6 +65,22 @@ func isHeadless(cmd *cobra.Command, valueFlags []string) bool {
 	return false
 }
 
+/**
+ * printDryRun
+ * Prints the files a dry run staged as a unified diff, stdout only carries the diff so it can be piped to a pager
+ * @param changes {*utils.ChangeSet} - the staged files
+ * @return ...
+ **/
+func printDryRun(changes *utils.ChangeSet) {
+	rootDir, _ := utils.FindBotConf()
+	diff := changes.Diff(rootDir)
+	if diff == "" {
+		fmt.Fprintln(os.Stderr, "Dry run: no files would change")
+		return
+	}
+	fmt.Print(diff)
+}
+
 /**
  * collectProjectValues
  * Reads the project flags, applies global config defaults, and validates everything
@@ -139,6 +163,14 @@ func collectProjectValues(cmd *cobra.Command, args []string) (map[string]string,
 		return nil, err
 	}
 
+	library, _ := flags.GetString("library")
+	if library == "" {
+		library = utils.DefaultLibrary
+	}
+	if err := utils.ValidateLibrary(library); err != nil {
+		return nil, err
+	}
+
 	// The docker flag rides the values bus as yes or no like the force flag does
 	docker, _ := flags.GetBool("docker")
 	dockerize := "no"
@@ -146,6 +178,30 @@ func collectProjectValues(cmd *cobra.Command, args []string) (map[string]string,
 		dockerize = "yes"
 	}
 
+	templateName, _ := flags.GetString("template")
+	projectTemplate, err := utils.FindProjectTemplate(templateName)
+	if err != nil {
+		return nil, err
+	}
+	given := map[string]string{}
+	vars, _ := flags.GetStringArray("var")
+	for _, v := range vars {
+		key, value, ok := strings.Cut(v, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --var '%s', use name=value", v)
+		}
+		given[key] = value
+	}
+	// Checked here so a template the library has no version of, or a missing variable, fails before the project directory is created
+	if err := projectTemplate.SupportsLibrary(library); err != nil {
+		return nil, err
+	}
+	resolved, err := projectTemplate.ResolveVariables(given)
+	if err != nil {
+		return nil, err
+	}
+	templateVars, _ := json.Marshal(resolved)
+
 	return map[string]string{
 		"botName":                name,
 		"botDescription":         description,
@@ -156,7 +212,10 @@ func collectProjectValues(cmd *cobra.Command, args []string) (map[string]string,
 		"botGuildDopplerEnv":     guildOrEnv,
 		"licenseType":            license,
 		"helpStyle":              helpStyle,
+		"library":                library,
 		"dockerize":              dockerize,
+		"template":               projectTemplate.Name,
+		"templateVars":           string(templateVars),
 	}, nil
 }
 
@@ -193,6 +252,31 @@ func setForceValue(model *utils.Model, force bool) {
 	model.ModelValues.Map["force"] = &s
 }
 
+/**
+ * setWrittenFiles
+ * Stores the paths a callback wrote on the model values bus, one per line
+ * @param model {*utils.Model} - the model to store the paths on
+ * @param paths {[]string} - the written paths
+ * @return ...
+ **/
+func setWrittenFiles(model *utils.Model, paths []string) {
+	s := strings.Join(paths, "\n")
+	model.ModelValues.Map["writtenFiles"] = &s
+}
+
+/**
+ * readWrittenFiles
+ * Reads the paths a callback wrote back off the model values bus
+ * @param values {utils.Values} - the model values
+ * @return []string - the written paths
+ **/
+func readWrittenFiles(values utils.Values) []string {
+	if v, ok := values.Map["writtenFiles"]; ok && v != nil && *v != "" {
+		return strings.Split(*v, "\n")
+	}
+	return []string{}
+}
+
 /**
  * readForceValue
  * Reads the force flag back off the model values bus
@@ -0,0 +1,184 @@
+/*
+Copyright © 2025 Austin "Choice404" Choi
+See end of file for extended copyright information
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/choice404/botbox/v2/cmd/utils"
+	"github.com/spf13/cobra"
+)
+
+var i18nCmd = &cobra.Command{
+	Use:   "i18n",
+	Short: "Manage translations for the current Bot Box project",
+	Long: `Manage the translations of app command names, descriptions, and modal labels.
+
+Translations are kept in src/locales with one <locale>.json file per Discord
+locale, like src/locales/de.json. The translator in src/utils/translator.py
+serves them to Discord when the bot syncs its commands.`,
+}
+
+var i18nExtractCmd = &cobra.Command{
+	Use:   "extract",
+	Short: "Write missing translation keys into the locale files",
+	Long: `Write every translation key missing from the project's locale files.
+
+The keys cover the name and description of every slash, hybrid, modal, component,
+and context menu command, their groups and arguments, and the labels of modal
+fields, for example:
+  - commands.ticket.open.description
+  - commands.ticket.open.params.reason.name
+  - commands.feedback.fields.summary.label
+
+A missing key is filled from the localizations in botbox.conf, or left empty for
+a translator to fill in. Text already in the locale files is never overwritten.
+
+Locale files are written for every --locale given, every locale file that
+already exists, and every locale botbox.conf has translations for.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runI18nExtract(cmd)
+	},
+}
+
+// localeResult is one locale file in the JSON result of i18n extract
+type localeResult struct {
+	Locale    string `json:"locale"`
+	File      string `json:"file"`
+	KeysAdded int    `json:"keys_added"`
+}
+
+// i18nExtractResult is the JSON result of i18n extract, Translator is the translator.py path when it was written
+type i18nExtractResult struct {
+	Locales    []localeResult `json:"locales"`
+	Translator string         `json:"translator"`
+	Warnings   []string       `json:"warnings"`
+}
+
+/**
+ * runI18nExtract
+ * Adds the missing translation keys of every cog to the project's locale files
+ * @param cmd {*cobra.Command} - the command holding the flags
+ * @return ...
+ **/
+func runI18nExtract(cmd *cobra.Command) {
+	rootDir := requireProject()
+
+	config, err := utils.LoadConfig()
+	if err != nil {
+		exitWithError(exitError, err)
+	}
+	// The translator is written for discord.py's command tree
+	if library := utils.NormalizeLibrary(config.BotInfo.Library); library != utils.LibraryDiscordPy {
+		exitWithError(exitUsage, fmt.Errorf("translations are only generated for discord.py projects, this project uses %s", library))
+	}
+	generator, err := utils.GeneratorFor(config.BotInfo.Library)
+	if err != nil {
+		exitWithError(exitError, err)
+	}
+
+	locales, _ := cmd.Flags().GetStringSlice("locale")
+	for _, locale := range locales {
+		if err := utils.ValidateLocale(locale); err != nil {
+			exitWithError(exitUsage, err)
+		}
+	}
+
+	changes := &utils.ChangeSet{}
+	added, err := utils.UpdateLocaleFiles(changes, rootDir, config, locales, false)
+	if err != nil {
+		exitWithError(exitError, err)
+	}
+
+	if len(added) == 0 {
+		exitWithError(exitUsage, fmt.Errorf("no locales to extract, pass one with --locale like --locale de"))
+	}
+	if err := changes.Apply(); err != nil {
+		exitWithError(exitError, err)
+	}
+
+	// Projects created before translations existed get the translator too
+	translatorPath, err := utils.WriteTranslator(rootDir, config)
+	if err != nil {
+		exitWithError(exitError, err)
+	}
+	warning := ""
+	if translatorPath != "" {
+		mainPath := generator.MainFile()
+		mainFile, err := os.ReadFile(filepath.Join(rootDir, mainPath))
+		if err == nil && !strings.Contains(string(mainFile), "set_translator") {
+			warning = filepath.ToSlash(mainPath) + " does not install the translator, call self.tree.set_translator(LocaleTranslator(...)) from the bot's setup_hook"
+		}
+	}
+
+	if jsonOutput() {
+		result := i18nExtractResult{Locales: []localeResult{}, Warnings: []string{}, Translator: translatorPath}
+		if warning != "" {
+			result.Warnings = append(result.Warnings, warning)
+		}
+		for _, locale := range slices.Sorted(maps.Keys(added)) {
+			result.Locales = append(result.Locales, localeResult{Locale: locale, File: utils.LocaleFilePath(generator, rootDir, locale), KeysAdded: added[locale]})
+		}
+		printResult(result)
+		return
+	}
+
+	if translatorPath != "" {
+		fmt.Println(translatorPath)
+		if warning != "" {
+			fmt.Fprintln(os.Stderr, "Warning:", warning)
+		}
+	}
+
+	for _, locale := range slices.Sorted(maps.Keys(added)) {
+		fmt.Printf("%s: %d keys added\n", utils.LocaleFilePath(generator, rootDir, locale), added[locale])
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(i18nCmd)
+	i18nCmd.AddCommand(i18nExtractCmd)
+
+	i18nExtractCmd.Flags().StringSlice("locale", nil, "Discord locale to write a locale file for, like de or pt-BR (repeatable)")
+}
+
+/*
+Copyright © 2025 Austin "Choice404" Choi
+
+https://github.com/choice404/botbox
+
+Bot Box
+A discord bot template generator to help create discord bots quickly and easily
+
+This code is licensed under the MIT License.
+
+MIT License
+
+Copyright (c) 2025 Austin Choi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
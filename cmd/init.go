@@ -8,6 +8,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/choice404/botbox/v2/cmd/utils"
 	"github.com/spf13/cobra"
@@ -42,28 +43,38 @@ func runInitHeadless(cmd *cobra.Command, args []string) {
 
 	values, err := collectProjectValues(cmd, args)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error:", err)
-		os.Exit(1)
+		exitWithError(exitUsage, err)
 	}
 
 	model := utils.CreateModel(CreateProjectInitCallback)
 	applyModelValues(&model, values)
 	setForceValue(&model, force)
 
-	if utils.PrintErrors(utils.RunHeadless(model)) {
-		os.Exit(1)
-	}
+	exitOnErrors(exitError, utils.RunHeadless(model))
 
-	if cwd, err := os.Getwd(); err == nil {
-		fmt.Println(cwd)
+	cwd, err := os.Getwd()
+	if err != nil {
+		exitWithError(exitError, err)
 	}
+	if jsonOutput() {
+		// The project is written relative to the current directory
+		files := readWrittenFiles(model.ModelValues)
+		for i, file := range files {
+			files[i] = filepath.Join(cwd, file)
+		}
+		printResult(projectResult{RootDir: cwd, Files: files})
+		return
+	}
+	fmt.Println(cwd)
 }
 
 func CreateProjectInitCallback(model *utils.Model) []error {
 	values := model.ModelValues
 	// TODO: Update so the CreateProject function does the overwrite form using the custom Tea/Huh manager.
 	// Or maybe not after some testing, still gonna figure it out a bit
-	if err := utils.CreateProject("./", values, readForceValue(values)); err != nil {
+	written, err := utils.CreateProject("./", values, readForceValue(values))
+	setWrittenFiles(model, written)
+	if err != nil {
 		return []error{fmt.Errorf("error creating project: %w", err)}
 	}
 	return nil
@@ -37,6 +37,12 @@ issues or understanding available settings.`,
 	RunE: runConfigList,
 }
 
+// configListResult is the JSON result of config list, Scope is local or global
+type configListResult struct {
+	Scope  string         `json:"scope"`
+	Values map[string]any `json:"values"`
+}
+
 func runConfigList(cmd *cobra.Command, args []string) error {
 	if listGlobal {
 		return handleGlobalConfigList()
@@ -64,6 +70,15 @@ func handleGlobalConfigList() error {
 
 	sort.Strings(keys)
 
+	if jsonOutput() {
+		result := configListResult{Scope: "global", Values: map[string]any{}}
+		for _, key := range keys {
+			result.Values[key] = utils.GetGlobalConfigValue(key)
+		}
+		printResult(result)
+		return nil
+	}
+
 	fmt.Println("Global Configuration:")
 	for _, key := range keys {
 		value := utils.GetGlobalConfigValue(key)
@@ -80,11 +95,24 @@ func handleGlobalConfigList() error {
 func handleLocalConfigList() error {
 	_, err := utils.FindBotConf()
 	if err != nil {
-		return fmt.Errorf("not in a botbox project: %w", err)
+		return withExitCode(exitNotProject, errNotProject)
 	}
 
 	keys := []string{
-		"bot.name", "bot.description", "bot.command_prefix", "bot.author", "bot.help_style", "bot.env_provider",
+		"bot.name", "bot.description", "bot.command_prefix", "bot.author", "bot.help_style", "bot.library", "bot.env_provider",
+	}
+
+	if jsonOutput() {
+		result := configListResult{Scope: "local", Values: map[string]any{}}
+		for _, key := range keys {
+			value, err := utils.GetLocalConfigValue(key)
+			if err != nil {
+				return err
+			}
+			result.Values[key] = value
+		}
+		printResult(result)
+		return nil
 	}
 
 	fmt.Println("Local Configuration:")
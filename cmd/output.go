@@ -0,0 +1,306 @@
+/*
+Copyright © 2025 Austin "Choice404" Choi
+See end of file for extended copyright information
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/choice404/botbox/v2/cmd/utils"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes are part of the cli's interface, scripts match on them so they are never renumbered
+const (
+	exitOK         = 0
+	exitError      = 1 // anything else that went wrong, like a failed write or a callback error
+	exitUsage      = 2 // an invalid flag, argument, or value
+	exitNotProject = 3 // the command needs a botbox project and the current directory is not in one
+	exitCogErrors  = 4 // a cog file has errors that stop the command, the diagnostics say where
+	exitDrift      = 5 // config sync --check found botbox.conf out of sync with the cog files
+)
+
+// errorCodes names each exit code in the errors of a JSON document
+var errorCodes = map[int]string{
+	exitError:      "error",
+	exitUsage:      "usage",
+	exitNotProject: "not-project",
+	exitCogErrors:  "cog-errors",
+	exitDrift:      "drift",
+}
+
+// jsonCommands are the commands that print a JSON document with --output json
+var jsonCommands = map[string]bool{
+	"create":          true,
+	"init":            true,
+	"add":             true,
+	"edit":            true,
+	"remove":          true,
+	"config":          true,
+	"config get":      true,
+	"config set":      true,
+	"config list":     true,
+	"config sync":     true,
+	"docker init":     true,
+	"backup list":     true,
+	"restore":         true,
+	"i18n extract":    true,
+	"project upgrade": true,
+	"plan":            true,
+	"apply":           true,
+	"template list":   true,
+	"template eject":  true,
+}
+
+var (
+	// outputFormat is text or json, read from the --output flag before a command runs
+	outputFormat = "text"
+	// commandName is the running command without the botbox prefix, like config sync. It stays empty
+	// until cobra accepted the command's flags and arguments
+	commandName string
+)
+
+var errNotProject = errors.New("current directory is not in a botbox project")
+
+// outputError is one error of a JSON document, errors from cog files keep their file and position
+type outputError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+}
+
+// outputDocument is the single document a command prints with --output json. Result is null when the
+// command failed before it had one, and Errors is empty when it succeeded
+type outputDocument struct {
+	Command  string        `json:"command"`
+	OK       bool          `json:"ok"`
+	ExitCode int           `json:"exit_code"`
+	Result   any           `json:"result"`
+	Errors   []outputError `json:"errors"`
+}
+
+// filesResult is the JSON result of commands that only write files, like docker init
+type filesResult struct {
+	Files []string `json:"files"`
+}
+
+// codedError carries the exit code a RunE command fails with back to Execute
+type codedError struct {
+	code int
+	err  error
+}
+
+func (e *codedError) Error() string { return e.err.Error() }
+
+func (e *codedError) Unwrap() error { return e.err }
+
+// withExitCode tags an error returned from RunE with the exit code the command ends with
+func withExitCode(code int, err error) error {
+	return &codedError{code: code, err: err}
+}
+
+// requestsJSONOutput reports whether raw command line arguments ask for --output json
+func requestsJSONOutput(args []string) bool {
+	for i, arg := range args {
+		switch {
+		case arg == "--":
+			return false
+		case arg == "--output=json" || arg == "-o=json" || arg == "-ojson":
+			return true
+		case (arg == "--output" || arg == "-o") && i+1 < len(args) && args[i+1] == "json":
+			return true
+		}
+	}
+	return false
+}
+
+// jsonOutput reports whether the command prints a JSON document instead of text
+func jsonOutput() bool {
+	return outputFormat == "json"
+}
+
+/**
+ * applyOutputFlag
+ * Reads the --output flag and records the running command, ending it when the format is unknown or unsupported
+ * @param cmd {*cobra.Command} - the command about to run
+ * @return ...
+ **/
+func applyOutputFlag(cmd *cobra.Command) {
+	commandName = strings.TrimPrefix(strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()), " ")
+
+	format, _ := cmd.Flags().GetString("output")
+	switch format {
+	case "text":
+	case "json":
+		outputFormat = format
+		// A JSON document is never mixed with prompts or a usage dump
+		utils.HeadlessMode = true
+		cmd.SilenceUsage = true
+		if !jsonCommands[commandName] {
+			exitWithError(exitUsage, fmt.Errorf("botbox %s does not support --output json", commandName))
+		}
+	default:
+		exitWithError(exitUsage, fmt.Errorf("invalid output format %q, use text or json", format))
+	}
+}
+
+// printJSONDocument writes a document to stdout, characters like < and & stay as written
+func printJSONDocument(document outputDocument) {
+	if document.Errors == nil {
+		document.Errors = []outputError{}
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(exitError)
+	}
+}
+
+/**
+ * printResult
+ * Prints the JSON document of a command that succeeded
+ * @param result {any} - the command's result
+ * @return ...
+ **/
+func printResult(result any) {
+	printJSONDocument(outputDocument{Command: commandName, OK: true, ExitCode: exitOK, Result: result})
+}
+
+/**
+ * exitWithResult
+ * Ends a command that failed after it got a result, like a sync that found errors in the cog files.
+ * JSON output prints the result with the errors, text output has already reported them
+ * @param code {int} - the exit code
+ * @param result {any} - the command's result
+ * @param errs {[]outputError} - why the command failed
+ * @return ...
+ **/
+func exitWithResult(code int, result any, errs []outputError) {
+	if jsonOutput() {
+		printJSONDocument(outputDocument{Command: commandName, OK: false, ExitCode: code, Result: result, Errors: errs})
+	}
+	os.Exit(code)
+}
+
+/**
+ * exitWithError
+ * Ends the command with an exit code, printing each error as Error: ... or as the JSON document
+ * @param code {int} - the exit code
+ * @param errs {...error} - the errors to report, nil entries are skipped
+ * @return ...
+ **/
+func exitWithError(code int, errs ...error) {
+	var outputErrors []outputError
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if !jsonOutput() {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+		}
+		outputErrors = append(outputErrors, outputError{Code: errorCodes[code], Message: err.Error()})
+	}
+	exitWithResult(code, nil, outputErrors)
+}
+
+// exitOnErrors ends the command with code when errs holds any error, as returned by utils.RunHeadless
+func exitOnErrors(code int, errs []error) {
+	for _, err := range errs {
+		if err != nil {
+			exitWithError(code, errs...)
+		}
+	}
+}
+
+// diagnosticErrors turns the error diagnostics of a sync into the errors of a JSON document
+func diagnosticErrors(diagnostics []utils.Diagnostic) []outputError {
+	var outputErrors []outputError
+	for _, diagnostic := range diagnostics {
+		outputErrors = append(outputErrors, outputError{
+			Code:    diagnostic.Code,
+			Message: diagnostic.Message,
+			File:    diagnostic.File,
+			Line:    diagnostic.Line,
+			Column:  diagnostic.Column,
+		})
+	}
+	return outputErrors
+}
+
+// requireProject returns the root of the project the command runs in, ending the command outside of one
+func requireProject() string {
+	rootDir, err := utils.FindBotConf()
+	if err != nil {
+		exitWithError(exitNotProject, errNotProject)
+	}
+	return rootDir
+}
+
+// changedFiles lists the files a change set writes or removes, in the order they were staged
+func changedFiles(changes *utils.ChangeSet) []string {
+	files := []string{}
+	for _, change := range changes.Changes() {
+		files = append(files, change.Path)
+	}
+	return files
+}
+
+// stagedConfig reads the botbox.conf a change set stages, or the one on disk when it is not staged
+func stagedConfig(changes *utils.ChangeSet, rootDir string) (utils.Config, error) {
+	configPath := filepath.Join(rootDir, "botbox.conf")
+	for _, change := range changes.Changes() {
+		if change.Path != configPath || change.Remove {
+			continue
+		}
+		var config utils.Config
+		if err := json.Unmarshal(change.After, &config); err != nil {
+			return config, fmt.Errorf("failed to parse staged botbox.conf: %w", err)
+		}
+		return config, nil
+	}
+	return utils.LoadConfig()
+}
+
+/*
+Copyright © 2025 Austin "Choice404" Choi
+
+https://github.com/choice404/botbox
+
+Bot Box
+A discord bot template generator to help create discord bots quickly and easily
+
+This code is licensed under the MIT License.
+
+MIT License
+
+Copyright (c) 2025 Austin Choi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
@@ -0,0 +1,85 @@
+/*
+Copyright © 2025 Austin "Choice404" Choi
+See end of file for extended copyright information
+*/
+
+package cmd
+
+import (
+	"testing"
+)
+
+func TestRequestsJSONOutput(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"config", "sync", "-o", "json"}, true},
+		{[]string{"config", "sync", "--output", "json"}, true},
+		{[]string{"config", "sync", "--output=json"}, true},
+		{[]string{"config", "sync", "-ojson"}, true},
+		{[]string{"config", "sync", "--output", "text"}, false},
+		{[]string{"config", "sync", "-o"}, false},
+		{[]string{"run", "--", "-o", "json"}, false},
+		{[]string{"config", "sync"}, false},
+	}
+
+	for _, test := range tests {
+		if got := requestsJSONOutput(test.args); got != test.want {
+			t.Errorf("requestsJSONOutput(%q) = %v, want %v", test.args, got, test.want)
+		}
+	}
+}
+
+func TestExitCodesAreStable(t *testing.T) {
+	// Scripts match on these numbers and names, changing one breaks them
+	want := map[int]string{
+		1: "error",
+		2: "usage",
+		3: "not-project",
+		4: "cog-errors",
+		5: "drift",
+	}
+
+	if len(errorCodes) != len(want) {
+		t.Fatalf("expected %d error codes, got %d", len(want), len(errorCodes))
+	}
+	for code, name := range want {
+		if errorCodes[code] != name {
+			t.Errorf("exit code %d is named %q, want %q", code, errorCodes[code], name)
+		}
+	}
+}
+
+/*
+Copyright © 2025 Austin "Choice404" Choi
+
+https://github.com/choice404/botbox
+
+Bot Box
+A discord bot template generator to help create discord bots quickly and easily
+
+This code is licensed under the MIT License.
+
+MIT License
+
+Copyright (c) 2025 Austin Choi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
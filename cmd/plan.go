@@ -0,0 +1,168 @@
+/*
+Copyright © 2025 Austin "Choice404" Choi
+See end of file for extended copyright information
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/choice404/botbox/v2/cmd/utils"
+	"github.com/spf13/cobra"
+)
+
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Show what applying a bot spec would change",
+	Long: `Compare a bot spec with the current Bot Box project without writing anything.
+
+A bot spec describes the bot info and every cog with its commands, listeners,
+and tasks in YAML, using the same keys as botbox.conf:
+
+  bot:
+    name: Ticketer
+    description: Opens support tickets
+  cogs:
+    - name: Tickets
+      slash_commands:
+        - Name: open
+          Type: slash
+          Description: Opens a ticket
+
+Cogs are matched by name. The plan lists the cogs botbox apply would add,
+regenerate, or remove, every command that differs, and the bot fields it would
+change. Use botbox apply --dry-run to see the file diffs.
+
+The admin, cog management, and help cogs botbox creates are never regenerated
+or removed by a spec, and the library can only be chosen by botbox create.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runPlan(cmd)
+	},
+}
+
+// planResult is the JSON result of plan
+type planResult struct {
+	utils.SpecPlan
+	InSync bool `json:"in_sync"`
+}
+
+/**
+ * loadSpecPlan
+ * Reads the spec named by the --file flag and plans it against the project the command runs inside
+ * @param cmd {*cobra.Command} - the command holding the flags
+ * @return string - the project root
+ * @return utils.SpecPlan - what applying the spec changes
+ **/
+func loadSpecPlan(cmd *cobra.Command) (string, utils.SpecPlan) {
+	rootDir := requireProject()
+
+	specPath, _ := cmd.Flags().GetString("file")
+	if specPath == "" {
+		exitWithError(exitUsage, fmt.Errorf("a spec file is required, pass one with -f bot.yaml"))
+	}
+	spec, err := utils.LoadSpec(specPath)
+	if err != nil {
+		exitWithError(exitUsage, err)
+	}
+
+	config, err := utils.LoadConfig()
+	if err != nil {
+		exitWithError(exitError, err)
+	}
+
+	plan, err := utils.PlanSpec(config, spec)
+	if err != nil {
+		exitWithError(exitUsage, err)
+	}
+	return rootDir, plan
+}
+
+/**
+ * printPlan
+ * Prints the bot fields and cogs a plan changes, and every command that differs
+ * @param plan {utils.SpecPlan} - the plan to print
+ * @param specPath {string} - the spec file, named when nothing changes
+ * @return ...
+ **/
+func printPlan(plan utils.SpecPlan, specPath string) {
+	if len(plan.SkippedCogs) > 0 {
+		fmt.Println("built-in cogs left as they are:", strings.Join(plan.SkippedCogs, ", "))
+	}
+	if plan.Empty() {
+		fmt.Printf("no changes, the project matches %s\n", filepath.Base(specPath))
+		return
+	}
+	if len(plan.BotChanges) > 0 {
+		fmt.Println("bot info to change:", strings.Join(plan.BotChanges, ", "))
+	}
+	if len(plan.AddedCogs) > 0 {
+		fmt.Println("cogs to add:", strings.Join(plan.AddedCogs, ", "))
+	}
+	if len(plan.UpdatedCogs) > 0 {
+		fmt.Println("cogs to update:", strings.Join(plan.UpdatedCogs, ", "))
+	}
+	if len(plan.RemovedCogs) > 0 {
+		fmt.Println("cogs to remove:", strings.Join(plan.RemovedCogs, ", "))
+	}
+	for _, change := range plan.CommandChanges {
+		fmt.Println("  " + change)
+	}
+}
+
+/**
+ * runPlan
+ * Prints what applying the spec named by the --file flag would change in the project
+ * @param cmd {*cobra.Command} - the command holding the flags
+ * @return ...
+ **/
+func runPlan(cmd *cobra.Command) {
+	_, plan := loadSpecPlan(cmd)
+
+	if jsonOutput() {
+		printResult(planResult{SpecPlan: plan, InSync: plan.Empty()})
+		return
+	}
+	specPath, _ := cmd.Flags().GetString("file")
+	printPlan(plan, specPath)
+}
+
+func init() {
+	rootCmd.AddCommand(planCmd)
+	planCmd.Flags().StringP("file", "f", "", "Bot spec to compare the project with, like bot.yaml")
+}
+
+/*
+Copyright © 2025 Austin "Choice404" Choi
+
+https://github.com/choice404/botbox
+
+Bot Box
+A discord bot template generator to help create discord bots quickly and easily
+
+This code is licensed under the MIT License.
+
+MIT License
+
+Copyright (c) 2025 Austin Choi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
@@ -39,16 +39,32 @@ with the latest Bot Box features and command structures.`,
 func runConfigUpgrade(cmd *cobra.Command, args []string) error {
 	_, err := utils.FindBotConf()
 	if err != nil {
-		return fmt.Errorf("not in a botbox project: %w", err)
+		return withExitCode(exitNotProject, errNotProject)
 	}
 
-	fmt.Println("🔍 Analyzing botbox.conf for upgrade...")
+	if !jsonOutput() {
+		fmt.Println("🔍 Analyzing botbox.conf for upgrade...")
+	}
 
 	result, err := utils.UpgradeConfig()
 	if err != nil {
 		return fmt.Errorf("upgrade failed: %w", err)
 	}
 
+	if jsonOutput() {
+		if result.Errors == nil {
+			result.Errors = []string{}
+		}
+		if result.UpgradedCogs == nil {
+			result.UpgradedCogs = []string{}
+		}
+		if !result.Success && !result.AlreadyUpgraded {
+			exitWithResult(exitError, result, []outputError{{Code: errorCodes[exitError], Message: "upgrade completed with errors"}})
+		}
+		printResult(result)
+		return nil
+	}
+
 	if result.AlreadyUpgraded {
 		fmt.Printf("✅ %s\n", result.Message)
 		return nil
@@ -58,7 +74,7 @@ func runConfigUpgrade(cmd *cobra.Command, args []string) error {
 		fmt.Printf("✅ %s\n", result.Message)
 
 		if result.BackupCreated {
-			fmt.Printf("💾 Backup created: %s\n", result.BackupPath)
+			fmt.Printf("💾 Backup created: %s, restore it with 'botbox restore %s'\n", result.BackupID, result.BackupID)
 		}
 
 		if len(result.UpgradedCogs) > 0 {
@@ -6,7 +6,6 @@ See end of file for extended copyright information
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -19,6 +18,10 @@ import (
 var (
 	removeCogName string
 	cogRemove     utils.CogConfig
+	// removedPath is the file of the removed cog, where the project's generator keeps it
+	removedPath string
+	// removeBackupID names the backup of the removed cog file and botbox.conf
+	removeBackupID string
 )
 
 var removeCmd = &cobra.Command{
@@ -32,14 +35,13 @@ This command will:
   - Update the botbox.conf configuration to remove the cog entry
   - Maintain project consistency by cleaning up all references
 
+The cog file and botbox.conf are backed up to .botbox/backups first, so
+botbox restore can bring the cog back.
+
 You can specify the cog name as an argument or select from an interactive list. 
 The command ensures safe removal without breaking your project configuration.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		_, err := utils.FindBotConf()
-		if err != nil {
-			fmt.Fprintln(os.Stderr, "Current directory is not in a botbox project.")
-			return
-		}
+		requireProject()
 
 		if len(args) > 0 {
 			removeCogName = args[0]
@@ -55,24 +57,52 @@ The command ensures safe removal without breaking your project configuration.`,
 
 		model := utils.RemoveModel(removeCallback, removeInitCallback)
 		utils.CupSleeve(model)
+		if model.Changes.Applied() {
+			printRemoveBackup()
+		}
 	},
 }
 
+// removeResult is the JSON result of remove, Files lists the removed cog file and the rewritten botbox.conf,
+// and Backup is the backup taken of them
+type removeResult struct {
+	Cog    utils.CogConfig `json:"cog"`
+	Files  []string        `json:"files"`
+	Backup string          `json:"backup"`
+}
+
 func runRemoveHeadless(args []string) {
 	if len(args) == 0 {
-		fmt.Fprintln(os.Stderr, "Error: a cog name is required when running without the TUI")
-		os.Exit(1)
+		exitWithError(exitUsage, fmt.Errorf("a cog name is required when running without the TUI"))
 	}
 
 	model := utils.RemoveModel(removeCallback, removeInitCallback)
 
-	if utils.PrintErrors(utils.RunHeadless(model)) {
-		os.Exit(1)
+	exitOnErrors(exitError, utils.RunHeadless(model))
+
+	if jsonOutput() {
+		rootDir := requireProject()
+		printResult(removeResult{
+			Cog: cogRemove,
+			Files: []string{
+				removedPath,
+				filepath.Join(rootDir, "botbox.conf"),
+			},
+			Backup: removeBackupID,
+		})
+		return
 	}
-
+	printRemoveBackup()
 	fmt.Println(removeCogName)
 }
 
+// printRemoveBackup tells on stderr how to bring the removed cog back
+func printRemoveBackup() {
+	if removeBackupID != "" {
+		fmt.Fprintf(os.Stderr, "Backup created: %s, restore it with 'botbox restore %s'\n", removeBackupID, removeBackupID)
+	}
+}
+
 func removeCallback(model *utils.Model) []error {
 	var errors []error
 	values := model.ModelValues
@@ -106,20 +136,23 @@ func removeCallback(model *utils.Model) []error {
 		return errors
 	}
 
-	jsonData, err := json.MarshalIndent(config, "", "  ")
+	removedPath, err = utils.CogFilePath(rootDir, config.BotInfo.Library, cogRemove.File)
 	if err != nil {
-		errors = append(errors, fmt.Errorf("error marshalling config to JSON: %w", err))
+		errors = append(errors, err)
 		return errors
 	}
 
-	err = os.Remove(filepath.Join(rootDir, "src", "cogs", cogRemove.File+".py"))
-	if err != nil && !os.IsNotExist(err) {
+	// Both files are backed up in one backup so botbox restore brings the cog back with its config entry
+	removeBackupID, err = utils.StageBackup(model.Changes, rootDir, "remove "+cogRemove.Name, removedPath, configPath)
+	if err != nil {
+		errors = append(errors, fmt.Errorf("failed to back up project files: %w", err))
+		return errors
+	}
+	if err := model.Changes.RemoveFile(removedPath); err != nil {
 		errors = append(errors, fmt.Errorf("error removing cog file: %w", err))
 		return errors
 	}
-
-	err = os.WriteFile(configPath, jsonData, 0644)
-	if err != nil {
+	if err := utils.StageConfig(model.Changes, rootDir, config); err != nil {
 		errors = append(errors, fmt.Errorf("failed to write updated botbox.conf: %w", err))
 		return errors
 	}
@@ -0,0 +1,84 @@
+/*
+Copyright © 2025 Austin "Choice404" Choi
+See end of file for extended copyright information
+*/
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/choice404/botbox/v2/cmd/utils"
+)
+
+func TestRemoveHeadlessCanBeRestored(t *testing.T) {
+	project := setupEditProject(t)
+	cog := loadEditedCog(t)
+	cogFile := readCogFile(t, project)
+
+	removeCogName = cog.Name
+	t.Cleanup(func() {
+		removeCogName = ""
+		removedPath = ""
+		removeBackupID = ""
+	})
+	if errs := utils.RunHeadless(utils.RemoveModel(removeCallback, removeInitCallback)); len(errs) > 0 {
+		t.Fatalf("remove callbacks failed: %v", errs)
+	}
+
+	if _, err := os.Stat(filepath.Join(project, "src", "cogs", "greetings.py")); !os.IsNotExist(err) {
+		t.Error("the removed cog file should be deleted")
+	}
+	backups := listTestBackups(t, project)
+	if removeBackupID == "" || len(backups) == 0 || backups[0].ID != removeBackupID {
+		t.Fatalf("expected a backup of the removed cog, got %q and %+v", removeBackupID, backups)
+	}
+
+	// Restoring the backup brings the cog file back with its config entry
+	changes := &utils.ChangeSet{}
+	if _, _, err := utils.StageRestore(changes, project, removeBackupID); err != nil {
+		t.Fatalf("StageRestore returned error: %v", err)
+	}
+	if err := changes.Apply(); err != nil {
+		t.Fatalf("failed to apply the restore: %v", err)
+	}
+	if restored := readCogFile(t, project); restored != cogFile {
+		t.Error("the restored cog file should match the removed one")
+	}
+	if restored := loadEditedCog(t); restored.Name != cog.Name {
+		t.Errorf("restored cog = %q, want %q", restored.Name, cog.Name)
+	}
+}
+
+/*
+Copyright © 2025 Austin "Choice404" Choi
+
+Bot Box
+A discord bot template generator to help create discord bots quickly and easily
+
+This code is licensed under the MIT License.
+
+MIT License
+
+Copyright (c) 2025 Austin Choi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
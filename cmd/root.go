@@ -6,8 +6,10 @@ See end of file for extended copyright information
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/choice404/botbox/v2/cmd/utils"
 	"github.com/spf13/cobra"
@@ -27,7 +29,10 @@ your Discord bot development workflow.
 
 Built with a cog-based architecture for modularity and featuring automatic updates, 
 global configuration management, and seamless project upgrades.`,
+	// Errors are printed by Execute so they follow --output and end with a stable exit code
+	SilenceErrors: true,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		applyOutputFlag(cmd)
 		if headless, err := cmd.Flags().GetBool("headless"); err == nil && headless {
 			utils.HeadlessMode = true
 		}
@@ -46,9 +51,25 @@ func Execute(version string) {
 		fmt.Fprintf(os.Stderr, "⚠️  Warning: failed to sync version: %v\n", err)
 	}
 
-	err := rootCmd.Execute()
+	// A bad flag stops parsing before --output is read, so the raw arguments decide how that error is printed
+	if requestsJSONOutput(os.Args[1:]) {
+		outputFormat = "json"
+		rootCmd.SilenceUsage = true
+	}
+
+	cmd, err := rootCmd.ExecuteC()
 	if err != nil {
-		os.Exit(1)
+		code := exitError
+		var coded *codedError
+		switch {
+		case errors.As(err, &coded):
+			code = coded.code
+		// Flags and arguments are checked before PersistentPreRun, so nothing recorded the command yet
+		case commandName == "":
+			code = exitUsage
+			commandName = strings.TrimPrefix(strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()), " ")
+		}
+		exitWithError(code, err)
 	}
 }
 
@@ -81,24 +102,25 @@ func checkForUpdatesIfEnabled() {
 
 func init() {
 	rootCmd.PersistentFlags().Bool("headless", false, "Run without the interactive TUI")
+	rootCmd.PersistentFlags().StringP("output", "o", "text", "Output format: text, or json to print one JSON document and imply --headless")
 
 	exists, err := utils.GlobalConfigExists()
 	if err != nil {
-		fmt.Printf("Error checking config: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error checking config: %v\n", err)
 		os.Exit(1)
 	}
 
 	if !exists {
-		fmt.Println("Config file does not exist. Creating...")
+		fmt.Fprintln(os.Stderr, "Config file does not exist. Creating...")
 		if err := utils.CreateGlobalConfig(); err != nil {
-			fmt.Printf("Error creating config: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error creating config: %v\n", err)
 			os.Exit(1)
 		}
 	}
 
 	GlobalConfig, err = utils.LoadGlobalConfig()
 	if err != nil {
-		fmt.Printf("Error loading config: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 		os.Exit(1)
 	}
 }
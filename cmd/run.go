@@ -10,7 +10,6 @@ import (
 	"os/exec"
 	"path/filepath"
 
-	"github.com/choice404/botbox/v2/cmd/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -19,19 +18,14 @@ var runCmd = &cobra.Command{
 	Short: "Run the bot",
 	Long:  `Run the bot`,
 	Run: func(cmd *cobra.Command, args []string) {
-		rootDir, err := utils.FindBotConf()
-		if err != nil {
-			fmt.Println("Error finding root directory:", err)
-			return
-		}
+		rootDir := requireProject()
 
 		runCmd := exec.Command("bash", filepath.Join(rootDir, "run.sh"))
 
 		output, err := runCmd.CombinedOutput()
 		if err != nil {
-			fmt.Println("Error running the bot:", err)
 			fmt.Println("Output:", string(output))
-			return
+			exitWithError(exitError, fmt.Errorf("error running the bot: %w", err))
 		}
 
 		fmt.Println("Bot is running...")
@@ -63,18 +63,22 @@ func handleGlobalConfigSet(key, valueStr string) error {
 	}
 
 	if !isValidGlobalConfigKey(key) {
-		return fmt.Errorf("invalid global config key: %s", key)
+		return withExitCode(exitUsage, fmt.Errorf("invalid global config key: %s", key))
 	}
 
 	value, err := parseValue(valueStr, key)
 	if err != nil {
-		return fmt.Errorf("invalid value for key %s: %w", key, err)
+		return withExitCode(exitUsage, fmt.Errorf("invalid value for key %s: %w", key, err))
 	}
 
 	if err := utils.SetGlobalConfigValue(key, value); err != nil {
 		return fmt.Errorf("failed to set global config value: %w", err)
 	}
 
+	if jsonOutput() {
+		printResult(configValueResult{Scope: "global", Key: key, Value: value})
+		return nil
+	}
 	fmt.Printf("✅ Set global %s = %v\n", key, value)
 	return nil
 }
@@ -82,17 +86,25 @@ func handleGlobalConfigSet(key, valueStr string) error {
 func handleLocalConfigSet(key, valueStr string) error {
 	_, err := utils.FindBotConf()
 	if err != nil {
-		return fmt.Errorf("not in a botbox project: %w", err)
+		return withExitCode(exitNotProject, errNotProject)
 	}
 
+	// Every generated file is written for the library, so it is only chosen by botbox create
+	if key == "bot.library" {
+		return withExitCode(exitUsage, fmt.Errorf("bot.library is chosen when the project is created and cannot be changed"))
+	}
 	if !isValidLocalConfigKey(key) {
-		return fmt.Errorf("invalid local config key: %s", key)
+		return withExitCode(exitUsage, fmt.Errorf("invalid local config key: %s", key))
 	}
 
 	if err := utils.SetLocalConfigValue(key, valueStr); err != nil {
 		return fmt.Errorf("failed to set local config value: %w", err)
 	}
 
+	if jsonOutput() {
+		printResult(configValueResult{Scope: "local", Key: key, Value: valueStr})
+		return nil
+	}
 	fmt.Printf("✅ Set local %s = %s\n", key, valueStr)
 	return nil
 }
@@ -27,29 +27,86 @@ This command analyzes your src/cogs/ directory and updates the configuration to:
 
 Use this command when you've manually added/removed cogs or when the 
 configuration seems out of sync with your actual project structure. 
-It ensures your bot will load all available cogs correctly.`,
+It ensures your bot will load all available cogs correctly.
+
+Use --check in CI or a pre-commit hook to fail when botbox.conf and the cog
+files have drifted apart. It compares them without writing anything, lists
+every command that differs, and exits with status 5 when a sync would change
+botbox.conf.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		if check, _ := cmd.Flags().GetBool("check"); check {
+			runSyncCheck(dryRun)
+			return
+		}
 		if isHeadless(cmd, nil) {
-			runSyncHeadless()
+			runSyncHeadless(dryRun)
 			return
 		}
 		model := utils.ConfigSyncModel(configCallback, configSyncInitCallback)
+		model.DryRun = dryRun
 		utils.CupSleeve(model)
+		if model.DryRun {
+			printDryRun(model.Changes)
+		}
 	},
 }
 
-func runSyncHeadless() {
-	result, err := utils.SyncCogsWithConfig()
+// syncResult is the JSON result of config sync, InSync is true when botbox.conf already matched the cog files
+type syncResult struct {
+	*utils.SyncResult
+	InSync bool   `json:"in_sync"`
+	DryRun bool   `json:"dry_run"`
+	Diff   string `json:"diff"`
+}
+
+/**
+ * stageSync
+ * Stages the sync and reports the problems found in the cog files, ending the command when any of them is an error
+ * @param changes {*utils.ChangeSet} - where botbox.conf is staged
+ * @param dryRun {bool} - whether the sync is only previewed
+ * @return syncResult - the staged sync
+ **/
+func stageSync(changes *utils.ChangeSet, dryRun bool) syncResult {
+	rootDir := requireProject()
+	result, err := utils.StageCogSync(changes)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error:", err)
-		os.Exit(1)
+		exitWithError(exitError, err)
 	}
 
-	if len(result.Errors) > 0 {
-		for _, syncErr := range result.Errors {
-			fmt.Fprintln(os.Stderr, "sync error:", syncErr)
+	output := syncResult{
+		SyncResult: result,
+		InSync:     len(result.AddedCogs) == 0 && len(result.UpdatedCogs) == 0 && len(result.RemovedCogs) == 0,
+		DryRun:     dryRun,
+	}
+	if dryRun {
+		output.Diff = changes.Diff(rootDir)
+	}
+
+	if !jsonOutput() {
+		for _, diagnostic := range result.Diagnostics {
+			fmt.Fprintln(os.Stderr, diagnostic)
 		}
-		os.Exit(1)
+	}
+	if len(result.Errors()) > 0 {
+		exitWithResult(exitCogErrors, output, diagnosticErrors(result.Errors()))
+	}
+	return output
+}
+
+func runSyncHeadless(dryRun bool) {
+	changes := &utils.ChangeSet{}
+	output := stageSync(changes, dryRun)
+	result := output.SyncResult
+
+	if !dryRun {
+		if err := changes.Apply(); err != nil {
+			exitWithError(exitError, err)
+		}
+	}
+	if jsonOutput() {
+		printResult(output)
+		return
 	}
 
 	if len(result.AddedCogs) > 0 {
@@ -61,12 +118,64 @@ func runSyncHeadless() {
 	if len(result.RemovedCogs) > 0 {
 		fmt.Println("removed:", strings.Join(result.RemovedCogs, ", "))
 	}
+	for _, change := range result.CommandChanges {
+		fmt.Println("  " + change)
+	}
 	if len(result.HeaderIssues) > 0 {
 		fmt.Fprintln(os.Stderr, "header issues:", strings.Join(result.HeaderIssues, ", "))
 	}
-	if len(result.AddedCogs) == 0 && len(result.UpdatedCogs) == 0 && len(result.RemovedCogs) == 0 {
+	if output.InSync {
 		fmt.Println("no changes")
 	}
+
+	if dryRun {
+		printDryRun(changes)
+	}
+}
+
+/**
+ * runSyncCheck
+ * Compares the cog files with botbox.conf without writing and ends the command with the drift exit code when they differ
+ * @param dryRun {bool} - also print the diff a sync would make
+ * @return ...
+ **/
+func runSyncCheck(dryRun bool) {
+	changes := &utils.ChangeSet{}
+	output := stageSync(changes, dryRun)
+	result := output.SyncResult
+
+	if output.InSync {
+		if jsonOutput() {
+			printResult(output)
+			return
+		}
+		fmt.Println("botbox.conf is in sync with the cog files")
+		return
+	}
+
+	drift := "botbox.conf is out of sync with the cog files, run botbox config sync to update it"
+	if jsonOutput() {
+		exitWithResult(exitDrift, output, []outputError{{Code: errorCodes[exitDrift], Message: drift}})
+	}
+
+	if len(result.AddedCogs) > 0 {
+		fmt.Println("cogs to add:", strings.Join(result.AddedCogs, ", "))
+	}
+	if len(result.UpdatedCogs) > 0 {
+		fmt.Println("cogs to update:", strings.Join(result.UpdatedCogs, ", "))
+	}
+	if len(result.RemovedCogs) > 0 {
+		fmt.Println("cogs to remove:", strings.Join(result.RemovedCogs, ", "))
+	}
+	for _, change := range result.CommandChanges {
+		fmt.Println("  " + change)
+	}
+	if dryRun {
+		printDryRun(changes)
+	}
+
+	fmt.Fprintln(os.Stderr, drift)
+	os.Exit(exitDrift)
 }
 
 func configSyncInitCallback(model *utils.Model, allFormsModels []utils.Values) {
@@ -77,7 +186,8 @@ func configSyncInitCallback(model *utils.Model, allFormsModels []utils.Values) {
 		return
 	}
 
-	result, err := utils.SyncCogsWithConfig()
+	// The sync is only staged here, the review screen writes it
+	result, err := utils.StageCogSync(model.Changes)
 	if err != nil {
 		errors := []error{fmt.Errorf("failed to sync cogs with config: %w", err)}
 		model.HandleError(errors)
@@ -111,15 +221,21 @@ func configSyncInitCallback(model *utils.Model, allFormsModels []utils.Values) {
 		*modelValues.Map["headerIssues"] = headerIssues
 	}
 
-	if len(result.Errors) > 0 {
+	if len(result.Errors()) > 0 {
 		var errors []error
-		for _, err := range result.Errors {
-			errors = append(errors, fmt.Errorf("sync error: %s", err))
+		for _, diagnostic := range result.Errors() {
+			errors = append(errors, fmt.Errorf("%s", diagnostic))
 		}
 		model.HandleError(errors)
 		return
 	}
 
+	var warnings []string
+	for _, diagnostic := range result.Warnings() {
+		warnings = append(warnings, diagnostic.String())
+	}
+	*modelValues.Map["diagnostics"] = strings.Join(warnings, "\n")
+
 	if len(result.AddedCogs) == 0 && len(result.UpdatedCogs) == 0 && len(result.RemovedCogs) == 0 {
 		*modelValues.Map["noChanges"] = "No changes detected in cogs."
 	}
@@ -127,6 +243,8 @@ func configSyncInitCallback(model *utils.Model, allFormsModels []utils.Values) {
 
 func init() {
 	configCmd.AddCommand(syncCmd)
+	syncCmd.Flags().Bool("dry-run", false, "Print a unified diff of botbox.conf without writing it")
+	syncCmd.Flags().Bool("check", false, "Exit with status 5 when botbox.conf differs from the cog files, without writing it")
 }
 
 /*
@@ -0,0 +1,228 @@
+/*
+Copyright © 2025 Austin "Choice404" Choi
+See end of file for extended copyright information
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/choice404/botbox/v2/cmd/utils"
+	"github.com/spf13/cobra"
+)
+
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Manage the project templates botbox create starts from",
+	Long: `Manage the project templates botbox create and botbox init start from.
+
+The built in templates are default, minimal, moderation, utility, and
+slash-only. User templates are directories in ~/.config/botbox/templates
+holding a template.json manifest, for example ~/.config/botbox/templates/tickets:
+
+  {
+    "description": "Support ticket bot",
+    "extends": "minimal",
+    "files": [
+      {"path": "src/cogs/tickets.py", "template": "tickets.py.tmpl", "executable": true}
+    ],
+    "variables": [
+      {"name": "channel", "prompt": "Ticket channel name", "default": "tickets"}
+    ]
+  }
+
+A user template writes the files of the built in template it extends, default
+when extends is left out, with its own files added or replacing the file at
+the same path. Its files are rendered with the project values between << and >>,
+like <<.Name>> for the bot name and <<.Vars.channel>> for a variable. Cog files
+under src/cogs are read into botbox.conf like config sync reads them.
+
+Any embedded template, like cog.py.tmpl for generated cogs, can be replaced by a
+file of the same name in the project's .botbox/templates directory or in
+~/.config/botbox/templates. The project's override wins, and an override is
+checked against the values its template is rendered with before it is used. Use
+botbox template eject to start from a copy of the embedded template.`,
+}
+
+var templateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the built in and user project templates",
+	Run: func(cmd *cobra.Command, args []string) {
+		runTemplateList()
+	},
+}
+
+var templateEjectCmd = &cobra.Command{
+	Use:   "eject <name>",
+	Short: "Copy an embedded template out to customise it",
+	Long: `Copy an embedded template, like cog.py.tmpl, into the project's
+.botbox/templates directory, or into ~/.config/botbox/templates with --global.
+
+The copy replaces the embedded template from then on, so botbox add and botbox
+edit generate cogs from it. Templates use << and >> as delimiters, and an
+override may only read the values its embedded template is rendered with.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runTemplateEject(cmd, args[0])
+	},
+}
+
+// templateListResult is the JSON result of template list, Warnings name the user templates that failed to load
+type templateListResult struct {
+	Templates []utils.ProjectTemplate  `json:"templates"`
+	Overrides []utils.TemplateOverride `json:"overrides"`
+	Warnings  []string                 `json:"warnings"`
+}
+
+// templateEjectResult is the JSON result of template eject
+type templateEjectResult struct {
+	Name  string `json:"name"`
+	Path  string `json:"path"`
+	Scope string `json:"scope"`
+}
+
+/**
+ * runTemplateList
+ * Prints every project template botbox create can start from
+ * @return ...
+ **/
+func runTemplateList() {
+	templates, errs := utils.ListProjectTemplates()
+	overrides, err := utils.ListTemplateOverrides()
+	if err != nil {
+		exitWithError(exitError, err)
+	}
+
+	for i := range templates {
+		templates[i].Libraries = templates[i].SupportedLibraries()
+	}
+
+	if jsonOutput() {
+		result := templateListResult{Templates: templates, Overrides: overrides, Warnings: []string{}}
+		if result.Overrides == nil {
+			result.Overrides = []utils.TemplateOverride{}
+		}
+		for _, err := range errs {
+			result.Warnings = append(result.Warnings, err.Error())
+		}
+		printResult(result)
+		return
+	}
+
+	for _, err := range errs {
+		fmt.Fprintln(os.Stderr, "Warning:", err)
+	}
+	width := 0
+	for _, projectTemplate := range templates {
+		width = max(width, len(projectTemplate.Name))
+	}
+	for _, projectTemplate := range templates {
+		fmt.Printf("%-*s  %s", width, projectTemplate.Name, projectTemplate.Description)
+		if projectTemplate.Source != utils.BuiltinTemplateSource {
+			fmt.Printf(" (%s)", projectTemplate.Source)
+		}
+		if note := projectTemplate.LibraryNote(); note != "" {
+			fmt.Printf(" (%s)", note)
+		}
+		fmt.Println()
+	}
+
+	if len(overrides) == 0 {
+		return
+	}
+	fmt.Println()
+	fmt.Println("Template overrides:")
+	for _, override := range overrides {
+		fmt.Printf("  %s  %s (%s)\n", override.Name, override.Path, override.Scope)
+		if override.Problem != "" {
+			fmt.Fprintf(os.Stderr, "Warning: %s: %s\n", override.Name, override.Problem)
+		}
+	}
+}
+
+/**
+ * runTemplateEject
+ * Copies an embedded template into the project or user template directory
+ * @param cmd {*cobra.Command} - the command holding the flags
+ * @param name {string} - the embedded template to copy
+ * @return ...
+ **/
+func runTemplateEject(cmd *cobra.Command, name string) {
+	global, _ := cmd.Flags().GetBool("global")
+	force, _ := cmd.Flags().GetBool("force")
+
+	scope := utils.OverrideScopeUser
+	dir, err := utils.UserTemplatesDir()
+	if err != nil {
+		exitWithError(exitError, err)
+	}
+	if !global {
+		scope = utils.OverrideScopeProject
+		dir = filepath.Join(requireProject(), utils.TemplateOverridesDir)
+	}
+
+	// A bad name or an existing override is a usage error, failing to write the copy is not
+	if !slices.Contains(utils.EmbeddedTemplates(), name) {
+		exitWithError(exitUsage, fmt.Errorf("unknown template '%s', use one of %s", name, strings.Join(utils.EmbeddedTemplates(), ", ")))
+	}
+	if _, err := os.Stat(filepath.Join(dir, name)); err == nil && !force {
+		exitWithError(exitUsage, fmt.Errorf("%s already exists, use --force to replace it", filepath.Join(dir, name)))
+	}
+	path, err := utils.EjectTemplate(name, dir, force)
+	if err != nil {
+		exitWithError(exitError, err)
+	}
+
+	if jsonOutput() {
+		printResult(templateEjectResult{Name: name, Path: path, Scope: scope})
+		return
+	}
+	fmt.Println(path)
+}
+
+func init() {
+	rootCmd.AddCommand(templateCmd)
+	templateCmd.AddCommand(templateListCmd)
+	templateCmd.AddCommand(templateEjectCmd)
+
+	templateEjectCmd.Flags().Bool("global", false, "Eject into ~/.config/botbox/templates for every project instead of the current project")
+	templateEjectCmd.Flags().Bool("force", false, "Replace an override that already exists")
+}
+
+/*
+Copyright © 2025 Austin "Choice404" Choi
+
+https://github.com/choice404/botbox
+
+Bot Box
+A discord bot template generator to help create discord bots quickly and easily
+
+This code is licensed under the MIT License.
+
+MIT License
+
+Copyright (c) 2025 Austin Choi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
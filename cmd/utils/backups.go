@@ -0,0 +1,265 @@
+/*
+Copyright © 2025 Austin "Choice404" Choi
+See end of file for extended copyright information
+*/
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// BackupsDir holds one <id>.json file per backup, relative to the project root
+var BackupsDir = filepath.Join(".botbox", "backups")
+
+// BackupRetention is how many backups a project keeps, the oldest are removed when a new backup goes past it
+var BackupRetention = 20
+
+// backupIDLayout names a backup after the time it was taken, so backup ids sort oldest first
+const backupIDLayout = "20060102-150405"
+
+// Backup is a copy of some project files taken before a command replaced them, Files maps each
+// file's slash separated path relative to the project root to its content
+type Backup struct {
+	ID      string            `json:"-"`
+	Created time.Time         `json:"created"`
+	Reason  string            `json:"reason"`
+	Files   map[string]string `json:"files"`
+}
+
+// BackupFilePath is where a backup is kept
+func BackupFilePath(rootDir string, id string) string {
+	return filepath.Join(rootDir, BackupsDir, id+".json")
+}
+
+// SortedFiles lists the backed up files in a stable order
+func (b Backup) SortedFiles() []string {
+	files := make([]string, 0, len(b.Files))
+	for file := range b.Files {
+		files = append(files, file)
+	}
+	slices.Sort(files)
+	return files
+}
+
+/**
+ * StageBackup
+ * Stages a backup of the current content of some project files, files that do not exist yet are left out.
+ * Backups past BackupRetention are staged for removal, oldest first
+ * @param changes {*ChangeSet} - the change set the backup is staged in
+ * @param rootDir {string} - the project root
+ * @param reason {string} - what replaced the files, like "edit Greetings"
+ * @param paths {[]string} - the files to back up
+ * @return string - the id of the backup, empty when none of the files exist
+ * @return error - any failure reading the files or the existing backups
+ **/
+func StageBackup(changes *ChangeSet, rootDir string, reason string, paths ...string) (string, error) {
+	return stageBackup(changes, rootDir, reason, "", paths...)
+}
+
+// stageBackup is StageBackup that never prunes the backup named by keep, the one a restore is reading from
+func stageBackup(changes *ChangeSet, rootDir string, reason string, keep string, paths ...string) (string, error) {
+	backup := Backup{Created: time.Now(), Reason: reason, Files: map[string]string{}}
+	var backedUp []string
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		backup.Files[displayPath(rootDir, path)] = string(data)
+		backedUp = append(backedUp, path)
+	}
+	if len(backedUp) == 0 {
+		return "", nil
+	}
+
+	existing, err := ListBackups(rootDir)
+	if err != nil {
+		return "", err
+	}
+
+	// Backups taken within the same second get a counter so none overwrites another
+	base := backup.Created.Format(backupIDLayout)
+	backup.ID = base
+	for n := 2; ; n++ {
+		path := BackupFilePath(rootDir, backup.ID)
+		if _, err := os.Stat(path); os.IsNotExist(err) && !changes.Staged(path) {
+			break
+		}
+		backup.ID = fmt.Sprintf("%s-%d", base, n)
+	}
+
+	data, err := json.MarshalIndent(backup, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal backup: %w", err)
+	}
+	if err := changes.WriteBackup(BackupFilePath(rootDir, backup.ID), data, backedUp); err != nil {
+		return "", fmt.Errorf("failed to write backup: %w", err)
+	}
+
+	// ListBackups is newest first, so everything past the retention limit less the new backup is the oldest.
+	// The kept backup counts toward the limit but is never the one removed
+	retained := BackupRetention - 1
+	if slices.ContainsFunc(existing, func(b Backup) bool { return b.ID == keep }) {
+		existing = slices.DeleteFunc(existing, func(b Backup) bool { return b.ID == keep })
+		retained--
+	}
+	if len(existing) > retained {
+		for _, old := range existing[max(0, retained):] {
+			if err := changes.RemoveFile(BackupFilePath(rootDir, old.ID)); err != nil {
+				return "", fmt.Errorf("failed to remove old backup: %w", err)
+			}
+		}
+	}
+
+	return backup.ID, nil
+}
+
+/**
+ * ListBackups
+ * Reads every backup of a project, newest first
+ * @param rootDir {string} - the project root
+ * @return []Backup - the backups
+ * @return error - any failure reading the backups directory or a backup
+ **/
+func ListBackups(rootDir string) ([]Backup, error) {
+	files, err := os.ReadDir(filepath.Join(rootDir, BackupsDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backups directory: %w", err)
+	}
+
+	var backups []Backup
+	for _, file := range files {
+		id, isJSON := strings.CutSuffix(file.Name(), ".json")
+		if file.IsDir() || !isJSON {
+			continue
+		}
+		backup, err := LoadBackup(rootDir, id)
+		if err != nil {
+			return nil, err
+		}
+		backups = append(backups, backup)
+	}
+
+	slices.SortFunc(backups, func(a, b Backup) int {
+		if c := b.Created.Compare(a.Created); c != 0 {
+			return c
+		}
+		return strings.Compare(b.ID, a.ID)
+	})
+	return backups, nil
+}
+
+/**
+ * LoadBackup
+ * Reads one backup of a project
+ * @param rootDir {string} - the project root
+ * @param id {string} - the backup id, as shown by botbox backup list
+ * @return Backup - the backup
+ * @return error - a missing or unreadable backup
+ **/
+func LoadBackup(rootDir string, id string) (Backup, error) {
+	var backup Backup
+	if id == "" || !filepath.IsLocal(id) || strings.ContainsAny(id, `/\`) {
+		return backup, fmt.Errorf("invalid backup id %q", id)
+	}
+
+	data, err := os.ReadFile(BackupFilePath(rootDir, id))
+	if os.IsNotExist(err) {
+		return backup, fmt.Errorf("backup %q does not exist, run 'botbox backup list' to see the backups", id)
+	}
+	if err != nil {
+		return backup, fmt.Errorf("failed to read backup %s: %w", id, err)
+	}
+	if err := json.Unmarshal(data, &backup); err != nil {
+		return backup, fmt.Errorf("failed to parse backup %s: %w", id, err)
+	}
+	for file := range backup.Files {
+		if !filepath.IsLocal(filepath.FromSlash(file)) {
+			return backup, fmt.Errorf("backup %s holds %q, which is outside the project", id, file)
+		}
+	}
+	backup.ID = id
+	return backup, nil
+}
+
+/**
+ * StageRestore
+ * Stages every file of a backup back to the content it was backed up with, the current content of
+ * those files is backed up first so a restore can be undone
+ * @param changes {*ChangeSet} - the change set the restore is staged in
+ * @param rootDir {string} - the project root
+ * @param id {string} - the backup to restore
+ * @return Backup - the restored backup
+ * @return string - the id of the backup of the current files, empty when none of them exist
+ * @return error - a missing backup or any failure staging the files
+ **/
+func StageRestore(changes *ChangeSet, rootDir string, id string) (Backup, string, error) {
+	backup, err := LoadBackup(rootDir, id)
+	if err != nil {
+		return backup, "", err
+	}
+
+	paths := make([]string, 0, len(backup.Files))
+	for _, file := range backup.SortedFiles() {
+		paths = append(paths, filepath.Join(rootDir, filepath.FromSlash(file)))
+	}
+
+	// The restored backup is being read, so pruning for the new backup must not remove it
+	previousID, err := stageBackup(changes, rootDir, "restore "+id, id, paths...)
+	if err != nil {
+		return backup, "", err
+	}
+	for i, file := range backup.SortedFiles() {
+		if err := changes.WriteFile(paths[i], []byte(backup.Files[file])); err != nil {
+			return backup, "", fmt.Errorf("failed to restore %s: %w", file, err)
+		}
+	}
+
+	return backup, previousID, nil
+}
+
+/*
+Copyright © 2025 Austin "Choice404" Choi
+
+https://github.com/choice404/botbox
+
+Bot Box
+A discord bot template generator to help create discord bots quickly and easily
+
+This code is licensed under the MIT License.
+
+MIT License
+
+Copyright (c) 2025 Austin Choi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
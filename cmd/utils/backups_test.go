@@ -0,0 +1,261 @@
+/*
+Copyright © 2025 Austin "Choice404" Choi
+See end of file for extended copyright information
+*/
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// writeBackupTestFiles plants a cog file and botbox.conf in a temp project
+func writeBackupTestFiles(t *testing.T, cog, conf string) (string, string, string) {
+	t.Helper()
+	rootDir := t.TempDir()
+	cogPath := filepath.Join(rootDir, "src", "cogs", "greet.py")
+	confPath := filepath.Join(rootDir, "botbox.conf")
+	if err := os.MkdirAll(filepath.Dir(cogPath), 0755); err != nil {
+		t.Fatalf("failed to create cogs dir: %v", err)
+	}
+	if err := os.WriteFile(cogPath, []byte(cog), 0644); err != nil {
+		t.Fatalf("failed to write cog file: %v", err)
+	}
+	if err := os.WriteFile(confPath, []byte(conf), 0644); err != nil {
+		t.Fatalf("failed to write botbox.conf: %v", err)
+	}
+	return rootDir, cogPath, confPath
+}
+
+// stageAndApply runs a staging step and writes what it staged
+func stageAndApply(t *testing.T, stage func(changes *ChangeSet) error) {
+	t.Helper()
+	changes := &ChangeSet{}
+	if err := stage(changes); err != nil {
+		t.Fatalf("staging failed: %v", err)
+	}
+	if err := changes.Apply(); err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+}
+
+func TestStageBackupKeepsHistory(t *testing.T) {
+	rootDir, cogPath, confPath := writeBackupTestFiles(t, "v1\n", "{}\n")
+	missing := filepath.Join(rootDir, "src", "cogs", "missing.py")
+
+	var ids []string
+	for _, content := range []string{"v2\n", "v3\n"} {
+		stageAndApply(t, func(changes *ChangeSet) error {
+			id, err := StageBackup(changes, rootDir, "edit Greet", cogPath, confPath, missing)
+			ids = append(ids, id)
+			if err != nil {
+				return err
+			}
+			return changes.WriteFile(cogPath, []byte(content))
+		})
+	}
+
+	// Backups taken within the same second get a counter instead of replacing each other
+	if ids[0] == ids[1] || (strings.HasPrefix(ids[1], ids[0]) && ids[1] != ids[0]+"-2") {
+		t.Errorf("backup ids = %v, want distinct ids with a -2 counter on a clash", ids)
+	}
+
+	backups, err := ListBackups(rootDir)
+	if err != nil {
+		t.Fatalf("ListBackups returned error: %v", err)
+	}
+	if len(backups) != 2 || backups[0].ID != ids[1] || backups[1].ID != ids[0] {
+		t.Fatalf("backups = %+v, want %v newest first", backups, ids)
+	}
+	if got := backups[1].Files["src/cogs/greet.py"]; got != "v1\n" {
+		t.Errorf("first backup cog file = %q, want v1", got)
+	}
+	if got := backups[0].Files["src/cogs/greet.py"]; got != "v2\n" {
+		t.Errorf("second backup cog file = %q, want v2", got)
+	}
+	if files := backups[0].SortedFiles(); !slices.Equal(files, []string{"botbox.conf", "src/cogs/greet.py"}) {
+		t.Errorf("backed up files = %v, missing files should be left out", files)
+	}
+	if backups[0].Reason != "edit Greet" {
+		t.Errorf("reason = %q, want edit Greet", backups[0].Reason)
+	}
+}
+
+func TestStageBackupRetention(t *testing.T) {
+	rootDir, cogPath, _ := writeBackupTestFiles(t, "v1\n", "{}\n")
+
+	original := BackupRetention
+	BackupRetention = 3
+	t.Cleanup(func() { BackupRetention = original })
+
+	var ids []string
+	for range 5 {
+		stageAndApply(t, func(changes *ChangeSet) error {
+			id, err := StageBackup(changes, rootDir, "edit Greet", cogPath)
+			ids = append(ids, id)
+			return err
+		})
+	}
+
+	backups, err := ListBackups(rootDir)
+	if err != nil {
+		t.Fatalf("ListBackups returned error: %v", err)
+	}
+	var kept []string
+	for _, backup := range backups {
+		kept = append(kept, backup.ID)
+	}
+	if want := []string{ids[4], ids[3], ids[2]}; !slices.Equal(kept, want) {
+		t.Errorf("kept backups = %v, want the newest three %v", kept, want)
+	}
+}
+
+func TestStageRestore(t *testing.T) {
+	rootDir, cogPath, confPath := writeBackupTestFiles(t, "old cog\n", "old conf\n")
+
+	var id string
+	stageAndApply(t, func(changes *ChangeSet) error {
+		var err error
+		if id, err = StageBackup(changes, rootDir, "edit Greet", cogPath, confPath); err != nil {
+			return err
+		}
+		if err := changes.WriteFile(cogPath, []byte("new cog\n")); err != nil {
+			return err
+		}
+		return changes.WriteFile(confPath, []byte("new conf\n"))
+	})
+
+	var previousID string
+	stageAndApply(t, func(changes *ChangeSet) error {
+		backup, previous, err := StageRestore(changes, rootDir, id)
+		previousID = previous
+		if err == nil && backup.ID != id {
+			t.Errorf("restored backup id = %q, want %q", backup.ID, id)
+		}
+		return err
+	})
+
+	for path, want := range map[string]string{cogPath: "old cog\n", confPath: "old conf\n"} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", path, err)
+		}
+		if string(data) != want {
+			t.Errorf("%s = %q, want %q", path, data, want)
+		}
+	}
+
+	// The restore backed up what it replaced, so it can be undone
+	previous, err := LoadBackup(rootDir, previousID)
+	if err != nil {
+		t.Fatalf("LoadBackup returned error: %v", err)
+	}
+	if previous.Files["src/cogs/greet.py"] != "new cog\n" || previous.Files["botbox.conf"] != "new conf\n" {
+		t.Errorf("backup of the replaced files = %+v, want the new content", previous.Files)
+	}
+	if previous.Reason != "restore "+id {
+		t.Errorf("reason = %q, want restore %s", previous.Reason, id)
+	}
+}
+
+func TestStageRestoreKeepsTheOldestBackup(t *testing.T) {
+	rootDir, cogPath, _ := writeBackupTestFiles(t, "v1\n", "{}\n")
+
+	original := BackupRetention
+	BackupRetention = 3
+	t.Cleanup(func() { BackupRetention = original })
+
+	var ids []string
+	for range 3 {
+		stageAndApply(t, func(changes *ChangeSet) error {
+			id, err := StageBackup(changes, rootDir, "edit Greet", cogPath)
+			ids = append(ids, id)
+			return err
+		})
+	}
+
+	// Restoring the oldest of a full history prunes the next oldest instead of the one being restored
+	var previousID string
+	stageAndApply(t, func(changes *ChangeSet) error {
+		_, previous, err := StageRestore(changes, rootDir, ids[0])
+		previousID = previous
+		return err
+	})
+
+	backups, err := ListBackups(rootDir)
+	if err != nil {
+		t.Fatalf("ListBackups returned error: %v", err)
+	}
+	var kept []string
+	for _, backup := range backups {
+		kept = append(kept, backup.ID)
+	}
+	if want := []string{previousID, ids[2], ids[0]}; !slices.Equal(kept, want) {
+		t.Errorf("kept backups = %v, want %v", kept, want)
+	}
+}
+
+func TestLoadBackupRejectsBadBackups(t *testing.T) {
+	rootDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(rootDir, BackupsDir), 0755); err != nil {
+		t.Fatalf("failed to create backups dir: %v", err)
+	}
+	outside := `{"created":"2026-01-02T03:04:05Z","reason":"edit","files":{"../evil.py":"x"}}`
+	if err := os.WriteFile(BackupFilePath(rootDir, "outside"), []byte(outside), 0644); err != nil {
+		t.Fatalf("failed to write backup: %v", err)
+	}
+
+	tests := []struct {
+		id      string
+		wantErr string
+	}{
+		{id: "missing", wantErr: `backup "missing" does not exist`},
+		{id: "../botbox", wantErr: `invalid backup id "../botbox"`},
+		{id: "outside", wantErr: "outside the project"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			_, err := LoadBackup(rootDir, tt.id)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadBackup(%q) error = %v, want %q", tt.id, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+/*
+Copyright © 2025 Austin "Choice404" Choi
+
+https://github.com/choice404/botbox
+
+Bot Box
+A discord bot template generator to help create discord bots quickly and easily
+
+This code is licensed under the MIT License.
+
+MIT License
+
+Copyright (c) 2025 Austin Choi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
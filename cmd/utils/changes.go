@@ -0,0 +1,388 @@
+/*
+Copyright © 2025 Austin "Choice404" Choi
+See end of file for extended copyright information
+*/
+
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Unchanged lines shown around each change, the same amount diff -u and git show
+const diffContext = 3
+
+// FileChange is one file a command writes, Before is nil for a file that does not exist yet
+type FileChange struct {
+	Path   string
+	Before []byte
+	After  []byte
+	// BackupOf names the files a backup copies, a backup is listed in the diff without its content
+	BackupOf []string
+	// Remove deletes the file instead of writing After
+	Remove bool
+}
+
+// ChangeSet collects the files a command writes so they can be shown as a unified diff before
+// any of them reach the disk, nothing is written until Apply
+type ChangeSet struct {
+	changes []FileChange
+	applied bool
+}
+
+/**
+ * WriteFile
+ * Stages the new content of a file, a file staged twice keeps the content it had on disk as its before side
+ * @param path {string} - the file to write
+ * @param data {[]byte} - the content the file will hold
+ * @return error - any failure reading the current file
+ **/
+func (c *ChangeSet) WriteFile(path string, data []byte) error {
+	if i := c.index(path); i != -1 {
+		c.changes[i].After = data
+		c.changes[i].Remove = false
+		return nil
+	}
+
+	before, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	c.changes = append(c.changes, FileChange{Path: path, Before: before, After: data})
+	return nil
+}
+
+/**
+ * WriteBackup
+ * Stages a backup holding the current content of some files
+ * @param backupPath {string} - where the backup goes
+ * @param data {[]byte} - the backup's content
+ * @param paths {[]string} - the files the backup copies
+ * @return error - any failure reading the current backup
+ **/
+func (c *ChangeSet) WriteBackup(backupPath string, data []byte, paths []string) error {
+	if err := c.WriteFile(backupPath, data); err != nil {
+		return err
+	}
+	c.changes[c.index(backupPath)].BackupOf = paths
+	return nil
+}
+
+/**
+ * RemoveFile
+ * Stages the removal of a file, a file that does not exist is left out of the changes
+ * @param path {string} - the file to remove
+ * @return error - any failure reading the current file
+ **/
+func (c *ChangeSet) RemoveFile(path string) error {
+	if err := c.WriteFile(path, nil); err != nil {
+		return err
+	}
+	c.changes[c.index(path)].Remove = true
+	return nil
+}
+
+// Staged reports whether a file is part of the change set
+func (c *ChangeSet) Staged(path string) bool {
+	return c.index(path) != -1
+}
+
+// index finds the staged change of a file, -1 when the file is not staged
+func (c *ChangeSet) index(path string) int {
+	return slices.IndexFunc(c.changes, func(change FileChange) bool { return change.Path == path })
+}
+
+// Changes lists the staged files whose content differs from the disk, in the order they were staged
+func (c *ChangeSet) Changes() []FileChange {
+	var changed []FileChange
+	for _, change := range c.changes {
+		if change.Remove && change.Before == nil {
+			continue
+		}
+		if !change.Remove && change.Before != nil && bytes.Equal(change.Before, change.After) {
+			continue
+		}
+		changed = append(changed, change)
+	}
+	return changed
+}
+
+// Empty reports whether applying the change set would leave every file as it is
+func (c *ChangeSet) Empty() bool {
+	return len(c.Changes()) == 0
+}
+
+/**
+ * Apply
+ * Writes every changed file, each through a sibling temp file so a failed write cannot leave one half written
+ * @return error - the first write that failed
+ **/
+func (c *ChangeSet) Apply() error {
+	for _, change := range c.Changes() {
+		if change.Remove {
+			if err := os.Remove(change.Path); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove %s: %w", change.Path, err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(change.Path), os.ModePerm); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", change.Path, err)
+		}
+		tempPath := change.Path + ".tmp"
+		if err := os.WriteFile(tempPath, change.After, 0644); err != nil {
+			return fmt.Errorf("failed to write temp file for %s: %w", change.Path, err)
+		}
+		if err := os.Rename(tempPath, change.Path); err != nil {
+			os.Remove(tempPath)
+			return fmt.Errorf("failed to replace %s: %w", change.Path, err)
+		}
+	}
+	c.applied = true
+	return nil
+}
+
+// Applied reports whether the staged changes were written
+func (c *ChangeSet) Applied() bool {
+	return c.applied
+}
+
+/**
+ * Diff
+ * Renders every changed file as a unified diff, paths are shown relative to the project root
+ * @param rootDir {string} - the project root
+ * @return string - the diff, empty when nothing changes
+ **/
+func (c *ChangeSet) Diff(rootDir string) string {
+	var diff strings.Builder
+	for _, change := range c.Changes() {
+		name := displayPath(rootDir, change.Path)
+
+		if change.Remove {
+			fmt.Fprintf(&diff, "Remove %s\n", name)
+			continue
+		}
+		if change.BackupOf != nil {
+			originals := make([]string, len(change.BackupOf))
+			for i, path := range change.BackupOf {
+				originals[i] = displayPath(rootDir, path)
+			}
+			fmt.Fprintf(&diff, "Backup %s -> %s\n", strings.Join(originals, ", "), name)
+			continue
+		}
+
+		from := "a/" + name
+		if change.Before == nil {
+			from = "/dev/null"
+		}
+		diff.WriteString(unifiedDiff(from, "b/"+name, change.Before, change.After))
+	}
+	return diff.String()
+}
+
+// displayPath shows a path relative to the project root, paths outside of it are shown as they are
+func displayPath(rootDir, path string) string {
+	if relative, err := filepath.Rel(rootDir, path); err == nil && !strings.HasPrefix(relative, "..") {
+		return filepath.ToSlash(relative)
+	}
+	return path
+}
+
+// diffOp is one line of an edit script, Kind is ' ' for a kept line, '-' for a removed one, and '+' for an added one
+type diffOp struct {
+	Kind byte
+	Line string
+}
+
+// splitLines splits content into lines that keep their newline, so a missing final newline is a difference too
+func splitLines(content []byte) []string {
+	var lines []string
+	for len(content) > 0 {
+		end := bytes.IndexByte(content, '\n') + 1
+		if end == 0 {
+			end = len(content)
+		}
+		lines = append(lines, string(content[:end]))
+		content = content[end:]
+	}
+	return lines
+}
+
+// diffLines finds the shortest edit script turning a into b with the Myers algorithm
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	offset := n + m
+	v := make([]int, 2*offset+2)
+	var trace [][]int
+
+	for d := 0; d <= n+m; d++ {
+		trace = append(trace, slices.Clone(v))
+		for k := -d; k <= d; k += 2 {
+			x := 0
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrackDiff(trace, a, b, offset)
+			}
+		}
+	}
+	return nil
+}
+
+// backtrackDiff walks the saved Myers frontiers back from the end of both inputs to build the edit script
+func backtrackDiff(trace [][]int, a, b []string, offset int) []diffOp {
+	var ops []diffOp
+	x, y := len(a), len(b)
+
+	for d := len(trace) - 1; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+		previousK := k - 1
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			previousK = k + 1
+		}
+		previousX := v[offset+previousK]
+		previousY := previousX - previousK
+
+		for x > previousX && y > previousY {
+			ops = append(ops, diffOp{Kind: ' ', Line: a[x-1]})
+			x--
+			y--
+		}
+		if x == previousX {
+			ops = append(ops, diffOp{Kind: '+', Line: b[y-1]})
+			y--
+		} else {
+			ops = append(ops, diffOp{Kind: '-', Line: a[x-1]})
+			x--
+		}
+	}
+	for x > 0 && y > 0 {
+		ops = append(ops, diffOp{Kind: ' ', Line: a[x-1]})
+		x--
+		y--
+	}
+
+	slices.Reverse(ops)
+	return ops
+}
+
+/**
+ * unifiedDiff
+ * Renders the difference between two versions of a file in the unified diff format
+ * @param from {string} - the name shown for the old version
+ * @param to {string} - the name shown for the new version
+ * @param before {[]byte} - the old content
+ * @param after {[]byte} - the new content
+ * @return string - the diff, empty when both versions match
+ **/
+func unifiedDiff(from, to string, before, after []byte) string {
+	ops := diffLines(splitLines(before), splitLines(after))
+	if !slices.ContainsFunc(ops, func(op diffOp) bool { return op.Kind != ' ' }) {
+		return ""
+	}
+
+	// Line numbers of both versions where each op starts
+	oldLine := make([]int, len(ops)+1)
+	newLine := make([]int, len(ops)+1)
+	for i, op := range ops {
+		oldLine[i+1], newLine[i+1] = oldLine[i], newLine[i]
+		if op.Kind != '+' {
+			oldLine[i+1]++
+		}
+		if op.Kind != '-' {
+			newLine[i+1]++
+		}
+	}
+
+	var diff strings.Builder
+	fmt.Fprintf(&diff, "--- %s\n+++ %s\n", from, to)
+
+	for i := 0; i < len(ops); {
+		for i < len(ops) && ops[i].Kind == ' ' {
+			i++
+		}
+		if i == len(ops) {
+			break
+		}
+
+		// Changes separated by no more than twice the context share a hunk
+		start := max(0, i-diffContext)
+		end := i + 1
+		for j := i + 1; j < len(ops); j++ {
+			if ops[j].Kind != ' ' {
+				end = j + 1
+			} else if j-end >= 2*diffContext {
+				break
+			}
+		}
+		end = min(len(ops), end+diffContext)
+
+		oldStart, oldCount := oldLine[start], oldLine[end]-oldLine[start]
+		newStart, newCount := newLine[start], newLine[end]-newLine[start]
+		if oldCount > 0 {
+			oldStart++
+		}
+		if newCount > 0 {
+			newStart++
+		}
+		fmt.Fprintf(&diff, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+
+		for _, op := range ops[start:end] {
+			diff.WriteByte(op.Kind)
+			diff.WriteString(op.Line)
+			if !strings.HasSuffix(op.Line, "\n") {
+				diff.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		i = end
+	}
+
+	return diff.String()
+}
+
+/*
+Copyright © 2025 Austin "Choice404" Choi
+
+https://github.com/choice404/botbox
+
+Bot Box
+A discord bot template generator to help create discord bots quickly and easily
+
+This code is licensed under the MIT License.
+
+MIT License
+
+Copyright (c) 2025 Austin Choi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
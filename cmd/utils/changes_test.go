@@ -0,0 +1,167 @@
+/*
+Copyright © 2025 Austin "Choice404" Choi
+See end of file for extended copyright information
+*/
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		want   string
+	}{
+		{
+			name:   "changed line",
+			before: "a\nb\nc\n",
+			after:  "a\nB\nc\n",
+			want:   "--- a/f\n+++ b/f\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			name:   "missing final newline",
+			before: "a\n",
+			after:  "a\nb",
+			want:   "--- a/f\n+++ b/f\n@@ -1,1 +1,2 @@\n a\n+b\n\\ No newline at end of file\n",
+		},
+		{
+			name:   "distant changes get their own hunks",
+			before: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			after:  "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\ntwelve\n",
+			want:   "--- a/f\n+++ b/f\n@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n@@ -9,4 +9,4 @@\n 9\n 10\n 11\n-12\n+twelve\n",
+		},
+		{
+			name:   "same content",
+			before: "a\n",
+			after:  "a\n",
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unifiedDiff("a/f", "b/f", []byte(tt.before), []byte(tt.after)); got != tt.want {
+				t.Errorf("unifiedDiff() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChangeSet(t *testing.T) {
+	root := t.TempDir()
+	existing := filepath.Join(root, "src", "cogs", "greet.py")
+	if err := os.MkdirAll(filepath.Dir(existing), 0755); err != nil {
+		t.Fatalf("failed to create cogs dir: %v", err)
+	}
+	if err := os.WriteFile(existing, []byte("old\n"), 0644); err != nil {
+		t.Fatalf("failed to write cog: %v", err)
+	}
+	unchanged := filepath.Join(root, "botbox.conf")
+	if err := os.WriteFile(unchanged, []byte("{}\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	created := filepath.Join(root, "src", "locales", "de.json")
+	removed := filepath.Join(root, "old.json")
+	if err := os.WriteFile(removed, []byte("{}\n"), 0644); err != nil {
+		t.Fatalf("failed to write old file: %v", err)
+	}
+
+	changes := &ChangeSet{}
+	if !changes.Empty() {
+		t.Error("new change set should be empty")
+	}
+	stage := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("failed to stage change: %v", err)
+		}
+	}
+	backup := filepath.Join(root, ".botbox", "backups", "1.json")
+	stage(changes.WriteBackup(backup, []byte("old\n"), []string{existing, unchanged}))
+	stage(changes.WriteFile(existing, []byte("new\n")))
+	stage(changes.WriteFile(unchanged, []byte("{}\n")))
+	stage(changes.WriteFile(created, []byte("{}\n")))
+	stage(changes.RemoveFile(removed))
+	stage(changes.RemoveFile(filepath.Join(root, "missing.json")))
+
+	if got := len(changes.Changes()); got != 4 {
+		t.Errorf("len(Changes()) = %d, want 4, unchanged and missing files are left out", got)
+	}
+	if _, err := os.Stat(created); !os.IsNotExist(err) {
+		t.Fatalf("staging wrote %s before Apply", created)
+	}
+
+	diff := changes.Diff(root)
+	for _, want := range []string{
+		"Backup src/cogs/greet.py, botbox.conf -> .botbox/backups/1.json\n",
+		"--- a/src/cogs/greet.py\n+++ b/src/cogs/greet.py\n@@ -1,1 +1,1 @@\n-old\n+new\n",
+		"--- /dev/null\n+++ b/src/locales/de.json\n@@ -0,0 +1,1 @@\n+{}\n",
+		"Remove old.json\n",
+	} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("Diff() missing %q:\n%s", want, diff)
+		}
+	}
+	if strings.Contains(diff, "b/botbox.conf") {
+		t.Errorf("Diff() should leave out unchanged files:\n%s", diff)
+	}
+
+	if err := changes.Apply(); err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+	if !changes.Applied() {
+		t.Error("Applied() = false after Apply")
+	}
+	if _, err := os.Stat(removed); !os.IsNotExist(err) {
+		t.Errorf("Apply should remove %s, stat error = %v", removed, err)
+	}
+	for path, want := range map[string]string{existing: "new\n", backup: "old\n", created: "{}\n"} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", path, err)
+		}
+		if string(data) != want {
+			t.Errorf("%s = %q, want %q", path, data, want)
+		}
+	}
+}
+
+/*
+Copyright © 2025 Austin "Choice404" Choi
+
+https://github.com/choice404/botbox
+
+Bot Box
+A discord bot template generator to help create discord bots quickly and easily
+
+This code is licensed under the MIT License.
+
+MIT License
+
+Copyright (c) 2025 Austin Choi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
@@ -7,9 +7,12 @@ package utils
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"reflect"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -134,7 +137,7 @@ func TestParseCogFile(t *testing.T) {
 			},
 		},
 		{
-			name: "default permissions and choices decorators do not disturb parsing",
+			name: "default permissions and choices opened on the decorator line are read",
 			file: "adminCog",
 			want: ParsedCogInfo{
 				FileName:    "adminCog",
@@ -149,8 +152,14 @@ func TestParseCogFile(t *testing.T) {
 						Type:        "slash",
 						Description: "Syncs slash commands with Discord",
 						ReturnType:  "None",
+						Permissions: []string{"administrator"},
 						Args: []ArgInfo{
-							{Name: "scope", Type: "str", Description: "Where to sync commands: guild or global"},
+							{
+								Name:        "scope",
+								Type:        "str",
+								Description: "Where to sync commands: guild or global",
+								Choices:     []ChoiceInfo{{Name: "guild", Value: "guild"}, {Name: "global", Value: "global"}},
+							},
 						},
 					},
 					{
@@ -159,6 +168,7 @@ func TestParseCogFile(t *testing.T) {
 						Type:        "slash",
 						Description: "Shows how long the bot has been online",
 						ReturnType:  "None",
+						Permissions: []string{"administrator"},
 					},
 				},
 			},
@@ -261,7 +271,7 @@ func TestParseCogFile(t *testing.T) {
 			},
 		},
 		{
-			name: "decorators the command regex cannot match",
+			name: "decorator split across lines is read while unusable decorators are skipped",
 			file: "oddShape",
 			want: ParsedCogInfo{
 				FileName:    "oddShape",
@@ -269,6 +279,31 @@ func TestParseCogFile(t *testing.T) {
 				Author:      "Austin Choi",
 				ProjectName: "TestBot",
 				Description: "A discord bot used by the parser tests",
+				SlashCommands: []CommandInfo{
+					{
+						Name:        "split",
+						Scope:       "guild",
+						Type:        "slash",
+						Description: "Decorator split across several lines",
+						ReturnType:  "None",
+					},
+				},
+				Diagnostics: []Diagnostic{
+					{
+						Line:     30,
+						Column:   6,
+						Severity: SeverityWarning,
+						Code:     DiagUnnamedCommand,
+						Message:  "app_commands.command on nameless needs name and description written as string literals, the command is left out of botbox.conf",
+					},
+					{
+						Line:     39,
+						Column:   5,
+						Severity: SeverityWarning,
+						Code:     DiagNotCoroutine,
+						Message:  "command not_async is not defined with async def, it is left out of botbox.conf",
+					},
+				},
 			},
 		},
 	}
@@ -276,7 +311,7 @@ func TestParseCogFile(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			path := filepath.Join(fixtureCogsDir, tt.file+".py")
-			got, err := parseCogFile(path, tt.file)
+			got, err := parseCogFile(path, tt.file, DefaultLibrary)
 			if err != nil {
 				t.Fatalf("parseCogFile(%s) returned error: %v", path, err)
 			}
@@ -290,7 +325,7 @@ func TestParseCogFile(t *testing.T) {
 
 // TestParseAllCogFiles checks that every fixture in the directory is picked up
 func TestParseAllCogFiles(t *testing.T) {
-	parsed, err := parseAllCogFiles(fixtureCogsDir)
+	parsed, _, err := parseAllCogFiles(fixtureCogsDir, DefaultLibrary)
 	if err != nil {
 		t.Fatalf("parseAllCogFiles returned error: %v", err)
 	}
@@ -409,8 +444,12 @@ func TestParseHeaderComment(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			module, err := parsePython(strings.Join(tt.lines, "\n"))
+			if err != nil {
+				t.Fatalf("parsePython returned error: %v", err)
+			}
 			parsed := &ParsedCogInfo{}
-			parseHeaderComment(tt.lines, parsed)
+			parseHeaderComment(module.Docstring, parsed)
 
 			if parsed.Author != tt.wantAuthor {
 				t.Errorf("author = %q, want %q", parsed.Author, tt.wantAuthor)
@@ -425,56 +464,179 @@ func TestParseHeaderComment(t *testing.T) {
 	}
 }
 
-// TestParseSlashCommandRejectsUnusableDecorators makes sure no nameless command reaches the config
-func TestParseSlashCommandRejectsUnusableDecorators(t *testing.T) {
+// TestParseCogSourceRejectsUnusableDecorators makes sure no nameless command reaches the config
+func TestParseCogSourceRejectsUnusableDecorators(t *testing.T) {
 	tests := []struct {
 		name  string
 		lines []string
 	}{
 		{
-			name: "decorator split across lines",
+			name: "decorator without a name",
 			lines: []string{
-				"    @app_commands.command(",
-				`        name="split",`,
-				`        description="Split decorator",`,
-				"    )",
-				"    async def split(self, interaction: discord.Interaction) -> None:",
+				`    @app_commands.command(description="No name")`,
+				"    async def nameless(self, interaction: discord.Interaction) -> None:",
 			},
 		},
 		{
-			name: "decorator without a name",
+			name: "name that is not a string literal",
 			lines: []string{
-				`    @app_commands.command(description="No name")`,
-				"    async def nameless(self, interaction: discord.Interaction) -> None:",
+				`    @app_commands.command(name=NAME, description="Name from a constant")`,
+				"    async def constant(self, interaction: discord.Interaction) -> None:",
+			},
+		},
+		{
+			name: "slash command that is not a coroutine",
+			lines: []string{
+				`    @app_commands.command(name="plain", description="Not a coroutine")`,
+				"    def plain(self, interaction: discord.Interaction) -> None:",
 			},
 		},
 		{
-			name: "decorator without a following function",
+			name: "prefix command that is not a coroutine",
 			lines: []string{
-				`    @app_commands.command(name="orphan", description="No function")`,
+				"    @commands.command()",
+				"    def not_async(self, ctx: commands.Context) -> None:",
 			},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if cmd := parseSlashCommand(tt.lines, 0); cmd != nil {
-				t.Errorf("parseSlashCommand returned %+v, want nil", *cmd)
+			source := "class Cog(commands.Cog):\n" + strings.Join(tt.lines, "\n") + "\n        return None\n"
+			parsed, err := parseCogSource(source, "cog", DefaultLibrary)
+			if err != nil {
+				t.Fatalf("parseCogSource returned error: %v", err)
+			}
+			if len(parsed.SlashCommands) > 0 || len(parsed.PrefixCommands) > 0 {
+				t.Errorf("parseCogSource returned %+v, want no commands", *parsed)
 			}
 		})
 	}
 }
 
-// TestParsePrefixCommandRejectsUnusableDecorators covers the same guard for prefix commands
-func TestParsePrefixCommandRejectsUnusableDecorators(t *testing.T) {
-	lines := []string{
-		"    @commands.command()",
-		"    def not_async(self, ctx: commands.Context) -> None:",
-		"        return None",
+// TestParseCogSourceReadsReformattedCode covers code a formatter like black rewrote, the parser
+// reads the Python and not the layout the generator wrote it in
+func TestParseCogSourceReadsReformattedCode(t *testing.T) {
+	source := `class Shop(commands.Cog):
+    @app_commands.command(
+        name="buy",  # the command users type
+        description='Buys an item from the "shop"',
+    )
+    @app_commands.describe(
+        item="The item to buy",
+        amount="How many \N to buy",
+    )
+    @app_commands.guilds(GUILD)
+    async def buy(
+        self,
+        interaction: discord.Interaction,
+        item: str,
+        amount: app_commands.Range[
+            int, 1, 10
+        ] = 1,
+    ) -> None:
+        """Buys an item"""
+        try:
+            await interaction.response.send_message(
+                f"Bought {amount} {item}", ephemeral=False
+            )
+        except Exception as e:
+            logger.error(e)
+
+    @commands.command(aliases=["p", 'pp'],)
+    async def ping(self, ctx: commands.Context, *, text: str = 'pong') -> None:
+        await ctx.send(text)
+`
+
+	parsed, err := parseCogSource(source, "shop", DefaultLibrary)
+	if err != nil {
+		t.Fatalf("parseCogSource returned error: %v", err)
+	}
+
+	wantSlash := []CommandInfo{
+		{
+			Name:        "buy",
+			Scope:       "guild",
+			Type:        "slash",
+			Description: `Buys an item from the "shop"`,
+			ReturnType:  "None",
+			Args: []ArgInfo{
+				{Name: "item", Type: "str", Description: "The item to buy"},
+				{Name: "amount", Type: "int", Description: `How many \N to buy`, Min: "1", Max: "10", Optional: true, Default: "1"},
+			},
+			Responses: []ResponseInfo{{Type: "message", Content: "Bought {amount} {item}"}},
+		},
+	}
+	if !reflect.DeepEqual(parsed.SlashCommands, wantSlash) {
+		t.Errorf("slash commands\ngot:  %+v\nwant: %+v", parsed.SlashCommands, wantSlash)
+	}
+
+	wantPrefix := []CommandInfo{
+		{
+			Name:       "ping",
+			Scope:      "global",
+			Type:       "prefix",
+			ReturnType: "None",
+			Aliases:    []string{"p", "pp"},
+			Args:       []ArgInfo{{Name: "text", Type: "str", Rest: true, Optional: true, Default: "pong"}},
+		},
+	}
+	if !reflect.DeepEqual(parsed.PrefixCommands, wantPrefix) {
+		t.Errorf("prefix commands\ngot:  %+v\nwant: %+v", parsed.PrefixCommands, wantPrefix)
+	}
+}
+
+// TestParseCogSourceReportsSyntaxErrors makes sure a broken cog is reported where it breaks instead of being half read
+func TestParseCogSourceReportsSyntaxErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		source     string
+		wantLine   int
+		wantColumn int
+	}{
+		{
+			name:       "decorator without a following function",
+			source:     "class Cog(commands.Cog):\n    @app_commands.command(name=\"orphan\", description=\"No function\")\n    x = 1\n",
+			wantLine:   3,
+			wantColumn: 5,
+		},
+		{
+			name:       "bracket never closed",
+			source:     "class Cog(commands.Cog):\n    @app_commands.command(\n        name=\"open\",\n\n    async def open(self):\n        pass\n",
+			wantLine:   2,
+			wantColumn: 26,
+		},
+		{
+			name:       "unterminated string",
+			source:     "GUILD = discord.Object(id=0)\nNAME = \"unterminated\n",
+			wantLine:   2,
+			wantColumn: 8,
+		},
+		{
+			name:       "function without a body",
+			source:     "class Cog(commands.Cog):\n    async def empty(self):\n\nx = 1\n",
+			wantLine:   4,
+			wantColumn: 1,
+		},
+		{
+			name:       "dedent to an unknown level",
+			source:     "class Cog(commands.Cog):\n        x = 1\n    y = 2\n",
+			wantLine:   3,
+			wantColumn: 5,
+		},
 	}
 
-	if cmd := parsePrefixCommand(lines, 0); cmd != nil {
-		t.Errorf("parsePrefixCommand returned %+v, want nil", *cmd)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseCogSource(tt.source, "cog", DefaultLibrary)
+			var syntaxErr *PySyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("parseCogSource returned %v, want a *PySyntaxError", err)
+			}
+			if syntaxErr.Line != tt.wantLine || syntaxErr.Column != tt.wantColumn {
+				t.Errorf("error at line %d, column %d, want line %d, column %d: %v", syntaxErr.Line, syntaxErr.Column, tt.wantLine, tt.wantColumn, err)
+			}
+		})
 	}
 }
 
@@ -509,7 +671,7 @@ func TestModalCommandTemplateParseRoundTrip(t *testing.T) {
 		t.Fatalf("failed to write rendered cog: %v", err)
 	}
 
-	parsed, err := parseCogFile(path, "modalCog")
+	parsed, err := parseCogFile(path, "modalCog", DefaultLibrary)
 	if err != nil {
 		t.Fatalf("parseCogFile returned error: %v", err)
 	}
@@ -567,7 +729,7 @@ func TestMultiPageCommandTemplateParseRoundTrip(t *testing.T) {
 		t.Fatalf("failed to write rendered cog: %v", err)
 	}
 
-	parsed, err := parseCogFile(path, "multipageCog")
+	parsed, err := parseCogFile(path, "multipageCog", DefaultLibrary)
 	if err != nil {
 		t.Fatalf("parseCogFile returned error: %v", err)
 	}
@@ -615,7 +777,7 @@ func TestResponseCommandTemplateParseRoundTrip(t *testing.T) {
 		t.Fatalf("failed to write rendered cog: %v", err)
 	}
 
-	parsed, err := parseCogFile(path, "responseCog")
+	parsed, err := parseCogFile(path, "responseCog", DefaultLibrary)
 	if err != nil {
 		t.Fatalf("parseCogFile returned error: %v", err)
 	}
@@ -629,170 +791,1082 @@ func TestResponseCommandTemplateParseRoundTrip(t *testing.T) {
 	}
 }
 
-func TestCommandEqual(t *testing.T) {
-	base := CommandInfo{
-		Name:        "greet",
+func TestContextMenuCommandTemplateParseRoundTrip(t *testing.T) {
+	profile := CommandInfo{
+		Name:        "profile",
+		Scope:       "guild",
+		Type:        "user_context",
+		Description: "Shows a member profile",
+		ReturnType:  "None",
+	}
+	report := CommandInfo{
+		Name:        "report",
+		Scope:       "global",
+		Type:        "message_context",
+		Description: "Reports a message",
+		ReturnType:  "None",
+		Responses:   []ResponseInfo{{Type: "message", Content: "Reported {message.id}", Ephemeral: true}},
+	}
+	ping := CommandInfo{
+		Name:        "ping",
 		Scope:       "guild",
 		Type:        "slash",
-		Description: "Greets a member",
-		ReturnType:  "str",
-		Args:        []ArgInfo{{Name: "member", Type: "discord.Member", Description: "The member to greet"}},
+		Description: "Pings the bot",
+		ReturnType:  "None",
 	}
+	// Menus are named the way Discord shows them and need no description
+	flag := CommandInfo{
+		Name:       "Flag Message",
+		Scope:      "guild",
+		Type:       "message_context",
+		ReturnType: "None",
+	}
+	want := []CommandInfo{profile, report, ping, flag}
 
-	withScope := base
-	withScope.Scope = "global"
-
-	withDescription := base
-	withDescription.Description = "Greets everyone"
-
-	withReturn := base
-	withReturn.ReturnType = "None"
+	content, err := RenderTemplate("cog.py.tmpl", CogTemplateData{
+		Author:         "Austin Choi",
+		BotName:        "TestBot",
+		BotDescription: "A discord bot used by the parser tests",
+		ClassName:      "ContextCog",
+		Filename:       "contextCog",
+		SlashCommands:  want,
+	})
+	if err != nil {
+		t.Fatalf("RenderTemplate returned error: %v", err)
+	}
 
-	withArgType := base
-	withArgType.Args = []ArgInfo{{Name: "member", Type: "str", Description: "The member to greet"}}
+	path := filepath.Join(t.TempDir(), "contextCog.py")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write rendered cog: %v", err)
+	}
 
-	withArgDescription := base
-	withArgDescription.Args = []ArgInfo{{Name: "member", Type: "discord.Member", Description: "Someone"}}
+	parsed, err := parseCogFile(path, "contextCog", DefaultLibrary)
+	if err != nil {
+		t.Fatalf("parseCogFile returned error: %v", err)
+	}
 
-	withExtraArg := base
-	withExtraArg.Args = append(append([]ArgInfo{}, base.Args...), ArgInfo{Name: "times", Type: "int"})
+	if !commandsEqual(parsed.SlashCommands, want) {
+		t.Errorf("round trip changed the commands\ngot:  %+v\nwant: %+v", parsed.SlashCommands, want)
+	}
+	if len(parsed.PrefixCommands) != 0 {
+		t.Errorf("context menus parsed as prefix commands: %+v", parsed.PrefixCommands)
+	}
+	if !strings.Contains(content, "async def Flag_Message(") {
+		t.Errorf("context menu callback not named with underscores:\n%s", content)
+	}
+}
 
-	modal := CommandInfo{
-		Name:        "feedback",
+func TestArgOptionsTemplateParseRoundTrip(t *testing.T) {
+	roll := CommandInfo{
+		Name:        "roll",
 		Scope:       "guild",
-		Type:        "modal",
-		Description: "Collects feedback",
+		Type:        "slash",
+		Description: "Rolls dice",
 		ReturnType:  "None",
-		Fields:      []FieldInfo{{Name: "summary", Label: "Summary", Style: "short", Required: true}},
+		Args: []ArgInfo{
+			{Name: "color", Type: "str", Description: "Dice color", Choices: []ChoiceInfo{{Name: "Red, bright", Value: "red"}, {Name: "Blue", Value: "blue"}}},
+			{Name: "sides", Type: "int", Description: "Sides per die", Optional: true, Default: "6", Min: "2", Max: "100"},
+			{Name: "scale", Type: "float", Description: "Result scale", Optional: true, Min: "0.5"},
+			{Name: "mode", Type: "int", Description: "Roll mode", Optional: true, Default: "1", Choices: []ChoiceInfo{{Name: "Sum", Value: "1"}, {Name: "Max", Value: "2"}}},
+			{Name: "target", Type: "discord.Member", Description: "Who rolls", Optional: true},
+		},
 	}
-
-	withFieldLabel := modal
-	withFieldLabel.Fields = []FieldInfo{{Name: "summary", Label: "Other", Style: "short", Required: true}}
-
-	withFieldRequired := modal
-	withFieldRequired.Fields = []FieldInfo{{Name: "summary", Label: "Summary", Style: "short", Required: false}}
-
-	withExtraField := modal
-	withExtraField.Fields = append(append([]FieldInfo{}, modal.Fields...), FieldInfo{Name: "details", Label: "Details", Style: "paragraph"})
-
-	flow := CommandInfo{
-		Name:        "survey",
-		Scope:       "guild",
-		Type:        "modal",
-		Description: "Runs a survey",
+	wave := CommandInfo{
+		Name:        "wave",
+		Scope:       "global",
+		Type:        "prefix",
+		Description: "Waves",
 		ReturnType:  "None",
-		Pages: []PageInfo{
-			{
-				Name:     "start",
-				Title:    "Start",
-				Fields:   []FieldInfo{{Name: "track", Label: "Track", Style: "short", Required: true}},
-				Branches: []BranchRule{{Field: "track", Equals: "backend", Goto: "start"}},
-			},
+		Args: []ArgInfo{
+			{Name: "greeting", Type: "str", Description: "What to say", Optional: true, Default: "hello, there"},
+			{Name: "loud", Type: "bool", Description: "Shout it", Optional: true, Default: "false"},
 		},
-		Responses: []ResponseInfo{{Type: "message", Content: "done", Ephemeral: true}},
 	}
 
-	withPageTitle := flow
-	withPageTitle.Pages = []PageInfo{flow.Pages[0]}
-	withPageTitle.Pages[0].Title = "Other"
-
-	withBranchGoto := flow
-	withBranchGoto.Pages = []PageInfo{flow.Pages[0]}
-	withBranchGoto.Pages[0].Branches = []BranchRule{{Field: "track", Equals: "backend", Goto: "other"}}
-
-	withResponseContent := flow
-	withResponseContent.Responses = []ResponseInfo{{Type: "message", Content: "changed", Ephemeral: true}}
-
-	withoutResponses := flow
-	withoutResponses.Responses = nil
-
-	tests := []struct {
-		name string
-		a    CommandInfo
-		b    CommandInfo
-		want bool
-	}{
-		{"identical", base, base, true},
-		{"different scope", base, withScope, false},
-		{"different description", base, withDescription, false},
-		{"different return type", base, withReturn, false},
-		{"different arg type", base, withArgType, false},
-		{"different arg description", base, withArgDescription, false},
-		{"extra arg", base, withExtraArg, false},
-		{"identical modal", modal, modal, true},
-		{"different field label", modal, withFieldLabel, false},
-		{"different field required", modal, withFieldRequired, false},
-		{"extra field", modal, withExtraField, false},
-		{"identical flow", flow, flow, true},
-		{"different page title", flow, withPageTitle, false},
-		{"different branch goto", flow, withBranchGoto, false},
-		{"different response content", flow, withResponseContent, false},
-		{"missing responses", flow, withoutResponses, false},
+	for _, command := range []CommandInfo{roll, wave} {
+		if err := ValidateCommand(command, nil); err != nil {
+			t.Fatalf("fixture command %s is invalid: %v", command.Name, err)
+		}
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if got := commandEqual(tt.a, tt.b); got != tt.want {
-				t.Errorf("commandEqual = %v, want %v", got, tt.want)
-			}
-		})
+	content, err := RenderTemplate("cog.py.tmpl", CogTemplateData{
+		Author:         "Austin Choi",
+		BotName:        "TestBot",
+		BotDescription: "A discord bot used by the parser tests",
+		ClassName:      "DiceCog",
+		Filename:       "diceCog",
+		SlashCommands:  []CommandInfo{roll},
+		PrefixCommands: []CommandInfo{wave},
+	})
+	if err != nil {
+		t.Fatalf("RenderTemplate returned error: %v", err)
 	}
-}
 
-func TestCommandsEqual(t *testing.T) {
-	greet := CommandInfo{Name: "greet", Scope: "guild", Type: "slash", Description: "Greets", ReturnType: "None"}
-	echo := CommandInfo{Name: "echo", Scope: "global", Type: "slash", Description: "Echoes", ReturnType: "str"}
-	greetGlobal := greet
-	greetGlobal.Scope = "global"
+	path := filepath.Join(t.TempDir(), "diceCog.py")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write rendered cog: %v", err)
+	}
 
-	tests := []struct {
-		name string
-		a    []CommandInfo
-		b    []CommandInfo
-		want bool
-	}{
-		{"both empty", nil, nil, true},
-		{"same order", []CommandInfo{greet, echo}, []CommandInfo{greet, echo}, true},
-		{"different order", []CommandInfo{greet, echo}, []CommandInfo{echo, greet}, true},
-		{"different length", []CommandInfo{greet}, []CommandInfo{greet, echo}, false},
-		{"one command changed", []CommandInfo{greet, echo}, []CommandInfo{greetGlobal, echo}, false},
-		{"duplicate name hides a different command", []CommandInfo{greet, greet}, []CommandInfo{greet, echo}, false},
-		{"duplicate name against a changed duplicate", []CommandInfo{greet, greet}, []CommandInfo{greet, greetGlobal}, false},
-		{"matching duplicates", []CommandInfo{greet, greet}, []CommandInfo{greet, greet}, true},
+	parsed, err := parseCogFile(path, "diceCog", DefaultLibrary)
+	if err != nil {
+		t.Fatalf("parseCogFile returned error: %v", err)
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if got := commandsEqual(tt.a, tt.b); got != tt.want {
-				t.Errorf("commandsEqual = %v, want %v", got, tt.want)
-			}
-		})
+	if !commandsEqual(parsed.SlashCommands, []CommandInfo{roll}) {
+		t.Errorf("round trip changed the slash commands\ngot:  %+v\nwant: %+v", parsed.SlashCommands, []CommandInfo{roll})
+	}
+	if !commandsEqual(parsed.PrefixCommands, []CommandInfo{wave}) {
+		t.Errorf("round trip changed the prefix commands\ngot:  %+v\nwant: %+v", parsed.PrefixCommands, []CommandInfo{wave})
 	}
 }
 
-// TestSaveConfigRoundTrip checks the config survives a write and leaves no temp file behind
-func TestSaveConfigRoundTrip(t *testing.T) {
-	rootDir := t.TempDir()
-
-	config := Config{
-		BotBox:  BotBoxConfig{Version: "2.5.4"},
-		BotInfo: BotConfig{Name: "TestBot", CommandPrefix: "!", Author: "Austin Choi", Description: "A test bot"},
-		Cogs: []CogConfig{
-			{
-				Name:          "ValidCog",
-				Env:           "development",
-				File:          "validCog",
-				SlashCommands: []CommandInfo{{Name: "greet", Scope: "guild", Type: "slash", Description: "Greets", ReturnType: "None"}},
-			},
+func TestAutocompleteTemplateParseRoundTrip(t *testing.T) {
+	tag := CommandInfo{
+		Name:        "tag",
+		Scope:       "guild",
+		Type:        "slash",
+		Description: "Shows a tag",
+		ReturnType:  "None",
+		Args: []ArgInfo{
+			{Name: "name", Type: "str", Description: "Tag name", Autocomplete: true, Suggestions: []string{"rules", "faq, long"}},
+			{Name: "page", Type: "int", Description: "Page number", Optional: true, Autocomplete: true, Suggestions: []string{"1", "2"}},
 		},
 	}
-
-	if err := saveConfig(rootDir, config); err != nil {
-		t.Fatalf("saveConfig returned error: %v", err)
+	// Both commands share the tickets source, so one source method must serve them
+	ticket := CommandInfo{
+		Name:        "ticket-show",
+		Scope:       "guild",
+		Type:        "slash",
+		Description: "Shows a ticket",
+		ReturnType:  "None",
+		Args:        []ArgInfo{{Name: "ticket", Type: "int", Description: "Ticket id", Autocomplete: true, AutocompleteSource: "tickets"}},
+	}
+	closeTicket := CommandInfo{
+		Name:        "ticket-close",
+		Scope:       "guild",
+		Type:        "slash",
+		Description: "Closes a ticket",
+		ReturnType:  "None",
+		Args:        []ArgInfo{{Name: "ticket", Type: "int", Description: "Ticket id", Autocomplete: true, AutocompleteSource: "tickets"}},
 	}
+	want := []CommandInfo{tag, ticket, closeTicket}
 
-	if _, err := os.Stat(filepath.Join(rootDir, "botbox.conf.tmp")); !os.IsNotExist(err) {
-		t.Errorf("temp config file was left behind: %v", err)
+	content, err := RenderTemplate("cog.py.tmpl", CogTemplateData{
+		Author:         "Austin Choi",
+		BotName:        "TestBot",
+		BotDescription: "A discord bot used by the parser tests",
+		ClassName:      "TagCog",
+		Filename:       "tagCog",
+		SlashCommands:  want,
+	})
+	if err != nil {
+		t.Fatalf("RenderTemplate returned error: %v", err)
+	}
+	if got := strings.Count(content, "async def autocomplete_tickets("); got != 1 {
+		t.Errorf("tickets source method rendered %d times, want 1", got)
+	}
+
+	path := filepath.Join(t.TempDir(), "tagCog.py")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write rendered cog: %v", err)
+	}
+
+	parsed, err := parseCogFile(path, "tagCog", DefaultLibrary)
+	if err != nil {
+		t.Fatalf("parseCogFile returned error: %v", err)
+	}
+
+	if !commandsEqual(parsed.SlashCommands, want) {
+		t.Errorf("round trip changed the commands\ngot:  %+v\nwant: %+v", parsed.SlashCommands, want)
+	}
+}
+
+func TestDiscordArgTypesTemplateParseRoundTrip(t *testing.T) {
+	slash := CommandInfo{
+		Name:        "inspect",
+		Scope:       "guild",
+		Type:        "slash",
+		Description: "Inspects a target",
+		ReturnType:  "None",
+		Args: []ArgInfo{
+			{Name: "target", Type: MentionableArgType, Description: "Member or role"},
+			{Name: "user", Type: "discord.User", Description: "Any user"},
+			{Name: "channel", Type: "discord.TextChannel", Description: "Text channel"},
+			{Name: "voice", Type: "discord.VoiceChannel", Description: "Voice channel"},
+			{Name: "anywhere", Type: "discord.abc.GuildChannel", Description: "Any channel"},
+			{Name: "thread", Type: "discord.Thread", Description: "Thread"},
+			{Name: "file", Type: "discord.Attachment", Description: "Upload"},
+			{Name: "tag", Type: "app_commands.Transform[str, TagTransformer]", Description: "Tag", Optional: true},
+		},
+	}
+	// The prefix command shares the transformer, so one class must serve both command kinds
+	prefix := CommandInfo{
+		Name:        "tag",
+		Scope:       "global",
+		Type:        "prefix",
+		Description: "Shows a tag",
+		ReturnType:  "None",
+		Args: []ArgInfo{
+			{Name: "tag", Type: "app_commands.Transform[str, TagTransformer]", Description: "Tag"},
+			{Name: "member", Type: MentionableArgType, Description: "Member or role"},
+		},
+	}
+
+	content, err := RenderTemplate("cog.py.tmpl", CogTemplateData{
+		Author:         "Austin Choi",
+		BotName:        "TestBot",
+		BotDescription: "A discord bot used by the parser tests",
+		ClassName:      "InspectCog",
+		Filename:       "inspectCog",
+		SlashCommands:  []CommandInfo{slash},
+		PrefixCommands: []CommandInfo{prefix},
+	})
+	if err != nil {
+		t.Fatalf("RenderTemplate returned error: %v", err)
+	}
+	if !strings.Contains(content, "from typing import Union") {
+		t.Errorf("rendered cog is missing the Union import")
+	}
+	if got := strings.Count(content, "class TagTransformer("); got != 1 {
+		t.Errorf("TagTransformer class rendered %d times, want 1", got)
+	}
+	if !strings.Contains(content, "tag: TagTransformer, ") {
+		t.Errorf("prefix command does not annotate with the converter class")
+	}
+
+	path := filepath.Join(t.TempDir(), "inspectCog.py")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write rendered cog: %v", err)
+	}
+
+	parsed, err := parseCogFile(path, "inspectCog", DefaultLibrary)
+	if err != nil {
+		t.Fatalf("parseCogFile returned error: %v", err)
+	}
+
+	if !commandsEqual(parsed.SlashCommands, []CommandInfo{slash}) {
+		t.Errorf("round trip changed the slash commands\ngot:  %+v\nwant: %+v", parsed.SlashCommands, slash)
+	}
+	if !commandsEqual(parsed.PrefixCommands, []CommandInfo{prefix}) {
+		t.Errorf("round trip changed the prefix commands\ngot:  %+v\nwant: %+v", parsed.PrefixCommands, prefix)
+	}
+}
+
+func TestCommandAccessTemplateParseRoundTrip(t *testing.T) {
+	slashCommands := []CommandInfo{
+		{
+			Name:            "purge",
+			Scope:           "guild",
+			Type:            "slash",
+			Description:     "Deletes messages",
+			ReturnType:      "None",
+			Permissions:     []string{"manage_messages", "read_message_history"},
+			Roles:           []string{"Moderator", "123456789012345678"},
+			AllowedInstalls: []string{"guild", "user"},
+			AllowedContexts: []string{"guild", "private_channel"},
+			Cooldown:        &CooldownInfo{Rate: 2, Per: 30, Bucket: "channel"},
+			Args:            []ArgInfo{{Name: "count", Type: "int", Description: "Messages to delete"}},
+		},
+		{
+			Name:        "appeal",
+			Scope:       "guild",
+			Type:        "modal",
+			Description: "Appeals a ban",
+			ReturnType:  "None",
+			GuildOnly:   true,
+			Cooldown:    &CooldownInfo{Rate: 1, Per: 3600, Bucket: "user"},
+			Fields:      []FieldInfo{{Name: "reason", Label: "Reason", Style: "paragraph", Required: true}},
+		},
+		{
+			Name:        "flag-message",
+			Scope:       "guild",
+			Type:        "message_context",
+			Description: "Flags a message",
+			ReturnType:  "None",
+			Permissions: []string{"manage_messages"},
+			GuildOnly:   true,
+		},
+	}
+	prefixCommands := []CommandInfo{
+		{
+			Name:        "warn",
+			Scope:       "global",
+			Type:        "prefix",
+			Description: "Warns a member",
+			ReturnType:  "None",
+			Permissions: []string{"kick_members"},
+			Roles:       []string{"Moderator"},
+			GuildOnly:   true,
+			Cooldown:    &CooldownInfo{Rate: 1, Per: 2.5, Bucket: "guild"},
+			Args:        []ArgInfo{{Name: "member", Type: "discord.Member", Description: "Member to warn"}},
+		},
+	}
+
+	content, err := RenderTemplate("cog.py.tmpl", CogTemplateData{
+		Author:         "Austin Choi",
+		BotName:        "TestBot",
+		BotDescription: "A discord bot used by the parser tests",
+		ClassName:      "ModCog",
+		Filename:       "modCog",
+		SlashCommands:  slashCommands,
+		PrefixCommands: prefixCommands,
+	})
+	if err != nil {
+		t.Fatalf("RenderTemplate returned error: %v", err)
+	}
+	for _, decorator := range []string{
+		`@app_commands.checks.has_any_role("Moderator", 123456789012345678)`,
+		"@app_commands.allowed_installs(guilds=True, users=True)",
+		"@app_commands.checks.cooldown(2, 30, key=lambda i: i.channel_id)",
+		"@commands.has_permissions(kick_members=True)",
+		"@commands.cooldown(1, 2.5, commands.BucketType.guild)",
+	} {
+		if !strings.Contains(content, decorator) {
+			t.Errorf("rendered cog is missing %s", decorator)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "modCog.py")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write rendered cog: %v", err)
+	}
+
+	parsed, err := parseCogFile(path, "modCog", DefaultLibrary)
+	if err != nil {
+		t.Fatalf("parseCogFile returned error: %v", err)
+	}
+
+	if !commandsEqual(parsed.SlashCommands, slashCommands) {
+		t.Errorf("round trip changed the slash commands\ngot:  %+v\nwant: %+v", parsed.SlashCommands, slashCommands)
+	}
+	if !commandsEqual(parsed.PrefixCommands, prefixCommands) {
+		t.Errorf("round trip changed the prefix commands\ngot:  %+v\nwant: %+v", parsed.PrefixCommands, prefixCommands)
+	}
+}
+
+func TestHybridCommandTemplateParseRoundTrip(t *testing.T) {
+	slashCommands := []CommandInfo{
+		{
+			Name:        "remind",
+			Scope:       "guild",
+			Type:        "hybrid",
+			Description: "Sets a reminder",
+			ReturnType:  "None",
+			Permissions: []string{"send_messages"},
+			Cooldown:    &CooldownInfo{Rate: 1, Per: 10, Bucket: "user"},
+			Args: []ArgInfo{
+				{Name: "when", Type: "app_commands.Transform[str, Duration]", Description: "How long to wait"},
+				{Name: "note", Type: "str", Description: "What to remind you of", Optional: true, Default: "stretch"},
+			},
+			Responses: []ResponseInfo{{Type: "message", Content: "Reminder set for {when}"}},
+		},
+		{
+			Name:        "whisper",
+			Scope:       "global",
+			Type:        "hybrid",
+			Description: "Whispers to you",
+			ReturnType:  "None",
+			Responses:   []ResponseInfo{{Type: "dm", Content: "Psst"}},
+		},
+		{
+			Name:        "ping",
+			Scope:       "guild",
+			Type:        "slash",
+			Description: "Pings the bot",
+			ReturnType:  "None",
+		},
+	}
+
+	content, err := RenderTemplate("cog.py.tmpl", CogTemplateData{
+		Author:         "Austin Choi",
+		BotName:        "TestBot",
+		BotDescription: "A discord bot used by the parser tests",
+		ClassName:      "HybridCog",
+		Filename:       "hybridCog",
+		SlashCommands:  slashCommands,
+	})
+	if err != nil {
+		t.Fatalf("RenderTemplate returned error: %v", err)
+	}
+	for _, want := range []string{
+		`@commands.hybrid_command(name="remind", description="Sets a reminder")`,
+		`async def remind(self, ctx: commands.Context, when: Duration, note: str = "stretch") -> None:`,
+		"@commands.has_permissions(send_messages=True)",
+		`await ctx.send("Sent you a direct message", ephemeral=True)`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("rendered cog is missing %s", want)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "hybridCog.py")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write rendered cog: %v", err)
+	}
+
+	parsed, err := parseCogFile(path, "hybridCog", DefaultLibrary)
+	if err != nil {
+		t.Fatalf("parseCogFile returned error: %v", err)
+	}
+
+	if !commandsEqual(parsed.SlashCommands, slashCommands) {
+		t.Errorf("round trip changed the slash commands\ngot:  %+v\nwant: %+v", parsed.SlashCommands, slashCommands)
+	}
+	if len(parsed.PrefixCommands) != 0 {
+		t.Errorf("hybrid commands should not be parsed as prefix commands, got %+v", parsed.PrefixCommands)
+	}
+}
+
+func TestPrefixOptionsTemplateParseRoundTrip(t *testing.T) {
+	// Subcommands come first so the template has to define the group commands ahead of them
+	prefixCommands := []CommandInfo{
+		{
+			Name:        "purge",
+			Scope:       "global",
+			Type:        "prefix",
+			Description: "Purges tags",
+			ReturnType:  "None",
+			Group:       "tag admin",
+			Permissions: []string{"manage_messages"},
+		},
+		{
+			Name:        "create",
+			Scope:       "global",
+			Type:        "prefix",
+			Description: "Creates a tag",
+			ReturnType:  "None",
+			Group:       "tag",
+			Aliases:     []string{"new", "add"},
+			Args: []ArgInfo{
+				{Name: "name", Type: "str", Description: "Tag name"},
+				{Name: "content", Type: "str", Description: "Tag text", Optional: true, Default: "empty", Rest: true},
+			},
+		},
+		{
+			Name:                 "tag",
+			Scope:                "global",
+			Type:                 "prefix",
+			Description:          "Manages tags",
+			ReturnType:           "None",
+			InvokeWithoutCommand: true,
+		},
+		{
+			Name:        "admin",
+			Scope:       "global",
+			Type:        "prefix",
+			Description: "Tag admin tools",
+			ReturnType:  "None",
+			Group:       "tag",
+			Hidden:      true,
+		},
+		{
+			Name:        "remind",
+			Scope:       "global",
+			Type:        "prefix",
+			Description: "Reminds members",
+			ReturnType:  "None",
+			Args: []ArgInfo{
+				{Name: "members", Type: "discord.Member", Description: "Who to remind", Greedy: true},
+				{Name: "after", Type: "app_commands.Transform[str, Duration]", Description: "When", Greedy: true},
+			},
+		},
+	}
+
+	content, err := RenderTemplate("cog.py.tmpl", CogTemplateData{
+		Author:         "Austin Choi",
+		BotName:        "TestBot",
+		BotDescription: "A discord bot used by the parser tests",
+		ClassName:      "TagCog",
+		Filename:       "tagCog",
+		PrefixCommands: prefixCommands,
+	})
+	if err != nil {
+		t.Fatalf("RenderTemplate returned error: %v", err)
+	}
+	for _, want := range []string{
+		"@commands.group(invoke_without_command=True)",
+		`@tag.command(name="create", aliases=["new", "add"])`,
+		`@tag.group(name="admin", hidden=True)`,
+		`@tag_admin.command(name="purge")`,
+		`async def tag_create(self, ctx: commands.Context, name: str, *, content: str = "empty") -> None:`,
+		"members: commands.Greedy[discord.Member], after: commands.Greedy[Duration]",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("rendered cog is missing %s", want)
+		}
+	}
+	if strings.Index(content, "async def tag(") > strings.Index(content, "@tag.command(") ||
+		strings.Index(content, "async def tag_admin(") > strings.Index(content, "@tag_admin.command(") {
+		t.Error("group commands must be defined before their subcommands")
+	}
+
+	path := filepath.Join(t.TempDir(), "tagCog.py")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write rendered cog: %v", err)
+	}
+
+	parsed, err := parseCogFile(path, "tagCog", DefaultLibrary)
+	if err != nil {
+		t.Fatalf("parseCogFile returned error: %v", err)
+	}
+
+	if !commandsEqual(parsed.PrefixCommands, prefixCommands) {
+		t.Errorf("round trip changed the prefix commands\ngot:  %+v\nwant: %+v", parsed.PrefixCommands, prefixCommands)
+	}
+}
+
+func TestResponseTypesTemplateParseRoundTrip(t *testing.T) {
+	embed := &EmbedInfo{
+		Title:     "Stats for {member}",
+		Color:     "#5865F2",
+		Fields:    []EmbedFieldInfo{{Name: "Joined", Value: "{member.joined_at}", Inline: true}, {Name: "Roles", Value: "{len(member.roles)}", Inline: true}},
+		Footer:    "Requested by {interaction.user}",
+		Thumbnail: "https://example.com/icon.png",
+	}
+	slashCommands := []CommandInfo{
+		{
+			Name:        "stats",
+			Scope:       "guild",
+			Type:        "slash",
+			Description: "Shows member stats",
+			ReturnType:  "None",
+			Args:        []ArgInfo{{Name: "member", Type: "discord.Member", Description: "Member to show"}},
+			Responses:   []ResponseInfo{{Type: "embed", Content: "Here is what I found", Ephemeral: true, Embed: embed}},
+		},
+		{
+			Name:        "banner",
+			Scope:       "global",
+			Type:        "slash",
+			Description: "Shows a title only embed",
+			ReturnType:  "None",
+			Responses:   []ResponseInfo{{Type: "embed", Embed: &EmbedInfo{Title: "Welcome"}}},
+		},
+		{
+			Name:        "remind",
+			Scope:       "global",
+			Type:        "slash",
+			Description: "Sends a reminder",
+			ReturnType:  "None",
+			Responses:   []ResponseInfo{{Type: "dm", Content: "Remember to vote"}},
+		},
+		{
+			Name:        "export",
+			Scope:       "guild",
+			Type:        "slash",
+			Description: "Exports the server data",
+			ReturnType:  "None",
+			Responses:   []ResponseInfo{{Type: "defer", Content: "Export finished", Ephemeral: true}},
+		},
+		{
+			Name:        "report-message",
+			Scope:       "guild",
+			Type:        "message_context",
+			Description: "Reports a message",
+			ReturnType:  "None",
+			Responses:   []ResponseInfo{{Type: "channel", Content: "{interaction.user} reported {message.jump_url}", ChannelID: "123456789012345678"}},
+		},
+	}
+	prefixCommands := []CommandInfo{
+		{
+			Name:        "info",
+			Scope:       "global",
+			Type:        "prefix",
+			Description: "Shows bot info",
+			ReturnType:  "None",
+			Responses:   []ResponseInfo{{Type: "embed", Content: "A bot", Embed: &EmbedInfo{Color: "#00ff00", Footer: "v1"}}},
+		},
+		{
+			Name:        "dmhelp",
+			Scope:       "global",
+			Type:        "prefix",
+			Description: "DMs the help text",
+			ReturnType:  "None",
+			Responses:   []ResponseInfo{{Type: "dm", Content: "Here is the help"}},
+		},
+		{
+			Name:        "announce",
+			Scope:       "global",
+			Type:        "prefix",
+			Description: "Posts an announcement",
+			ReturnType:  "None",
+			Args:        []ArgInfo{{Name: "text", Type: "str", Description: "Announcement"}},
+			Responses:   []ResponseInfo{{Type: "channel", Content: "{text}", ChannelID: "876543210987654321"}},
+		},
+		{
+			Name:        "crunch",
+			Scope:       "global",
+			Type:        "prefix",
+			Description: "Crunches numbers",
+			ReturnType:  "None",
+			Responses:   []ResponseInfo{{Type: "defer", Content: "Done crunching"}},
+		},
+	}
+
+	content, err := RenderTemplate("cog.py.tmpl", CogTemplateData{
+		Author:         "Austin Choi",
+		BotName:        "TestBot",
+		BotDescription: "A discord bot used by the parser tests",
+		ClassName:      "InfoCog",
+		Filename:       "infoCog",
+		SlashCommands:  slashCommands,
+		PrefixCommands: prefixCommands,
+	})
+	if err != nil {
+		t.Fatalf("RenderTemplate returned error: %v", err)
+	}
+	for _, snippet := range []string{
+		`embed = discord.Embed(title=f"Stats for {member}", description=f"Here is what I found", colour=discord.Colour(0x5865F2))`,
+		"await interaction.response.defer(ephemeral=True, thinking=True)",
+		"channel = interaction.client.get_channel(123456789012345678) or await interaction.client.fetch_channel(123456789012345678)",
+		`await ctx.author.send(f"Here is the help")`,
+		"async with ctx.typing():",
+	} {
+		if !strings.Contains(content, snippet) {
+			t.Errorf("rendered cog is missing %s", snippet)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "infoCog.py")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write rendered cog: %v", err)
+	}
+
+	parsed, err := parseCogFile(path, "infoCog", DefaultLibrary)
+	if err != nil {
+		t.Fatalf("parseCogFile returned error: %v", err)
+	}
+
+	if !commandsEqual(parsed.SlashCommands, slashCommands) {
+		t.Errorf("round trip changed the slash commands\ngot:  %+v\nwant: %+v", parsed.SlashCommands, slashCommands)
+	}
+	if !commandsEqual(parsed.PrefixCommands, prefixCommands) {
+		t.Errorf("round trip changed the prefix commands\ngot:  %+v\nwant: %+v", parsed.PrefixCommands, prefixCommands)
+	}
+}
+
+func TestListenerTemplateParseRoundTrip(t *testing.T) {
+	listeners := []ListenerInfo{
+		{Event: "on_ready", Action: "log"},
+		{Event: "on_member_join", Action: "message", ChannelID: "123456789012345678", Content: "Welcome {member.mention}!"},
+		{Event: "on_raw_reaction_add", Action: "role", RoleID: "223456789012345678"},
+		{Event: "on_message"},
+	}
+
+	content, err := RenderTemplate("cog.py.tmpl", CogTemplateData{
+		Author:         "Austin Choi",
+		BotName:        "TestBot",
+		BotDescription: "A discord bot used by the parser tests",
+		ClassName:      "EventsCog",
+		Filename:       "eventsCog",
+		Listeners:      listeners,
+	})
+	if err != nil {
+		t.Fatalf("RenderTemplate returned error: %v", err)
+	}
+	for _, snippet := range []string{
+		"async def on_member_join(self, member: discord.Member) -> None:",
+		"async def on_raw_reaction_add(self, payload: discord.RawReactionActionEvent) -> None:",
+		"member = payload.member",
+		"if message.author.bot:",
+	} {
+		if !strings.Contains(content, snippet) {
+			t.Errorf("rendered cog is missing %s", snippet)
+		}
+	}
+
+	// A hand written listener can name its event in the decorator, unknown events are left alone
+	content = strings.Replace(content, "\n\nasync def setup(bot):", `
+    @commands.Cog.listener("on_guild_join")
+    async def welcome_guild(self, guild: discord.Guild) -> None:
+        pass
+
+    @commands.Cog.listener()
+    async def on_thread_create(self, thread: discord.Thread) -> None:
+        pass
+
+
+async def setup(bot):`, 1)
+
+	path := filepath.Join(t.TempDir(), "eventsCog.py")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write rendered cog: %v", err)
+	}
+
+	parsed, err := parseCogFile(path, "eventsCog", DefaultLibrary)
+	if err != nil {
+		t.Fatalf("parseCogFile returned error: %v", err)
+	}
+
+	want := append(listeners, ListenerInfo{Event: "on_guild_join"})
+	if !slices.Equal(parsed.Listeners, want) {
+		t.Errorf("round trip changed the listeners\ngot:  %+v\nwant: %+v", parsed.Listeners, want)
+	}
+
+	existing := CogConfig{Name: "EventsCog", File: "eventsCog"}
+	if !updateCogConfig(&existing, *parsed) {
+		t.Error("updateCogConfig should report the detected listeners as a change")
+	}
+	if !slices.Equal(existing.Listeners, want) {
+		t.Errorf("updateCogConfig kept %+v, want %+v", existing.Listeners, want)
+	}
+}
+
+func TestTaskTemplateParseRoundTrip(t *testing.T) {
+	tasks := []TaskInfo{
+		{Name: "heartbeat", Interval: 30, Unit: "seconds"},
+		{Name: "digest", Times: []string{"09:00", "21:30"}, Timezone: "America/New_York", ChannelID: "123456789012345678", Content: "Daily digest from {self.bot.user}"},
+		{Name: "cleanup", Times: []string{"03:05"}, ChannelID: "876543210987654321"},
+	}
+	slashCommands := []CommandInfo{
+		{Name: "report", Scope: "guild", Type: "message_context", Description: "Reports a message", ReturnType: "None"},
+	}
+
+	content, err := RenderTemplate("cog.py.tmpl", CogTemplateData{
+		Author:         "Austin Choi",
+		BotName:        "TestBot",
+		BotDescription: "A discord bot used by the parser tests",
+		ClassName:      "ScheduleCog",
+		Filename:       "scheduleCog",
+		SlashCommands:  slashCommands,
+		Tasks:          tasks,
+	})
+	if err != nil {
+		t.Fatalf("RenderTemplate returned error: %v", err)
+	}
+	for _, snippet := range []string{
+		"from discord.ext import commands, tasks",
+		"from zoneinfo import ZoneInfo",
+		"@tasks.loop(seconds=30)",
+		`@tasks.loop(time=[datetime.time(hour=9, minute=0, tzinfo=ZoneInfo("America/New_York")), datetime.time(hour=21, minute=30, tzinfo=ZoneInfo("America/New_York"))])`,
+		"@tasks.loop(time=[datetime.time(hour=3, minute=5, tzinfo=datetime.timezone.utc)])",
+		"    @digest.before_loop\n    async def before_digest(self) -> None:\n        await self.bot.wait_until_ready()",
+		"        self.heartbeat.start()",
+		"        self.cleanup.cancel()",
+		"self.bot.tree.remove_command(self.report_menu.name",
+	} {
+		if !strings.Contains(content, snippet) {
+			t.Errorf("rendered cog is missing %s", snippet)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "scheduleCog.py")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write rendered cog: %v", err)
+	}
+
+	parsed, err := parseCogFile(path, "scheduleCog", DefaultLibrary)
+	if err != nil {
+		t.Fatalf("parseCogFile returned error: %v", err)
+	}
+
+	if !slices.EqualFunc(parsed.Tasks, tasks, taskEqual) {
+		t.Errorf("round trip changed the tasks\ngot:  %+v\nwant: %+v", parsed.Tasks, tasks)
+	}
+	if !commandsEqual(parsed.SlashCommands, slashCommands) {
+		t.Errorf("round trip changed the slash commands\ngot:  %+v\nwant: %+v", parsed.SlashCommands, slashCommands)
+	}
+
+	existing := CogConfig{Name: "ScheduleCog", File: "scheduleCog", SlashCommands: slashCommands, Tasks: tasks}
+	if updateCogConfig(&existing, *parsed) {
+		t.Error("updateCogConfig should not report a change for unchanged tasks")
+	}
+}
+
+func TestComponentCommandTemplateParseRoundTrip(t *testing.T) {
+	slashCommands := []CommandInfo{
+		{
+			Name:        "roles",
+			Scope:       "guild",
+			Type:        "component",
+			Description: "Hands out notification roles",
+			ReturnType:  "None",
+			Args:        []ArgInfo{{Name: "note", Type: "str", Description: "Note shown above the menu", Optional: true, Default: "Pick your roles"}},
+			Responses:   []ResponseInfo{{Type: "message", Content: "{note}"}},
+			Components: &ComponentsInfo{
+				Timeout:    300,
+				AuthorOnly: true,
+				Items: []ComponentInfo{
+					{Name: "confirm", Type: "button", Label: "Confirm", Style: "success", Response: ResponseInfo{Type: "message", Content: "Saved your roles", Ephemeral: true}},
+					{Name: "cancel", Type: "button", Label: "Cancel", Style: "danger", Response: ResponseInfo{Type: "message", Content: "Nothing changed"}},
+					{Name: "topics", Type: "select", Label: "Pick a topic", Options: []SelectOptionInfo{
+						{Label: "News", Value: "news", Response: ResponseInfo{Type: "message", Content: "Subscribed to news", Ephemeral: true}},
+						{Label: "Events", Value: "events", Response: ResponseInfo{Type: "message", Content: "Subscribed to events"}},
+					}},
+				},
+			},
+		},
+		{
+			Name:        "ping",
+			Scope:       "global",
+			Type:        "component",
+			Description: "Pings with a button",
+			ReturnType:  "None",
+			Components: &ComponentsInfo{
+				Timeout: 0,
+				Items: []ComponentInfo{
+					{Name: "pong", Type: "button", Label: "Pong", Style: "primary", Response: ResponseInfo{Type: "message", Content: "Pong!"}},
+				},
+			},
+		},
+	}
+
+	content, err := RenderTemplate("cog.py.tmpl", CogTemplateData{
+		Author:         "Austin Choi",
+		BotName:        "TestBot",
+		BotDescription: "A discord bot used by the parser tests",
+		ClassName:      "RolesCog",
+		Filename:       "rolesCog",
+		SlashCommands:  slashCommands,
+	})
+	if err != nil {
+		t.Fatalf("RenderTemplate returned error: %v", err)
+	}
+	for _, snippet := range []string{
+		"class RolesView(discord.ui.View):",
+		"view = RolesView(interaction.user.id)",
+		"view.message = await interaction.original_response()",
+	} {
+		if !strings.Contains(content, snippet) {
+			t.Errorf("rendered cog is missing %s", snippet)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "rolesCog.py")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write rendered cog: %v", err)
+	}
+
+	parsed, err := parseCogFile(path, "rolesCog", DefaultLibrary)
+	if err != nil {
+		t.Fatalf("parseCogFile returned error: %v", err)
+	}
+
+	if !commandsEqual(parsed.SlashCommands, slashCommands) {
+		t.Errorf("round trip changed the slash commands\ngot:  %+v\nwant: %+v", parsed.SlashCommands, slashCommands)
+	}
+}
+
+func TestGroupCommandTemplateParseRoundTrip(t *testing.T) {
+	open := CommandInfo{
+		Name:             "open",
+		Scope:            "guild",
+		Type:             "slash",
+		Description:      "Opens a ticket",
+		Group:            "ticket",
+		GroupDescription: "Ticket tools",
+		ReturnType:       "None",
+		Args:             []ArgInfo{{Name: "topic", Type: "str", Description: "What the ticket is about"}},
+	}
+	// The admin subgroup has no description, so the generated default must parse back to empty
+	purge := CommandInfo{
+		Name:        "purge",
+		Scope:       "guild",
+		Type:        "modal",
+		Description: "Purges closed tickets",
+		Group:       "ticket admin",
+		ReturnType:  "None",
+		Fields:      []FieldInfo{{Name: "reason", Label: "Reason", Style: "short", Required: true}},
+	}
+	ping := CommandInfo{
+		Name:        "ping",
+		Scope:       "global",
+		Type:        "slash",
+		Description: "Pings the bot",
+		ReturnType:  "None",
+	}
+	want := []CommandInfo{open, purge, ping}
+
+	content, err := RenderTemplate("cog.py.tmpl", CogTemplateData{
+		Author:         "Austin Choi",
+		BotName:        "TestBot",
+		BotDescription: "A discord bot used by the parser tests",
+		ClassName:      "TicketCog",
+		Filename:       "ticketCog",
+		SlashCommands:  want,
+	})
+	if err != nil {
+		t.Fatalf("RenderTemplate returned error: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "ticketCog.py")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write rendered cog: %v", err)
+	}
+
+	parsed, err := parseCogFile(path, "ticketCog", DefaultLibrary)
+	if err != nil {
+		t.Fatalf("parseCogFile returned error: %v", err)
+	}
+
+	if !commandsEqual(parsed.SlashCommands, want) {
+		t.Errorf("round trip changed the commands\ngot:  %+v\nwant: %+v", parsed.SlashCommands, want)
+	}
+}
+
+func TestCommandEqual(t *testing.T) {
+	base := CommandInfo{
+		Name:        "greet",
+		Scope:       "guild",
+		Type:        "slash",
+		Description: "Greets a member",
+		ReturnType:  "str",
+		Args:        []ArgInfo{{Name: "member", Type: "discord.Member", Description: "The member to greet"}},
+	}
+
+	withScope := base
+	withScope.Scope = "global"
+
+	withDescription := base
+	withDescription.Description = "Greets everyone"
+
+	withReturn := base
+	withReturn.ReturnType = "None"
+
+	withArgType := base
+	withArgType.Args = []ArgInfo{{Name: "member", Type: "str", Description: "The member to greet"}}
+
+	withArgDescription := base
+	withArgDescription.Args = []ArgInfo{{Name: "member", Type: "discord.Member", Description: "Someone"}}
+
+	withExtraArg := base
+	withExtraArg.Args = append(append([]ArgInfo{}, base.Args...), ArgInfo{Name: "times", Type: "int"})
+
+	modal := CommandInfo{
+		Name:        "feedback",
+		Scope:       "guild",
+		Type:        "modal",
+		Description: "Collects feedback",
+		ReturnType:  "None",
+		Fields:      []FieldInfo{{Name: "summary", Label: "Summary", Style: "short", Required: true}},
+	}
+
+	withFieldLabel := modal
+	withFieldLabel.Fields = []FieldInfo{{Name: "summary", Label: "Other", Style: "short", Required: true}}
+
+	withFieldRequired := modal
+	withFieldRequired.Fields = []FieldInfo{{Name: "summary", Label: "Summary", Style: "short", Required: false}}
+
+	withExtraField := modal
+	withExtraField.Fields = append(append([]FieldInfo{}, modal.Fields...), FieldInfo{Name: "details", Label: "Details", Style: "paragraph"})
+
+	flow := CommandInfo{
+		Name:        "survey",
+		Scope:       "guild",
+		Type:        "modal",
+		Description: "Runs a survey",
+		ReturnType:  "None",
+		Pages: []PageInfo{
+			{
+				Name:     "start",
+				Title:    "Start",
+				Fields:   []FieldInfo{{Name: "track", Label: "Track", Style: "short", Required: true}},
+				Branches: []BranchRule{{Field: "track", Equals: "backend", Goto: "start"}},
+			},
+		},
+		Responses: []ResponseInfo{{Type: "message", Content: "done", Ephemeral: true}},
+	}
+
+	withPageTitle := flow
+	withPageTitle.Pages = []PageInfo{flow.Pages[0]}
+	withPageTitle.Pages[0].Title = "Other"
+
+	withBranchGoto := flow
+	withBranchGoto.Pages = []PageInfo{flow.Pages[0]}
+	withBranchGoto.Pages[0].Branches = []BranchRule{{Field: "track", Equals: "backend", Goto: "other"}}
+
+	withResponseContent := flow
+	withResponseContent.Responses = []ResponseInfo{{Type: "message", Content: "changed", Ephemeral: true}}
+
+	withoutResponses := flow
+	withoutResponses.Responses = nil
+
+	tests := []struct {
+		name string
+		a    CommandInfo
+		b    CommandInfo
+		want bool
+	}{
+		{"identical", base, base, true},
+		{"different scope", base, withScope, false},
+		{"different description", base, withDescription, false},
+		{"different return type", base, withReturn, false},
+		{"different arg type", base, withArgType, false},
+		{"different arg description", base, withArgDescription, false},
+		{"extra arg", base, withExtraArg, false},
+		{"identical modal", modal, modal, true},
+		{"different field label", modal, withFieldLabel, false},
+		{"different field required", modal, withFieldRequired, false},
+		{"extra field", modal, withExtraField, false},
+		{"identical flow", flow, flow, true},
+		{"different page title", flow, withPageTitle, false},
+		{"different branch goto", flow, withBranchGoto, false},
+		{"different response content", flow, withResponseContent, false},
+		{"missing responses", flow, withoutResponses, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := commandEqual(tt.a, tt.b); got != tt.want {
+				t.Errorf("commandEqual = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommandsEqual(t *testing.T) {
+	greet := CommandInfo{Name: "greet", Scope: "guild", Type: "slash", Description: "Greets", ReturnType: "None"}
+	echo := CommandInfo{Name: "echo", Scope: "global", Type: "slash", Description: "Echoes", ReturnType: "str"}
+	greetGlobal := greet
+	greetGlobal.Scope = "global"
+
+	tests := []struct {
+		name string
+		a    []CommandInfo
+		b    []CommandInfo
+		want bool
+	}{
+		{"both empty", nil, nil, true},
+		{"same order", []CommandInfo{greet, echo}, []CommandInfo{greet, echo}, true},
+		{"different order", []CommandInfo{greet, echo}, []CommandInfo{echo, greet}, true},
+		{"different length", []CommandInfo{greet}, []CommandInfo{greet, echo}, false},
+		{"one command changed", []CommandInfo{greet, echo}, []CommandInfo{greetGlobal, echo}, false},
+		{"duplicate name hides a different command", []CommandInfo{greet, greet}, []CommandInfo{greet, echo}, false},
+		{"duplicate name against a changed duplicate", []CommandInfo{greet, greet}, []CommandInfo{greet, greetGlobal}, false},
+		{"matching duplicates", []CommandInfo{greet, greet}, []CommandInfo{greet, greet}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := commandsEqual(tt.a, tt.b); got != tt.want {
+				t.Errorf("commandsEqual = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSaveConfigRoundTrip checks the config survives a write and leaves no temp file behind
+func TestSaveConfigRoundTrip(t *testing.T) {
+	rootDir := t.TempDir()
+
+	config := Config{
+		BotBox:  BotBoxConfig{Version: "2.5.4"},
+		BotInfo: BotConfig{Name: "TestBot", CommandPrefix: "!", Author: "Austin Choi", Description: "A test bot"},
+		Cogs: []CogConfig{
+			{
+				Name:          "ValidCog",
+				Env:           "development",
+				File:          "validCog",
+				SlashCommands: []CommandInfo{{Name: "greet", Scope: "guild", Type: "slash", Description: "Greets", ReturnType: "None"}},
+			},
+		},
+	}
+
+	if err := saveConfig(rootDir, config); err != nil {
+		t.Fatalf("saveConfig returned error: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(rootDir, "botbox.conf.tmp")); !os.IsNotExist(err) {
+		t.Errorf("temp config file was left behind: %v", err)
 	}
 
 	var reloaded Config
@@ -840,7 +1914,7 @@ func TestSyncCogsWithConfigKeepsCogsWhenNoneParsed(t *testing.T) {
 		t.Fatalf("SyncCogsWithConfig returned error: %v", err)
 	}
 
-	if len(result.Errors) == 0 {
+	if len(result.Errors()) == 0 {
 		t.Errorf("expected a sync error explaining that nothing was parsed")
 	}
 
@@ -870,8 +1944,8 @@ func TestSyncCogsWithConfigAddsParsedCogs(t *testing.T) {
 		t.Fatalf("SyncCogsWithConfig returned error: %v", err)
 	}
 
-	if len(result.Errors) != 0 {
-		t.Errorf("sync errors = %v, want none", result.Errors)
+	if len(result.Errors()) != 0 {
+		t.Errorf("sync errors = %v, want none", result.Errors())
 	}
 
 	if len(result.HeaderIssues) != 0 {
@@ -898,6 +1972,180 @@ func TestSyncCogsWithConfigAddsParsedCogs(t *testing.T) {
 	}
 }
 
+// TestStageCogSyncWritesNothing covers the dry run path, the config change is only staged
+func TestStageCogSyncWritesNothing(t *testing.T) {
+	rootDir := newTestProject(t, Config{
+		BotBox:  BotBoxConfig{Version: "2.5.4"},
+		BotInfo: BotConfig{Name: "TestBot", Author: "Austin Choi", Description: "A discord bot used by the parser tests"},
+	})
+	copyFixtureCog(t, "validCog", rootDir)
+
+	before, err := os.ReadFile(filepath.Join(rootDir, "botbox.conf"))
+	if err != nil {
+		t.Fatalf("failed to read botbox.conf: %v", err)
+	}
+
+	changes := &ChangeSet{}
+	result, err := StageCogSync(changes)
+	if err != nil {
+		t.Fatalf("StageCogSync returned error: %v", err)
+	}
+	if !reflect.DeepEqual(result.AddedCogs, []string{"validCog"}) {
+		t.Errorf("added cogs = %v, want [validCog]", result.AddedCogs)
+	}
+
+	after, err := os.ReadFile(filepath.Join(rootDir, "botbox.conf"))
+	if err != nil {
+		t.Fatalf("failed to read botbox.conf: %v", err)
+	}
+	if string(after) != string(before) {
+		t.Error("StageCogSync wrote botbox.conf")
+	}
+	if diff := changes.Diff(rootDir); !strings.Contains(diff, "+++ b/botbox.conf") || !strings.Contains(diff, `+      "file": "validCog",`) {
+		t.Errorf("staged diff does not add the cog to botbox.conf:\n%s", diff)
+	}
+}
+
+// TestCommandChanges checks that drift is reported per command, matched by type and path
+func TestCommandChanges(t *testing.T) {
+	configured := []CommandInfo{
+		{Name: "ping", Type: "slash", Description: "Pings"},
+		{Name: "open", Type: "slash", Group: "ticket", Description: "Opens a ticket"},
+		{Name: "ping", Type: "prefix", Description: "Pings"},
+	}
+	parsed := []CommandInfo{
+		{Name: "ping", Type: "slash", Description: "Pings"},
+		{Name: "open", Type: "slash", Group: "ticket", Description: "Opens a support ticket"},
+		{Name: "close", Type: "slash", Group: "ticket", Description: "Closes a ticket"},
+	}
+
+	got := commandChanges("support", configured, parsed)
+	want := []string{
+		`support: slash command "ticket open" changed`,
+		`support: slash command "ticket close" added`,
+		`support: prefix command "ping" removed`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("commandChanges = %q, want %q", got, want)
+	}
+
+	if got := commandChanges("support", configured, configured); len(got) != 0 {
+		t.Errorf("commandChanges of identical commands = %q, want none", got)
+	}
+}
+
+// TestStageCogSyncReportsCommandChanges covers the drift a sync --check prints
+func TestStageCogSyncReportsCommandChanges(t *testing.T) {
+	rootDir := newTestProject(t, Config{
+		BotBox:  BotBoxConfig{Version: "2.5.4"},
+		BotInfo: BotConfig{Name: "TestBot", Author: "Austin Choi", Description: "A discord bot used by the parser tests"},
+		Cogs: []CogConfig{
+			{Name: "GoneCog", Env: "development", File: "gone", SlashCommands: []CommandInfo{{Name: "old", Type: "slash", Description: "Old"}}},
+		},
+	})
+	copyFixtureCog(t, "validCog", rootDir)
+
+	result, err := StageCogSync(&ChangeSet{})
+	if err != nil {
+		t.Fatalf("StageCogSync returned error: %v", err)
+	}
+
+	want := []string{
+		`validCog: slash command "greet" added`,
+		`validCog: prefix command "ping" added`,
+		`gone: slash command "old" removed`,
+	}
+	if !reflect.DeepEqual(result.CommandChanges, want) {
+		t.Errorf("command changes = %q, want %q", result.CommandChanges, want)
+	}
+	if !reflect.DeepEqual(result.RemovedCogs, []string{"gone"}) {
+		t.Errorf("removed cogs = %v, want [gone]", result.RemovedCogs)
+	}
+}
+
+func TestUpdateLocaleFiles(t *testing.T) {
+	rootDir := t.TempDir()
+	config := Config{Cogs: []CogConfig{{
+		File: "ticketCog",
+		SlashCommands: []CommandInfo{
+			{
+				Name:                     "open",
+				Type:                     "slash",
+				Description:              "Opens a ticket",
+				Group:                    "ticket",
+				DescriptionLocalizations: map[string]string{"de": "Öffnet ein Ticket"},
+			},
+			{
+				Name:        "feedback",
+				Type:        "modal",
+				Description: "Sends feedback",
+				Fields:      []FieldInfo{{Name: "summary", Label: "Summary", LabelLocalizations: map[string]string{"de": "Zusammenfassung"}}},
+			},
+		},
+		PrefixCommands: []CommandInfo{{Name: "ping", Type: "prefix", Description: "Pings"}},
+	}}}
+
+	generator, err := GeneratorFor(config.BotInfo.Library)
+	if err != nil {
+		t.Fatalf("GeneratorFor returned error: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(rootDir, generator.LocalesDir()), 0755); err != nil {
+		t.Fatalf("failed to create locales directory: %v", err)
+	}
+	existing := `{"commands.ticket.open.description": "Ouvre un ticket", "commands.ticket.name": "billet"}`
+	if err := os.WriteFile(LocaleFilePath(generator, rootDir, "fr"), []byte(existing), 0644); err != nil {
+		t.Fatalf("failed to write fr.json: %v", err)
+	}
+
+	changes := &ChangeSet{}
+	added, err := UpdateLocaleFiles(changes, rootDir, config, []string{"ja"}, false)
+	if err != nil {
+		t.Fatalf("UpdateLocaleFiles returned error: %v", err)
+	}
+	// Locale files are only staged until the change set is applied
+	if _, err := os.Stat(LocaleFilePath(generator, rootDir, "ja")); !os.IsNotExist(err) {
+		t.Errorf("ja.json should not exist before Apply, stat error = %v", err)
+	}
+	if err := changes.Apply(); err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+	// Two group keys, two for open, two for feedback, and the modal label, prefix commands are never translated
+	if !reflect.DeepEqual(added, map[string]int{"de": 7, "fr": 5, "ja": 7}) {
+		t.Errorf("added = %v", added)
+	}
+
+	var french, german map[string]string
+	readJSONFile(t, LocaleFilePath(generator, rootDir, "fr"), &french)
+	readJSONFile(t, LocaleFilePath(generator, rootDir, "de"), &german)
+	if french["commands.ticket.open.description"] != "Ouvre un ticket" || french["commands.ticket.name"] != "billet" {
+		t.Errorf("fr.json lost existing translations: %v", french)
+	}
+	if german["commands.feedback.fields.summary.label"] != "Zusammenfassung" || german["commands.ticket.open.name"] != "" {
+		t.Errorf("de.json = %v", german)
+	}
+	if _, exists := german["commands.ping.name"]; exists {
+		t.Error("prefix commands must not get translation keys")
+	}
+
+	// Overwriting lets the config replace text in the files but leaves keys the config does not translate
+	config.Cogs[0].SlashCommands[0].DescriptionLocalizations["fr"] = "Ouvre un nouveau ticket"
+	changes = &ChangeSet{}
+	if _, err := UpdateLocaleFiles(changes, rootDir, config, nil, true); err != nil {
+		t.Fatalf("UpdateLocaleFiles returned error: %v", err)
+	}
+	if err := changes.Apply(); err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+	readJSONFile(t, LocaleFilePath(generator, rootDir, "fr"), &french)
+	if french["commands.ticket.open.description"] != "Ouvre un nouveau ticket" || french["commands.ticket.name"] != "billet" {
+		t.Errorf("fr.json after overwrite = %v", french)
+	}
+
+	if _, err := UpdateLocaleFiles(&ChangeSet{}, rootDir, config, []string{"klingon"}, false); err == nil {
+		t.Error("expected an error for an unknown locale")
+	}
+}
+
 // newTestProject writes a botbox project into a temp dir and makes it the working directory
 func newTestProject(t *testing.T, config Config) string {
 	t.Helper()
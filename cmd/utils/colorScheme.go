@@ -37,6 +37,8 @@ type Styles struct {
 	ValueText,
 	Help lipgloss.Style
 	FooterText lipgloss.Style
+	DiffAdded,
+	DiffRemoved lipgloss.Style
 }
 
 func SetColorScheme(scheme string) {
@@ -96,6 +98,10 @@ func NewStyles(lg *lipgloss.Renderer) *Styles {
 		Foreground(lipgloss.Color("240")).
 		Bold(true).
 		Padding(0, 1, 0, 2)
+	s.DiffAdded = lg.NewStyle().
+		Foreground(green)
+	s.DiffRemoved = lg.NewStyle().
+		Foreground(red)
 	return &s
 }
 
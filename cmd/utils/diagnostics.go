@@ -0,0 +1,123 @@
+/*
+Copyright © 2025 Austin "Choice404" Choi
+See end of file for extended copyright information
+*/
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Diagnostic severities, an error stops a sync from writing botbox.conf while a warning only reports
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Diagnostic codes, stable so scripts can match on them
+const (
+	// DiagSyntax is a cog file that is not valid Python
+	DiagSyntax = "syntax"
+	// DiagNoCogs is a sync that found no cog files while botbox.conf still lists some
+	DiagNoCogs = "no-cogs"
+	// DiagUnnamedCommand is a command decorator without a name and description written as string literals
+	DiagUnnamedCommand = "unnamed-command"
+	// DiagNotCoroutine is a command, listener, or task defined with def rather than async def
+	DiagNotCoroutine = "not-coroutine"
+	// DiagUnknownGroup is a command registered on something that is not a group declared in the cog
+	DiagUnknownGroup = "unknown-group"
+	// DiagUntypedArgument is a command argument without a type annotation
+	DiagUntypedArgument = "untyped-argument"
+	// DiagUnknownArgument is a decorator describing an argument the command does not take
+	DiagUnknownArgument = "unknown-argument"
+	// DiagArgumentType is a command argument whose type botbox cannot generate
+	DiagArgumentType = "argument-type"
+	// DiagUndescribedArgument is a command argument without a description
+	DiagUndescribedArgument = "undescribed-argument"
+	// DiagContextMenu is a context menu whose callback cannot be read
+	DiagContextMenu = "context-menu"
+	// DiagModalClass is a send_modal call that does not name the modal class it opens
+	DiagModalClass = "modal-class"
+	// DiagTaskSchedule is a task loop scheduled in a way botbox.conf cannot hold
+	DiagTaskSchedule = "task-schedule"
+)
+
+// Diagnostic is a problem found in a project file, Line and Column are 1 based and 0 when the problem has no position
+type Diagnostic struct {
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Severity string `json:"severity"`
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+}
+
+// String renders the diagnostic the way compilers do, file:line:column: severity: message [code]
+func (d Diagnostic) String() string {
+	position := d.File
+	if d.Line > 0 {
+		position += fmt.Sprintf(":%d", d.Line)
+		if d.Column > 0 {
+			position += fmt.Sprintf(":%d", d.Column)
+		}
+	}
+	return fmt.Sprintf("%s: %s: %s [%s]", position, d.Severity, d.Message, d.Code)
+}
+
+// syntaxDiagnostic turns a parse failure of file into an error diagnostic, with its position when the parser gave one
+func syntaxDiagnostic(file string, err error) Diagnostic {
+	diagnostic := Diagnostic{File: file, Severity: SeverityError, Code: DiagSyntax, Message: err.Error()}
+	var syntaxErr *PySyntaxError
+	if errors.As(err, &syntaxErr) {
+		diagnostic.Line = syntaxErr.Line
+		diagnostic.Column = syntaxErr.Column
+		diagnostic.Message = syntaxErr.Message
+	}
+	return diagnostic
+}
+
+// filterDiagnostics returns the diagnostics of one severity
+func filterDiagnostics(diagnostics []Diagnostic, severity string) []Diagnostic {
+	var filtered []Diagnostic
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity == severity {
+			filtered = append(filtered, diagnostic)
+		}
+	}
+	return filtered
+}
+
+/*
+Copyright © 2025 Austin "Choice404" Choi
+
+https://github.com/choice404/botbox
+
+Bot Box
+A discord bot template generator to help create discord bots quickly and easily
+
+This code is licensed under the MIT License.
+
+MIT License
+
+Copyright (c) 2025 Austin Choi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
@@ -0,0 +1,204 @@
+/*
+Copyright © 2025 Austin "Choice404" Choi
+See end of file for extended copyright information
+*/
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestDiagnosticString checks the compiler style rendering, with and without a position
+func TestDiagnosticString(t *testing.T) {
+	tests := []struct {
+		diagnostic Diagnostic
+		want       string
+	}{
+		{
+			Diagnostic{File: "src/cogs/admin.py", Line: 12, Column: 5, Severity: SeverityError, Code: DiagSyntax, Message: "'(' was never closed"},
+			"src/cogs/admin.py:12:5: error: '(' was never closed [syntax]",
+		},
+		{
+			Diagnostic{File: "src/cogs/admin.py", Line: 3, Severity: SeverityWarning, Code: DiagUntypedArgument, Message: "untyped"},
+			"src/cogs/admin.py:3: warning: untyped [untyped-argument]",
+		},
+		{
+			Diagnostic{File: "src/cogs", Severity: SeverityError, Code: DiagNoCogs, Message: "no cog files parsed"},
+			"src/cogs: error: no cog files parsed [no-cogs]",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := tt.diagnostic.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+// TestParseCogSourceWarnings covers code that looks like a command but cannot be fully read
+func TestParseCogSourceWarnings(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantCode   string
+		wantLine   int
+		wantColumn int
+	}{
+		{
+			name:       "command on an undeclared group",
+			body:       "    @admin.command(name=\"ban\", description=\"Bans a member\")\n    async def ban(self, interaction: discord.Interaction) -> None:\n        pass\n",
+			wantCode:   DiagUnknownGroup,
+			wantLine:   3,
+			wantColumn: 6,
+		},
+		{
+			name:       "argument without a type",
+			body:       "    @app_commands.command(name=\"echo\", description=\"Echoes\")\n    async def echo(self, interaction: discord.Interaction, text) -> None:\n        pass\n",
+			wantCode:   DiagUntypedArgument,
+			wantLine:   4,
+			wantColumn: 60,
+		},
+		{
+			name:       "argument of a type botbox cannot generate",
+			body:       "    @app_commands.command(name=\"echo\", description=\"Echoes\")\n    @app_commands.describe(text=\"Text to echo\")\n    async def echo(self, interaction: discord.Interaction, text: bytes) -> None:\n        pass\n",
+			wantCode:   DiagArgumentType,
+			wantLine:   5,
+			wantColumn: 60,
+		},
+		{
+			name:       "argument without a description",
+			body:       "    @app_commands.command(name=\"echo\", description=\"Echoes\")\n    async def echo(self, interaction: discord.Interaction, text: str) -> None:\n        pass\n",
+			wantCode:   DiagUndescribedArgument,
+			wantLine:   4,
+			wantColumn: 60,
+		},
+		{
+			name:       "describe naming an unknown argument",
+			body:       "    @app_commands.command(name=\"echo\", description=\"Echoes\")\n    @app_commands.describe(txt=\"Text to echo\", text=\"Text to echo\")\n    async def echo(self, interaction: discord.Interaction, text: str) -> None:\n        pass\n",
+			wantCode:   DiagUnknownArgument,
+			wantLine:   4,
+			wantColumn: 6,
+		},
+		{
+			name:       "modal not created by class name",
+			body:       "    @app_commands.command(name=\"form\", description=\"Opens a form\")\n    async def form(self, interaction: discord.Interaction) -> None:\n        await interaction.response.send_modal(self.modal)\n",
+			wantCode:   DiagModalClass,
+			wantLine:   4,
+			wantColumn: 5,
+		},
+		{
+			name:       "context menu with an unreadable callback",
+			body:       "    def __init__(self, bot):\n        self.menu = app_commands.ContextMenu(name=\"Report\", callback=report)\n",
+			wantCode:   DiagContextMenu,
+			wantLine:   4,
+			wantColumn: 21,
+		},
+		{
+			name:       "task without a supported schedule",
+			body:       "    @tasks.loop(count=3)\n    async def cleanup(self) -> None:\n        pass\n",
+			wantCode:   DiagTaskSchedule,
+			wantLine:   3,
+			wantColumn: 6,
+		},
+		{
+			name:       "listener that is not a coroutine",
+			body:       "    @commands.Cog.listener()\n    def on_ready(self) -> None:\n        pass\n",
+			wantCode:   DiagNotCoroutine,
+			wantLine:   4,
+			wantColumn: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := "class Cog(commands.Cog):\n    \"\"\"Cog\"\"\"\n" + tt.body
+			parsed, err := parseCogSource(source, "cog", DefaultLibrary)
+			if err != nil {
+				t.Fatalf("parseCogSource returned error: %v", err)
+			}
+			if len(parsed.Diagnostics) != 1 {
+				t.Fatalf("diagnostics = %v, want exactly one", parsed.Diagnostics)
+			}
+			got := parsed.Diagnostics[0]
+			if got.Code != tt.wantCode || got.Severity != SeverityWarning || got.Line != tt.wantLine || got.Column != tt.wantColumn {
+				t.Errorf("diagnostic = %+v, want a %s warning at line %d, column %d", got, tt.wantCode, tt.wantLine, tt.wantColumn)
+			}
+		})
+	}
+}
+
+// TestStageCogSyncStopsOnSyntaxErrors makes sure a cog that is not valid Python never drops out of botbox.conf
+func TestStageCogSyncStopsOnSyntaxErrors(t *testing.T) {
+	rootDir := newTestProject(t, Config{
+		BotBox:  BotBoxConfig{Version: "2.5.4"},
+		BotInfo: BotConfig{Name: "TestBot", Author: "Austin Choi", Description: "A discord bot used by the parser tests"},
+		Cogs: []CogConfig{
+			{Name: "BrokenCog", Env: "development", File: "broken"},
+		},
+	})
+	copyFixtureCog(t, "validCog", rootDir)
+	broken := "class BrokenCog(commands.Cog):\n    @app_commands.command(\n        name=\"open\",\n"
+	if err := os.WriteFile(filepath.Join(rootDir, "src", "cogs", "broken.py"), []byte(broken), 0644); err != nil {
+		t.Fatalf("failed to write broken.py: %v", err)
+	}
+
+	changes := &ChangeSet{}
+	result, err := StageCogSync(changes)
+	if err != nil {
+		t.Fatalf("StageCogSync returned error: %v", err)
+	}
+
+	want := Diagnostic{
+		File:     "src/cogs/broken.py",
+		Line:     2,
+		Column:   26,
+		Severity: SeverityError,
+		Code:     DiagSyntax,
+		Message:  "'(' was never closed",
+	}
+	if got := result.Errors(); len(got) != 1 || got[0] != want {
+		t.Errorf("errors = %+v, want [%+v]", got, want)
+	}
+	if len(result.AddedCogs) != 0 || len(result.RemovedCogs) != 0 {
+		t.Errorf("sync went ahead: added=%v removed=%v", result.AddedCogs, result.RemovedCogs)
+	}
+	if diff := changes.Diff(rootDir); diff != "" {
+		t.Errorf("staged changes despite the syntax error:\n%s", diff)
+	}
+}
+
+/*
+Copyright © 2025 Austin "Choice404" Choi
+
+https://github.com/choice404/botbox
+
+Bot Box
+A discord bot template generator to help create discord bots quickly and easily
+
+This code is licensed under the MIT License.
+
+MIT License
+
+Copyright (c) 2025 Austin Choi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
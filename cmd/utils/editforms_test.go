@@ -28,11 +28,19 @@ const (
 	editIdxPageNext
 	editIdxModInfo
 	editIdxRedefine
+	editIdxComponentView
+	editIdxComponentStart
+	editIdxComponentInfo
+	editIdxSelectOptionInfo
 	editIdxRedefineResponses
 	editIdxResponseStart
 	editIdxResponseInfo
 	editIdxPickCommand
 	editIdxRemoveCommand
+	editIdxListenerInfo
+	editIdxRemoveListener
+	editIdxTaskInfo
+	editIdxRemoveTask
 )
 
 // newEditModelValues builds the model value bus the edit flow expects
@@ -47,6 +55,8 @@ func newEditModelValues() Values {
 		"pages":           new(string),
 		"slashCommands":   new(string),
 		"prefixCommands":  new(string),
+		"listeners":       new(string),
+		"tasks":           new(string),
 	}
 	emptySlash := "[]"
 	emptyPrefix := "[]"
@@ -72,8 +82,8 @@ func editGreetCommand() CommandInfo {
 
 func TestEditFormWrapperGeneratorFormCount(t *testing.T) {
 	forms := EditFormWrapperGenerator()
-	if len(forms) != editIdxRemoveCommand+1 {
-		t.Fatalf("expected %d forms, got %d", editIdxRemoveCommand+1, len(forms))
+	if len(forms) != editIdxRemoveTask+1 {
+		t.Fatalf("expected %d forms, got %d", editIdxRemoveTask+1, len(forms))
 	}
 }
 
@@ -86,6 +96,10 @@ func TestEditActionRouting(t *testing.T) {
 		{"add enters the add command flow", "add", editIdxCmdInfo},
 		{"edit picks a command", "edit", editIdxPickCommand},
 		{"remove picks a command to remove", "remove", editIdxRemoveCommand},
+		{"listener adds or changes a listener", "listener", editIdxListenerInfo},
+		{"removeListener picks a listener to remove", "removeListener", editIdxRemoveListener},
+		{"task adds or changes a task", "task", editIdxTaskInfo},
+		{"removeTask picks a task to remove", "removeTask", editIdxRemoveTask},
 		{"apply ends the flow", "apply", -2},
 	}
 	for _, tt := range tests {
@@ -220,6 +234,36 @@ func TestEditModInfoPreservesCollectedSets(t *testing.T) {
 	}
 }
 
+func TestEditModInfoContextMenuDropsSetsAndSkipsRedefine(t *testing.T) {
+	forms := EditFormWrapperGenerator()
+	modelValues := newEditModelValues()
+	command := editGreetCommand()
+	commandString, _ := command.ToJSON()
+	setModelValue(modelValues, "currentCommand", commandString)
+	setFormValue(forms, editIdxModInfo, "cmdName", "profile")
+	setFormValue(forms, editIdxModInfo, "cmdType", "user_context")
+	setFormValue(forms, editIdxModInfo, "cmdScope", "guild")
+	setFormValue(forms, editIdxModInfo, "cmdDescription", "Shows a profile")
+	setFormValue(forms, editIdxModInfo, "cmdReturnType", "str")
+
+	forms[editIdxModInfo].Callback(forms[editIdxModInfo].Values, modelValues, forms)
+
+	current, err := JSONToCmdInfo(*modelValues.Map["currentCommand"])
+	if err != nil {
+		t.Fatalf("failed to parse current command: %v", err)
+	}
+	if current.ReturnType != "None" {
+		t.Errorf("context menu return type = %q, want None", current.ReturnType)
+	}
+	if len(current.Args) != 0 || len(current.Responses) != 1 {
+		t.Errorf("context menu should drop args and keep responses, got %+v", current)
+	}
+
+	if got := forms[editIdxModInfo].BranchCallback(forms[editIdxModInfo].Values, forms); got != editIdxRedefineResponses {
+		t.Errorf("mod info routed to %d, want %d", got, editIdxRedefineResponses)
+	}
+}
+
 func TestEditRedefineNoKeepsSets(t *testing.T) {
 	forms := EditFormWrapperGenerator()
 	modelValues := newEditModelValues()
@@ -414,12 +458,115 @@ func TestEditRemoveCommandCallback(t *testing.T) {
 	}
 }
 
+func TestEditRemovePrefixGroupRemovesSubcommands(t *testing.T) {
+	forms := EditFormWrapperGenerator()
+	modelValues := newEditModelValues()
+	prefixJSON, _ := CmdInfoSliceToJSON([]CommandInfo{
+		{Name: "tag", Type: "prefix", Scope: "guild", Description: "d", ReturnType: "None"},
+		{Name: "admin", Type: "prefix", Scope: "guild", Description: "d", ReturnType: "None", Group: "tag"},
+		{Name: "purge", Type: "prefix", Scope: "guild", Description: "d", ReturnType: "None", Group: "tag admin"},
+		{Name: "wave", Type: "prefix", Scope: "guild", Description: "d", ReturnType: "None"},
+	})
+	setModelValue(modelValues, "prefixCommands", prefixJSON)
+
+	setFormValue(forms, editIdxRemoveCommand, "removeCmdName", "tag")
+	setFormValue(forms, editIdxRemoveCommand, "removeConfirm", "yes")
+	forms[editIdxRemoveCommand].Callback(forms[editIdxRemoveCommand].Values, modelValues, forms)
+
+	prefixCommands, _ := JSONToCmdInfoSlice(*modelValues.Map["prefixCommands"])
+	if len(prefixCommands) != 1 || prefixCommands[0].Name != "wave" {
+		t.Errorf("prefix commands = %+v, want only wave", prefixCommands)
+	}
+}
+
+func TestEditAcceptRenamedGroupMovesSubcommands(t *testing.T) {
+	forms := EditFormWrapperGenerator()
+	modelValues := newEditModelValues()
+	prefixJSON, _ := CmdInfoSliceToJSON([]CommandInfo{
+		{Name: "create", Type: "prefix", Scope: "guild", Description: "d", ReturnType: "None", Group: "tag"},
+	})
+	setModelValue(modelValues, "prefixCommands", prefixJSON)
+	original, _ := (&CommandInfo{Name: "tag", Type: "prefix", Scope: "guild", Description: "d", ReturnType: "None"}).ToJSON()
+	setModelValue(modelValues, "editingOriginal", original)
+	edited, _ := (&CommandInfo{Name: "tags", Type: "prefix", Scope: "guild", Description: "d", ReturnType: "None"}).ToJSON()
+	setModelValue(modelValues, "currentCommand", edited)
+
+	if err := validateAcceptedCommand(modelValues); err != nil {
+		t.Fatalf("renamed group should validate, got %v", err)
+	}
+
+	setFormValue(forms, editIdxAccept, "cmdAcceptConfirm", "yes")
+	forms[editIdxAccept].Callback(forms[editIdxAccept].Values, modelValues, forms)
+
+	prefixCommands, _ := JSONToCmdInfoSlice(*modelValues.Map["prefixCommands"])
+	for _, command := range prefixCommands {
+		if command.Name == "create" && command.Group != "tags" {
+			t.Errorf("subcommand group = %q, want tags", command.Group)
+		}
+	}
+}
+
+func TestEditListenerCallbacks(t *testing.T) {
+	forms := EditFormWrapperGenerator()
+	modelValues := newEditModelValues()
+	listenerJSON, _ := ListenerInfoSliceToJSON([]ListenerInfo{{Event: "on_ready", Action: "log"}})
+	setModelValue(modelValues, "listeners", listenerJSON)
+
+	setFormValue(forms, editIdxListenerInfo, "listenerEvent", "on_message")
+	setFormValue(forms, editIdxListenerInfo, "listenerAction", "")
+	forms[editIdxListenerInfo].Callback(forms[editIdxListenerInfo].Values, modelValues, forms)
+	listeners, _ := JSONToListenerInfoSlice(*modelValues.Map["listeners"])
+	if len(listeners) != 2 {
+		t.Fatalf("added listener left %d listeners, want 2", len(listeners))
+	}
+
+	// A declined confirm leaves the listener in place
+	setFormValue(forms, editIdxRemoveListener, "removeListenerEvent", "on_ready")
+	setFormValue(forms, editIdxRemoveListener, "removeListenerConfirm", "no")
+	forms[editIdxRemoveListener].Callback(forms[editIdxRemoveListener].Values, modelValues, forms)
+	listeners, _ = JSONToListenerInfoSlice(*modelValues.Map["listeners"])
+	if len(listeners) != 2 {
+		t.Errorf("declined remove left %d listeners, want 2", len(listeners))
+	}
+
+	setFormValue(forms, editIdxRemoveListener, "removeListenerConfirm", "yes")
+	forms[editIdxRemoveListener].Callback(forms[editIdxRemoveListener].Values, modelValues, forms)
+	listeners, _ = JSONToListenerInfoSlice(*modelValues.Map["listeners"])
+	if len(listeners) != 1 || listeners[0].Event != "on_message" {
+		t.Errorf("confirmed remove left %+v, want only on_message", listeners)
+	}
+
+	for _, index := range []int{editIdxListenerInfo, editIdxRemoveListener} {
+		if got := forms[index].BranchCallback(forms[index].Values, forms); got != editIdxAction {
+			t.Errorf("form %d routed to %d, want %d", index, got, editIdxAction)
+		}
+	}
+}
+
+func TestEditRemoveTaskCallback(t *testing.T) {
+	forms := EditFormWrapperGenerator()
+	modelValues := newEditModelValues()
+	taskJSON, _ := TaskInfoSliceToJSON([]TaskInfo{{Name: "cleanup", Interval: 1, Unit: "hours"}})
+	setModelValue(modelValues, "tasks", taskJSON)
+
+	setFormValue(forms, editIdxRemoveTask, "removeTaskName", "cleanup")
+	setFormValue(forms, editIdxRemoveTask, "removeTaskConfirm", "yes")
+	forms[editIdxRemoveTask].Callback(forms[editIdxRemoveTask].Values, modelValues, forms)
+	tasks, _ := JSONToTaskInfoSlice(*modelValues.Map["tasks"])
+	if len(tasks) != 0 {
+		t.Errorf("confirmed remove left %+v, want none", tasks)
+	}
+	if got := forms[editIdxRemoveTask].BranchCallback(forms[editIdxRemoveTask].Values, forms); got != editIdxAction {
+		t.Errorf("remove task routed to %d, want %d", got, editIdxAction)
+	}
+}
+
 func TestEditLoopExitsRouteToRedefineResponses(t *testing.T) {
 	forms := EditFormWrapperGenerator()
 
 	setFormValue(forms, editIdxArgStart, "argStartConfirm", "no")
-	if got := forms[editIdxArgStart].BranchCallback(forms[editIdxArgStart].Values, forms); got != editIdxRedefineResponses {
-		t.Errorf("arg start no routed to %d, want %d", got, editIdxRedefineResponses)
+	if got := forms[editIdxArgStart].BranchCallback(forms[editIdxArgStart].Values, forms); got != editIdxComponentView {
+		t.Errorf("arg start no routed to %d, want %d", got, editIdxComponentView)
 	}
 
 	setFormValue(forms, editIdxFieldStart, "fieldStartConfirm", "no")
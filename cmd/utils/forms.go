@@ -8,6 +8,8 @@ package utils
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/huh"
@@ -27,6 +29,7 @@ func CreateFormWrapperGenerator() []FormWrapper {
 		"botGuildDopplerEnv":     new(string),
 		"licenseType":            new(string),
 		"helpStyle":              new(string),
+		"library":                new(string),
 		"dockerize":              new(string),
 	}
 
@@ -66,15 +69,137 @@ func CreateFormWrapperGenerator() []FormWrapper {
 			if formValues.Map["helpStyle"] != nil {
 				*modelValues.Map["helpStyle"] = *formValues.Map["helpStyle"]
 			}
+			if formValues.Map["library"] != nil {
+				*modelValues.Map["library"] = *formValues.Map["library"]
+			}
 			if formValues.Map["dockerize"] != nil {
 				*modelValues.Map["dockerize"] = *formValues.Map["dockerize"]
 			}
 		},
 	}
-	return []FormWrapper{wrapper}
+
+	// The template is picked first so its variables can be asked for before the bot details
+	templateWrapper := FormWrapper{
+		Name: "Project Template",
+		Form: templateFormGenerator,
+		Values: Values{
+			Map: map[string]*string{
+				"template": new(string),
+			},
+			Name: "projectTemplateValues",
+		},
+		Callback: func(formValues Values, modelValues Values, allForms []FormWrapper) {
+			if formValues.Map["template"] != nil {
+				*modelValues.Map["template"] = *formValues.Map["template"]
+			}
+		},
+	}
+
+	// Holds one value per template variable, keyed by the variable name
+	variablesWrapper := FormWrapper{
+		Name: "Template Variables",
+		Form: templateVariablesFormGenerator,
+		Values: Values{
+			Map:  map[string]*string{},
+			Name: "templateVariableValues",
+		},
+		Callback: func(formValues Values, modelValues Values, allForms []FormWrapper) {
+			projectTemplate, err := FindProjectTemplate(*modelValues.Map["template"])
+			if err != nil {
+				return
+			}
+			vars := map[string]string{}
+			for _, variable := range projectTemplate.Variables {
+				if formValues.Map[variable.Name] != nil {
+					vars[variable.Name] = *formValues.Map[variable.Name]
+				}
+			}
+			jsonData, _ := json.Marshal(vars)
+			*modelValues.Map["templateVars"] = string(jsonData)
+		},
+		SkipCondition: func(modelValues Values, allForms []FormWrapper, currentIndex int) bool {
+			projectTemplate, err := FindProjectTemplate(*modelValues.Map["template"])
+			return err != nil || len(projectTemplate.Variables) == 0
+		},
+	}
+
+	return []FormWrapper{templateWrapper, variablesWrapper, wrapper}
+}
+
+func templateFormGenerator(values Values, modelValues Values) *huh.Form {
+	if *values.Map["template"] == "" {
+		*values.Map["template"] = DefaultProjectTemplate
+	}
+
+	// Broken user templates are left out of the list, the note says why
+	templates, errs := ListProjectTemplates()
+	options := make([]huh.Option[string], 0, len(templates))
+	for _, projectTemplate := range templates {
+		label := fmt.Sprintf("%s - %s", projectTemplate.Name, projectTemplate.Description)
+		if note := projectTemplate.LibraryNote(); note != "" {
+			label += fmt.Sprintf(" (%s)", note)
+		}
+		options = append(options, huh.NewOption(label, projectTemplate.Name))
+	}
+
+	fields := []huh.Field{
+		huh.NewSelect[string]().
+			Title("Which template should the project start from?").
+			Options(options...).
+			Value(values.Map["template"]),
+	}
+	if len(errs) > 0 {
+		problems := make([]string, 0, len(errs))
+		for _, err := range errs {
+			problems = append(problems, err.Error())
+		}
+		fields = append(fields, huh.NewNote().
+			Title("Some user templates could not be loaded").
+			Description(strings.Join(problems, "\n")))
+	}
+
+	return huh.NewForm(huh.NewGroup(fields...)).
+		WithWidth(100).
+		WithShowHelp(false).
+		WithShowErrors(false)
+}
+
+func templateVariablesFormGenerator(values Values, modelValues Values) *huh.Form {
+	projectTemplate, _ := FindProjectTemplate(*modelValues.Map["template"])
+
+	fields := make([]huh.Field, 0, len(projectTemplate.Variables))
+	for _, variable := range projectTemplate.Variables {
+		if values.Map[variable.Name] == nil {
+			value := variable.Default
+			values.Map[variable.Name] = &value
+		}
+		fields = append(fields, huh.NewInput().
+			Title(variable.Prompt).
+			Prompt("> ").
+			Value(values.Map[variable.Name]).
+			Validate(func(s string) error {
+				if variable.Required && s == "" {
+					return fmt.Errorf("%s cannot be empty", variable.Name)
+				}
+				return nil
+			}))
+	}
+
+	return huh.NewForm(huh.NewGroup(fields...)).
+		WithWidth(100).
+		WithShowHelp(false).
+		WithShowErrors(false)
 }
 
 func createFormGenerator(values Values, modelValues Values) *huh.Form {
+	// The template is picked first, so only the libraries it has files for are offered
+	libraries := GeneratorNames()
+	if modelValues.Map["template"] != nil {
+		if projectTemplate, err := FindProjectTemplate(*modelValues.Map["template"]); err == nil {
+			libraries = projectTemplate.SupportedLibraries()
+		}
+	}
+
 	createForm := huh.NewForm(
 		huh.NewGroup(
 			huh.NewInput().
@@ -167,6 +292,12 @@ func createFormGenerator(values Values, modelValues Values) *huh.Form {
 				Value(values.Map["helpStyle"]).
 				Validate(ValidateHelpStyle),
 
+			huh.NewSelect[string]().
+				Title("Which Discord library should the bot use?").
+				Options(huh.NewOptions(libraries...)...).
+				Value(values.Map["library"]).
+				Validate(ValidateLibrary),
+
 			huh.NewConfirm().
 				Title("Generate Docker files for this project?").
 				Affirmative("yes").
@@ -210,8 +341,16 @@ func AddFormWrapperGenerator() []FormWrapper {
 		idxBranchStart
 		idxBranchInfo
 		idxPageNext
+		idxComponentView
+		idxComponentStart
+		idxComponentInfo
+		idxSelectOptionInfo
 		idxResponseStart
 		idxResponseInfo
+		idxListenerStart
+		idxListenerInfo
+		idxTaskStart
+		idxTaskInfo
 	)
 
 	forms := []FormWrapper{}
@@ -271,6 +410,17 @@ func AddFormWrapperGenerator() []FormWrapper {
 				allForms[idxCmdInfo].Values.Map["cmdScope"] = new(string)
 				allForms[idxCmdInfo].Values.Map["cmdDescription"] = new(string)
 				allForms[idxCmdInfo].Values.Map["cmdReturnType"] = new(string)
+				allForms[idxCmdInfo].Values.Map["cmdGroup"] = new(string)
+				allForms[idxCmdInfo].Values.Map["cmdGroupDescription"] = new(string)
+				allForms[idxCmdInfo].Values.Map["cmdPermissions"] = new(string)
+				allForms[idxCmdInfo].Values.Map["cmdRoles"] = new(string)
+				allForms[idxCmdInfo].Values.Map["cmdGuildOnly"] = new(string)
+				allForms[idxCmdInfo].Values.Map["cmdCooldown"] = new(string)
+				allForms[idxCmdInfo].Values.Map["cmdAllowedInstalls"] = new(string)
+				allForms[idxCmdInfo].Values.Map["cmdAllowedContexts"] = new(string)
+				allForms[idxCmdInfo].Values.Map["cmdAliases"] = new(string)
+				allForms[idxCmdInfo].Values.Map["cmdHidden"] = new(string)
+				allForms[idxCmdInfo].Values.Map["cmdInvokeWithout"] = new(string)
 				allForms[idxArgInfo].Values.Map["args"] = new(string)
 				allForms[idxFieldInfo].Values.Map["fields"] = new(string)
 				// Every new command starts with clean page and response state
@@ -287,7 +437,7 @@ func AddFormWrapperGenerator() []FormWrapper {
 				if *formValues.Map["cmdStartConfirm"] == "yes" {
 					return -1
 				} else {
-					return -2
+					return idxListenerStart
 				}
 			},
 		}
@@ -295,11 +445,22 @@ func AddFormWrapperGenerator() []FormWrapper {
 	}
 	{ // NOTE: idxCmdInfo
 		values := map[string]*string{
-			"cmdName":        new(string),
-			"cmdType":        new(string),
-			"cmdScope":       new(string),
-			"cmdDescription": new(string),
-			"cmdReturnType":  new(string),
+			"cmdName":             new(string),
+			"cmdType":             new(string),
+			"cmdScope":            new(string),
+			"cmdDescription":      new(string),
+			"cmdReturnType":       new(string),
+			"cmdGroup":            new(string),
+			"cmdGroupDescription": new(string),
+			"cmdPermissions":      new(string),
+			"cmdRoles":            new(string),
+			"cmdGuildOnly":        new(string),
+			"cmdCooldown":         new(string),
+			"cmdAllowedInstalls":  new(string),
+			"cmdAllowedContexts":  new(string),
+			"cmdAliases":          new(string),
+			"cmdHidden":           new(string),
+			"cmdInvokeWithout":    new(string),
 		}
 		wrapper := FormWrapper{
 			Name: "Add Command Info",
@@ -311,9 +472,8 @@ func AddFormWrapperGenerator() []FormWrapper {
 			ShowStatus: false,
 			FormGroup:  "command",
 			Callback: func(formValues Values, modelValues Values, allForms []FormWrapper) {
-				// Modal commands only respond through the modal, so their return type is fixed
 				returnType := *formValues.Map["cmdReturnType"]
-				if *formValues.Map["cmdType"] == "modal" {
+				if HasFixedReturnType(*formValues.Map["cmdType"]) {
 					returnType = "None"
 				}
 				command := CommandInfo{
@@ -327,6 +487,13 @@ func AddFormWrapperGenerator() []FormWrapper {
 					Responses:   []ResponseInfo{},
 					ReturnType:  returnType,
 				}
+				// The group inputs stay hidden for other types, so any leftover text is ignored
+				if CanBeGrouped(command.Type) {
+					command.Group = strings.TrimSpace(*formValues.Map["cmdGroup"])
+					command.GroupDescription = strings.TrimSpace(*formValues.Map["cmdGroupDescription"])
+				}
+				applyCommandAccess(&command, formValues.Map)
+				applyPrefixOptions(&command, formValues.Map)
 				commandString, _ := command.ToJSON()
 				modelValues.Map["currentCommand"] = &commandString
 			},
@@ -335,6 +502,9 @@ func AddFormWrapperGenerator() []FormWrapper {
 				if *formValues.Map["cmdType"] == "modal" {
 					return idxMultiPage
 				}
+				if !TakesArguments(*formValues.Map["cmdType"]) {
+					return idxResponseStart
+				}
 				return -1
 			},
 		}
@@ -357,22 +527,47 @@ func AddFormWrapperGenerator() []FormWrapper {
 				allForms[idxArgInfo].Values.Map["argName"] = new(string)
 				allForms[idxArgInfo].Values.Map["argDescription"] = new(string)
 				allForms[idxArgInfo].Values.Map["argType"] = new(string)
+				allForms[idxArgInfo].Values.Map["argTransformer"] = new(string)
+				allForms[idxArgInfo].Values.Map["argTransformerType"] = new(string)
+				allForms[idxArgInfo].Values.Map["argOptional"] = new(string)
+				allForms[idxArgInfo].Values.Map["argDefault"] = new(string)
+				allForms[idxArgInfo].Values.Map["argChoices"] = new(string)
+				allForms[idxArgInfo].Values.Map["argMin"] = new(string)
+				allForms[idxArgInfo].Values.Map["argMax"] = new(string)
+				allForms[idxArgInfo].Values.Map["argAutocomplete"] = new(string)
+				allForms[idxArgInfo].Values.Map["argSuggestions"] = new(string)
+				allForms[idxArgInfo].Values.Map["argAutocompleteSource"] = new(string)
+				allForms[idxArgInfo].Values.Map["argGreedy"] = new(string)
+				allForms[idxArgInfo].Values.Map["argRest"] = new(string)
 			},
 			BranchCallback: func(formValues Values, allForms []FormWrapper) int {
 				if *formValues.Map["argStartConfirm"] == "yes" {
 					return -1
 				}
-				return idxResponseStart
+				// Component commands collect their view before the responses, other types skip past it
+				return idxComponentView
 			},
 		}
 		forms = append(forms, wrapper)
 	}
 	{ // NOTE: idxArgInfo
 		values := map[string]*string{
-			"args":           new(string),
-			"argName":        new(string),
-			"argDescription": new(string),
-			"argType":        new(string),
+			"args":                  new(string),
+			"argName":               new(string),
+			"argDescription":        new(string),
+			"argType":               new(string),
+			"argTransformer":        new(string),
+			"argTransformerType":    new(string),
+			"argOptional":           new(string),
+			"argDefault":            new(string),
+			"argChoices":            new(string),
+			"argMin":                new(string),
+			"argMax":                new(string),
+			"argAutocomplete":       new(string),
+			"argSuggestions":        new(string),
+			"argAutocompleteSource": new(string),
+			"argGreedy":             new(string),
+			"argRest":               new(string),
 		}
 		wrapper := FormWrapper{
 			Name: "Add Argument Info",
@@ -386,11 +581,7 @@ func AddFormWrapperGenerator() []FormWrapper {
 			Callback: func(formValues Values, modelValues Values, allForms []FormWrapper) {
 				currentCommand, _ := JSONToCmdInfo(*modelValues.Map["currentCommand"])
 
-				currentCommand.Args = append(currentCommand.Args, ArgInfo{
-					Name:        *values["argName"],
-					Type:        *values["argType"],
-					Description: *values["argDescription"],
-				})
+				currentCommand.Args = append(currentCommand.Args, buildArgFromForm(values))
 				argString, _ := ArgInfoSliceToJSON(currentCommand.Args)
 				formValues.Map["args"] = &argString
 				commandString, _ := currentCommand.ToJSON()
@@ -507,13 +698,12 @@ func AddFormWrapperGenerator() []FormWrapper {
 			Callback: func(formValues Values, modelValues Values, allForms []FormWrapper) {
 				if *formValues.Map["cmdAcceptConfirm"] == "yes" {
 					command, _ := JSONToCmdInfo(*modelValues.Map["currentCommand"])
-					// Modal commands are app commands, so they live with the slash commands
-					if command.Type == "slash" || command.Type == "modal" {
+					if IsAppCommand(command.Type) {
 						slashCommandList, _ := JSONToCmdInfoSlice(*modelValues.Map["slashCommands"])
 						slashCommandList = append(slashCommandList, *command)
 						jsonData, _ := CmdInfoSliceToJSON(slashCommandList)
 						modelValues.Map["slashCommands"] = &jsonData
-					} else if command.Type == "prefix" {
+					} else {
 						prefixCommandList, _ := JSONToCmdInfoSlice(*modelValues.Map["prefixCommands"])
 						prefixCommandList = append(prefixCommandList, *command)
 						jsonData, _ := CmdInfoSliceToJSON(prefixCommandList)
@@ -780,6 +970,174 @@ func AddFormWrapperGenerator() []FormWrapper {
 		}
 		forms = append(forms, wrapper)
 	}
+	{ // NOTE: idxComponentView
+		values := map[string]*string{
+			"componentTimeout":    new(string),
+			"componentAuthorOnly": new(string),
+		}
+		wrapper := FormWrapper{
+			Name: "Add Component View",
+			Form: addComponentViewFormGenerator,
+			Values: Values{
+				Map:  values,
+				Name: "addComponentViewValues",
+			},
+			ShowStatus:    false,
+			FormGroup:     "component",
+			SkipCondition: skipUnlessComponentCommand,
+			Callback: func(formValues Values, modelValues Values, allForms []FormWrapper) {
+				currentCommand, _ := JSONToCmdInfo(*modelValues.Map["currentCommand"])
+
+				currentCommand.Components = buildComponentsFromForm(formValues.Map)
+				commandString, _ := currentCommand.ToJSON()
+				modelValues.Map["currentCommand"] = &commandString
+			},
+		}
+		forms = append(forms, wrapper)
+	}
+	{ // NOTE: idxComponentStart
+		values := map[string]*string{
+			"componentStartConfirm": new(string),
+		}
+		wrapper := FormWrapper{
+			Name: "Add Component Start",
+			Form: addComponentStartFormGenerator,
+			Values: Values{
+				Map:  values,
+				Name: "addComponentStartValues",
+			},
+			ShowStatus:    false,
+			FormGroup:     "component",
+			SkipCondition: skipUnlessComponentCommand,
+			Callback: func(formValues Values, modelValues Values, allForms []FormWrapper) {
+				allForms[idxComponentInfo].Values.Map["componentName"] = new(string)
+				allForms[idxComponentInfo].Values.Map["componentType"] = new(string)
+				allForms[idxComponentInfo].Values.Map["componentLabel"] = new(string)
+				allForms[idxComponentInfo].Values.Map["componentStyle"] = new(string)
+				allForms[idxComponentInfo].Values.Map["componentResponseContent"] = new(string)
+				allForms[idxComponentInfo].Values.Map["componentResponseEphemeral"] = new(string)
+			},
+			BranchCallback: func(formValues Values, allForms []FormWrapper) int {
+				if *formValues.Map["componentStartConfirm"] == "yes" {
+					return -1
+				}
+				return idxResponseStart
+			},
+		}
+		forms = append(forms, wrapper)
+	}
+	{ // NOTE: idxComponentInfo
+		values := map[string]*string{
+			"componentName":              new(string),
+			"componentType":              new(string),
+			"componentLabel":             new(string),
+			"componentStyle":             new(string),
+			"componentResponseContent":   new(string),
+			"componentResponseEphemeral": new(string),
+			"viewFull":                   new(string),
+		}
+		wrapper := FormWrapper{
+			Name: "Add Component Info",
+			Form: addComponentInfoFormGenerator,
+			Values: Values{
+				Map:  values,
+				Name: "addComponentInfoValues",
+			},
+			ShowStatus: false,
+			FormGroup:  "component",
+			Callback: func(formValues Values, modelValues Values, allForms []FormWrapper) {
+				currentCommand, _ := JSONToCmdInfo(*modelValues.Map["currentCommand"])
+				if currentCommand.Components == nil {
+					currentCommand.Components = &ComponentsInfo{Items: []ComponentInfo{}}
+				}
+
+				component := buildComponentFromForm(formValues.Map)
+				currentCommand.Components.Items = append(currentCommand.Components.Items, component)
+				commandString, _ := currentCommand.ToJSON()
+				modelValues.Map["currentCommand"] = &commandString
+
+				// The branch callback cannot see the model values, so whether the view has room rides on the form
+				viewFull := "no"
+				if viewIsFull(currentCommand.Components.Items) {
+					viewFull = "yes"
+				}
+				formValues.Map["viewFull"] = &viewFull
+
+				// A new select menu collects its own options
+				allForms[idxSelectOptionInfo].Values.Map["selectOptions"] = new(string)
+				resetSelectOptionInputs(allForms[idxSelectOptionInfo].Values)
+			},
+			BranchCallback: func(formValues Values, allForms []FormWrapper) int {
+				if *formValues.Map["componentType"] == "select" {
+					return idxSelectOptionInfo
+				}
+				if *formValues.Map["viewFull"] == "yes" {
+					return idxResponseStart
+				}
+				return idxComponentStart
+			},
+		}
+		forms = append(forms, wrapper)
+	}
+	{ // NOTE: idxSelectOptionInfo
+		values := map[string]*string{
+			"selectOptions":           new(string),
+			"optionLabel":             new(string),
+			"optionValue":             new(string),
+			"optionResponseContent":   new(string),
+			"optionResponseEphemeral": new(string),
+			"optionAnotherConfirm":    new(string),
+		}
+		wrapper := FormWrapper{
+			Name: "Add Select Option Info",
+			Form: addSelectOptionInfoFormGenerator,
+			Values: Values{
+				Map:  values,
+				Name: "addSelectOptionInfoValues",
+			},
+			ShowStatus: false,
+			FormGroup:  "component",
+			Callback: func(formValues Values, modelValues Values, allForms []FormWrapper) {
+				currentCommand, _ := JSONToCmdInfo(*modelValues.Map["currentCommand"])
+				if currentCommand.Components == nil || len(currentCommand.Components.Items) == 0 {
+					return
+				}
+
+				// Options always belong to the select menu added last
+				last := &currentCommand.Components.Items[len(currentCommand.Components.Items)-1]
+				last.Options = append(last.Options, buildSelectOptionFromForm(formValues.Map))
+				optionString, _ := json.Marshal(last.Options)
+				options := string(optionString)
+				formValues.Map["selectOptions"] = &options
+				commandString, _ := currentCommand.ToJSON()
+				modelValues.Map["currentCommand"] = &commandString
+
+				viewFull := "no"
+				if viewIsFull(currentCommand.Components.Items) {
+					viewFull = "yes"
+				}
+				allForms[idxComponentInfo].Values.Map["viewFull"] = &viewFull
+			},
+			BranchCallback: func(formValues Values, allForms []FormWrapper) int {
+				var options []SelectOptionInfo
+				_ = json.Unmarshal([]byte(*formValues.Map["selectOptions"]), &options)
+				if *formValues.Map["optionAnotherConfirm"] == "yes" && len(options) < MaxSelectOptions {
+					return idxSelectOptionInfo
+				}
+				if *allForms[idxComponentInfo].Values.Map["viewFull"] == "yes" {
+					return idxResponseStart
+				}
+				return idxComponentStart
+			},
+			BranchValueHandler: func(targetFormIndex int, targetValues Values) {
+				// Looping back for another option keeps the collected options but clears the inputs
+				if targetFormIndex == idxSelectOptionInfo {
+					resetSelectOptionInputs(targetValues)
+				}
+			},
+		}
+		forms = append(forms, wrapper)
+	}
 	{ // NOTE: idxResponseStart
 		values := map[string]*string{
 			"responseStartConfirm": new(string),
@@ -794,8 +1152,7 @@ func AddFormWrapperGenerator() []FormWrapper {
 			ShowStatus: false,
 			FormGroup:  "response",
 			Callback: func(formValues Values, modelValues Values, allForms []FormWrapper) {
-				allForms[idxResponseInfo].Values.Map["responseContent"] = new(string)
-				allForms[idxResponseInfo].Values.Map["responseEphemeral"] = new(string)
+				resetResponseInputs(allForms[idxResponseInfo].Values)
 			},
 			BranchCallback: func(formValues Values, allForms []FormWrapper) int {
 				if *formValues.Map["responseStartConfirm"] == "yes" {
@@ -808,9 +1165,17 @@ func AddFormWrapperGenerator() []FormWrapper {
 	}
 	{ // NOTE: idxResponseInfo
 		values := map[string]*string{
-			"responses":         new(string),
-			"responseContent":   new(string),
-			"responseEphemeral": new(string),
+			"responses":              new(string),
+			"responseType":           new(string),
+			"responseContent":        new(string),
+			"responseEphemeral":      new(string),
+			"responseEmbedTitle":     new(string),
+			"responseEmbedColor":     new(string),
+			"responseEmbedFields":    new(string),
+			"responseEmbedInline":    new(string),
+			"responseEmbedFooter":    new(string),
+			"responseEmbedThumbnail": new(string),
+			"responseChannelID":      new(string),
 		}
 		wrapper := FormWrapper{
 			Name: "Add Response Info",
@@ -824,12 +1189,7 @@ func AddFormWrapperGenerator() []FormWrapper {
 			Callback: func(formValues Values, modelValues Values, allForms []FormWrapper) {
 				currentCommand, _ := JSONToCmdInfo(*modelValues.Map["currentCommand"])
 
-				// Plain messages are the only response type that exists today
-				currentCommand.Responses = append(currentCommand.Responses, ResponseInfo{
-					Type:      "message",
-					Content:   *formValues.Map["responseContent"],
-					Ephemeral: *formValues.Map["responseEphemeral"] == "yes",
-				})
+				currentCommand.Responses = append(currentCommand.Responses, buildResponseFromForm(formValues.Map))
 				responseString, _ := ResponseInfoSliceToJSON(currentCommand.Responses)
 				formValues.Map["responses"] = &responseString
 				commandString, _ := currentCommand.ToJSON()
@@ -846,6 +1206,111 @@ func AddFormWrapperGenerator() []FormWrapper {
 		}
 		forms = append(forms, wrapper)
 	}
+	{ // NOTE: idxListenerStart
+		values := map[string]*string{
+			"listenerStartConfirm": new(string),
+		}
+		wrapper := FormWrapper{
+			Name: "Add Listener Start",
+			Form: addListenerStartFormGenerator,
+			Values: Values{
+				Map:  values,
+				Name: "addListenerStartValues",
+			},
+			ShowStatus: false,
+			FormGroup:  "listener",
+			Callback: func(formValues Values, modelValues Values, allForms []FormWrapper) {
+				resetListenerInputs(allForms[idxListenerInfo].Values)
+			},
+			BranchCallback: func(formValues Values, allForms []FormWrapper) int {
+				if *formValues.Map["listenerStartConfirm"] == "yes" {
+					return -1
+				}
+				return idxTaskStart
+			},
+		}
+		forms = append(forms, wrapper)
+	}
+	{ // NOTE: idxListenerInfo
+		values := map[string]*string{
+			"listenerEvent":     new(string),
+			"listenerAction":    new(string),
+			"listenerChannelID": new(string),
+			"listenerContent":   new(string),
+			"listenerRoleID":    new(string),
+		}
+		wrapper := FormWrapper{
+			Name: "Add Listener Info",
+			Form: addListenerInfoFormGenerator,
+			Values: Values{
+				Map:  values,
+				Name: "addListenerInfoValues",
+			},
+			ShowStatus: false,
+			FormGroup:  "listener",
+			Callback: func(formValues Values, modelValues Values, allForms []FormWrapper) {
+				putListener(modelValues, buildListenerFromForm(formValues.Map))
+			},
+			BranchCallback: func(formValues Values, allForms []FormWrapper) int {
+				return idxListenerStart
+			},
+		}
+		forms = append(forms, wrapper)
+	}
+	{ // NOTE: idxTaskStart
+		values := map[string]*string{
+			"taskStartConfirm": new(string),
+		}
+		wrapper := FormWrapper{
+			Name: "Add Task Start",
+			Form: addTaskStartFormGenerator,
+			Values: Values{
+				Map:  values,
+				Name: "addTaskStartValues",
+			},
+			ShowStatus: false,
+			FormGroup:  "task",
+			Callback: func(formValues Values, modelValues Values, allForms []FormWrapper) {
+				resetTaskInputs(allForms[idxTaskInfo].Values)
+			},
+			BranchCallback: func(formValues Values, allForms []FormWrapper) int {
+				if *formValues.Map["taskStartConfirm"] == "yes" {
+					return -1
+				}
+				return -2
+			},
+		}
+		forms = append(forms, wrapper)
+	}
+	{ // NOTE: idxTaskInfo
+		values := map[string]*string{
+			"taskName":      new(string),
+			"taskSchedule":  new(string),
+			"taskInterval":  new(string),
+			"taskUnit":      new(string),
+			"taskTimes":     new(string),
+			"taskTimezone":  new(string),
+			"taskChannelID": new(string),
+			"taskContent":   new(string),
+		}
+		wrapper := FormWrapper{
+			Name: "Add Task Info",
+			Form: addTaskInfoFormGenerator,
+			Values: Values{
+				Map:  values,
+				Name: "addTaskInfoValues",
+			},
+			ShowStatus: false,
+			FormGroup:  "task",
+			Callback: func(formValues Values, modelValues Values, allForms []FormWrapper) {
+				putTask(modelValues, buildTaskFromForm(formValues.Map))
+			},
+			BranchCallback: func(formValues Values, allForms []FormWrapper) int {
+				return idxTaskStart
+			},
+		}
+		forms = append(forms, wrapper)
+	}
 
 	return forms
 }
@@ -891,27 +1356,48 @@ func addCmdStartFormGenerator(values Values, modelValues Values) *huh.Form {
 	return cmdStartForm
 }
 
+// validateGroupedCommandPath checks the group entered for the command in the info form, then that the command's
+// full path is not taken by another command of the cog
+func validateGroupedCommandPath(group string, values Values, modelValues Values) error {
+	if err := ValidateCommandGroup(group); err != nil {
+		return err
+	}
+	slashCommandList, _ := JSONToCmdInfoSlice(*modelValues.Map["slashCommands"])
+	prefixCommandList, _ := JSONToCmdInfoSlice(*modelValues.Map["prefixCommands"])
+	command := CommandInfo{Name: *values.Map["cmdName"], Group: group}
+	return validateCommandPath(command, append(slashCommandList, prefixCommandList...))
+}
+
 func addCmdInfoFormGenerator(values Values, modelValues Values) *huh.Form {
 	cmdInfoForm := huh.NewForm(
 		huh.NewGroup(
-			huh.NewInput().
-				Value(values.Map["cmdName"]).
-				Title("Enter the command name").
-				Prompt("> ").
-				Validate(func(s string) error {
-					slashCommandList, _ := JSONToCmdInfoSlice(*modelValues.Map["slashCommands"])
-					prefixCommandList, _ := JSONToCmdInfoSlice(*modelValues.Map["prefixCommands"])
-					return ValidateCommandName(s, append(slashCommandList, prefixCommandList...))
-				}),
 			huh.NewSelect[string]().
 				Value(values.Map["cmdType"]).
 				Title("Select the command type").
 				Options(
 					huh.NewOption("slash", "slash"),
 					huh.NewOption("prefix", "prefix"),
+					huh.NewOption("hybrid (slash and prefix)", "hybrid"),
 					huh.NewOption("modal", "modal"),
+					huh.NewOption("component (buttons and select menus)", "component"),
+					huh.NewOption("user context menu", "user_context"),
+					huh.NewOption("message context menu", "message_context"),
 				).
 				Validate(ValidateCommandType),
+			// The type comes first, context menu names follow different rules than the other commands
+			huh.NewInput().
+				Value(values.Map["cmdName"]).
+				Title("Enter the command name").
+				Prompt("> ").
+				Validate(func(s string) error {
+					// A command that can be grouped is checked against the others once its group is known
+					if CanBeGrouped(*values.Map["cmdType"]) || *values.Map["cmdType"] == "prefix" {
+						return validateCommandNameFor(*values.Map["cmdType"], s, nil)
+					}
+					slashCommandList, _ := JSONToCmdInfoSlice(*modelValues.Map["slashCommands"])
+					prefixCommandList, _ := JSONToCmdInfoSlice(*modelValues.Map["prefixCommands"])
+					return validateCommandNameFor(*values.Map["cmdType"], s, append(slashCommandList, prefixCommandList...))
+				}),
 			huh.NewSelect[string]().
 				Value(values.Map["cmdScope"]).
 				Title("Select the command scope").
@@ -923,8 +1409,11 @@ func addCmdInfoFormGenerator(values Values, modelValues Values) *huh.Form {
 			huh.NewText().
 				Value(values.Map["cmdDescription"]).
 				Title("Enter the command description").
+				Description("Optional for context menus, Discord does not show their description").
 				CharLimit(400).
-				Validate(ValidateCommandDescription),
+				Validate(func(s string) error {
+					return validateCommandDescriptionFor(*values.Map["cmdType"], s)
+				}),
 			huh.NewSelect[string]().
 				Value(values.Map["cmdReturnType"]).
 				Title("Enter the command return type").
@@ -937,38 +1426,239 @@ func addCmdInfoFormGenerator(values Values, modelValues Values) *huh.Form {
 				).
 				Validate(ValidateReturnType),
 		),
-	)
-	return cmdInfoForm
-}
-
-// buildCommandSummary renders the accept screen text for a fully collected command
-func buildCommandSummary(command CommandInfo) string {
-	commandArgs := "None"
-	if len(command.Args) > 0 {
-		argNames := make([]string, len(command.Args))
-		for i, arg := range command.Args {
-			argNames[i] = fmt.Sprintf("%s (%s)", arg.Name, arg.Type)
-		}
-		commandArgs = strings.Join(argNames, ", ")
-	}
-
-	commandFields := "None"
-	if len(command.Fields) > 0 {
-		fieldNames := make([]string, len(command.Fields))
-		for i, field := range command.Fields {
-			fieldNames[i] = fmt.Sprintf("%s (%s)", field.Name, field.Style)
-		}
-		commandFields = strings.Join(fieldNames, ", ")
-	}
-
-	summary := fmt.Sprintf("Command Name: %s\nCommand Type: %s\nDescription: %s\nReturn Type: %s\nArguments: %v",
-		command.Name, command.Type, command.Description, command.ReturnType, commandArgs)
-	if command.Type == "modal" {
-		summary = fmt.Sprintf("Command Name: %s\nCommand Type: %s\nDescription: %s\nReturn Type: %s\nFields: %v",
-			command.Name, command.Type, command.Description, command.ReturnType, commandFields)
-		// A multi page modal lists its pages instead of a flat field list
-		if len(command.Pages) > 0 {
-			pageLines := make([]string, len(command.Pages))
+		huh.NewGroup(
+			huh.NewInput().
+				Value(values.Map["cmdGroup"]).
+				Title("Enter the command group (optional)").
+				Description("Nests the command under a slash group, for example ticket or ticket admin").
+				Prompt("> ").
+				Validate(func(s string) error {
+					return validateGroupedCommandPath(s, values, modelValues)
+				}),
+			huh.NewInput().
+				Value(values.Map["cmdGroupDescription"]).
+				Title("Enter the group description (optional)").
+				Prompt("> ").
+				Validate(ValidateGroupDescription),
+		).WithHideFunc(func() bool {
+			// Only commands registered through a command decorator can live in a group
+			return !CanBeGrouped(*values.Map["cmdType"])
+		}),
+		huh.NewGroup(
+			huh.NewInput().
+				Value(values.Map["cmdGroup"]).
+				Title("Enter the group command (optional)").
+				Description("Makes this a subcommand of an existing prefix command, for example tag or tag admin").
+				Prompt("> ").
+				Validate(func(s string) error {
+					return validateGroupedCommandPath(s, values, modelValues)
+				}),
+			huh.NewInput().
+				Value(values.Map["cmdAliases"]).
+				Title("Enter the aliases (optional)").
+				Description("Comma separated extra names the command answers to").
+				Prompt("> ").
+				Validate(func(s string) error {
+					for _, alias := range parseCommaList(s) {
+						if strings.ContainsAny(alias, " \t\"\\") {
+							return fmt.Errorf("alias '%s' cannot contain spaces, double quotes, or backslashes", alias)
+						}
+					}
+					return nil
+				}),
+			huh.NewConfirm().
+				Title("Should the command be hidden from /help?").
+				Affirmative("yes").
+				Negative("no").
+				Validate(func(b bool) error {
+					var s string
+					if b {
+						s = "yes"
+					} else {
+						s = "no"
+					}
+					values.Map["cmdHidden"] = &s
+					return nil
+				}),
+			huh.NewConfirm().
+				Title("Should the command only run when no subcommand is given?").
+				Description("Turns the command into a group that hands off to its subcommands").
+				Affirmative("yes").
+				Negative("no").
+				Validate(func(b bool) error {
+					var s string
+					if b {
+						s = "yes"
+					} else {
+						s = "no"
+					}
+					values.Map["cmdInvokeWithout"] = &s
+					return nil
+				}),
+		).WithHideFunc(func() bool {
+			return *values.Map["cmdType"] != "prefix"
+		}),
+		huh.NewGroup(
+			huh.NewInput().
+				Value(values.Map["cmdPermissions"]).
+				Title("Enter the required permissions (optional)").
+				Description("Comma separated discord.Permissions flags, for example manage_messages, kick_members").
+				Prompt("> ").
+				Validate(func(s string) error {
+					return ValidateCommandPermissions(parseCommaList(s))
+				}),
+			huh.NewInput().
+				Value(values.Map["cmdRoles"]).
+				Title("Enter the required roles (optional)").
+				Description("Comma separated role names or IDs, members need any one of them").
+				Prompt("> ").
+				Validate(func(s string) error {
+					return ValidateCommandRoles(parseCommaList(s))
+				}),
+			huh.NewInput().
+				Value(values.Map["cmdCooldown"]).
+				Title("Enter the cooldown (optional)").
+				Description("Uses per seconds for each user, guild, or channel, for example 3/60 user").
+				Prompt("> ").
+				Validate(func(s string) error {
+					cooldown, err := parseCooldownInput(s)
+					if err != nil {
+						return err
+					}
+					return ValidateCooldown(cooldown)
+				}),
+			huh.NewConfirm().
+				Title("Should the command only work in servers?").
+				Affirmative("yes").
+				Negative("no").
+				Validate(func(b bool) error {
+					var s string
+					if b {
+						s = "yes"
+					} else {
+						s = "no"
+					}
+					values.Map["cmdGuildOnly"] = &s
+					return nil
+				}),
+		),
+		huh.NewGroup(
+			huh.NewInput().
+				Value(values.Map["cmdAllowedInstalls"]).
+				Title("Enter the allowed installs (optional)").
+				Description("Comma separated, guild or user, for user installable apps").
+				Prompt("> ").
+				Validate(func(s string) error {
+					return validateInstallSettings("install", parseCommaList(s), validInstallTypes)
+				}),
+			huh.NewInput().
+				Value(values.Map["cmdAllowedContexts"]).
+				Title("Enter the allowed contexts (optional)").
+				Description("Comma separated, guild, dm, or private_channel").
+				Prompt("> ").
+				Validate(func(s string) error {
+					return validateInstallSettings("context", parseCommaList(s), validInstallContexts)
+				}),
+		).WithHideFunc(func() bool {
+			// Installs and contexts are read from top level application commands only
+			return UsesContext(*values.Map["cmdType"]) || *values.Map["cmdGuildOnly"] == "yes" ||
+				CanBeGrouped(*values.Map["cmdType"]) && strings.TrimSpace(*values.Map["cmdGroup"]) != ""
+		}),
+	)
+	return cmdInfoForm
+}
+
+// applyCommandAccess copies the permission, role, cooldown, and context inputs onto a command
+func applyCommandAccess(command *CommandInfo, values map[string]*string) {
+	command.Permissions = parseCommaList(*values["cmdPermissions"])
+	command.Roles = parseCommaList(*values["cmdRoles"])
+	command.GuildOnly = *values["cmdGuildOnly"] == "yes"
+	command.Cooldown, _ = parseCooldownInput(*values["cmdCooldown"])
+	command.AllowedInstalls = nil
+	command.AllowedContexts = nil
+	// The install inputs stay hidden for these commands, so any leftover text is ignored
+	if !UsesContext(command.Type) && command.Group == "" && !command.GuildOnly {
+		command.AllowedInstalls = parseCommaList(*values["cmdAllowedInstalls"])
+		command.AllowedContexts = parseCommaList(*values["cmdAllowedContexts"])
+	}
+}
+
+// applyPrefixOptions copies the group, alias, hidden, and invoke without command inputs onto a prefix command
+func applyPrefixOptions(command *CommandInfo, values map[string]*string) {
+	command.Aliases = nil
+	command.Hidden = false
+	command.InvokeWithoutCommand = false
+	// The prefix inputs stay hidden for other types, so any leftover text is ignored
+	if command.Type != "prefix" {
+		return
+	}
+	command.Group = strings.TrimSpace(*values["cmdGroup"])
+	command.Aliases = parseCommaList(*values["cmdAliases"])
+	command.Hidden = *values["cmdHidden"] == "yes"
+	command.InvokeWithoutCommand = *values["cmdInvokeWithout"] == "yes"
+}
+
+// parseCooldownInput reads a cooldown typed as rate/seconds bucket, like 3/60 user, empty means none
+func parseCooldownInput(s string) (*CooldownInfo, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	limit, bucket, found := strings.Cut(s, " ")
+	rate, per, hasPer := strings.Cut(limit, "/")
+	if !found || !hasPer {
+		return nil, fmt.Errorf("cooldown must look like 3/60 user")
+	}
+	rateValue, err := strconv.Atoi(rate)
+	if err != nil {
+		return nil, fmt.Errorf("cooldown rate '%s' is not a whole number", rate)
+	}
+	perValue, err := strconv.ParseFloat(per, 64)
+	if err != nil {
+		return nil, fmt.Errorf("cooldown period '%s' is not a number of seconds", per)
+	}
+	return &CooldownInfo{Rate: rateValue, Per: perValue, Bucket: strings.TrimSpace(bucket)}, nil
+}
+
+// formatCooldownInput writes a cooldown back in the rate/seconds bucket form the info form reads
+func formatCooldownInput(cooldown *CooldownInfo) string {
+	if cooldown == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d/%s %s", cooldown.Rate, formatSeconds(cooldown.Per), cooldown.Bucket)
+}
+
+// buildCommandSummary renders the accept screen text for a fully collected command
+func buildCommandSummary(command CommandInfo) string {
+	commandArgs := "None"
+	if len(command.Args) > 0 {
+		argNames := make([]string, len(command.Args))
+		for i, arg := range command.Args {
+			argNames[i] = fmt.Sprintf("%s (%s)", arg.Name, arg.Type)
+			if arg.Optional {
+				argNames[i] = fmt.Sprintf("%s (%s, optional)", arg.Name, arg.Type)
+			}
+		}
+		commandArgs = strings.Join(argNames, ", ")
+	}
+
+	commandFields := "None"
+	if len(command.Fields) > 0 {
+		fieldNames := make([]string, len(command.Fields))
+		for i, field := range command.Fields {
+			fieldNames[i] = fmt.Sprintf("%s (%s)", field.Name, field.Style)
+		}
+		commandFields = strings.Join(fieldNames, ", ")
+	}
+
+	summary := fmt.Sprintf("Command Name: %s\nCommand Type: %s\nDescription: %s\nReturn Type: %s\nArguments: %v",
+		command.Name, command.Type, command.Description, command.ReturnType, commandArgs)
+	if command.Type == "modal" {
+		summary = fmt.Sprintf("Command Name: %s\nCommand Type: %s\nDescription: %s\nReturn Type: %s\nFields: %v",
+			command.Name, command.Type, command.Description, command.ReturnType, commandFields)
+		// A multi page modal lists its pages instead of a flat field list
+		if len(command.Pages) > 0 {
+			pageLines := make([]string, len(command.Pages))
 			for i, page := range command.Pages {
 				next := page.Next
 				if next == "" {
@@ -979,6 +1669,42 @@ func buildCommandSummary(command CommandInfo) string {
 			summary = fmt.Sprintf("Command Name: %s\nCommand Type: %s\nDescription: %s\nReturn Type: %s\nPages:\n%s",
 				command.Name, command.Type, command.Description, command.ReturnType, strings.Join(pageLines, "\n"))
 		}
+	} else if IsContextMenuType(command.Type) {
+		// Context menu commands receive the clicked target, so there are no arguments to list
+		summary = fmt.Sprintf("Command Name: %s\nCommand Type: %s\nDescription: %s\nReturn Type: %s",
+			command.Name, command.Type, command.Description, command.ReturnType)
+	}
+
+	if command.Group != "" {
+		summary += fmt.Sprintf("\nGroup: %s", command.Group)
+	}
+
+	if len(command.Permissions) > 0 {
+		summary += fmt.Sprintf("\nPermissions: %s", strings.Join(command.Permissions, ", "))
+	}
+	if len(command.Roles) > 0 {
+		summary += fmt.Sprintf("\nRoles: %s", strings.Join(command.Roles, ", "))
+	}
+	if command.GuildOnly {
+		summary += "\nGuild Only: yes"
+	}
+	if len(command.AllowedInstalls) > 0 || len(command.AllowedContexts) > 0 {
+		summary += fmt.Sprintf("\nInstalls: %s\nContexts: %s", strings.Join(command.AllowedInstalls, ", "), strings.Join(command.AllowedContexts, ", "))
+	}
+	if command.Cooldown != nil {
+		summary += fmt.Sprintf("\nCooldown: %s", formatCooldownInput(command.Cooldown))
+	}
+
+	if command.Components != nil {
+		componentLines := make([]string, len(command.Components.Items))
+		for i, component := range command.Components.Items {
+			if component.Type == "select" {
+				componentLines[i] = fmt.Sprintf("  %s (select, %d options)", component.Name, len(component.Options))
+			} else {
+				componentLines[i] = fmt.Sprintf("  %s (button, %s)", component.Name, component.Style)
+			}
+		}
+		summary += "\nComponents:\n" + strings.Join(componentLines, "\n")
 	}
 
 	if len(command.Responses) > 0 {
@@ -988,7 +1714,14 @@ func buildCommandSummary(command CommandInfo) string {
 			if response.Ephemeral {
 				marker = ", ephemeral"
 			}
-			responseLines[i] = fmt.Sprintf("  %s: %s%s", response.Type, response.Content, marker)
+			if response.Type == "channel" {
+				marker += fmt.Sprintf(", channel %s", response.ChannelID)
+			}
+			content := response.Content
+			if response.Type == "embed" && response.Embed != nil && response.Embed.Title != "" {
+				content = strings.TrimSpace(response.Embed.Title + " " + content)
+			}
+			responseLines[i] = fmt.Sprintf("  %s: %s%s", response.Type, content, marker)
 		}
 		summary += "\nResponses:\n" + strings.Join(responseLines, "\n")
 	}
@@ -1058,6 +1791,15 @@ func addArgStartFormGenerator(values Values, modelValues Values) *huh.Form {
 }
 
 func addArgInfoFormGenerator(values Values, modelValues Values) *huh.Form {
+	// Choices, ranges, and autocomplete only exist on plain slash command options
+	valueOptionsHidden := func() bool {
+		currentCommand, err := JSONToCmdInfo(*modelValues.Map["currentCommand"])
+		if err != nil || !HasSlashOptions(currentCommand.Type) {
+			return true
+		}
+		return !contains(valueArgTypes, *values.Map["argType"])
+	}
+
 	argInfoForm := huh.NewForm(
 		huh.NewGroup(
 			huh.NewInput().
@@ -1082,19 +1824,47 @@ func addArgInfoFormGenerator(values Values, modelValues Values) *huh.Form {
 					huh.NewOption("float", "float"),
 					huh.NewOption("bool", "bool"),
 					huh.NewOption("discord.Member", "discord.Member"),
+					huh.NewOption("discord.User", "discord.User"),
 					huh.NewOption("discord.Role", "discord.Role"),
+					huh.NewOption("Mentionable (member or role)", MentionableArgType),
+					huh.NewOption("discord.TextChannel", "discord.TextChannel"),
+					huh.NewOption("discord.VoiceChannel", "discord.VoiceChannel"),
+					huh.NewOption("discord.abc.GuildChannel", "discord.abc.GuildChannel"),
+					huh.NewOption("discord.Thread", "discord.Thread"),
+					huh.NewOption("discord.Attachment", "discord.Attachment"),
+					huh.NewOption("Custom transformer", transformArgOption),
 				).
-				Validate(ValidateArgType),
+				Validate(func(s string) error {
+					// The transformer type is composed from the next group
+					if s == transformArgOption {
+						return nil
+					}
+					return ValidateArgType(s)
+				}),
 		),
-	)
-	return argInfoForm
-}
-
-func addFieldStartFormGenerator(values Values, modelValues Values) *huh.Form {
-	fieldStartForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Value(values.Map["argTransformer"]).
+				Title("Enter the transformer class name").
+				Description("Generates a converter class of this name for you to fill in").
+				Prompt("> "),
+			huh.NewSelect[string]().
+				Value(values.Map["argTransformerType"]).
+				Title("Enter the raw value type the transformer receives").
+				Options(
+					huh.NewOption("str", "str"),
+					huh.NewOption("int", "int"),
+					huh.NewOption("float", "float"),
+				).
+				Validate(func(s string) error {
+					return ValidateArgType(TransformArgType(s, strings.TrimSpace(*values.Map["argTransformer"])))
+				}),
+		).WithHideFunc(func() bool {
+			return *values.Map["argType"] != transformArgOption
+		}),
 		huh.NewGroup(
 			huh.NewConfirm().
-				Title("Do you want to add a modal field?").
+				Title("Is the argument optional?").
 				Affirmative("yes").
 				Negative("no").
 				Validate(func(b bool) error {
@@ -1104,49 +1874,53 @@ func addFieldStartFormGenerator(values Values, modelValues Values) *huh.Form {
 					} else {
 						s = "no"
 					}
-					values.Map["fieldStartConfirm"] = &s
+					values.Map["argOptional"] = &s
 					return nil
 				}),
 		),
-	)
-	return fieldStartForm
-}
-
-func addFieldInfoFormGenerator(values Values, modelValues Values) *huh.Form {
-	return fieldInfoFormGenerator(values, "fields")
-}
-
-func addPageFieldInfoFormGenerator(values Values, modelValues Values) *huh.Form {
-	return fieldInfoFormGenerator(values, "pageFields")
-}
-
-// fieldInfoFormGenerator builds the shared field form, fieldsKey names the accumulator on the wrapper
-func fieldInfoFormGenerator(values Values, fieldsKey string) *huh.Form {
-	fieldInfoForm := huh.NewForm(
 		huh.NewGroup(
 			huh.NewInput().
-				Value(values.Map["fieldName"]).
-				Title("Enter the field name").
+				Value(values.Map["argDefault"]).
+				Title("Enter the default value (optional)").
+				Description("Leave empty to default to None").
 				Prompt("> ").
 				Validate(func(s string) error {
-					fields, _ := JSONToFieldInfoSlice(*values.Map[fieldsKey])
-					return ValidateFieldName(s, fields)
+					return ValidateArgDefault(*values.Map["argType"], s)
 				}),
+		).WithHideFunc(func() bool {
+			// Only plain values can be written as a default, everything else falls back to None
+			return *values.Map["argOptional"] != "yes" ||
+				(!contains(valueArgTypes, *values.Map["argType"]) && *values.Map["argType"] != "bool")
+		}),
+		huh.NewGroup(
 			huh.NewInput().
-				Value(values.Map["fieldLabel"]).
-				Title("Enter the field label").
+				Value(values.Map["argChoices"]).
+				Title("Enter the fixed choices (optional)").
+				Description("Comma separated name=value pairs, for example Red=red, Blue=blue").
 				Prompt("> ").
-				Validate(ValidateFieldLabel),
-			huh.NewSelect[string]().
-				Value(values.Map["fieldStyle"]).
-				Title("Select the field style").
-				Options(
-					huh.NewOption("short", "short"),
-					huh.NewOption("paragraph", "paragraph"),
-				).
-				Validate(ValidateFieldStyle),
+				Validate(func(s string) error {
+					choices := parseChoiceList(s)
+					return ValidateArgChoices(*values.Map["argType"], choices)
+				}),
+			huh.NewInput().
+				Value(values.Map["argMin"]).
+				Title("Enter the minimum (optional)").
+				Description("Bounds the value of a number or the length of a string").
+				Prompt("> "),
+			huh.NewInput().
+				Value(values.Map["argMax"]).
+				Title("Enter the maximum (optional)").
+				Prompt("> ").
+				Validate(func(s string) error {
+					if *values.Map["argChoices"] != "" && (s != "" || *values.Map["argMin"] != "") {
+						return fmt.Errorf("an argument cannot have both choices and a min or max")
+					}
+					return ValidateArgRange(*values.Map["argType"], *values.Map["argMin"], s)
+				}),
+		).WithHideFunc(valueOptionsHidden),
+		huh.NewGroup(
 			huh.NewConfirm().
-				Title("Is the field required?").
+				Title("Should the argument offer autocomplete suggestions?").
 				Affirmative("yes").
 				Negative("no").
 				Validate(func(b bool) error {
@@ -1156,19 +1930,218 @@ func fieldInfoFormGenerator(values Values, fieldsKey string) *huh.Form {
 					} else {
 						s = "no"
 					}
-					values.Map["fieldRequired"] = &s
+					values.Map["argAutocomplete"] = &s
 					return nil
 				}),
+		).WithHideFunc(func() bool {
+			// Discord does not allow autocomplete next to fixed choices
+			return valueOptionsHidden() || *values.Map["argChoices"] != ""
+		}),
+		huh.NewGroup(
 			huh.NewInput().
-				Value(values.Map["fieldPlaceholder"]).
-				Title("Enter the field placeholder (optional)").
+				Value(values.Map["argSuggestions"]).
+				Title("Enter the suggestions").
+				Description("Comma separated values, leave empty to use a source instead").
 				Prompt("> "),
-		),
+			huh.NewInput().
+				Value(values.Map["argAutocompleteSource"]).
+				Title("Enter the autocomplete source").
+				Description("Generates a cog method named autocomplete_<source> for you to fill in").
+				Prompt("> ").
+				Validate(func(s string) error {
+					arg := ArgInfo{
+						Type:               *values.Map["argType"],
+						Autocomplete:       true,
+						Suggestions:        parseCommaList(*values.Map["argSuggestions"]),
+						AutocompleteSource: strings.TrimSpace(s),
+					}
+					return validateArgAutocomplete(arg)
+				}),
+		).WithHideFunc(func() bool {
+			return valueOptionsHidden() || *values.Map["argAutocomplete"] != "yes"
+		}),
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("Should the argument be greedy?").
+				Description("Collects as many words as convert to the type, like several members").
+				Affirmative("yes").
+				Negative("no").
+				Validate(func(b bool) error {
+					var s string
+					if b {
+						s = "yes"
+					} else {
+						s = "no"
+					}
+					if b && *values.Map["argType"] == "str" {
+						return fmt.Errorf("greedy arguments cannot be str")
+					}
+					values.Map["argGreedy"] = &s
+					return nil
+				}),
+			huh.NewConfirm().
+				Title("Should the argument take the rest of the message?").
+				Description("Only the last argument can, it reads everything left as one value").
+				Affirmative("yes").
+				Negative("no").
+				Validate(func(b bool) error {
+					var s string
+					if b {
+						s = "yes"
+					} else {
+						s = "no"
+					}
+					if b && *values.Map["argGreedy"] == "yes" {
+						return fmt.Errorf("an argument cannot be both greedy and take the rest of the message")
+					}
+					values.Map["argRest"] = &s
+					return nil
+				}),
+		).WithHideFunc(func() bool {
+			// Greedy and keyword only arguments come from ext.commands, app commands have neither
+			currentCommand, err := JSONToCmdInfo(*modelValues.Map["currentCommand"])
+			return err != nil || currentCommand.Type != "prefix"
+		}),
 	)
-	return fieldInfoForm
+	return argInfoForm
 }
 
-// pageToJSON moves a single page onto the string value bus
+// parseCommaList reads a comma separated list typed into a form, like suggestions or role names
+func parseCommaList(s string) []string {
+	var suggestions []string
+	for item := range strings.SplitSeq(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			suggestions = append(suggestions, item)
+		}
+	}
+	return suggestions
+}
+
+// parseChoiceList reads the comma separated name=value choices typed into the argument form,
+// a bare value is used as its own name
+func parseChoiceList(s string) []ChoiceInfo {
+	var choices []ChoiceInfo
+	for item := range strings.SplitSeq(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, value, found := strings.Cut(item, "=")
+		if !found {
+			value = name
+		}
+		choices = append(choices, ChoiceInfo{Name: strings.TrimSpace(name), Value: strings.TrimSpace(value)})
+	}
+	return choices
+}
+
+// buildArgFromForm turns the argument form values into an ArgInfo
+func buildArgFromForm(values map[string]*string) ArgInfo {
+	arg := ArgInfo{
+		Name:        *values["argName"],
+		Type:        *values["argType"],
+		Description: *values["argDescription"],
+		Optional:    *values["argOptional"] == "yes",
+	}
+	if arg.Type == transformArgOption {
+		arg.Type = TransformArgType(*values["argTransformerType"], strings.TrimSpace(*values["argTransformer"]))
+	}
+	// Hidden inputs can keep text from an earlier pass, so only the settings that apply are kept
+	if arg.Optional {
+		arg.Default = strings.TrimSpace(*values["argDefault"])
+	}
+	arg.Choices = parseChoiceList(*values["argChoices"])
+	arg.Min = strings.TrimSpace(*values["argMin"])
+	arg.Max = strings.TrimSpace(*values["argMax"])
+	if *values["argAutocomplete"] == "yes" {
+		arg.Autocomplete = true
+		arg.Suggestions = parseCommaList(*values["argSuggestions"])
+		arg.AutocompleteSource = strings.TrimSpace(*values["argAutocompleteSource"])
+	}
+	arg.Greedy = *values["argGreedy"] == "yes"
+	arg.Rest = *values["argRest"] == "yes"
+	return arg
+}
+
+func addFieldStartFormGenerator(values Values, modelValues Values) *huh.Form {
+	fieldStartForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("Do you want to add a modal field?").
+				Affirmative("yes").
+				Negative("no").
+				Validate(func(b bool) error {
+					var s string
+					if b {
+						s = "yes"
+					} else {
+						s = "no"
+					}
+					values.Map["fieldStartConfirm"] = &s
+					return nil
+				}),
+		),
+	)
+	return fieldStartForm
+}
+
+func addFieldInfoFormGenerator(values Values, modelValues Values) *huh.Form {
+	return fieldInfoFormGenerator(values, "fields")
+}
+
+func addPageFieldInfoFormGenerator(values Values, modelValues Values) *huh.Form {
+	return fieldInfoFormGenerator(values, "pageFields")
+}
+
+// fieldInfoFormGenerator builds the shared field form, fieldsKey names the accumulator on the wrapper
+func fieldInfoFormGenerator(values Values, fieldsKey string) *huh.Form {
+	fieldInfoForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Value(values.Map["fieldName"]).
+				Title("Enter the field name").
+				Prompt("> ").
+				Validate(func(s string) error {
+					fields, _ := JSONToFieldInfoSlice(*values.Map[fieldsKey])
+					return ValidateFieldName(s, fields)
+				}),
+			huh.NewInput().
+				Value(values.Map["fieldLabel"]).
+				Title("Enter the field label").
+				Prompt("> ").
+				Validate(ValidateFieldLabel),
+			huh.NewSelect[string]().
+				Value(values.Map["fieldStyle"]).
+				Title("Select the field style").
+				Options(
+					huh.NewOption("short", "short"),
+					huh.NewOption("paragraph", "paragraph"),
+				).
+				Validate(ValidateFieldStyle),
+			huh.NewConfirm().
+				Title("Is the field required?").
+				Affirmative("yes").
+				Negative("no").
+				Validate(func(b bool) error {
+					var s string
+					if b {
+						s = "yes"
+					} else {
+						s = "no"
+					}
+					values.Map["fieldRequired"] = &s
+					return nil
+				}),
+			huh.NewInput().
+				Value(values.Map["fieldPlaceholder"]).
+				Title("Enter the field placeholder (optional)").
+				Prompt("> "),
+		),
+	)
+	return fieldInfoForm
+}
+
+// pageToJSON moves a single page onto the string value bus
 func pageToJSON(page PageInfo) (string, error) {
 	jsonData, err := json.Marshal(page)
 	if err != nil {
@@ -1242,7 +2215,23 @@ func validateAcceptedCommand(modelValues Values) error {
 		prefixCommandList, _ := JSONToCmdInfoSlice(*modelValues.Map["prefixCommands"])
 		existing = append(existing, prefixCommandList...)
 	}
-	return ValidateCommand(*command, existing)
+	if err := ValidateCommand(*command, existing); err != nil {
+		return err
+	}
+	// Subcommands follow an edited group command, so the set is checked the way it will be saved
+	return ValidatePrefixGroups(append(followEditedGroup(existing, modelValues, *command), *command))
+}
+
+// followEditedGroup moves the subcommands of the prefix command being edited along with any change to its path
+func followEditedGroup(commands []CommandInfo, modelValues Values, command CommandInfo) []CommandInfo {
+	if modelValues.Map["editingOriginal"] == nil || *modelValues.Map["editingOriginal"] == "" {
+		return commands
+	}
+	original, err := JSONToCmdInfo(*modelValues.Map["editingOriginal"])
+	if err != nil || original.Type != "prefix" || command.Type != "prefix" {
+		return commands
+	}
+	return renamePrefixGroup(commands, CommandPath(*original), CommandPath(command))
 }
 
 func addMultiPageFormGenerator(values Values, modelValues Values) *huh.Form {
@@ -1420,7 +2409,763 @@ func addResponseStartFormGenerator(values Values, modelValues Values) *huh.Form
 	responseStartForm := huh.NewForm(
 		huh.NewGroup(
 			huh.NewConfirm().
-				Title("Do you want to add a custom response?").
+				Title("Do you want to add a custom response?").
+				Affirmative("yes").
+				Negative("no").
+				Validate(func(b bool) error {
+					var s string
+					if b {
+						s = "yes"
+					} else {
+						s = "no"
+					}
+					values.Map["responseStartConfirm"] = &s
+					return nil
+				}),
+		),
+	)
+	return responseStartForm
+}
+
+func addResponseInfoFormGenerator(values Values, modelValues Values) *huh.Form {
+	responseInfoForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Value(values.Map["responseType"]).
+				Title("Select the response type").
+				Options(
+					huh.NewOption("message", "message"),
+					huh.NewOption("embed", "embed"),
+					huh.NewOption("direct message to the user", "dm"),
+					huh.NewOption("message in a channel", "channel"),
+					huh.NewOption("defer, then follow up", "defer"),
+				),
+		),
+		huh.NewGroup(
+			huh.NewInput().
+				Value(values.Map["responseContent"]).
+				Title("Enter the response message content").
+				Description("Embeds show this as their description and can leave it empty").
+				Prompt("> ").
+				Validate(func(s string) error {
+					if *values.Map["responseType"] == "embed" {
+						return validateResponseText("content", s, maxEmbedDescriptionLength)
+					}
+					return validateResponseContent(s)
+				}),
+		),
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("Should the response be ephemeral?").
+				Affirmative("yes").
+				Negative("no").
+				Validate(func(b bool) error {
+					var s string
+					if b {
+						s = "yes"
+					} else {
+						s = "no"
+					}
+					values.Map["responseEphemeral"] = &s
+					return nil
+				}),
+		).WithHideFunc(func() bool {
+			// Direct messages and channel posts are seen by everyone who can read them, only replies can be ephemeral
+			responseType := *values.Map["responseType"]
+			return responseType == "dm" || responseType == "channel"
+		}),
+		huh.NewGroup(
+			huh.NewInput().
+				Value(values.Map["responseEmbedTitle"]).
+				Title("Enter the embed title").
+				Description("Optional").
+				Prompt("> ").
+				Validate(func(s string) error {
+					return validateResponseText("embed title", s, maxEmbedTitleLength)
+				}),
+			huh.NewInput().
+				Value(values.Map["responseEmbedColor"]).
+				Title("Enter the embed color").
+				Description("Optional hex color like #5865F2").
+				Prompt("> ").
+				Validate(func(s string) error {
+					if s = strings.TrimSpace(s); s != "" && !embedColorRegex.MatchString(s) {
+						return fmt.Errorf("embed color must be a hex color like #5865F2")
+					}
+					return nil
+				}),
+			huh.NewInput().
+				Value(values.Map["responseEmbedFields"]).
+				Title("Enter the embed fields").
+				Description("Optional name=value pairs separated by semicolons").
+				Prompt("> "),
+			huh.NewConfirm().
+				Title("Should the fields be shown side by side?").
+				Affirmative("yes").
+				Negative("no").
+				Validate(func(b bool) error {
+					var s string
+					if b {
+						s = "yes"
+					} else {
+						s = "no"
+					}
+					values.Map["responseEmbedInline"] = &s
+					return nil
+				}),
+			huh.NewInput().
+				Value(values.Map["responseEmbedFooter"]).
+				Title("Enter the embed footer").
+				Description("Optional").
+				Prompt("> "),
+			huh.NewInput().
+				Value(values.Map["responseEmbedThumbnail"]).
+				Title("Enter the embed thumbnail URL").
+				Description("Optional").
+				Prompt("> ").
+				Validate(func(s string) error {
+					// The last embed input checks the embed as a whole
+					response := buildResponseFromForm(values.Map)
+					response.Embed.Thumbnail = strings.TrimSpace(s)
+					return ValidateEmbed(response.Embed, response.Content)
+				}),
+		).WithHideFunc(func() bool {
+			return *values.Map["responseType"] != "embed"
+		}),
+		huh.NewGroup(
+			huh.NewInput().
+				Value(values.Map["responseChannelID"]).
+				Title("Enter the ID of the channel to post in").
+				Prompt("> ").
+				Validate(func(s string) error {
+					return ValidateChannelID(strings.TrimSpace(s))
+				}),
+		).WithHideFunc(func() bool {
+			return *values.Map["responseType"] != "channel"
+		}),
+	)
+	return responseInfoForm
+}
+
+// parseEmbedFieldList reads the semicolon separated name=value embed fields typed into the response form
+func parseEmbedFieldList(s string, inline bool) []EmbedFieldInfo {
+	var fields []EmbedFieldInfo
+	for item := range strings.SplitSeq(s, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(item, "=")
+		fields = append(fields, EmbedFieldInfo{Name: strings.TrimSpace(name), Value: strings.TrimSpace(value), Inline: inline})
+	}
+	return fields
+}
+
+// buildResponseFromForm turns the response form values into a response, keeping only the settings its type uses
+func buildResponseFromForm(values map[string]*string) ResponseInfo {
+	response := ResponseInfo{
+		Type:      *values["responseType"],
+		Content:   *values["responseContent"],
+		Ephemeral: *values["responseEphemeral"] == "yes",
+	}
+	// An unset type falls back to a plain message
+	if response.Type == "" {
+		response.Type = "message"
+	}
+
+	switch response.Type {
+	case "embed":
+		response.Embed = &EmbedInfo{
+			Title:     *values["responseEmbedTitle"],
+			Color:     strings.TrimSpace(*values["responseEmbedColor"]),
+			Fields:    parseEmbedFieldList(*values["responseEmbedFields"], *values["responseEmbedInline"] == "yes"),
+			Footer:    *values["responseEmbedFooter"],
+			Thumbnail: strings.TrimSpace(*values["responseEmbedThumbnail"]),
+		}
+	case "channel":
+		response.ChannelID = strings.TrimSpace(*values["responseChannelID"])
+		response.Ephemeral = false
+	case "dm":
+		response.Ephemeral = false
+	}
+	return response
+}
+
+// resetResponseInputs clears the response inputs while keeping the responses already collected
+func resetResponseInputs(values Values) {
+	values.Map["responseType"] = new(string)
+	values.Map["responseContent"] = new(string)
+	values.Map["responseEphemeral"] = new(string)
+	values.Map["responseEmbedTitle"] = new(string)
+	values.Map["responseEmbedColor"] = new(string)
+	values.Map["responseEmbedFields"] = new(string)
+	values.Map["responseEmbedInline"] = new(string)
+	values.Map["responseEmbedFooter"] = new(string)
+	values.Map["responseEmbedThumbnail"] = new(string)
+	values.Map["responseChannelID"] = new(string)
+}
+
+func addListenerStartFormGenerator(values Values, modelValues Values) *huh.Form {
+	listenerStartForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("Do you want to add an event listener?").
+				Affirmative("yes").
+				Negative("no").
+				Validate(func(b bool) error {
+					var s string
+					if b {
+						s = "yes"
+					} else {
+						s = "no"
+					}
+					values.Map["listenerStartConfirm"] = &s
+					return nil
+				}),
+		),
+	)
+	return listenerStartForm
+}
+
+// modelCogMembers reads the commands, listeners, and tasks the cog has so far from the model value bus
+func modelCogMembers(modelValues Values) ([]CommandInfo, []ListenerInfo, []TaskInfo) {
+	var commands []CommandInfo
+	for _, key := range []string{"slashCommands", "prefixCommands"} {
+		if modelValues.Map[key] != nil && *modelValues.Map[key] != "" {
+			list, _ := JSONToCmdInfoSlice(*modelValues.Map[key])
+			commands = append(commands, list...)
+		}
+	}
+	var listeners []ListenerInfo
+	if modelValues.Map["listeners"] != nil && *modelValues.Map["listeners"] != "" {
+		listeners, _ = JSONToListenerInfoSlice(*modelValues.Map["listeners"])
+	}
+	var tasks []TaskInfo
+	if modelValues.Map["tasks"] != nil && *modelValues.Map["tasks"] != "" {
+		tasks, _ = JSONToTaskInfoSlice(*modelValues.Map["tasks"])
+	}
+	return commands, listeners, tasks
+}
+
+func addListenerInfoFormGenerator(values Values, modelValues Values) *huh.Form {
+	listenerInfoForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Value(values.Map["listenerEvent"]).
+				Height(8).
+				Title("Select the event to listen to").
+				Description("Picking an event the cog already listens to replaces that listener").
+				Options(huh.NewOptions(ListenerEventNames()...)...).
+				Validate(func(s string) error {
+					commands, _, tasks := modelCogMembers(modelValues)
+					if owner := cogMethodOwner(s, commands, nil, tasks); owner != "" {
+						return fmt.Errorf("%s already defines the method %s", owner, s)
+					}
+					return nil
+				}),
+			huh.NewSelect[string]().
+				Value(values.Map["listenerAction"]).
+				Title("Select what the listener does").
+				Options(
+					huh.NewOption("nothing yet, I will write it", ""),
+					huh.NewOption("log the event", "log"),
+					huh.NewOption("send a message to a channel", "message"),
+					huh.NewOption("assign a role", "role"),
+				).
+				Validate(func(s string) error {
+					if s != "role" {
+						return nil
+					}
+					event, _ := findListenerEvent(*values.Map["listenerEvent"])
+					if event.Member == "" {
+						return fmt.Errorf("%s has no member to assign a role to", event.Name)
+					}
+					return nil
+				}),
+		),
+		huh.NewGroup(
+			huh.NewInput().
+				Value(values.Map["listenerChannelID"]).
+				Title("Enter the ID of the channel to post in").
+				Prompt("> ").
+				Validate(func(s string) error {
+					return ValidateChannelID(strings.TrimSpace(s))
+				}),
+			huh.NewInput().
+				Value(values.Map["listenerContent"]).
+				Title("Enter the message content").
+				Description("The event arguments can be used like {member.mention}").
+				Prompt("> ").
+				Validate(func(s string) error {
+					if s == "" {
+						return fmt.Errorf("message actions need content")
+					}
+					return validateResponseText("content", s, maxEmbedDescriptionLength)
+				}),
+		).WithHideFunc(func() bool {
+			return *values.Map["listenerAction"] != "message"
+		}),
+		huh.NewGroup(
+			huh.NewInput().
+				Value(values.Map["listenerRoleID"]).
+				Title("Enter the ID of the role to assign").
+				Prompt("> ").
+				Validate(func(s string) error {
+					return ValidateRoleID(strings.TrimSpace(s))
+				}),
+		).WithHideFunc(func() bool {
+			return *values.Map["listenerAction"] != "role"
+		}),
+	)
+	return listenerInfoForm
+}
+
+// buildListenerFromForm turns the listener form values into a listener, keeping only the settings its action uses
+func buildListenerFromForm(values map[string]*string) ListenerInfo {
+	listener := ListenerInfo{
+		Event:  *values["listenerEvent"],
+		Action: *values["listenerAction"],
+	}
+	switch listener.Action {
+	case "message":
+		listener.ChannelID = strings.TrimSpace(*values["listenerChannelID"])
+		listener.Content = *values["listenerContent"]
+	case "role":
+		listener.RoleID = strings.TrimSpace(*values["listenerRoleID"])
+	}
+	return listener
+}
+
+// resetListenerInputs clears the listener form so the next listener starts empty
+func resetListenerInputs(values Values) {
+	values.Map["listenerEvent"] = new(string)
+	values.Map["listenerAction"] = new(string)
+	values.Map["listenerChannelID"] = new(string)
+	values.Map["listenerContent"] = new(string)
+	values.Map["listenerRoleID"] = new(string)
+}
+
+// putListener stores a listener on the model value bus, replacing the cog's listener for the same event
+func putListener(modelValues Values, listener ListenerInfo) {
+	listeners := []ListenerInfo{}
+	if modelValues.Map["listeners"] != nil && *modelValues.Map["listeners"] != "" {
+		listeners, _ = JSONToListenerInfoSlice(*modelValues.Map["listeners"])
+	}
+	listeners = slices.DeleteFunc(listeners, func(existing ListenerInfo) bool {
+		return existing.Event == listener.Event
+	})
+	listeners = append(listeners, listener)
+	listenerJSON, _ := ListenerInfoSliceToJSON(listeners)
+	modelValues.Map["listeners"] = &listenerJSON
+}
+
+func addTaskStartFormGenerator(values Values, modelValues Values) *huh.Form {
+	taskStartForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("Do you want to add a scheduled task?").
+				Affirmative("yes").
+				Negative("no").
+				Validate(func(b bool) error {
+					var s string
+					if b {
+						s = "yes"
+					} else {
+						s = "no"
+					}
+					values.Map["taskStartConfirm"] = &s
+					return nil
+				}),
+		),
+	)
+	return taskStartForm
+}
+
+func addTaskInfoFormGenerator(values Values, modelValues Values) *huh.Form {
+	taskInfoForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Value(values.Map["taskName"]).
+				Title("Enter the task name").
+				Description("Using the name of an existing task replaces that task").
+				Prompt("> ").
+				Validate(func(s string) error {
+					if err := ValidateTaskName(s, nil); err != nil {
+						return err
+					}
+					commands, listeners, _ := modelCogMembers(modelValues)
+					return validateTaskMethods(s, commands, listeners)
+				}),
+			huh.NewSelect[string]().
+				Value(values.Map["taskSchedule"]).
+				Title("When should the task run?").
+				Options(
+					huh.NewOption("on an interval", "interval"),
+					huh.NewOption("at times of day", "times"),
+				),
+		),
+		huh.NewGroup(
+			huh.NewInput().
+				Value(values.Map["taskInterval"]).
+				Title("Enter the interval").
+				Prompt("> ").
+				Validate(func(s string) error {
+					interval, err := strconv.Atoi(strings.TrimSpace(s))
+					if err != nil || interval <= 0 {
+						return fmt.Errorf("interval must be a positive whole number")
+					}
+					return nil
+				}),
+			huh.NewSelect[string]().
+				Value(values.Map["taskUnit"]).
+				Title("Select the interval unit").
+				Options(huh.NewOptions(validTaskUnits...)...),
+		).WithHideFunc(func() bool {
+			return *values.Map["taskSchedule"] != "interval"
+		}),
+		huh.NewGroup(
+			huh.NewInput().
+				Value(values.Map["taskTimes"]).
+				Title("Enter the times of day").
+				Description("24 hour times separated by commas like 09:00, 21:30").
+				Prompt("> ").
+				Validate(func(s string) error {
+					times, err := ParseTaskTimes(s)
+					if err != nil {
+						return err
+					}
+					if len(times) == 0 {
+						return fmt.Errorf("enter at least one time of day")
+					}
+					return nil
+				}),
+			huh.NewInput().
+				Value(values.Map["taskTimezone"]).
+				Title("Enter the timezone").
+				Description("Optional IANA zone like America/New_York, defaults to UTC").
+				Prompt("> ").
+				Validate(func(s string) error {
+					return ValidateTimezone(strings.TrimSpace(s))
+				}),
+		).WithHideFunc(func() bool {
+			return *values.Map["taskSchedule"] != "times"
+		}),
+		huh.NewGroup(
+			huh.NewInput().
+				Value(values.Map["taskChannelID"]).
+				Title("Enter the ID of the channel the task posts to").
+				Description("Optional").
+				Prompt("> ").
+				Validate(func(s string) error {
+					if s = strings.TrimSpace(s); s == "" {
+						return nil
+					}
+					return ValidateChannelID(s)
+				}),
+			huh.NewInput().
+				Value(values.Map["taskContent"]).
+				Title("Enter the message to post on every run").
+				Description("Optional, needs a channel").
+				Prompt("> ").
+				Validate(func(s string) error {
+					if s == "" {
+						return nil
+					}
+					if strings.TrimSpace(*values.Map["taskChannelID"]) == "" {
+						return fmt.Errorf("task content needs a channel ID to post to")
+					}
+					return validateResponseText("content", s, maxEmbedDescriptionLength)
+				}),
+		),
+	)
+	return taskInfoForm
+}
+
+// buildTaskFromForm turns the task form values into a task, keeping only the settings its schedule uses
+func buildTaskFromForm(values map[string]*string) TaskInfo {
+	task := TaskInfo{
+		Name:      *values["taskName"],
+		ChannelID: strings.TrimSpace(*values["taskChannelID"]),
+		Content:   *values["taskContent"],
+	}
+	if *values["taskSchedule"] == "times" {
+		task.Times, _ = ParseTaskTimes(*values["taskTimes"])
+		task.Timezone = strings.TrimSpace(*values["taskTimezone"])
+	} else {
+		task.Interval, _ = strconv.Atoi(strings.TrimSpace(*values["taskInterval"]))
+		task.Unit = *values["taskUnit"]
+	}
+	return task
+}
+
+// resetTaskInputs clears the task form so the next task starts empty
+func resetTaskInputs(values Values) {
+	values.Map["taskName"] = new(string)
+	values.Map["taskSchedule"] = new(string)
+	values.Map["taskInterval"] = new(string)
+	values.Map["taskUnit"] = new(string)
+	values.Map["taskTimes"] = new(string)
+	values.Map["taskTimezone"] = new(string)
+	values.Map["taskChannelID"] = new(string)
+	values.Map["taskContent"] = new(string)
+}
+
+// putTask stores a task on the model value bus, replacing the cog's task with the same name
+func putTask(modelValues Values, task TaskInfo) {
+	taskList := []TaskInfo{}
+	if modelValues.Map["tasks"] != nil && *modelValues.Map["tasks"] != "" {
+		taskList, _ = JSONToTaskInfoSlice(*modelValues.Map["tasks"])
+	}
+	taskList = slices.DeleteFunc(taskList, func(existing TaskInfo) bool {
+		return existing.Name == task.Name
+	})
+	taskList = append(taskList, task)
+	taskJSON, _ := TaskInfoSliceToJSON(taskList)
+	modelValues.Map["tasks"] = &taskJSON
+}
+
+// skipUnlessComponentCommand hides the component forms while collecting any other command type
+func skipUnlessComponentCommand(modelValues Values, allForms []FormWrapper, currentIndex int) bool {
+	if modelValues.Map["currentCommand"] == nil || *modelValues.Map["currentCommand"] == "" {
+		return true
+	}
+	currentCommand, err := JSONToCmdInfo(*modelValues.Map["currentCommand"])
+	if err != nil {
+		return true
+	}
+	return currentCommand.Type != "component"
+}
+
+// Seconds a component view stays active when the timeout input is left empty, matching discord.py
+const defaultComponentTimeout = 180
+
+// buildComponentsFromForm starts the view of a component command from the view settings form
+func buildComponentsFromForm(values map[string]*string) *ComponentsInfo {
+	timeout := defaultComponentTimeout
+	if parsed, err := strconv.Atoi(strings.TrimSpace(*values["componentTimeout"])); err == nil {
+		timeout = parsed
+	}
+	return &ComponentsInfo{
+		Timeout:    timeout,
+		AuthorOnly: *values["componentAuthorOnly"] == "yes",
+		Items:      []ComponentInfo{},
+	}
+}
+
+// buildComponentFromForm turns the component form values into a button or an empty select menu
+func buildComponentFromForm(values map[string]*string) ComponentInfo {
+	component := ComponentInfo{
+		Name:  *values["componentName"],
+		Type:  *values["componentType"],
+		Label: *values["componentLabel"],
+	}
+	// Select menus answer per option, so only buttons keep the style and response inputs
+	if component.Type == "button" {
+		component.Style = *values["componentStyle"]
+		component.Response = ResponseInfo{
+			Type:      "message",
+			Content:   *values["componentResponseContent"],
+			Ephemeral: *values["componentResponseEphemeral"] == "yes",
+		}
+	} else {
+		component.Options = []SelectOptionInfo{}
+	}
+	return component
+}
+
+// buildSelectOptionFromForm turns the select option form values into an option and its response
+func buildSelectOptionFromForm(values map[string]*string) SelectOptionInfo {
+	return SelectOptionInfo{
+		Label: *values["optionLabel"],
+		Value: strings.TrimSpace(*values["optionValue"]),
+		Response: ResponseInfo{
+			Type:      "message",
+			Content:   *values["optionResponseContent"],
+			Ephemeral: *values["optionResponseEphemeral"] == "yes",
+		},
+	}
+}
+
+// resetSelectOptionInputs clears the option inputs while keeping the options already collected
+func resetSelectOptionInputs(values Values) {
+	values.Map["optionLabel"] = new(string)
+	values.Map["optionValue"] = new(string)
+	values.Map["optionResponseContent"] = new(string)
+	values.Map["optionResponseEphemeral"] = new(string)
+	values.Map["optionAnotherConfirm"] = new(string)
+}
+
+// viewIsFull reports whether a view has no room left for another button or select menu
+func viewIsFull(items []ComponentInfo) bool {
+	return viewRows(append(items, ComponentInfo{Type: "button"})) > maxViewRows
+}
+
+// currentComponents returns the components already collected for the command being built
+func currentComponents(modelValues Values) []ComponentInfo {
+	if modelValues.Map["currentCommand"] == nil || *modelValues.Map["currentCommand"] == "" {
+		return nil
+	}
+	currentCommand, err := JSONToCmdInfo(*modelValues.Map["currentCommand"])
+	if err != nil || currentCommand.Components == nil {
+		return nil
+	}
+	return currentCommand.Components.Items
+}
+
+func addComponentViewFormGenerator(values Values, modelValues Values) *huh.Form {
+	componentViewForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Value(values.Map["componentTimeout"]).
+				Title("Enter how many seconds the buttons and menus stay active").
+				Description(fmt.Sprintf("Leave empty for %d, 0 keeps them active until the bot restarts", defaultComponentTimeout)).
+				Prompt("> ").
+				Validate(func(s string) error {
+					return ValidateComponentTimeout(strings.TrimSpace(s))
+				}),
+			huh.NewConfirm().
+				Title("Should only the person who used the command be able to use them?").
+				Affirmative("yes").
+				Negative("no").
+				Validate(func(b bool) error {
+					var s string
+					if b {
+						s = "yes"
+					} else {
+						s = "no"
+					}
+					values.Map["componentAuthorOnly"] = &s
+					return nil
+				}),
+		),
+	)
+	return componentViewForm
+}
+
+func addComponentStartFormGenerator(values Values, modelValues Values) *huh.Form {
+	componentStartForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("Do you want to add a button or select menu?").
+				Affirmative("yes").
+				Negative("no").
+				Validate(func(b bool) error {
+					// Discord rejects a component message with nothing to click
+					if !b && len(currentComponents(modelValues)) == 0 {
+						return fmt.Errorf("component commands need at least one button or select menu")
+					}
+					var s string
+					if b {
+						s = "yes"
+					} else {
+						s = "no"
+					}
+					values.Map["componentStartConfirm"] = &s
+					return nil
+				}),
+		),
+	)
+	return componentStartForm
+}
+
+func addComponentInfoFormGenerator(values Values, modelValues Values) *huh.Form {
+	componentInfoForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Value(values.Map["componentName"]).
+				Title("Enter the component name").
+				Prompt("> ").
+				Validate(func(s string) error {
+					return ValidateComponentName(s, currentComponents(modelValues))
+				}),
+			huh.NewSelect[string]().
+				Value(values.Map["componentType"]).
+				Title("Select the component type").
+				Options(
+					huh.NewOption("button", "button"),
+					huh.NewOption("select menu", "select"),
+				).
+				Validate(func(s string) error {
+					if !contains(validComponentTypes, s) {
+						return fmt.Errorf("component type must be one of %s", strings.Join(validComponentTypes, ", "))
+					}
+					if viewRows(append(currentComponents(modelValues), ComponentInfo{Type: s})) > maxViewRows {
+						return fmt.Errorf("there is no room left for another %s", s)
+					}
+					return nil
+				}),
+			huh.NewInput().
+				Value(values.Map["componentLabel"]).
+				Title("Enter the button label or select menu placeholder").
+				Prompt("> ").
+				Validate(func(s string) error {
+					return ValidateComponentLabel(*values.Map["componentType"], s)
+				}),
+		),
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Value(values.Map["componentStyle"]).
+				Title("Select the button style").
+				Options(
+					huh.NewOption("primary", "primary"),
+					huh.NewOption("secondary", "secondary"),
+					huh.NewOption("success", "success"),
+					huh.NewOption("danger", "danger"),
+				),
+			huh.NewInput().
+				Value(values.Map["componentResponseContent"]).
+				Title("Enter the message sent when the button is clicked").
+				Prompt("> ").
+				Validate(validateResponseContent),
+			huh.NewConfirm().
+				Title("Should the response be ephemeral?").
+				Affirmative("yes").
+				Negative("no").
+				Validate(func(b bool) error {
+					var s string
+					if b {
+						s = "yes"
+					} else {
+						s = "no"
+					}
+					values.Map["componentResponseEphemeral"] = &s
+					return nil
+				}),
+		).WithHideFunc(func() bool {
+			// Each select option gets its own response in the next form
+			return *values.Map["componentType"] != "button"
+		}),
+	)
+	return componentInfoForm
+}
+
+func addSelectOptionInfoFormGenerator(values Values, modelValues Values) *huh.Form {
+	selectOptionInfoForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Value(values.Map["optionLabel"]).
+				Title("Enter the option label").
+				Prompt("> "),
+			huh.NewInput().
+				Value(values.Map["optionValue"]).
+				Title("Enter the option value").
+				Prompt("> "),
+			huh.NewInput().
+				Value(values.Map["optionResponseContent"]).
+				Title("Enter the message sent when the option is picked").
+				Prompt("> ").
+				Validate(func(s string) error {
+					var existing []SelectOptionInfo
+					_ = json.Unmarshal([]byte(*values.Map["selectOptions"]), &existing)
+					option := SelectOptionInfo{
+						Label:    *values.Map["optionLabel"],
+						Value:    strings.TrimSpace(*values.Map["optionValue"]),
+						Response: ResponseInfo{Type: "message", Content: s},
+					}
+					return ValidateSelectOption(option, existing)
+				}),
+			huh.NewConfirm().
+				Title("Should the response be ephemeral?").
 				Affirmative("yes").
 				Negative("no").
 				Validate(func(b bool) error {
@@ -1430,24 +3175,11 @@ func addResponseStartFormGenerator(values Values, modelValues Values) *huh.Form
 					} else {
 						s = "no"
 					}
-					values.Map["responseStartConfirm"] = &s
+					values.Map["optionResponseEphemeral"] = &s
 					return nil
 				}),
-		),
-	)
-	return responseStartForm
-}
-
-func addResponseInfoFormGenerator(values Values, modelValues Values) *huh.Form {
-	responseInfoForm := huh.NewForm(
-		huh.NewGroup(
-			huh.NewInput().
-				Value(values.Map["responseContent"]).
-				Title("Enter the response message content").
-				Prompt("> ").
-				Validate(validateResponseContent),
 			huh.NewConfirm().
-				Title("Should the response be ephemeral?").
+				Title("Do you want to add another option?").
 				Affirmative("yes").
 				Negative("no").
 				Validate(func(b bool) error {
@@ -1457,12 +3189,12 @@ func addResponseInfoFormGenerator(values Values, modelValues Values) *huh.Form {
 					} else {
 						s = "no"
 					}
-					values.Map["responseEphemeral"] = &s
+					values.Map["optionAnotherConfirm"] = &s
 					return nil
 				}),
 		),
 	)
-	return responseInfoForm
+	return selectOptionInfoForm
 }
 
 /**
@@ -1574,11 +3306,19 @@ func EditFormWrapperGenerator() []FormWrapper {
 		idxEditPageNext
 		idxEditModInfo
 		idxEditRedefine
+		idxEditComponentView
+		idxEditComponentStart
+		idxEditComponentInfo
+		idxEditSelectOptionInfo
 		idxEditRedefineResponses
 		idxEditResponseStart
 		idxEditResponseInfo
 		idxEditPickCommand
 		idxEditRemoveCommand
+		idxEditListenerInfo
+		idxEditRemoveListener
+		idxEditTaskInfo
+		idxEditRemoveTask
 	)
 
 	// resetCommandState clears every per command form so a new command flow starts clean
@@ -1588,6 +3328,17 @@ func EditFormWrapperGenerator() []FormWrapper {
 		allForms[idxEditCmdInfo].Values.Map["cmdScope"] = new(string)
 		allForms[idxEditCmdInfo].Values.Map["cmdDescription"] = new(string)
 		allForms[idxEditCmdInfo].Values.Map["cmdReturnType"] = new(string)
+		allForms[idxEditCmdInfo].Values.Map["cmdGroup"] = new(string)
+		allForms[idxEditCmdInfo].Values.Map["cmdGroupDescription"] = new(string)
+		allForms[idxEditCmdInfo].Values.Map["cmdPermissions"] = new(string)
+		allForms[idxEditCmdInfo].Values.Map["cmdRoles"] = new(string)
+		allForms[idxEditCmdInfo].Values.Map["cmdGuildOnly"] = new(string)
+		allForms[idxEditCmdInfo].Values.Map["cmdCooldown"] = new(string)
+		allForms[idxEditCmdInfo].Values.Map["cmdAllowedInstalls"] = new(string)
+		allForms[idxEditCmdInfo].Values.Map["cmdAllowedContexts"] = new(string)
+		allForms[idxEditCmdInfo].Values.Map["cmdAliases"] = new(string)
+		allForms[idxEditCmdInfo].Values.Map["cmdHidden"] = new(string)
+		allForms[idxEditCmdInfo].Values.Map["cmdInvokeWithout"] = new(string)
 		allForms[idxEditArgInfo].Values.Map["args"] = new(string)
 		allForms[idxEditFieldInfo].Values.Map["fields"] = new(string)
 		allForms[idxEditPageInfo].Values.Map["pageName"] = new(string)
@@ -1653,8 +3404,19 @@ func EditFormWrapperGenerator() []FormWrapper {
 			FormGroup:  "action",
 			Callback: func(formValues Values, modelValues Values, allForms []FormWrapper) {
 				// A fresh add starts with a clean command flow, edit prefills it later
-				if *formValues.Map["editAction"] == "add" {
+				switch *formValues.Map["editAction"] {
+				case "add":
 					resetCommandState(modelValues, allForms)
+				case "listener":
+					resetListenerInputs(allForms[idxEditListenerInfo].Values)
+				case "removeListener":
+					allForms[idxEditRemoveListener].Values.Map["removeListenerEvent"] = new(string)
+					allForms[idxEditRemoveListener].Values.Map["removeListenerConfirm"] = new(string)
+				case "task":
+					resetTaskInputs(allForms[idxEditTaskInfo].Values)
+				case "removeTask":
+					allForms[idxEditRemoveTask].Values.Map["removeTaskName"] = new(string)
+					allForms[idxEditRemoveTask].Values.Map["removeTaskConfirm"] = new(string)
 				}
 			},
 			BranchCallback: func(formValues Values, allForms []FormWrapper) int {
@@ -1665,6 +3427,14 @@ func EditFormWrapperGenerator() []FormWrapper {
 					return idxEditPickCommand
 				case "remove":
 					return idxEditRemoveCommand
+				case "listener":
+					return idxEditListenerInfo
+				case "removeListener":
+					return idxEditRemoveListener
+				case "task":
+					return idxEditTaskInfo
+				case "removeTask":
+					return idxEditRemoveTask
 				default:
 					return -2
 				}
@@ -1674,11 +3444,22 @@ func EditFormWrapperGenerator() []FormWrapper {
 	}
 	{ // NOTE: idxEditCmdInfo
 		values := map[string]*string{
-			"cmdName":        new(string),
-			"cmdType":        new(string),
-			"cmdScope":       new(string),
-			"cmdDescription": new(string),
-			"cmdReturnType":  new(string),
+			"cmdName":             new(string),
+			"cmdType":             new(string),
+			"cmdScope":            new(string),
+			"cmdDescription":      new(string),
+			"cmdReturnType":       new(string),
+			"cmdGroup":            new(string),
+			"cmdGroupDescription": new(string),
+			"cmdPermissions":      new(string),
+			"cmdRoles":            new(string),
+			"cmdGuildOnly":        new(string),
+			"cmdCooldown":         new(string),
+			"cmdAllowedInstalls":  new(string),
+			"cmdAllowedContexts":  new(string),
+			"cmdAliases":          new(string),
+			"cmdHidden":           new(string),
+			"cmdInvokeWithout":    new(string),
 		}
 		wrapper := FormWrapper{
 			Name: "Edit Add Command Info",
@@ -1690,9 +3471,8 @@ func EditFormWrapperGenerator() []FormWrapper {
 			ShowStatus: false,
 			FormGroup:  "command",
 			Callback: func(formValues Values, modelValues Values, allForms []FormWrapper) {
-				// Modal commands only respond through the modal, so their return type is fixed
 				returnType := *formValues.Map["cmdReturnType"]
-				if *formValues.Map["cmdType"] == "modal" {
+				if HasFixedReturnType(*formValues.Map["cmdType"]) {
 					returnType = "None"
 				}
 				command := CommandInfo{
@@ -1706,6 +3486,13 @@ func EditFormWrapperGenerator() []FormWrapper {
 					Responses:   []ResponseInfo{},
 					ReturnType:  returnType,
 				}
+				// The group inputs stay hidden for other types, so any leftover text is ignored
+				if CanBeGrouped(command.Type) {
+					command.Group = strings.TrimSpace(*formValues.Map["cmdGroup"])
+					command.GroupDescription = strings.TrimSpace(*formValues.Map["cmdGroupDescription"])
+				}
+				applyCommandAccess(&command, formValues.Map)
+				applyPrefixOptions(&command, formValues.Map)
 				commandString, _ := command.ToJSON()
 				modelValues.Map["currentCommand"] = &commandString
 			},
@@ -1714,6 +3501,9 @@ func EditFormWrapperGenerator() []FormWrapper {
 				if *formValues.Map["cmdType"] == "modal" {
 					return idxEditMultiPage
 				}
+				if !TakesArguments(*formValues.Map["cmdType"]) {
+					return idxEditResponseStart
+				}
 				return -1
 			},
 		}
@@ -1736,22 +3526,47 @@ func EditFormWrapperGenerator() []FormWrapper {
 				allForms[idxEditArgInfo].Values.Map["argName"] = new(string)
 				allForms[idxEditArgInfo].Values.Map["argDescription"] = new(string)
 				allForms[idxEditArgInfo].Values.Map["argType"] = new(string)
+				allForms[idxEditArgInfo].Values.Map["argTransformer"] = new(string)
+				allForms[idxEditArgInfo].Values.Map["argTransformerType"] = new(string)
+				allForms[idxEditArgInfo].Values.Map["argOptional"] = new(string)
+				allForms[idxEditArgInfo].Values.Map["argDefault"] = new(string)
+				allForms[idxEditArgInfo].Values.Map["argChoices"] = new(string)
+				allForms[idxEditArgInfo].Values.Map["argMin"] = new(string)
+				allForms[idxEditArgInfo].Values.Map["argMax"] = new(string)
+				allForms[idxEditArgInfo].Values.Map["argAutocomplete"] = new(string)
+				allForms[idxEditArgInfo].Values.Map["argSuggestions"] = new(string)
+				allForms[idxEditArgInfo].Values.Map["argAutocompleteSource"] = new(string)
+				allForms[idxEditArgInfo].Values.Map["argGreedy"] = new(string)
+				allForms[idxEditArgInfo].Values.Map["argRest"] = new(string)
 			},
 			BranchCallback: func(formValues Values, allForms []FormWrapper) int {
 				if *formValues.Map["argStartConfirm"] == "yes" {
 					return -1
 				}
-				return idxEditRedefineResponses
+				// Component commands collect their view before the responses, other types skip past it
+				return idxEditComponentView
 			},
 		}
 		forms = append(forms, wrapper)
 	}
 	{ // NOTE: idxEditArgInfo
 		values := map[string]*string{
-			"args":           new(string),
-			"argName":        new(string),
-			"argDescription": new(string),
-			"argType":        new(string),
+			"args":                  new(string),
+			"argName":               new(string),
+			"argDescription":        new(string),
+			"argType":               new(string),
+			"argTransformer":        new(string),
+			"argTransformerType":    new(string),
+			"argOptional":           new(string),
+			"argDefault":            new(string),
+			"argChoices":            new(string),
+			"argMin":                new(string),
+			"argMax":                new(string),
+			"argAutocomplete":       new(string),
+			"argSuggestions":        new(string),
+			"argAutocompleteSource": new(string),
+			"argGreedy":             new(string),
+			"argRest":               new(string),
 		}
 		wrapper := FormWrapper{
 			Name: "Edit Argument Info",
@@ -1765,11 +3580,7 @@ func EditFormWrapperGenerator() []FormWrapper {
 			Callback: func(formValues Values, modelValues Values, allForms []FormWrapper) {
 				currentCommand, _ := JSONToCmdInfo(*modelValues.Map["currentCommand"])
 
-				currentCommand.Args = append(currentCommand.Args, ArgInfo{
-					Name:        *values["argName"],
-					Type:        *values["argType"],
-					Description: *values["argDescription"],
-				})
+				currentCommand.Args = append(currentCommand.Args, buildArgFromForm(values))
 				argString, _ := ArgInfoSliceToJSON(currentCommand.Args)
 				formValues.Map["args"] = &argString
 				commandString, _ := currentCommand.ToJSON()
@@ -1870,15 +3681,14 @@ func EditFormWrapperGenerator() []FormWrapper {
 			Callback: func(formValues Values, modelValues Values, allForms []FormWrapper) {
 				if *formValues.Map["cmdAcceptConfirm"] == "yes" {
 					command, _ := JSONToCmdInfo(*modelValues.Map["currentCommand"])
-					// Modal commands are app commands, so they live with the slash commands
-					if command.Type == "slash" || command.Type == "modal" {
+					if IsAppCommand(command.Type) {
 						slashCommandList, _ := JSONToCmdInfoSlice(*modelValues.Map["slashCommands"])
 						slashCommandList = append(slashCommandList, *command)
 						jsonData, _ := CmdInfoSliceToJSON(slashCommandList)
 						modelValues.Map["slashCommands"] = &jsonData
-					} else if command.Type == "prefix" {
+					} else {
 						prefixCommandList, _ := JSONToCmdInfoSlice(*modelValues.Map["prefixCommands"])
-						prefixCommandList = append(prefixCommandList, *command)
+						prefixCommandList = append(followEditedGroup(prefixCommandList, modelValues, *command), *command)
 						jsonData, _ := CmdInfoSliceToJSON(prefixCommandList)
 						modelValues.Map["prefixCommands"] = &jsonData
 					}
@@ -2155,11 +3965,22 @@ func EditFormWrapperGenerator() []FormWrapper {
 	}
 	{ // NOTE: idxEditModInfo
 		values := map[string]*string{
-			"cmdName":        new(string),
-			"cmdType":        new(string),
-			"cmdScope":       new(string),
-			"cmdDescription": new(string),
-			"cmdReturnType":  new(string),
+			"cmdName":             new(string),
+			"cmdType":             new(string),
+			"cmdScope":            new(string),
+			"cmdDescription":      new(string),
+			"cmdReturnType":       new(string),
+			"cmdGroup":            new(string),
+			"cmdGroupDescription": new(string),
+			"cmdPermissions":      new(string),
+			"cmdRoles":            new(string),
+			"cmdGuildOnly":        new(string),
+			"cmdCooldown":         new(string),
+			"cmdAllowedInstalls":  new(string),
+			"cmdAllowedContexts":  new(string),
+			"cmdAliases":          new(string),
+			"cmdHidden":           new(string),
+			"cmdInvokeWithout":    new(string),
 		}
 		wrapper := FormWrapper{
 			Name: "Edit Command Info",
@@ -2180,16 +4001,34 @@ func EditFormWrapperGenerator() []FormWrapper {
 				currentCommand.Type = *formValues.Map["cmdType"]
 				currentCommand.Scope = *formValues.Map["cmdScope"]
 				currentCommand.Description = *formValues.Map["cmdDescription"]
-				// Modal commands only respond through the modal, so their return type is fixed
+				currentCommand.Group = ""
+				currentCommand.GroupDescription = ""
+				if CanBeGrouped(currentCommand.Type) {
+					currentCommand.Group = strings.TrimSpace(*formValues.Map["cmdGroup"])
+					currentCommand.GroupDescription = strings.TrimSpace(*formValues.Map["cmdGroupDescription"])
+				}
+				applyCommandAccess(currentCommand, formValues.Map)
+				applyPrefixOptions(currentCommand, formValues.Map)
+				// The forms leave translations alone, a type change only drops the ones the new type cannot use
+				dropUntranslatedLocalizations(currentCommand)
 				returnType := *formValues.Map["cmdReturnType"]
-				if currentCommand.Type == "modal" {
+				if HasFixedReturnType(currentCommand.Type) {
 					returnType = "None"
 				}
 				currentCommand.ReturnType = returnType
+				// Context menu commands cannot hold arguments, fields, or pages, so there is nothing to redefine
+				if IsContextMenuType(currentCommand.Type) {
+					currentCommand.Args = []ArgInfo{}
+					currentCommand.Fields = []FieldInfo{}
+					currentCommand.Pages = []PageInfo{}
+				}
 				commandString, _ := currentCommand.ToJSON()
 				modelValues.Map["currentCommand"] = &commandString
 			},
 			BranchCallback: func(formValues Values, allForms []FormWrapper) int {
+				if IsContextMenuType(*formValues.Map["cmdType"]) {
+					return idxEditRedefineResponses
+				}
 				return idxEditRedefine
 			},
 		}
@@ -2225,6 +4064,7 @@ func EditFormWrapperGenerator() []FormWrapper {
 				currentCommand.Args = []ArgInfo{}
 				currentCommand.Fields = []FieldInfo{}
 				currentCommand.Pages = []PageInfo{}
+				currentCommand.Components = nil
 				commandString, _ := currentCommand.ToJSON()
 				modelValues.Map["currentCommand"] = &commandString
 
@@ -2250,6 +4090,174 @@ func EditFormWrapperGenerator() []FormWrapper {
 		}
 		forms = append(forms, wrapper)
 	}
+	{ // NOTE: idxEditComponentView
+		values := map[string]*string{
+			"componentTimeout":    new(string),
+			"componentAuthorOnly": new(string),
+		}
+		wrapper := FormWrapper{
+			Name: "Edit Component View",
+			Form: addComponentViewFormGenerator,
+			Values: Values{
+				Map:  values,
+				Name: "editComponentViewValues",
+			},
+			ShowStatus:    false,
+			FormGroup:     "component",
+			SkipCondition: skipUnlessComponentCommand,
+			Callback: func(formValues Values, modelValues Values, allForms []FormWrapper) {
+				currentCommand, _ := JSONToCmdInfo(*modelValues.Map["currentCommand"])
+
+				currentCommand.Components = buildComponentsFromForm(formValues.Map)
+				commandString, _ := currentCommand.ToJSON()
+				modelValues.Map["currentCommand"] = &commandString
+			},
+		}
+		forms = append(forms, wrapper)
+	}
+	{ // NOTE: idxEditComponentStart
+		values := map[string]*string{
+			"componentStartConfirm": new(string),
+		}
+		wrapper := FormWrapper{
+			Name: "Edit Component Start",
+			Form: addComponentStartFormGenerator,
+			Values: Values{
+				Map:  values,
+				Name: "editComponentStartValues",
+			},
+			ShowStatus:    false,
+			FormGroup:     "component",
+			SkipCondition: skipUnlessComponentCommand,
+			Callback: func(formValues Values, modelValues Values, allForms []FormWrapper) {
+				allForms[idxEditComponentInfo].Values.Map["componentName"] = new(string)
+				allForms[idxEditComponentInfo].Values.Map["componentType"] = new(string)
+				allForms[idxEditComponentInfo].Values.Map["componentLabel"] = new(string)
+				allForms[idxEditComponentInfo].Values.Map["componentStyle"] = new(string)
+				allForms[idxEditComponentInfo].Values.Map["componentResponseContent"] = new(string)
+				allForms[idxEditComponentInfo].Values.Map["componentResponseEphemeral"] = new(string)
+			},
+			BranchCallback: func(formValues Values, allForms []FormWrapper) int {
+				if *formValues.Map["componentStartConfirm"] == "yes" {
+					return -1
+				}
+				return idxEditRedefineResponses
+			},
+		}
+		forms = append(forms, wrapper)
+	}
+	{ // NOTE: idxEditComponentInfo
+		values := map[string]*string{
+			"componentName":              new(string),
+			"componentType":              new(string),
+			"componentLabel":             new(string),
+			"componentStyle":             new(string),
+			"componentResponseContent":   new(string),
+			"componentResponseEphemeral": new(string),
+			"viewFull":                   new(string),
+		}
+		wrapper := FormWrapper{
+			Name: "Edit Component Info",
+			Form: addComponentInfoFormGenerator,
+			Values: Values{
+				Map:  values,
+				Name: "editComponentInfoValues",
+			},
+			ShowStatus: false,
+			FormGroup:  "component",
+			Callback: func(formValues Values, modelValues Values, allForms []FormWrapper) {
+				currentCommand, _ := JSONToCmdInfo(*modelValues.Map["currentCommand"])
+				if currentCommand.Components == nil {
+					currentCommand.Components = &ComponentsInfo{Items: []ComponentInfo{}}
+				}
+
+				component := buildComponentFromForm(formValues.Map)
+				currentCommand.Components.Items = append(currentCommand.Components.Items, component)
+				commandString, _ := currentCommand.ToJSON()
+				modelValues.Map["currentCommand"] = &commandString
+
+				// The branch callback cannot see the model values, so whether the view has room rides on the form
+				viewFull := "no"
+				if viewIsFull(currentCommand.Components.Items) {
+					viewFull = "yes"
+				}
+				formValues.Map["viewFull"] = &viewFull
+
+				// A new select menu collects its own options
+				allForms[idxEditSelectOptionInfo].Values.Map["selectOptions"] = new(string)
+				resetSelectOptionInputs(allForms[idxEditSelectOptionInfo].Values)
+			},
+			BranchCallback: func(formValues Values, allForms []FormWrapper) int {
+				if *formValues.Map["componentType"] == "select" {
+					return idxEditSelectOptionInfo
+				}
+				if *formValues.Map["viewFull"] == "yes" {
+					return idxEditRedefineResponses
+				}
+				return idxEditComponentStart
+			},
+		}
+		forms = append(forms, wrapper)
+	}
+	{ // NOTE: idxEditSelectOptionInfo
+		values := map[string]*string{
+			"selectOptions":           new(string),
+			"optionLabel":             new(string),
+			"optionValue":             new(string),
+			"optionResponseContent":   new(string),
+			"optionResponseEphemeral": new(string),
+			"optionAnotherConfirm":    new(string),
+		}
+		wrapper := FormWrapper{
+			Name: "Edit Select Option Info",
+			Form: addSelectOptionInfoFormGenerator,
+			Values: Values{
+				Map:  values,
+				Name: "editSelectOptionInfoValues",
+			},
+			ShowStatus: false,
+			FormGroup:  "component",
+			Callback: func(formValues Values, modelValues Values, allForms []FormWrapper) {
+				currentCommand, _ := JSONToCmdInfo(*modelValues.Map["currentCommand"])
+				if currentCommand.Components == nil || len(currentCommand.Components.Items) == 0 {
+					return
+				}
+
+				// Options always belong to the select menu added last
+				last := &currentCommand.Components.Items[len(currentCommand.Components.Items)-1]
+				last.Options = append(last.Options, buildSelectOptionFromForm(formValues.Map))
+				optionString, _ := json.Marshal(last.Options)
+				options := string(optionString)
+				formValues.Map["selectOptions"] = &options
+				commandString, _ := currentCommand.ToJSON()
+				modelValues.Map["currentCommand"] = &commandString
+
+				viewFull := "no"
+				if viewIsFull(currentCommand.Components.Items) {
+					viewFull = "yes"
+				}
+				allForms[idxEditComponentInfo].Values.Map["viewFull"] = &viewFull
+			},
+			BranchCallback: func(formValues Values, allForms []FormWrapper) int {
+				var options []SelectOptionInfo
+				_ = json.Unmarshal([]byte(*formValues.Map["selectOptions"]), &options)
+				if *formValues.Map["optionAnotherConfirm"] == "yes" && len(options) < MaxSelectOptions {
+					return idxEditSelectOptionInfo
+				}
+				if *allForms[idxEditComponentInfo].Values.Map["viewFull"] == "yes" {
+					return idxEditRedefineResponses
+				}
+				return idxEditComponentStart
+			},
+			BranchValueHandler: func(targetFormIndex int, targetValues Values) {
+				// Looping back for another option keeps the collected options but clears the inputs
+				if targetFormIndex == idxEditSelectOptionInfo {
+					resetSelectOptionInputs(targetValues)
+				}
+			},
+		}
+		forms = append(forms, wrapper)
+	}
 	{ // NOTE: idxEditRedefineResponses
 		values := map[string]*string{
 			"redefineResponsesConfirm": new(string),
@@ -2313,8 +4321,7 @@ func EditFormWrapperGenerator() []FormWrapper {
 			ShowStatus: false,
 			FormGroup:  "response",
 			Callback: func(formValues Values, modelValues Values, allForms []FormWrapper) {
-				allForms[idxEditResponseInfo].Values.Map["responseContent"] = new(string)
-				allForms[idxEditResponseInfo].Values.Map["responseEphemeral"] = new(string)
+				resetResponseInputs(allForms[idxEditResponseInfo].Values)
 			},
 			BranchCallback: func(formValues Values, allForms []FormWrapper) int {
 				if *formValues.Map["responseStartConfirm"] == "yes" {
@@ -2327,9 +4334,17 @@ func EditFormWrapperGenerator() []FormWrapper {
 	}
 	{ // NOTE: idxEditResponseInfo
 		values := map[string]*string{
-			"responses":         new(string),
-			"responseContent":   new(string),
-			"responseEphemeral": new(string),
+			"responses":              new(string),
+			"responseType":           new(string),
+			"responseContent":        new(string),
+			"responseEphemeral":      new(string),
+			"responseEmbedTitle":     new(string),
+			"responseEmbedColor":     new(string),
+			"responseEmbedFields":    new(string),
+			"responseEmbedInline":    new(string),
+			"responseEmbedFooter":    new(string),
+			"responseEmbedThumbnail": new(string),
+			"responseChannelID":      new(string),
 		}
 		wrapper := FormWrapper{
 			Name: "Edit Response Info",
@@ -2343,12 +4358,7 @@ func EditFormWrapperGenerator() []FormWrapper {
 			Callback: func(formValues Values, modelValues Values, allForms []FormWrapper) {
 				currentCommand, _ := JSONToCmdInfo(*modelValues.Map["currentCommand"])
 
-				// Plain messages are the only response type that exists today
-				currentCommand.Responses = append(currentCommand.Responses, ResponseInfo{
-					Type:      "message",
-					Content:   *formValues.Map["responseContent"],
-					Ephemeral: *formValues.Map["responseEphemeral"] == "yes",
-				})
+				currentCommand.Responses = append(currentCommand.Responses, buildResponseFromForm(formValues.Map))
 				responseString, _ := ResponseInfoSliceToJSON(currentCommand.Responses)
 				formValues.Map["responses"] = &responseString
 				commandString, _ := currentCommand.ToJSON()
@@ -2397,7 +4407,7 @@ func EditFormWrapperGenerator() []FormWrapper {
 				// The picked command leaves its list so name checks run against the rest
 				var command *CommandInfo
 				for i, candidate := range slashCommandList {
-					if candidate.Name == name {
+					if CommandPath(candidate) == name {
 						picked := candidate
 						command = &picked
 						slashCommandList = append(slashCommandList[:i], slashCommandList[i+1:]...)
@@ -2406,7 +4416,7 @@ func EditFormWrapperGenerator() []FormWrapper {
 				}
 				if command == nil {
 					for i, candidate := range prefixCommandList {
-						if candidate.Name == name {
+						if CommandPath(candidate) == name {
 							picked := candidate
 							command = &picked
 							prefixCommandList = append(prefixCommandList[:i], prefixCommandList[i+1:]...)
@@ -2434,11 +4444,27 @@ func EditFormWrapperGenerator() []FormWrapper {
 				cmdScope := command.Scope
 				cmdDescription := command.Description
 				cmdReturnType := command.ReturnType
+				cmdGroup := command.Group
+				cmdGroupDescription := command.GroupDescription
 				allForms[idxEditModInfo].Values.Map["cmdName"] = &cmdName
 				allForms[idxEditModInfo].Values.Map["cmdType"] = &cmdType
 				allForms[idxEditModInfo].Values.Map["cmdScope"] = &cmdScope
 				allForms[idxEditModInfo].Values.Map["cmdDescription"] = &cmdDescription
 				allForms[idxEditModInfo].Values.Map["cmdReturnType"] = &cmdReturnType
+				allForms[idxEditModInfo].Values.Map["cmdGroup"] = &cmdGroup
+				allForms[idxEditModInfo].Values.Map["cmdGroupDescription"] = &cmdGroupDescription
+				cmdPermissions := strings.Join(command.Permissions, ", ")
+				cmdRoles := strings.Join(command.Roles, ", ")
+				cmdCooldown := formatCooldownInput(command.Cooldown)
+				cmdAllowedInstalls := strings.Join(command.AllowedInstalls, ", ")
+				cmdAllowedContexts := strings.Join(command.AllowedContexts, ", ")
+				allForms[idxEditModInfo].Values.Map["cmdPermissions"] = &cmdPermissions
+				allForms[idxEditModInfo].Values.Map["cmdRoles"] = &cmdRoles
+				allForms[idxEditModInfo].Values.Map["cmdCooldown"] = &cmdCooldown
+				allForms[idxEditModInfo].Values.Map["cmdAllowedInstalls"] = &cmdAllowedInstalls
+				allForms[idxEditModInfo].Values.Map["cmdAllowedContexts"] = &cmdAllowedContexts
+				cmdAliases := strings.Join(command.Aliases, ", ")
+				allForms[idxEditModInfo].Values.Map["cmdAliases"] = &cmdAliases
 
 				yes := "yes"
 				formValues.Map["editFound"] = &yes
@@ -2479,23 +4505,136 @@ func EditFormWrapperGenerator() []FormWrapper {
 				}
 
 				slashCommandList, _ := JSONToCmdInfoSlice(*modelValues.Map["slashCommands"])
-				for i, candidate := range slashCommandList {
-					if candidate.Name == name {
-						slashCommandList = append(slashCommandList[:i], slashCommandList[i+1:]...)
-						slashJSON, _ := CmdInfoSliceToJSON(slashCommandList)
-						modelValues.Map["slashCommands"] = &slashJSON
-						return
-					}
+				if remaining, removed := RemoveCommand(slashCommandList, name); removed {
+					slashJSON, _ := CmdInfoSliceToJSON(remaining)
+					modelValues.Map["slashCommands"] = &slashJSON
+					return
 				}
+				// Removing a prefix group command also removes its subcommands
 				prefixCommandList, _ := JSONToCmdInfoSlice(*modelValues.Map["prefixCommands"])
-				for i, candidate := range prefixCommandList {
-					if candidate.Name == name {
-						prefixCommandList = append(prefixCommandList[:i], prefixCommandList[i+1:]...)
-						prefixJSON, _ := CmdInfoSliceToJSON(prefixCommandList)
-						modelValues.Map["prefixCommands"] = &prefixJSON
-						return
-					}
+				if remaining, removed := RemoveCommand(prefixCommandList, name); removed {
+					prefixJSON, _ := CmdInfoSliceToJSON(remaining)
+					modelValues.Map["prefixCommands"] = &prefixJSON
+				}
+			},
+			BranchCallback: func(formValues Values, allForms []FormWrapper) int {
+				return idxEditAction
+			},
+		}
+		forms = append(forms, wrapper)
+	}
+	{ // NOTE: idxEditListenerInfo
+		values := map[string]*string{
+			"listenerEvent":     new(string),
+			"listenerAction":    new(string),
+			"listenerChannelID": new(string),
+			"listenerContent":   new(string),
+			"listenerRoleID":    new(string),
+		}
+		wrapper := FormWrapper{
+			Name: "Edit Listener Info",
+			Form: addListenerInfoFormGenerator,
+			Values: Values{
+				Map:  values,
+				Name: "editListenerInfoValues",
+			},
+			ShowStatus: false,
+			FormGroup:  "listener",
+			Callback: func(formValues Values, modelValues Values, allForms []FormWrapper) {
+				putListener(modelValues, buildListenerFromForm(formValues.Map))
+			},
+			BranchCallback: func(formValues Values, allForms []FormWrapper) int {
+				return idxEditAction
+			},
+		}
+		forms = append(forms, wrapper)
+	}
+	{ // NOTE: idxEditRemoveListener
+		values := map[string]*string{
+			"removeListenerEvent":   new(string),
+			"removeListenerConfirm": new(string),
+		}
+		wrapper := FormWrapper{
+			Name: "Edit Remove Listener",
+			Form: editRemoveListenerFormGenerator,
+			Values: Values{
+				Map:  values,
+				Name: "editRemoveListenerValues",
+			},
+			ShowStatus: false,
+			FormGroup:  "listener",
+			Callback: func(formValues Values, modelValues Values, allForms []FormWrapper) {
+				if *formValues.Map["removeListenerConfirm"] != "yes" || *formValues.Map["removeListenerEvent"] == "" {
+					return
+				}
+				event := *formValues.Map["removeListenerEvent"]
+				listeners, _ := JSONToListenerInfoSlice(*modelValues.Map["listeners"])
+				listeners = slices.DeleteFunc(listeners, func(listener ListenerInfo) bool {
+					return listener.Event == event
+				})
+				listenerJSON, _ := ListenerInfoSliceToJSON(listeners)
+				modelValues.Map["listeners"] = &listenerJSON
+			},
+			BranchCallback: func(formValues Values, allForms []FormWrapper) int {
+				return idxEditAction
+			},
+		}
+		forms = append(forms, wrapper)
+	}
+	{ // NOTE: idxEditTaskInfo
+		values := map[string]*string{
+			"taskName":      new(string),
+			"taskSchedule":  new(string),
+			"taskInterval":  new(string),
+			"taskUnit":      new(string),
+			"taskTimes":     new(string),
+			"taskTimezone":  new(string),
+			"taskChannelID": new(string),
+			"taskContent":   new(string),
+		}
+		wrapper := FormWrapper{
+			Name: "Edit Task Info",
+			Form: addTaskInfoFormGenerator,
+			Values: Values{
+				Map:  values,
+				Name: "editTaskInfoValues",
+			},
+			ShowStatus: false,
+			FormGroup:  "task",
+			Callback: func(formValues Values, modelValues Values, allForms []FormWrapper) {
+				putTask(modelValues, buildTaskFromForm(formValues.Map))
+			},
+			BranchCallback: func(formValues Values, allForms []FormWrapper) int {
+				return idxEditAction
+			},
+		}
+		forms = append(forms, wrapper)
+	}
+	{ // NOTE: idxEditRemoveTask
+		values := map[string]*string{
+			"removeTaskName":    new(string),
+			"removeTaskConfirm": new(string),
+		}
+		wrapper := FormWrapper{
+			Name: "Edit Remove Task",
+			Form: editRemoveTaskFormGenerator,
+			Values: Values{
+				Map:  values,
+				Name: "editRemoveTaskValues",
+			},
+			ShowStatus: false,
+			FormGroup:  "task",
+			Callback: func(formValues Values, modelValues Values, allForms []FormWrapper) {
+				if *formValues.Map["removeTaskConfirm"] != "yes" || *formValues.Map["removeTaskName"] == "" {
+					return
 				}
+				name := *formValues.Map["removeTaskName"]
+				taskList, _ := JSONToTaskInfoSlice(*modelValues.Map["tasks"])
+				taskList = slices.DeleteFunc(taskList, func(task TaskInfo) bool {
+					return task.Name == name
+				})
+				taskJSON, _ := TaskInfoSliceToJSON(taskList)
+				modelValues.Map["tasks"] = &taskJSON
 			},
 			BranchCallback: func(formValues Values, allForms []FormWrapper) int {
 				return idxEditAction
@@ -2507,7 +4646,7 @@ func EditFormWrapperGenerator() []FormWrapper {
 	return forms
 }
 
-// editLoadCogCommands fills the model value bus with the selected cog's command lists
+// editLoadCogCommands fills the model value bus with the selected cog's command, listener, and task lists
 func editLoadCogCommands(modelValues Values) {
 	config, err := LoadConfig()
 	if err != nil {
@@ -2529,23 +4668,35 @@ func editLoadCogCommands(modelValues Values) {
 		modelValues.Map["slashCommands"] = &slashJSON
 		prefixJSON, _ := CmdInfoSliceToJSON(prefixCommands)
 		modelValues.Map["prefixCommands"] = &prefixJSON
+		listeners := cog.Listeners
+		if listeners == nil {
+			listeners = []ListenerInfo{}
+		}
+		listenerJSON, _ := ListenerInfoSliceToJSON(listeners)
+		modelValues.Map["listeners"] = &listenerJSON
+		taskList := cog.Tasks
+		if taskList == nil {
+			taskList = []TaskInfo{}
+		}
+		taskJSON, _ := TaskInfoSliceToJSON(taskList)
+		modelValues.Map["tasks"] = &taskJSON
 		return
 	}
 }
 
-// editCommandNames lists every command name on the model value bus, slash commands first
+// editCommandNames lists the full path of every command on the model value bus, slash commands first
 func editCommandNames(modelValues Values) []string {
 	var names []string
 	if modelValues.Map["slashCommands"] != nil {
 		slashCommandList, _ := JSONToCmdInfoSlice(*modelValues.Map["slashCommands"])
 		for _, command := range slashCommandList {
-			names = append(names, command.Name)
+			names = append(names, CommandPath(command))
 		}
 	}
 	if modelValues.Map["prefixCommands"] != nil {
 		prefixCommandList, _ := JSONToCmdInfoSlice(*modelValues.Map["prefixCommands"])
 		for _, command := range prefixCommandList {
-			names = append(names, command.Name)
+			names = append(names, CommandPath(command))
 		}
 	}
 	return names
@@ -2615,6 +4766,10 @@ func editActionFormGenerator(values Values, modelValues Values) *huh.Form {
 					huh.NewOption("Add a command", "add"),
 					huh.NewOption("Edit a command", "edit"),
 					huh.NewOption("Remove a command", "remove"),
+					huh.NewOption("Add or change a listener", "listener"),
+					huh.NewOption("Remove a listener", "removeListener"),
+					huh.NewOption("Add or change a task", "task"),
+					huh.NewOption("Remove a task", "removeTask"),
 					huh.NewOption("Apply changes", "apply"),
 				),
 		),
@@ -2672,6 +4827,94 @@ func editRemoveCommandFormGenerator(values Values, modelValues Values) *huh.Form
 	return removeForm
 }
 
+func editRemoveListenerFormGenerator(values Values, modelValues Values) *huh.Form {
+	var events []string
+	if modelValues.Map["listeners"] != nil && *modelValues.Map["listeners"] != "" {
+		listeners, _ := JSONToListenerInfoSlice(*modelValues.Map["listeners"])
+		for _, listener := range listeners {
+			events = append(events, listener.Event)
+		}
+	}
+	if len(events) == 0 {
+		return huh.NewForm(
+			huh.NewGroup(
+				huh.NewNote().
+					Title("No Listeners").
+					Description("This cog has no event listeners yet. Add a listener first."),
+			),
+		)
+	}
+
+	removeForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Value(values.Map["removeListenerEvent"]).
+				Height(8).
+				Title("Select a listener to remove").
+				Options(huh.NewOptions(events...)...),
+			huh.NewConfirm().
+				Title("Remove this listener?").
+				Affirmative("yes").
+				Negative("no").
+				Validate(func(b bool) error {
+					var s string
+					if b {
+						s = "yes"
+					} else {
+						s = "no"
+					}
+					values.Map["removeListenerConfirm"] = &s
+					return nil
+				}),
+		),
+	)
+	return removeForm
+}
+
+func editRemoveTaskFormGenerator(values Values, modelValues Values) *huh.Form {
+	var names []string
+	if modelValues.Map["tasks"] != nil && *modelValues.Map["tasks"] != "" {
+		taskList, _ := JSONToTaskInfoSlice(*modelValues.Map["tasks"])
+		for _, task := range taskList {
+			names = append(names, task.Name)
+		}
+	}
+	if len(names) == 0 {
+		return huh.NewForm(
+			huh.NewGroup(
+				huh.NewNote().
+					Title("No Tasks").
+					Description("This cog has no scheduled tasks yet. Add a task first."),
+			),
+		)
+	}
+
+	removeForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Value(values.Map["removeTaskName"]).
+				Height(8).
+				Title("Select a task to remove").
+				Options(huh.NewOptions(names...)...),
+			huh.NewConfirm().
+				Title("Remove this task?").
+				Affirmative("yes").
+				Negative("no").
+				Validate(func(b bool) error {
+					var s string
+					if b {
+						s = "yes"
+					} else {
+						s = "no"
+					}
+					values.Map["removeTaskConfirm"] = &s
+					return nil
+				}),
+		),
+	)
+	return removeForm
+}
+
 func editRedefineFormGenerator(values Values, modelValues Values) *huh.Form {
 	redefineForm := huh.NewForm(
 		huh.NewGroup(
@@ -6,6 +6,7 @@ See end of file for extended copyright information
 package utils
 
 import (
+	"slices"
 	"strings"
 	"testing"
 )
@@ -27,8 +28,16 @@ const (
 	testIdxBranchStart
 	testIdxBranchInfo
 	testIdxPageNext
+	testIdxComponentView
+	testIdxComponentStart
+	testIdxComponentInfo
+	testIdxSelectOptionInfo
 	testIdxResponseStart
 	testIdxResponseInfo
+	testIdxListenerStart
+	testIdxListenerInfo
+	testIdxTaskStart
+	testIdxTaskInfo
 )
 
 // setFormValue plants a value on a wrapper as if the form had collected it
@@ -46,13 +55,19 @@ func newAddModelValues() Values {
 		"pages":          new(string),
 		"slashCommands":  new(string),
 		"prefixCommands": new(string),
+		"listeners":      new(string),
+		"tasks":          new(string),
 	}
 	emptySlash := "[]"
 	emptyPrefix := "[]"
 	emptyPages := "[]"
+	emptyListeners := "[]"
+	emptyTasks := "[]"
 	values["slashCommands"] = &emptySlash
 	values["prefixCommands"] = &emptyPrefix
 	values["pages"] = &emptyPages
+	values["listeners"] = &emptyListeners
+	values["tasks"] = &emptyTasks
 	return Values{Map: values, Name: "ModelValues"}
 }
 
@@ -73,8 +88,8 @@ func makeFields(count int) []FieldInfo {
 
 func TestAddFormWrapperGeneratorFormCount(t *testing.T) {
 	forms := AddFormWrapperGenerator()
-	if len(forms) != testIdxResponseInfo+1 {
-		t.Fatalf("expected %d forms, got %d", testIdxResponseInfo+1, len(forms))
+	if len(forms) != testIdxTaskInfo+1 {
+		t.Fatalf("expected %d forms, got %d", testIdxTaskInfo+1, len(forms))
 	}
 }
 
@@ -87,6 +102,9 @@ func TestCmdInfoBranchRouting(t *testing.T) {
 		{"modal goes to multi page confirm", "modal", testIdxMultiPage},
 		{"slash goes to the arg loop", "slash", -1},
 		{"prefix goes to the arg loop", "prefix", -1},
+		{"hybrid goes to the arg loop", "hybrid", -1},
+		{"user context skips to responses", "user_context", testIdxResponseStart},
+		{"message context skips to responses", "message_context", testIdxResponseStart},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -100,6 +118,358 @@ func TestCmdInfoBranchRouting(t *testing.T) {
 	}
 }
 
+func TestCmdInfoCallbackKeepsGroupOnlyForGroupableTypes(t *testing.T) {
+	tests := []struct {
+		cmdType   string
+		wantGroup string
+	}{
+		{"slash", "ticket admin"},
+		{"modal", "ticket admin"},
+		{"prefix", "ticket admin"},
+		{"hybrid", ""},
+		{"user_context", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cmdType, func(t *testing.T) {
+			forms := AddFormWrapperGenerator()
+			modelValues := newAddModelValues()
+			setFormValue(forms, testIdxCmdInfo, "cmdName", "close")
+			setFormValue(forms, testIdxCmdInfo, "cmdType", tt.cmdType)
+			setFormValue(forms, testIdxCmdInfo, "cmdScope", "guild")
+			setFormValue(forms, testIdxCmdInfo, "cmdDescription", "Closes a ticket")
+			setFormValue(forms, testIdxCmdInfo, "cmdReturnType", "None")
+			setFormValue(forms, testIdxCmdInfo, "cmdGroup", " ticket admin ")
+			setFormValue(forms, testIdxCmdInfo, "cmdGroupDescription", "Admin tools")
+
+			forms[testIdxCmdInfo].Callback(forms[testIdxCmdInfo].Values, modelValues, forms)
+
+			current, err := JSONToCmdInfo(*modelValues.Map["currentCommand"])
+			if err != nil {
+				t.Fatalf("failed to parse current command: %v", err)
+			}
+			if current.Group != tt.wantGroup {
+				t.Errorf("Group = %q, want %q", current.Group, tt.wantGroup)
+			}
+		})
+	}
+}
+
+func TestCmdInfoCallbackBuildsCommandAccess(t *testing.T) {
+	tests := []struct {
+		name         string
+		cmdType      string
+		guildOnly    string
+		wantInstalls []string
+	}{
+		{"slash keeps installs", "slash", "no", []string{"guild", "user"}},
+		{"prefix drops installs", "prefix", "no", nil},
+		{"hybrid drops installs", "hybrid", "no", nil},
+		{"guild only drops installs", "slash", "yes", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forms := AddFormWrapperGenerator()
+			modelValues := newAddModelValues()
+			setFormValue(forms, testIdxCmdInfo, "cmdName", "purge")
+			setFormValue(forms, testIdxCmdInfo, "cmdType", tt.cmdType)
+			setFormValue(forms, testIdxCmdInfo, "cmdScope", "guild")
+			setFormValue(forms, testIdxCmdInfo, "cmdDescription", "Deletes messages")
+			setFormValue(forms, testIdxCmdInfo, "cmdReturnType", "None")
+			setFormValue(forms, testIdxCmdInfo, "cmdPermissions", "manage_messages, ")
+			setFormValue(forms, testIdxCmdInfo, "cmdRoles", "Moderator, 1234")
+			setFormValue(forms, testIdxCmdInfo, "cmdCooldown", " 3/7.5 user ")
+			setFormValue(forms, testIdxCmdInfo, "cmdGuildOnly", tt.guildOnly)
+			setFormValue(forms, testIdxCmdInfo, "cmdAllowedInstalls", "guild, user")
+
+			forms[testIdxCmdInfo].Callback(forms[testIdxCmdInfo].Values, modelValues, forms)
+
+			current, err := JSONToCmdInfo(*modelValues.Map["currentCommand"])
+			if err != nil {
+				t.Fatalf("failed to parse current command: %v", err)
+			}
+			if !slices.Equal(current.Permissions, []string{"manage_messages"}) || !slices.Equal(current.Roles, []string{"Moderator", "1234"}) {
+				t.Errorf("Permissions = %v, Roles = %v", current.Permissions, current.Roles)
+			}
+			if current.Cooldown == nil || *current.Cooldown != (CooldownInfo{Rate: 3, Per: 7.5, Bucket: "user"}) {
+				t.Errorf("Cooldown = %+v, want 3/7.5 user", current.Cooldown)
+			}
+			if current.GuildOnly != (tt.guildOnly == "yes") {
+				t.Errorf("GuildOnly = %v, want %v", current.GuildOnly, tt.guildOnly == "yes")
+			}
+			if !slices.Equal(current.AllowedInstalls, tt.wantInstalls) {
+				t.Errorf("AllowedInstalls = %v, want %v", current.AllowedInstalls, tt.wantInstalls)
+			}
+		})
+	}
+}
+
+func TestArgInfoCallbackBuildsArgOptions(t *testing.T) {
+	forms := AddFormWrapperGenerator()
+	modelValues := newAddModelValues()
+	command := CommandInfo{Name: "paint", Type: "slash", Scope: "guild", Description: "Paints", ReturnType: "None"}
+	commandString, _ := command.ToJSON()
+	setModelValue(modelValues, "currentCommand", commandString)
+	setFormValue(forms, testIdxArgInfo, "argName", "color")
+	setFormValue(forms, testIdxArgInfo, "argType", "str")
+	setFormValue(forms, testIdxArgInfo, "argDescription", "Which color")
+	setFormValue(forms, testIdxArgInfo, "argOptional", "yes")
+	setFormValue(forms, testIdxArgInfo, "argDefault", "red")
+	setFormValue(forms, testIdxArgInfo, "argChoices", "Red=red, Blue = blue, green")
+
+	forms[testIdxArgInfo].Callback(forms[testIdxArgInfo].Values, modelValues, forms)
+
+	current, err := JSONToCmdInfo(*modelValues.Map["currentCommand"])
+	if err != nil {
+		t.Fatalf("failed to parse current command: %v", err)
+	}
+	want := ArgInfo{
+		Name:        "color",
+		Type:        "str",
+		Description: "Which color",
+		Optional:    true,
+		Default:     "red",
+		Choices:     []ChoiceInfo{{Name: "Red", Value: "red"}, {Name: "Blue", Value: "blue"}, {Name: "green", Value: "green"}},
+	}
+	if len(current.Args) != 1 || !argEqual(current.Args[0], want) {
+		t.Errorf("args = %+v, want [%+v]", current.Args, want)
+	}
+}
+
+func TestArgInfoCallbackBuildsTransformType(t *testing.T) {
+	forms := AddFormWrapperGenerator()
+	modelValues := newAddModelValues()
+	command := CommandInfo{Name: "tag", Type: "slash", Scope: "guild", Description: "Shows a tag", ReturnType: "None"}
+	commandString, _ := command.ToJSON()
+	setModelValue(modelValues, "currentCommand", commandString)
+	setFormValue(forms, testIdxArgInfo, "argName", "tag")
+	setFormValue(forms, testIdxArgInfo, "argType", "transform")
+	setFormValue(forms, testIdxArgInfo, "argTransformer", " TagTransformer ")
+	setFormValue(forms, testIdxArgInfo, "argTransformerType", "int")
+	setFormValue(forms, testIdxArgInfo, "argDescription", "Tag to show")
+	setFormValue(forms, testIdxArgInfo, "argOptional", "no")
+
+	forms[testIdxArgInfo].Callback(forms[testIdxArgInfo].Values, modelValues, forms)
+
+	current, err := JSONToCmdInfo(*modelValues.Map["currentCommand"])
+	if err != nil {
+		t.Fatalf("failed to parse current command: %v", err)
+	}
+	want := ArgInfo{Name: "tag", Type: "app_commands.Transform[int, TagTransformer]", Description: "Tag to show"}
+	if len(current.Args) != 1 || !argEqual(current.Args[0], want) {
+		t.Errorf("args = %+v, want [%+v]", current.Args, want)
+	}
+}
+
+func TestArgInfoCallbackBuildsPrefixArgs(t *testing.T) {
+	forms := AddFormWrapperGenerator()
+	modelValues := newAddModelValues()
+	command := CommandInfo{Name: "kick", Type: "prefix", Scope: "guild", Description: "Kicks members", ReturnType: "None"}
+	commandString, _ := command.ToJSON()
+	setModelValue(modelValues, "currentCommand", commandString)
+	setFormValue(forms, testIdxArgInfo, "argName", "members")
+	setFormValue(forms, testIdxArgInfo, "argType", "discord.Member")
+	setFormValue(forms, testIdxArgInfo, "argDescription", "Who to kick")
+	setFormValue(forms, testIdxArgInfo, "argOptional", "no")
+	setFormValue(forms, testIdxArgInfo, "argGreedy", "yes")
+	forms[testIdxArgInfo].Callback(forms[testIdxArgInfo].Values, modelValues, forms)
+
+	setFormValue(forms, testIdxArgInfo, "argName", "reason")
+	setFormValue(forms, testIdxArgInfo, "argType", "str")
+	setFormValue(forms, testIdxArgInfo, "argDescription", "Why")
+	setFormValue(forms, testIdxArgInfo, "argGreedy", "no")
+	setFormValue(forms, testIdxArgInfo, "argRest", "yes")
+	forms[testIdxArgInfo].Callback(forms[testIdxArgInfo].Values, modelValues, forms)
+
+	current, err := JSONToCmdInfo(*modelValues.Map["currentCommand"])
+	if err != nil {
+		t.Fatalf("failed to parse current command: %v", err)
+	}
+	want := []ArgInfo{
+		{Name: "members", Type: "discord.Member", Description: "Who to kick", Greedy: true},
+		{Name: "reason", Type: "str", Description: "Why", Rest: true},
+	}
+	if !slices.EqualFunc(current.Args, want, argEqual) {
+		t.Errorf("args = %+v, want %+v", current.Args, want)
+	}
+}
+
+func TestCmdInfoCallbackBuildsPrefixOptions(t *testing.T) {
+	tests := []struct {
+		cmdType     string
+		wantAliases []string
+		wantHidden  bool
+	}{
+		{"prefix", []string{"t", "tg"}, true},
+		{"slash", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cmdType, func(t *testing.T) {
+			forms := AddFormWrapperGenerator()
+			modelValues := newAddModelValues()
+			setFormValue(forms, testIdxCmdInfo, "cmdName", "tag")
+			setFormValue(forms, testIdxCmdInfo, "cmdType", tt.cmdType)
+			setFormValue(forms, testIdxCmdInfo, "cmdScope", "guild")
+			setFormValue(forms, testIdxCmdInfo, "cmdDescription", "Shows a tag")
+			setFormValue(forms, testIdxCmdInfo, "cmdReturnType", "None")
+			setFormValue(forms, testIdxCmdInfo, "cmdAliases", "t, tg, ")
+			setFormValue(forms, testIdxCmdInfo, "cmdHidden", "yes")
+			setFormValue(forms, testIdxCmdInfo, "cmdInvokeWithout", "yes")
+
+			forms[testIdxCmdInfo].Callback(forms[testIdxCmdInfo].Values, modelValues, forms)
+
+			current, err := JSONToCmdInfo(*modelValues.Map["currentCommand"])
+			if err != nil {
+				t.Fatalf("failed to parse current command: %v", err)
+			}
+			if !slices.Equal(current.Aliases, tt.wantAliases) || current.Hidden != tt.wantHidden || current.InvokeWithoutCommand != tt.wantHidden {
+				t.Errorf("Aliases = %v, Hidden = %v, InvokeWithoutCommand = %v", current.Aliases, current.Hidden, current.InvokeWithoutCommand)
+			}
+		})
+	}
+}
+
+func TestComponentFormsBuildView(t *testing.T) {
+	forms := AddFormWrapperGenerator()
+	modelValues := newAddModelValues()
+
+	slash := CommandInfo{Name: "ping", Type: "slash", Scope: "guild", Description: "Pings", ReturnType: "None"}
+	slashString, _ := slash.ToJSON()
+	setModelValue(modelValues, "currentCommand", slashString)
+	if !forms[testIdxComponentView].SkipCondition(modelValues, forms, testIdxComponentView) {
+		t.Error("slash command should skip the component view")
+	}
+
+	command := CommandInfo{Name: "roles", Type: "component", Scope: "guild", Description: "Hands out roles", ReturnType: "None"}
+	commandString, _ := command.ToJSON()
+	setModelValue(modelValues, "currentCommand", commandString)
+	if forms[testIdxComponentView].SkipCondition(modelValues, forms, testIdxComponentView) {
+		t.Error("component command should not skip the component view")
+	}
+
+	setFormValue(forms, testIdxComponentView, "componentTimeout", "")
+	setFormValue(forms, testIdxComponentView, "componentAuthorOnly", "yes")
+	forms[testIdxComponentView].Callback(forms[testIdxComponentView].Values, modelValues, forms)
+
+	setFormValue(forms, testIdxComponentInfo, "componentName", "confirm")
+	setFormValue(forms, testIdxComponentInfo, "componentType", "button")
+	setFormValue(forms, testIdxComponentInfo, "componentLabel", "Confirm")
+	setFormValue(forms, testIdxComponentInfo, "componentStyle", "success")
+	setFormValue(forms, testIdxComponentInfo, "componentResponseContent", "Saved")
+	setFormValue(forms, testIdxComponentInfo, "componentResponseEphemeral", "yes")
+	forms[testIdxComponentInfo].Callback(forms[testIdxComponentInfo].Values, modelValues, forms)
+	if got := forms[testIdxComponentInfo].BranchCallback(forms[testIdxComponentInfo].Values, forms); got != testIdxComponentStart {
+		t.Errorf("button routed to %d, want %d", got, testIdxComponentStart)
+	}
+
+	setFormValue(forms, testIdxComponentInfo, "componentName", "topics")
+	setFormValue(forms, testIdxComponentInfo, "componentType", "select")
+	setFormValue(forms, testIdxComponentInfo, "componentLabel", "Pick a topic")
+	forms[testIdxComponentInfo].Callback(forms[testIdxComponentInfo].Values, modelValues, forms)
+	if got := forms[testIdxComponentInfo].BranchCallback(forms[testIdxComponentInfo].Values, forms); got != testIdxSelectOptionInfo {
+		t.Errorf("select routed to %d, want %d", got, testIdxSelectOptionInfo)
+	}
+
+	setFormValue(forms, testIdxSelectOptionInfo, "optionLabel", "News")
+	setFormValue(forms, testIdxSelectOptionInfo, "optionValue", " news ")
+	setFormValue(forms, testIdxSelectOptionInfo, "optionResponseContent", "Subscribed")
+	setFormValue(forms, testIdxSelectOptionInfo, "optionResponseEphemeral", "no")
+	setFormValue(forms, testIdxSelectOptionInfo, "optionAnotherConfirm", "yes")
+	forms[testIdxSelectOptionInfo].Callback(forms[testIdxSelectOptionInfo].Values, modelValues, forms)
+	if got := forms[testIdxSelectOptionInfo].BranchCallback(forms[testIdxSelectOptionInfo].Values, forms); got != testIdxSelectOptionInfo {
+		t.Errorf("another option routed to %d, want %d", got, testIdxSelectOptionInfo)
+	}
+
+	setFormValue(forms, testIdxSelectOptionInfo, "optionAnotherConfirm", "no")
+	if got := forms[testIdxSelectOptionInfo].BranchCallback(forms[testIdxSelectOptionInfo].Values, forms); got != testIdxComponentStart {
+		t.Errorf("last option routed to %d, want %d", got, testIdxComponentStart)
+	}
+
+	setFormValue(forms, testIdxComponentStart, "componentStartConfirm", "no")
+	if got := forms[testIdxComponentStart].BranchCallback(forms[testIdxComponentStart].Values, forms); got != testIdxResponseStart {
+		t.Errorf("component start no routed to %d, want %d", got, testIdxResponseStart)
+	}
+
+	current, err := JSONToCmdInfo(*modelValues.Map["currentCommand"])
+	if err != nil {
+		t.Fatalf("failed to parse current command: %v", err)
+	}
+	want := &ComponentsInfo{
+		Timeout:    defaultComponentTimeout,
+		AuthorOnly: true,
+		Items: []ComponentInfo{
+			{Name: "confirm", Type: "button", Label: "Confirm", Style: "success", Response: ResponseInfo{Type: "message", Content: "Saved", Ephemeral: true}},
+			{Name: "topics", Type: "select", Label: "Pick a topic", Options: []SelectOptionInfo{
+				{Label: "News", Value: "news", Response: ResponseInfo{Type: "message", Content: "Subscribed"}},
+			}},
+		},
+	}
+	if !componentsEqual(current.Components, want) {
+		t.Errorf("components = %+v, want %+v", current.Components, want)
+	}
+}
+
+func TestViewIsFull(t *testing.T) {
+	buttons := func(n int) []ComponentInfo {
+		items := make([]ComponentInfo, n)
+		for i := range items {
+			items[i] = ComponentInfo{Type: "button"}
+		}
+		return items
+	}
+	selects := func(n int) []ComponentInfo {
+		items := make([]ComponentInfo, n)
+		for i := range items {
+			items[i] = ComponentInfo{Type: "select"}
+		}
+		return items
+	}
+
+	tests := []struct {
+		name  string
+		items []ComponentInfo
+		want  bool
+	}{
+		{"empty", nil, false},
+		{"four selects", selects(4), false},
+		{"five selects", selects(5), true},
+		{"four selects and a button", append(selects(4), buttons(1)...), false},
+		{"four selects and a full row", append(selects(4), buttons(5)...), true},
+		{"twenty five buttons", buttons(25), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := viewIsFull(tt.items); got != tt.want {
+				t.Errorf("viewIsFull() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArgInfoCallbackBuildsAutocomplete(t *testing.T) {
+	forms := AddFormWrapperGenerator()
+	modelValues := newAddModelValues()
+	command := CommandInfo{Name: "tag", Type: "slash", Scope: "guild", Description: "Shows a tag", ReturnType: "None"}
+	commandString, _ := command.ToJSON()
+	setModelValue(modelValues, "currentCommand", commandString)
+	setFormValue(forms, testIdxArgInfo, "argName", "name")
+	setFormValue(forms, testIdxArgInfo, "argType", "str")
+	setFormValue(forms, testIdxArgInfo, "argDescription", "Tag name")
+	setFormValue(forms, testIdxArgInfo, "argOptional", "no")
+	setFormValue(forms, testIdxArgInfo, "argAutocomplete", "yes")
+	setFormValue(forms, testIdxArgInfo, "argSuggestions", "rules, faq,")
+
+	forms[testIdxArgInfo].Callback(forms[testIdxArgInfo].Values, modelValues, forms)
+
+	current, err := JSONToCmdInfo(*modelValues.Map["currentCommand"])
+	if err != nil {
+		t.Fatalf("failed to parse current command: %v", err)
+	}
+	want := ArgInfo{Name: "name", Type: "str", Description: "Tag name", Autocomplete: true, Suggestions: []string{"rules", "faq"}}
+	if len(current.Args) != 1 || !argEqual(current.Args[0], want) {
+		t.Errorf("args = %+v, want [%+v]", current.Args, want)
+	}
+}
+
 func TestMultiPageConfirmRouting(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -354,9 +724,10 @@ func TestPageNextBranchRouting(t *testing.T) {
 func TestArgAndFieldLoopsRouteToResponseConfirm(t *testing.T) {
 	forms := AddFormWrapperGenerator()
 
+	// Arguments hand off to the component view, which every other command type skips
 	setFormValue(forms, testIdxArgStart, "argStartConfirm", "no")
-	if got := forms[testIdxArgStart].BranchCallback(forms[testIdxArgStart].Values, forms); got != testIdxResponseStart {
-		t.Errorf("arg start no routed to %d, want %d", got, testIdxResponseStart)
+	if got := forms[testIdxArgStart].BranchCallback(forms[testIdxArgStart].Values, forms); got != testIdxComponentView {
+		t.Errorf("arg start no routed to %d, want %d", got, testIdxComponentView)
 	}
 
 	setFormValue(forms, testIdxFieldStart, "fieldStartConfirm", "no")
@@ -412,6 +783,64 @@ func TestResponseInfoCallbackAppendsResponse(t *testing.T) {
 	}
 }
 
+func TestBuildResponseFromForm(t *testing.T) {
+	tests := []struct {
+		name   string
+		values map[string]string
+		want   ResponseInfo
+	}{
+		{
+			"embed",
+			map[string]string{
+				"responseType":           "embed",
+				"responseContent":        "Current numbers",
+				"responseEphemeral":      "yes",
+				"responseEmbedTitle":     "Stats",
+				"responseEmbedColor":     " #5865F2 ",
+				"responseEmbedFields":    "Members = {count}; Online={online};",
+				"responseEmbedInline":    "yes",
+				"responseEmbedFooter":    "Hourly",
+				"responseEmbedThumbnail": "https://example.com/icon.png",
+			},
+			ResponseInfo{Type: "embed", Content: "Current numbers", Ephemeral: true, Embed: &EmbedInfo{
+				Title:     "Stats",
+				Color:     "#5865F2",
+				Fields:    []EmbedFieldInfo{{Name: "Members", Value: "{count}", Inline: true}, {Name: "Online", Value: "{online}", Inline: true}},
+				Footer:    "Hourly",
+				Thumbnail: "https://example.com/icon.png",
+			}},
+		},
+		{
+			"channel drops ephemeral",
+			map[string]string{"responseType": "channel", "responseContent": "Report", "responseEphemeral": "yes", "responseChannelID": " 123456789012345678 "},
+			ResponseInfo{Type: "channel", Content: "Report", ChannelID: "123456789012345678"},
+		},
+		{
+			"dm ignores embed inputs",
+			map[string]string{"responseType": "dm", "responseContent": "Hi", "responseEmbedTitle": "left over"},
+			ResponseInfo{Type: "dm", Content: "Hi"},
+		},
+		{
+			"defer",
+			map[string]string{"responseType": "defer", "responseContent": "Done", "responseEphemeral": "yes"},
+			ResponseInfo{Type: "defer", Content: "Done", Ephemeral: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := Values{Map: map[string]*string{}}
+			resetResponseInputs(values)
+			for key, value := range tt.values {
+				v := value
+				values.Map[key] = &v
+			}
+			if got := buildResponseFromForm(values.Map); !responseEqual(got, tt.want) {
+				t.Errorf("buildResponseFromForm() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestResponseInfoBranchStopsAtMaxResponses(t *testing.T) {
 	forms := AddFormWrapperGenerator()
 	fullResponses := make([]ResponseInfo, MaxCommandResponses)
@@ -431,6 +860,108 @@ func TestResponseInfoBranchStopsAtMaxResponses(t *testing.T) {
 	}
 }
 
+func TestListenerFormsFlow(t *testing.T) {
+	forms := AddFormWrapperGenerator()
+	modelValues := newAddModelValues()
+
+	// Declining another command moves on to the listeners
+	setFormValue(forms, testIdxCmdStart, "cmdStartConfirm", "no")
+	if got := forms[testIdxCmdStart].BranchCallback(forms[testIdxCmdStart].Values, forms); got != testIdxListenerStart {
+		t.Fatalf("command start no routed to %d, want %d", got, testIdxListenerStart)
+	}
+
+	setFormValue(forms, testIdxListenerInfo, "listenerEvent", "stale")
+	forms[testIdxListenerStart].Callback(forms[testIdxListenerStart].Values, modelValues, forms)
+	if got := *forms[testIdxListenerInfo].Values.Map["listenerEvent"]; got != "" {
+		t.Errorf("listener start should reset the event, got %q", got)
+	}
+
+	setFormValue(forms, testIdxListenerInfo, "listenerEvent", "on_member_join")
+	setFormValue(forms, testIdxListenerInfo, "listenerAction", "role")
+	setFormValue(forms, testIdxListenerInfo, "listenerChannelID", "123456789012345678")
+	setFormValue(forms, testIdxListenerInfo, "listenerRoleID", " 223456789012345678 ")
+	forms[testIdxListenerInfo].Callback(forms[testIdxListenerInfo].Values, modelValues, forms)
+
+	// Picking the same event again replaces the listener instead of adding a second
+	setFormValue(forms, testIdxListenerInfo, "listenerAction", "log")
+	forms[testIdxListenerInfo].Callback(forms[testIdxListenerInfo].Values, modelValues, forms)
+	setFormValue(forms, testIdxListenerInfo, "listenerEvent", "on_ready")
+	setFormValue(forms, testIdxListenerInfo, "listenerAction", "")
+	forms[testIdxListenerInfo].Callback(forms[testIdxListenerInfo].Values, modelValues, forms)
+
+	listeners, _ := JSONToListenerInfoSlice(*modelValues.Map["listeners"])
+	want := []ListenerInfo{{Event: "on_member_join", Action: "log"}, {Event: "on_ready"}}
+	if !slices.Equal(listeners, want) {
+		t.Errorf("listeners = %+v, want %+v", listeners, want)
+	}
+
+	if got := forms[testIdxListenerInfo].BranchCallback(forms[testIdxListenerInfo].Values, forms); got != testIdxListenerStart {
+		t.Errorf("listener info routed to %d, want %d", got, testIdxListenerStart)
+	}
+	setFormValue(forms, testIdxListenerStart, "listenerStartConfirm", "no")
+	if got := forms[testIdxListenerStart].BranchCallback(forms[testIdxListenerStart].Values, forms); got != testIdxTaskStart {
+		t.Errorf("listener start no routed to %d, want %d", got, testIdxTaskStart)
+	}
+}
+
+func TestBuildListenerFromForm(t *testing.T) {
+	values := map[string]*string{}
+	set := func(key, value string) {
+		v := value
+		values[key] = &v
+	}
+	set("listenerEvent", "on_member_join")
+	set("listenerAction", "message")
+	set("listenerChannelID", " 123456789012345678 ")
+	set("listenerContent", "Welcome {member.mention}")
+	set("listenerRoleID", "223456789012345678")
+
+	got := buildListenerFromForm(values)
+	want := ListenerInfo{Event: "on_member_join", Action: "message", ChannelID: "123456789012345678", Content: "Welcome {member.mention}"}
+	if got != want {
+		t.Errorf("buildListenerFromForm() = %+v, want %+v", got, want)
+	}
+}
+
+func TestTaskFormsFlow(t *testing.T) {
+	forms := AddFormWrapperGenerator()
+	modelValues := newAddModelValues()
+
+	setFormValue(forms, testIdxTaskInfo, "taskName", "stale")
+	forms[testIdxTaskStart].Callback(forms[testIdxTaskStart].Values, modelValues, forms)
+	if got := *forms[testIdxTaskInfo].Values.Map["taskName"]; got != "" {
+		t.Errorf("task start should reset the name, got %q", got)
+	}
+
+	setFormValue(forms, testIdxTaskInfo, "taskName", "digest")
+	setFormValue(forms, testIdxTaskInfo, "taskSchedule", "interval")
+	setFormValue(forms, testIdxTaskInfo, "taskInterval", "6")
+	setFormValue(forms, testIdxTaskInfo, "taskUnit", "hours")
+	forms[testIdxTaskInfo].Callback(forms[testIdxTaskInfo].Values, modelValues, forms)
+
+	// Reusing a name replaces the task, and only the chosen schedule is kept
+	setFormValue(forms, testIdxTaskInfo, "taskSchedule", "times")
+	setFormValue(forms, testIdxTaskInfo, "taskTimes", "09:00, 21:30")
+	setFormValue(forms, testIdxTaskInfo, "taskTimezone", " America/New_York ")
+	setFormValue(forms, testIdxTaskInfo, "taskChannelID", "123456789012345678")
+	setFormValue(forms, testIdxTaskInfo, "taskContent", "Daily digest")
+	forms[testIdxTaskInfo].Callback(forms[testIdxTaskInfo].Values, modelValues, forms)
+
+	tasks, _ := JSONToTaskInfoSlice(*modelValues.Map["tasks"])
+	want := TaskInfo{Name: "digest", Times: []string{"09:00", "21:30"}, Timezone: "America/New_York", ChannelID: "123456789012345678", Content: "Daily digest"}
+	if len(tasks) != 1 || !taskEqual(tasks[0], want) {
+		t.Errorf("tasks = %+v, want only %+v", tasks, want)
+	}
+
+	if got := forms[testIdxTaskInfo].BranchCallback(forms[testIdxTaskInfo].Values, forms); got != testIdxTaskStart {
+		t.Errorf("task info routed to %d, want %d", got, testIdxTaskStart)
+	}
+	setFormValue(forms, testIdxTaskStart, "taskStartConfirm", "no")
+	if got := forms[testIdxTaskStart].BranchCallback(forms[testIdxTaskStart].Values, forms); got != -2 {
+		t.Errorf("task start no routed to %d, want -2", got)
+	}
+}
+
 func TestCmdStartCallbackResetsPageAndResponseState(t *testing.T) {
 	forms := AddFormWrapperGenerator()
 	modelValues := newAddModelValues()
@@ -525,6 +1056,7 @@ func TestValidateAcceptedCommand(t *testing.T) {
 		{"branch goto to a missing page fails", brokenGoto, "[]", true},
 		{"valid slash command passes", validSlash, "[]", false},
 		{"duplicate command name fails", validSlash, `[{"Name":"greet","Type":"slash"}]`, true},
+		{"prefix subcommand without its group fails", CommandInfo{Name: "create", Type: "prefix", Scope: "guild", Description: "d", ReturnType: "None", Group: "tag"}, "[]", true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,204 @@
+/*
+Copyright © 2025 Austin "Choice404" Choi
+See end of file for extended copyright information
+*/
+
+package utils
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Generator writes and reads the code of one bot.library target, commands reach it through GeneratorFor
+// so a new library or language only needs a Generator registered for its bot.library value
+type Generator interface {
+	// Name is the bot.library value the generator is registered under
+	Name() string
+	// CreateProject writes the files of a new project into rootDir and returns the paths it wrote
+	CreateProject(rootDir string, values Values, force bool) ([]string, error)
+	// GenerateDockerFiles writes the container files of a project and returns the paths it wrote
+	GenerateDockerFiles(rootDir string, runtimeVersion string, envProvider string, force bool) ([]string, error)
+	// RenderCog renders the source of a cog file from the cog's config definition
+	RenderCog(bot BotConfig, cog CogConfig) (string, error)
+	// ParseCog reads a cog's definition back out of the source of its file
+	ParseCog(source string, fileName string) (*ParsedCogInfo, error)
+	// CogsDir is the directory cog files are kept in, relative to the project root
+	CogsDir() string
+	// CogFileName is the name of the file in CogsDir that holds the cog with a botbox.conf file name
+	CogFileName(file string) string
+	// CogFile is the botbox.conf file name of a file in CogsDir, ok is false for files that hold no cog
+	CogFile(name string) (file string, ok bool)
+	// RegionMarker starts the comments that mark protected regions, followed by begin or end and the region name
+	RegionMarker() string
+	// MainFile is the bot's entry point, relative to the project root
+	MainFile() string
+	// TranslatorFile is the file of the translator that reads the locale files, relative to the project root
+	TranslatorFile() string
+	// LocalesDir holds one <locale>.json file per Discord locale, relative to the project root
+	LocalesDir() string
+}
+
+// generators holds the registered generators in the order they are offered, discord.py first as the default
+var generators = []Generator{
+	pythonGenerator{library: LibraryDiscordPy},
+	pythonGenerator{library: LibraryPycord},
+	pythonGenerator{library: LibraryNextcord},
+	pythonGenerator{library: LibraryDisnake},
+}
+
+// RegisterGenerator adds a generator for its bot.library value, replacing the one already registered under it
+func RegisterGenerator(generator Generator) {
+	if i := slices.IndexFunc(generators, func(g Generator) bool { return g.Name() == generator.Name() }); i >= 0 {
+		generators[i] = generator
+		return
+	}
+	generators = append(generators, generator)
+}
+
+// GeneratorNames lists the bot.library values a generator is registered for
+func GeneratorNames() []string {
+	names := make([]string, 0, len(generators))
+	for _, generator := range generators {
+		names = append(names, generator.Name())
+	}
+	return names
+}
+
+// GeneratorFor is the generator of a bot.library value, projects without one use the discord.py generator
+func GeneratorFor(library string) (Generator, error) {
+	library = NormalizeLibrary(library)
+	for _, generator := range generators {
+		if generator.Name() == library {
+			return generator, nil
+		}
+	}
+	return nil, fmt.Errorf("there is no generator for library %s, library must be one of %s", library, strings.Join(GeneratorNames(), ", "))
+}
+
+// CogFilePath is the path of a cog's file in the project at rootDir, where the generator of library keeps cogs
+func CogFilePath(rootDir string, library string, file string) (string, error) {
+	generator, err := GeneratorFor(library)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(rootDir, generator.CogsDir(), generator.CogFileName(file)), nil
+}
+
+// pythonRegionMarker is the comment protected regions are marked with in Python cogs
+const pythonRegionMarker = "# botbox:"
+
+// pythonGenerator writes Python bots from the embedded templates, the libraries differ only in their dialect and template set
+type pythonGenerator struct {
+	library string
+}
+
+// Name is the library the generator writes for
+func (g pythonGenerator) Name() string {
+	return g.library
+}
+
+// RenderCog renders the cog template of the library's set, a cog using what the library has no template for fails first
+func (g pythonGenerator) RenderCog(bot BotConfig, cog CogConfig) (string, error) {
+	if err := ValidateLibraryCog(g.library, cog); err != nil {
+		return "", err
+	}
+	name, err := LibraryTemplate(g.library, "cog.py.tmpl")
+	if err != nil {
+		return "", err
+	}
+	content, err := RenderTemplate(name, CogTemplateData{
+		Author:         bot.Author,
+		BotName:        bot.Name,
+		BotDescription: bot.Description,
+		ClassName:      cog.Name,
+		Filename:       cog.File,
+		SlashCommands:  cog.SlashCommands,
+		PrefixCommands: cog.PrefixCommands,
+		Listeners:      cog.Listeners,
+		Tasks:          cog.Tasks,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render cog template: %w", err)
+	}
+	return content, nil
+}
+
+// ParseCog reads the cog in the library's dialect
+func (g pythonGenerator) ParseCog(source string, fileName string) (*ParsedCogInfo, error) {
+	return parseCogSource(source, fileName, g.library)
+}
+
+// CogsDir is the cogs package main.py loads extensions from
+func (g pythonGenerator) CogsDir() string {
+	return filepath.Join("src", "cogs")
+}
+
+// CogFileName is the module of a cog
+func (g pythonGenerator) CogFileName(file string) string {
+	return file + ".py"
+}
+
+// CogFile is the module name of a Python file, the package's __init__.py is not a cog
+func (g pythonGenerator) CogFile(name string) (string, bool) {
+	file, ok := strings.CutSuffix(name, ".py")
+	if !ok || file == "__init__" {
+		return "", false
+	}
+	return file, true
+}
+
+// RegionMarker is a Python comment
+func (g pythonGenerator) RegionMarker() string {
+	return pythonRegionMarker
+}
+
+// MainFile is the script run.sh and the docker image start the bot with
+func (g pythonGenerator) MainFile() string {
+	return filepath.Join("src", "main.py")
+}
+
+// TranslatorFile sits in the utils package next to the logger
+func (g pythonGenerator) TranslatorFile() string {
+	return filepath.Join("src", "utils", "translator.py")
+}
+
+// LocalesDir lives under src so the generated translator and the docker image find it next to main.py
+func (g pythonGenerator) LocalesDir() string {
+	return filepath.Join("src", "locales")
+}
+
+/*
+Copyright © 2025 Austin "Choice404" Choi
+
+https://github.com/choice404/botbox
+
+Bot Box
+A discord bot template generator to help create discord bots quickly and easily
+
+This code is licensed under the MIT License.
+
+MIT License
+
+Copyright (c) 2025 Austin Choi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
@@ -0,0 +1,203 @@
+/*
+Copyright © 2025 Austin "Choice404" Choi
+See end of file for extended copyright information
+*/
+
+package utils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// fakeGenerator writes every cog as a fixed line and an empty class region, kept in its own directory with its own
+// extension and comment syntax, so tests can see which generator a command used
+type fakeGenerator struct {
+	created []string
+}
+
+func (g *fakeGenerator) Name() string {
+	return "fake"
+}
+
+func (g *fakeGenerator) CreateProject(rootDir string, values Values, force bool) ([]string, error) {
+	g.created = append(g.created, rootDir)
+	return []string{rootDir}, nil
+}
+
+func (g *fakeGenerator) GenerateDockerFiles(rootDir string, runtimeVersion string, envProvider string, force bool) ([]string, error) {
+	return nil, errors.New("fake generator writes no docker files")
+}
+
+func (g *fakeGenerator) RenderCog(bot BotConfig, cog CogConfig) (string, error) {
+	return "fake " + cog.Name + "\n// botbox:begin class\n// botbox:end class\n", nil
+}
+
+func (g *fakeGenerator) ParseCog(source string, fileName string) (*ParsedCogInfo, error) {
+	name, _, _ := strings.Cut(strings.TrimPrefix(source, "fake "), "\n")
+	return &ParsedCogInfo{FileName: fileName, CogName: name}, nil
+}
+
+func (g *fakeGenerator) CogsDir() string {
+	return filepath.Join("bot", "cogs")
+}
+
+func (g *fakeGenerator) CogFileName(file string) string {
+	return file + ".fake"
+}
+
+func (g *fakeGenerator) CogFile(name string) (string, bool) {
+	return strings.CutSuffix(name, ".fake")
+}
+
+func (g *fakeGenerator) RegionMarker() string {
+	return "// botbox:"
+}
+
+func (g *fakeGenerator) MainFile() string {
+	return filepath.Join("bot", "main.fake")
+}
+
+func (g *fakeGenerator) TranslatorFile() string {
+	return filepath.Join("bot", "translator.fake")
+}
+
+func (g *fakeGenerator) LocalesDir() string {
+	return filepath.Join("bot", "locales")
+}
+
+// registerFakeGenerator registers a fakeGenerator for the length of the test
+func registerFakeGenerator(t *testing.T) *fakeGenerator {
+	t.Helper()
+	registered := slices.Clone(generators)
+	t.Cleanup(func() { generators = registered })
+	generator := &fakeGenerator{}
+	RegisterGenerator(generator)
+	return generator
+}
+
+func TestGeneratorFor(t *testing.T) {
+	for _, library := range []string{"", LibraryDiscordPy, LibraryPycord, LibraryNextcord, LibraryDisnake} {
+		generator, err := GeneratorFor(library)
+		if err != nil {
+			t.Fatalf("GeneratorFor(%q) error = %v", library, err)
+		}
+		if want := NormalizeLibrary(library); generator.Name() != want {
+			t.Errorf("GeneratorFor(%q).Name() = %q, want %q", library, generator.Name(), want)
+		}
+	}
+
+	if _, err := GeneratorFor("discord.js"); err == nil || !strings.Contains(err.Error(), "discord.js") {
+		t.Errorf("GeneratorFor(discord.js) error = %v, want the unknown library named", err)
+	}
+	if err := ValidateLibrary("discord.js"); err == nil {
+		t.Error("ValidateLibrary(discord.js) = nil, want an error for a library without a generator")
+	}
+}
+
+func TestRegisterGenerator(t *testing.T) {
+	generator := registerFakeGenerator(t)
+
+	if err := ValidateLibrary("fake"); err != nil {
+		t.Errorf("ValidateLibrary(fake) error = %v, want a registered library accepted", err)
+	}
+	if names := GeneratorNames(); names[0] != DefaultLibrary || names[len(names)-1] != "fake" {
+		t.Errorf("GeneratorNames() = %v, want the default first and the new generator last", names)
+	}
+
+	// A second registration under the same name replaces the first instead of listing it twice
+	RegisterGenerator(generator)
+	if got := GeneratorNames(); len(got) != 5 {
+		t.Errorf("GeneratorNames() = %v, want 5 generators", got)
+	}
+}
+
+func TestRegisteredGeneratorIsUsedByProjectCommands(t *testing.T) {
+	generator := registerFakeGenerator(t)
+	dir := t.TempDir()
+
+	library := "fake"
+	if _, err := CreateProject(dir, Values{Map: map[string]*string{"library": &library}}, true); err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+	if !slices.Equal(generator.created, []string{dir}) {
+		t.Errorf("fake generator created %v, want %v", generator.created, []string{dir})
+	}
+
+	config := Config{BotInfo: BotConfig{Name: "Bot", Library: library}}
+	changes := &ChangeSet{}
+	if _, err := RegenerateCogFile(changes, dir, config, config, CogConfig{Name: "Dice", File: "dice"}, false); err != nil {
+		t.Fatalf("RegenerateCogFile() error = %v", err)
+	}
+	if err := changes.Apply(); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	path := filepath.Join(dir, "bot", "cogs", "dice.fake")
+	rendered := "fake Dice\n// botbox:begin class\n// botbox:end class\n"
+	if got, err := os.ReadFile(path); err != nil || string(got) != rendered {
+		t.Fatalf("cog file = %q, %v, want the fake generator's rendering in its cogs directory", got, err)
+	}
+
+	// Sync only reads the files the generator calls cogs
+	if err := os.WriteFile(filepath.Join(dir, "bot", "cogs", "notes.txt"), []byte("not a cog\n"), 0644); err != nil {
+		t.Fatalf("failed to write notes: %v", err)
+	}
+	parsed, _, err := parseAllCogFiles(filepath.Join(dir, "bot", "cogs"), library)
+	if err != nil {
+		t.Fatalf("parseAllCogFiles() error = %v", err)
+	}
+	if len(parsed) != 1 || parsed[0].CogName != "Dice" || parsed[0].FileName != "dice" {
+		t.Errorf("parseAllCogFiles() = %+v, want only the Dice cog from the fake generator", parsed)
+	}
+
+	// Protected regions are found by the generator's comment syntax
+	custom := strings.Replace(rendered, "// botbox:begin class\n", "// botbox:begin class\nhelper()\n", 1)
+	if err := os.WriteFile(path, []byte(custom), 0644); err != nil {
+		t.Fatalf("failed to write custom code: %v", err)
+	}
+	config.Cogs = []CogConfig{{Name: "Dice", File: "dice"}}
+	result, err := RegenerateCogFile(&ChangeSet{}, dir, config, config, CogConfig{Name: "Dice", File: "dice"}, false)
+	if err != nil {
+		t.Fatalf("RegenerateCogFile() error = %v", err)
+	}
+	if result.Unprotected || !slices.Equal(result.Preserved, []string{"class"}) {
+		t.Errorf("RegenerateCogFile() = %+v, want the class region kept", result)
+	}
+}
+
+/*
+Copyright © 2025 Austin "Choice404" Choi
+
+https://github.com/choice404/botbox
+
+Bot Box
+A discord bot template generator to help create discord bots quickly and easily
+
+This code is licensed under the MIT License.
+
+MIT License
+
+Copyright (c) 2025 Austin Choi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
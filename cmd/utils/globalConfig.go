@@ -6,12 +6,15 @@ See end of file for extended copyright information
 package utils
 
 import (
-	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/viper"
@@ -220,7 +223,28 @@ func SetGlobalConfigValue(key string, value any) error {
 }
 
 func SyncCogsWithConfig() (*SyncResult, error) {
-	result := &SyncResult{}
+	changes := &ChangeSet{}
+	result, err := StageCogSync(changes)
+	if err != nil {
+		return nil, err
+	}
+	if err := changes.Apply(); err != nil {
+		return nil, fmt.Errorf("failed to save updated config: %w", err)
+	}
+	return result, nil
+}
+
+// StageCogSync works out the config the cog files describe and stages botbox.conf with it, nothing is written
+func StageCogSync(changes *ChangeSet) (*SyncResult, error) {
+	// Empty lists rather than nil ones, so the JSON output always has arrays
+	result := &SyncResult{
+		UpdatedCogs:    []string{},
+		AddedCogs:      []string{},
+		RemovedCogs:    []string{},
+		Diagnostics:    []Diagnostic{},
+		HeaderIssues:   []string{},
+		CommandChanges: []string{},
+	}
 
 	rootDir, err := FindBotConf()
 	if err != nil {
@@ -232,11 +256,29 @@ func SyncCogsWithConfig() (*SyncResult, error) {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
-	cogsDir := filepath.Join(rootDir, "src", "cogs")
-	parsedCogs, err := parseAllCogFiles(cogsDir)
+	generator, err := GeneratorFor(config.BotInfo.Library)
+	if err != nil {
+		return nil, err
+	}
+	cogsDir := filepath.Join(rootDir, generator.CogsDir())
+	parsedCogs, diagnostics, err := parseAllCogFiles(cogsDir, config.BotInfo.Library)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse cog files: %w", err)
 	}
+	for _, diagnostic := range diagnostics {
+		diagnostic.File = displayPath(rootDir, diagnostic.File)
+		result.Diagnostics = append(result.Diagnostics, diagnostic)
+	}
+
+	// A cog that could not be parsed would drop out of botbox.conf, so nothing is staged until it is fixed
+	if len(result.Errors()) > 0 {
+		return result, nil
+	}
+
+	// Translations live in the locale files rather than the cogs, they are read back from there
+	if err := applyLocaleFiles(filepath.Join(rootDir, generator.LocalesDir()), parsedCogs); err != nil {
+		return nil, fmt.Errorf("failed to read locale files: %w", err)
+	}
 
 	existingCogs := make(map[string]*CogConfig)
 	for i := range config.Cogs {
@@ -246,6 +288,8 @@ func SyncCogsWithConfig() (*SyncResult, error) {
 	var newCogs []CogConfig
 	for _, parsed := range parsedCogs {
 		if existing, exists := existingCogs[parsed.FileName]; exists {
+			result.CommandChanges = append(result.CommandChanges, commandChanges(parsed.FileName, existing.SlashCommands, parsed.SlashCommands)...)
+			result.CommandChanges = append(result.CommandChanges, commandChanges(parsed.FileName, existing.PrefixCommands, parsed.PrefixCommands)...)
 			updated := updateCogConfig(existing, parsed)
 			if updated {
 				result.UpdatedCogs = append(result.UpdatedCogs, parsed.FileName)
@@ -256,6 +300,7 @@ func SyncCogsWithConfig() (*SyncResult, error) {
 			newCog := createCogConfigFromParsed(parsed)
 			newCogs = append(newCogs, newCog)
 			result.AddedCogs = append(result.AddedCogs, parsed.FileName)
+			result.CommandChanges = append(result.CommandChanges, commandChanges(parsed.FileName, nil, slices.Concat(parsed.SlashCommands, parsed.PrefixCommands))...)
 		}
 
 		if headerIssue := checkHeaderIssues(parsed, &config.BotInfo); headerIssue != "" {
@@ -263,87 +308,146 @@ func SyncCogsWithConfig() (*SyncResult, error) {
 		}
 	}
 
-	for fileName := range existingCogs {
-		result.RemovedCogs = append(result.RemovedCogs, fileName)
+	// Removed cogs are listed in config order so the report reads the same on every run
+	for _, cog := range config.Cogs {
+		if _, removed := existingCogs[cog.File]; removed {
+			result.RemovedCogs = append(result.RemovedCogs, cog.File)
+			result.CommandChanges = append(result.CommandChanges, commandChanges(cog.File, slices.Concat(cog.SlashCommands, cog.PrefixCommands), nil)...)
+		}
 	}
 
 	// Never let an empty parse result wipe cogs that are still recorded in the config
 	if len(newCogs) == 0 && len(config.Cogs) > 0 {
-		result.RemovedCogs = nil
-		result.Errors = append(result.Errors, fmt.Sprintf("no cog files parsed from %s, keeping the %d cog entries already in botbox.conf", cogsDir, len(config.Cogs)))
+		result.RemovedCogs = []string{}
+		result.CommandChanges = []string{}
+		result.Diagnostics = append(result.Diagnostics, Diagnostic{
+			File:     displayPath(rootDir, cogsDir),
+			Severity: SeverityError,
+			Code:     DiagNoCogs,
+			Message:  fmt.Sprintf("no cog files parsed, keeping the %d cog entries already in botbox.conf", len(config.Cogs)),
+		})
 		return result, nil
 	}
 
 	config.Cogs = newCogs
 
-	if err := saveConfig(rootDir, config); err != nil {
+	if err := StageConfig(changes, rootDir, config); err != nil {
 		return nil, fmt.Errorf("failed to save updated config: %w", err)
 	}
 
 	return result, nil
 }
 
-func parseAllCogFiles(cogsDir string) ([]ParsedCogInfo, error) {
+// parseAllCogFiles parses every cog file the generator of library finds in cogsDir, a cog that is not valid code is left out
+// and reported as an error diagnostic. Diagnostics carry the full path of their file
+func parseAllCogFiles(cogsDir string, library string) ([]ParsedCogInfo, []Diagnostic, error) {
 	var parsedCogs []ParsedCogInfo
+	var diagnostics []Diagnostic
 
+	generator, err := GeneratorFor(library)
+	if err != nil {
+		return nil, nil, err
+	}
 	files, err := os.ReadDir(cogsDir)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read cogs directory: %w", err)
+		return nil, nil, fmt.Errorf("failed to read cogs directory: %w", err)
 	}
 
 	for _, file := range files {
-		if !file.IsDir() && strings.HasSuffix(file.Name(), ".py") && file.Name() != "__init__.py" {
+		fileName, isCog := generator.CogFile(file.Name())
+		if !file.IsDir() && isCog {
 			filePath := filepath.Join(cogsDir, file.Name())
-			fileName := strings.TrimSuffix(file.Name(), ".py")
 
-			parsed, err := parseCogFile(filePath, fileName)
+			parsed, err := parseCogFile(filePath, fileName, library)
+			var syntaxErr *PySyntaxError
+			if errors.As(err, &syntaxErr) {
+				diagnostics = append(diagnostics, syntaxDiagnostic(filePath, err))
+				continue
+			}
 			if err != nil {
-				return nil, fmt.Errorf("failed to parse %s: %w", file.Name(), err)
+				return nil, nil, fmt.Errorf("failed to parse %s: %w", file.Name(), err)
 			}
 
+			for _, diagnostic := range parsed.Diagnostics {
+				diagnostic.File = filePath
+				diagnostics = append(diagnostics, diagnostic)
+			}
 			parsedCogs = append(parsedCogs, *parsed)
 		}
 	}
 
-	return parsedCogs, nil
+	return parsedCogs, diagnostics, nil
 }
 
-func parseCogFile(filePath, fileName string) (*ParsedCogInfo, error) {
-	file, err := os.Open(filePath)
+// parseCogFile parses a cog file with the generator of library, the project's bot.library
+func parseCogFile(filePath, fileName string, library string) (*ParsedCogInfo, error) {
+	generator, err := GeneratorFor(library)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
+		return nil, err
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	return generator.ParseCog(string(data), fileName)
+}
+
+// parseCogSource parses the source of a cog file, hand written code in the custom regions is never read.
+// Source Python cannot parse is reported as a *PySyntaxError with its line and column. Slash commands
+// and responses are read in the dialect of library, the project's bot.library
+func parseCogSource(source, fileName string, library string) (*ParsedCogInfo, error) {
+	lines := maskCustomRegions(strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n"), pythonRegionMarker)
+	module, err := parsePython(strings.Join(lines, "\n"))
+	if err != nil {
+		return nil, err
 	}
-	defer file.Close()
 
 	parsed := &ParsedCogInfo{
 		FileName: fileName,
 	}
 
-	scanner := bufio.NewScanner(file)
-	var lines []string
-	for scanner.Scan() {
-		lines = append(lines, scanner.Text())
-	}
+	// Every statement in source order, the commands are read in the order they are written
+	stmts := flattenStatements(module.Body)
 
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
-	}
+	parseHeaderComment(module.Docstring, parsed)
 
-	parseHeaderComment(lines, parsed)
+	parseCogClassName(stmts, parsed)
 
-	parseCogClassName(lines, parsed)
+	parseCommands(stmts, parsed, dialectFor(library))
 
-	parseCommands(lines, parsed)
+	parseListeners(stmts, parsed)
+
+	parseTasks(stmts, parsed)
+
+	// Each reader warns as it goes, report them in the order they appear in the file
+	slices.SortStableFunc(parsed.Diagnostics, func(a, b Diagnostic) int {
+		if a.Line != b.Line {
+			return a.Line - b.Line
+		}
+		return a.Column - b.Column
+	})
 
 	return parsed, nil
 }
 
-func parseHeaderComment(lines []string, parsed *ParsedCogInfo) {
-	docstringLines := collectHeaderDocstring(lines)
-	if len(docstringLines) == 0 {
+func parseHeaderComment(docstring string, parsed *ParsedCogInfo) {
+	if docstring == "" {
 		return
 	}
 
+	docstringLines := strings.Split(docstring, "\n")
+	for i := range docstringLines {
+		docstringLines[i] = strings.TrimSpace(docstringLines[i])
+	}
+	// Generated headers put the quotes on lines of their own
+	if docstringLines[0] == "" {
+		docstringLines = docstringLines[1:]
+	}
+	if len(docstringLines) > 0 && docstringLines[len(docstringLines)-1] == "" {
+		docstringLines = docstringLines[:len(docstringLines)-1]
+	}
+
 	// Everything after the author line is positional, so find that line first
 	bodyStart := 0
 	for i, line := range docstringLines {
@@ -370,212 +474,850 @@ func parseHeaderComment(lines []string, parsed *ParsedCogInfo) {
 	}
 }
 
-func collectHeaderDocstring(lines []string) []string {
-	inDocstring := false
-	docstringLines := []string{}
+func parseCogClassName(stmts []pyStmt, parsed *ParsedCogInfo) {
+	for _, stmt := range stmts {
+		if stmt.Class != nil && classHasBase(stmt.Class, "commands.Cog") {
+			parsed.CogName = stmt.Class.Name
+			break
+		}
+	}
+
+	if parsed.CogName == "" {
+		parsed.CogName = parsed.FileName
+	}
+}
+
+// classHasBase reports whether a class lists base among its bases
+func classHasBase(class *pyClass, base string) bool {
+	for _, expr := range class.Bases {
+		if expr.Source == base {
+			return true
+		}
+	}
+	return false
+}
 
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
+// warn records a warning about code in the cog that could not be fully read, the file is filled in by the caller
+func (p *ParsedCogInfo) warn(line, column int, code, format string, args ...any) {
+	p.Diagnostics = append(p.Diagnostics, Diagnostic{
+		Line:     line,
+		Column:   column,
+		Severity: SeverityWarning,
+		Code:     code,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
 
-		if strings.HasPrefix(trimmed, `"""`) {
-			if inDocstring {
-				break
+// decoratorMethod splits a decorator like @ticket.command() into ticket and command, ok is false for any other shape
+func decoratorMethod(decorator pyExpr) (owner, method string, ok bool) {
+	callee := decorator
+	if callee.Kind == pyExprCall {
+		callee = *callee.Value
+	}
+	if callee.Kind != pyExprAttr || callee.Value.Kind != pyExprName {
+		return "", "", false
+	}
+	return callee.Value.Name, callee.Name, true
+}
+
+func parseCommands(stmts []pyStmt, parsed *ParsedCogInfo, d libraryDialect) {
+	slashSyntax, prefixSyntax := d.slashSyntax(), d.prefixSyntax()
+	groups := parseSlashGroups(stmts)
+	converters := parseTransformerClasses(stmts)
+	// Prefix group commands are defined before their subcommands, so each one is known by the time they register on it.
+	// Subcommands register on the group's method, so the groups are keyed by method name
+	prefixGroups := map[string]string{}
+
+	for _, stmt := range stmts {
+		// Context menus are registered in __init__ rather than by a decorator
+		if stmt.Func == nil {
+			if cmd := parseContextMenuCommand(parsed, stmts, stmt); cmd != nil {
+				parsed.SlashCommands = append(parsed.SlashCommands, *cmd)
 			}
+			continue
+		}
 
-			inDocstring = true
-			if strings.Count(trimmed, `"""`) == 2 {
-				content := strings.Trim(trimmed, `"`)
-				docstringLines = append(docstringLines, content)
+		// The first decorator that registers a command decides what kind of command the function is
+		for _, decorator := range stmt.Func.Decorators {
+			owner, method, _ := decoratorMethod(decorator)
+			_, slashGroup := groups[owner]
+			prefixGroup, isPrefixGroup := prefixGroups[owner]
+
+			switch {
+			case decorator.Callee() == "app_commands.command":
+				if cmd := parseSlashCommand(parsed, stmts, stmt.Func, decorator); cmd != nil {
+					warnUnsupportedArgs(parsed, stmt.Func, cmd)
+					parsed.SlashCommands = append(parsed.SlashCommands, *cmd)
+				}
+
+			// A command registered on a declared group is a subcommand of that group
+			case slashGroup && method == "command":
+				if cmd := parseSlashCommand(parsed, stmts, stmt.Func, decorator); cmd != nil {
+					group := groups[owner]
+					cmd.Group = group.Path
+					cmd.GroupDescription = group.Description
+					cmd.Scope = group.Scope
+					warnUnsupportedArgs(parsed, stmt.Func, cmd)
+					parsed.SlashCommands = append(parsed.SlashCommands, *cmd)
+				}
+
+			// The other libraries register slash commands with one decorator that takes the scope and permissions too
+			case isLibrarySlashDecorator(decorator, d):
+				if cmd := parseLibrarySlashCommand(parsed, stmt.Func, decorator, d, slashSyntax); cmd != nil {
+					warnUnsupportedArgs(parsed, stmt.Func, cmd)
+					parsed.SlashCommands = append(parsed.SlashCommands, *cmd)
+				}
+
+			case decorator.Callee() == "commands.command" || decorator.Callee() == "commands.group":
+				if cmd := parsePrefixCommand(parsed, stmt.Func, decorator, "", prefixSyntax); cmd != nil {
+					d.restoreArgTypes(cmd)
+					restoreTransformArgs(cmd, converters)
+					prefixGroups[stmt.Func.Name] = cmd.Name
+					warnUnsupportedArgs(parsed, stmt.Func, cmd)
+					parsed.PrefixCommands = append(parsed.PrefixCommands, *cmd)
+				}
+
+			// A prefix command registered on an earlier prefix command's method is one of its subcommands
+			case isPrefixGroup && !slashGroup && (method == "command" || method == "group"):
+				if cmd := parsePrefixCommand(parsed, stmt.Func, decorator, prefixGroup, prefixSyntax); cmd != nil {
+					d.restoreArgTypes(cmd)
+					restoreTransformArgs(cmd, converters)
+					prefixGroups[stmt.Func.Name] = CommandPath(*cmd)
+					warnUnsupportedArgs(parsed, stmt.Func, cmd)
+					parsed.PrefixCommands = append(parsed.PrefixCommands, *cmd)
+				}
+
+			// Hybrid commands register a slash command too, so they live with the slash commands
+			case decorator.Callee() == "commands.hybrid_command":
+				if cmd := parseHybridCommand(parsed, stmt.Func, decorator); cmd != nil {
+					restoreTransformArgs(cmd, converters)
+					warnUnsupportedArgs(parsed, stmt.Func, cmd)
+					parsed.SlashCommands = append(parsed.SlashCommands, *cmd)
+				}
+
+			// Commands registered on an attribute the cog never declared as a group cannot be placed
+			case (method == "command" || method == "group") && owner != "app_commands" && owner != "commands":
+				parsed.warn(decorator.Line, decorator.Col, DiagUnknownGroup,
+					"%s registers %s on %s, which is not a group declared in this cog, the command is left out of botbox.conf", decorator.Callee(), stmt.Func.Name, owner)
+
+			default:
+				continue
+			}
+			break
+		}
+	}
+}
+
+// Listener body shapes the generator writes
+var (
+	listenerLogRegex  = regexp.MustCompile(`^logger\.info\(f"(on_\w+)[ "]`)
+	listenerRoleRegex = regexp.MustCompile(`^role = member\.guild\.get_role\((\d+)\)$`)
+)
+
+// parseListeners reads every cog listener into the listeners of the parsed cog, the decorator may name the event
+// when the method is called something else. A body that is not one of the generated actions keeps the event
+// with no action so hand written listeners survive a sync
+func parseListeners(stmts []pyStmt, parsed *ParsedCogInfo) {
+	for _, stmt := range stmts {
+		fn := stmt.Func
+		if fn == nil {
+			continue
+		}
+
+		for _, decorator := range fn.Decorators {
+			if decorator.Kind != pyExprCall || decorator.Callee() != "commands.Cog.listener" {
+				continue
+			}
+			if !fn.Async {
+				parsed.warn(fn.Line, fn.Col, DiagNotCoroutine, "listener %s is not defined with async def, it is left out of botbox.conf", fn.Name)
 				break
 			}
 
-			content := strings.TrimPrefix(trimmed, `"""`)
-			if content != "" {
-				docstringLines = append(docstringLines, content)
+			event := fn.Name
+			if len(decorator.Args) > 0 {
+				if decorator.Args[0].Kind != pyExprString {
+					break
+				}
+				event = decorator.Args[0].Text
+			}
+
+			// Only events with a known signature can be regenerated, other listeners stay hand written
+			if _, ok := findListenerEvent(event); !ok {
+				break
 			}
-		} else if inDocstring {
-			docstringLines = append(docstringLines, trimmed)
+
+			listener := ListenerInfo{Event: event}
+			parseListenerAction(fn, &listener)
+			parsed.Listeners = append(parsed.Listeners, listener)
+			break
 		}
 	}
+}
+
+// parseListenerAction recognizes the generated canned action in a listener body
+func parseListenerAction(fn *pyFunc, listener *ListenerInfo) {
+	body := flattenStatements(bodyWithoutDocstring(fn.Body))
 
-	return docstringLines
+	for i, stmt := range body {
+		line := stmt.Source()
+
+		// The generated bot message guard comes before the action
+		if line == "if message.author.bot:" || line == "return" {
+			continue
+		}
+
+		if matches := listenerLogRegex.FindStringSubmatch(line); matches != nil && matches[1] == listener.Event {
+			listener.Action = "log"
+			return
+		}
+		if matches := channelRegex.FindStringSubmatch(line); matches != nil {
+			if i+1 < len(body) {
+				if sendMatches := channelSendRegex.FindStringSubmatch(body[i+1].Source()); sendMatches != nil {
+					listener.Action = "message"
+					listener.ChannelID = matches[1]
+					listener.Content = sendMatches[1]
+				}
+			}
+			return
+		}
+		// A role action looks up the event's member before the role
+		if strings.HasPrefix(line, "member = ") || line == "if member is not None:" {
+			continue
+		}
+		if matches := listenerRoleRegex.FindStringSubmatch(line); matches != nil {
+			listener.Action = "role"
+			listener.RoleID = matches[1]
+		}
+		return
+	}
 }
 
-func parseCogClassName(lines []string, parsed *ParsedCogInfo) {
-	classRegex := regexp.MustCompile(`^class\s+(\w+)\s*\(.*commands\.Cog.*\):`)
+// parseTasks reads every generated task loop into the tasks of the parsed cog
+func parseTasks(stmts []pyStmt, parsed *ParsedCogInfo) {
+	for _, stmt := range stmts {
+		fn := stmt.Func
+		if fn == nil {
+			continue
+		}
 
-	for _, line := range lines {
-		if matches := classRegex.FindStringSubmatch(strings.TrimSpace(line)); matches != nil {
-			parsed.CogName = matches[1]
+		for _, decorator := range fn.Decorators {
+			if decorator.Kind != pyExprCall || decorator.Callee() != "tasks.loop" {
+				continue
+			}
+			if !fn.Async {
+				parsed.warn(fn.Line, fn.Col, DiagNotCoroutine, "task %s is not defined with async def, it is left out of botbox.conf", fn.Name)
+				break
+			}
+			task, ok := parseTaskLoop(decorator)
+			if !ok {
+				parsed.warn(decorator.Line, decorator.Col, DiagTaskSchedule,
+					"task %s is not scheduled with a single seconds, minutes, or hours interval or a list of times, it is left out of botbox.conf", fn.Name)
+				break
+			}
+			task.Name = fn.Name
+			parseTaskChannel(fn, &task)
+			parsed.Tasks = append(parsed.Tasks, task)
 			break
 		}
 	}
+}
 
-	if parsed.CogName == "" {
-		parsed.CogName = parsed.FileName
+// parseTaskLoop reads the schedule of a tasks.loop decorator, only the single interval or list of times
+// the generator writes is understood and other tasks.loop arguments are left to hand written code
+func parseTaskLoop(decorator pyExpr) (TaskInfo, bool) {
+	task := TaskInfo{}
+	if len(decorator.Args) > 0 || len(decorator.Keywords) != 1 {
+		return task, false
 	}
-}
 
-func parseCommands(lines []string, parsed *ParsedCogInfo) {
-	for i := range lines {
-		line := strings.TrimSpace(lines[i])
+	keyword := decorator.Keywords[0]
+	switch keyword.Name {
+	case "seconds", "minutes", "hours":
+		interval, err := strconv.Atoi(keyword.Value.Source)
+		if err != nil || interval <= 0 {
+			return task, false
+		}
+		task.Unit = keyword.Name
+		task.Interval = interval
+		return task, true
 
-		if strings.Contains(line, "@app_commands.command") {
-			cmd := parseSlashCommand(lines, i)
-			if cmd != nil {
-				parsed.SlashCommands = append(parsed.SlashCommands, *cmd)
+	case "time":
+		if keyword.Value.Kind != pyExprList {
+			return task, false
+		}
+		for _, loopTime := range keyword.Value.Args {
+			hourExpr, _ := loopTime.Keyword("hour")
+			minuteExpr, _ := loopTime.Keyword("minute")
+			tzinfo, _ := loopTime.Keyword("tzinfo")
+			hour, hourErr := strconv.Atoi(hourExpr.Source)
+			minute, minuteErr := strconv.Atoi(minuteExpr.Source)
+			if loopTime.Callee() != "datetime.time" || hourErr != nil || minuteErr != nil {
+				continue
+			}
+			switch {
+			case tzinfo.Source == "datetime.timezone.utc":
+				task.Timezone = ""
+			case tzinfo.Callee() == "ZoneInfo" && len(tzinfo.Args) == 1 && tzinfo.Args[0].Kind == pyExprString:
+				task.Timezone = tzinfo.Args[0].Text
+			default:
+				continue
 			}
+			task.Times = append(task.Times, fmt.Sprintf("%02d:%02d", hour, minute))
 		}
+		return task, len(task.Times) > 0
+	}
 
-		if strings.Contains(line, "@commands.command") {
-			cmd := parsePrefixCommand(lines, i)
-			if cmd != nil {
-				parsed.PrefixCommands = append(parsed.PrefixCommands, *cmd)
-			}
+	return task, false
+}
+
+// parseTaskChannel recognizes the generated channel lookup and message in a task body
+func parseTaskChannel(fn *pyFunc, task *TaskInfo) {
+	body := flattenStatements(bodyWithoutDocstring(fn.Body))
+	if len(body) == 0 {
+		return
+	}
+
+	matches := channelRegex.FindStringSubmatch(body[0].Source())
+	if matches == nil {
+		return
+	}
+	task.ChannelID = matches[1]
+	if len(body) > 1 {
+		if sendMatches := channelSendRegex.FindStringSubmatch(body[1].Source()); sendMatches != nil {
+			task.Content = sendMatches[1]
 		}
 	}
 }
 
-// Line budgets for locating the function a command decorator belongs to
-const (
-	maxSlashDecoratorLines  = 30
-	maxPrefixDecoratorLines = 5
-)
+// taskEqual compares two tasks including their times of day
+func taskEqual(a, b TaskInfo) bool {
+	return a.Name == b.Name && a.Interval == b.Interval && a.Unit == b.Unit && slices.Equal(a.Times, b.Times) &&
+		a.Timezone == b.Timezone && a.ChannelID == b.ChannelID && a.Content == b.Content
+}
 
-func parseSlashCommand(lines []string, startIndex int) *CommandInfo {
-	commandRegex := regexp.MustCompile(`@app_commands\.command\s*\(\s*name\s*=\s*["']([^"']+)["']\s*,\s*description\s*=\s*["']([^"']+)["']\s*\)`)
-	matches := commandRegex.FindStringSubmatch(lines[startIndex])
+// parseTransformerClasses maps each generated transformer class name to the raw value type it converts,
+// the generated classes subclass both app_commands.Transformer and commands.Converter
+func parseTransformerClasses(stmts []pyStmt) map[string]string {
+	converters := map[string]string{}
+	for _, stmt := range stmts {
+		class := stmt.Class
+		if class == nil || len(class.Bases) != 2 || class.Bases[0].Source != "app_commands.Transformer" || class.Bases[1].Source != "commands.Converter" {
+			continue
+		}
+		for _, member := range class.Body {
+			fn := member.Func
+			if fn == nil || fn.Name != "transform" || !fn.Async || len(fn.Params) != 3 {
+				continue
+			}
+			value := fn.Params[2]
+			if value.Name == "value" && value.Annotation != nil && value.Annotation.Kind == pyExprName {
+				converters[class.Name] = value.Annotation.Name
+			}
+		}
+	}
+	return converters
+}
 
-	// An unrecognized decorator carries no command identity, so record nothing
-	if matches == nil {
-		return nil
+// parsedGroup is a declared slash group resolved to its full path and the scope of its top level group
+type parsedGroup struct {
+	Path        string
+	Description string
+	Scope       string
+}
+
+// parseSlashGroups reads every app_commands.Group declaration keyed by the attribute holding it
+func parseSlashGroups(stmts []pyStmt) map[string]parsedGroup {
+	type declaration struct {
+		name, description, parent string
+		guild                     bool
+	}
+	declarations := map[string]declaration{}
+	for _, stmt := range stmts {
+		target, value, ok := stmt.assignment()
+		if !ok || target.Kind != pyExprName || value.Kind != pyExprCall || value.Callee() != "app_commands.Group" {
+			continue
+		}
+		name, hasName := value.stringKeyword("name")
+		description, hasDescription := value.stringKeyword("description")
+		if !hasName || !hasDescription || name == "" {
+			continue
+		}
+		decl := declaration{name: name, description: description}
+		if parent, ok := value.Keyword("parent"); ok && parent.Kind == pyExprName {
+			decl.parent = parent.Name
+		}
+		_, decl.guild = value.Keyword("guild_ids")
+		// The generator fills in a default for undescribed groups, stripping it keeps configs round trip stable
+		if decl.description == defaultGroupDescription(decl.name) {
+			decl.description = ""
+		}
+		declarations[target.Name] = decl
 	}
 
-	cmd := &CommandInfo{
-		Type:        "slash",
-		Scope:       "global",
-		Name:        matches[1],
-		Description: matches[2],
+	groups := map[string]parsedGroup{}
+	for attr, decl := range declarations {
+		path := decl.name
+		root := decl
+		// Discord only nests two levels deep, so a parent of a parent is not a group we can represent
+		if decl.parent != "" {
+			parent, ok := declarations[decl.parent]
+			if !ok || parent.parent != "" {
+				continue
+			}
+			path = parent.name + " " + decl.name
+			root = parent
+		}
+		scope := "global"
+		if root.guild {
+			scope = "guild"
+		}
+		groups[attr] = parsedGroup{Path: path, Description: decl.description, Scope: scope}
 	}
 
-	funcIndex, describeIndex := scanDecoratorBlock(lines, startIndex, cmd)
+	return groups
+}
 
-	if funcIndex == -1 {
+func parseSlashCommand(parsed *ParsedCogInfo, stmts []pyStmt, fn *pyFunc, decorator pyExpr) *CommandInfo {
+	// A decorator without a written name and description carries no command identity, and a plain
+	// function is never registered, so record nothing
+	name, description, ok := readCommandIdentity(parsed, fn, decorator)
+	if !ok {
 		return nil
 	}
 
-	parseCommandFunction(strings.TrimSpace(lines[funcIndex]), cmd)
-
-	// Argument descriptions are applied after the arguments themselves exist
-	if describeIndex != -1 {
-		parseDescribeDecorator(lines, describeIndex, cmd)
+	cmd := &CommandInfo{
+		Type:        "slash",
+		Scope:       commandScope(fn),
+		Name:        name,
+		Description: description,
 	}
 
-	parseCommandDocstring(lines, funcIndex, cmd)
+	parseCommandFunction(parsed, fn, cmd)
+	parseCommandChecks(fn.Decorators, cmd)
+
+	// Argument descriptions and choices are applied after the arguments themselves exist
+	parseDescribeDecorator(parsed, fn.Decorators, cmd)
+	parseChoicesDecorator(parsed, fn.Decorators, cmd)
+
+	parseCommandDocstring(fn, cmd)
 
 	// A command whose body opens a modal is recorded as a modal command
-	if modalClass, found := findSendModal(lines, funcIndex); found {
+	if modalClass, found := findSendModal(fn); found {
 		cmd.Type = "modal"
 		cmd.Args = nil
+		if modalClass == "" {
+			parsed.warn(fn.Line, fn.Col, DiagModalClass, "command %s opens a modal that is not created by class name, its fields are not read", cmd.Name)
+		}
 		// A FLOW blob is the single source for a multi page command, only single page modals fall back to the class
-		if flow, ok := parseCommandFlow(lines, cmd.Name); ok {
+		if flow, ok := parseCommandFlow(stmts, fn.Name); ok {
 			cmd.Pages = flow.Pages
 			cmd.Responses = flow.Responses
 		} else if modalClass != "" {
-			cmd.Fields = parseModalFields(lines, modalClass)
+			cmd.Fields = parseModalFields(stmts, modalClass)
 		}
 	} else {
-		parseCommandResponse(lines, funcIndex, cmd, slashResponseRegex)
+		parseCommandResponse(fn, cmd, slashResponseSyntax)
+		parseArgAutocomplete(parsed, stmts, fn.Name, cmd)
+		// A COMPONENTS blob marks a command that answers with a view of buttons and select menus
+		var components ComponentsInfo
+		if readJSONBlob(stmts, CommandConstName(fn.Name)+"_COMPONENTS", &components) {
+			cmd.Type = "component"
+			cmd.Components = &components
+		}
 	}
 
 	return cmd
 }
 
-// parseCommandFlow reads the FLOW JSON blob generated next to a multi page modal command
-func parseCommandFlow(lines []string, commandName string) (*commandFlow, bool) {
-	marker := CommandConstName(commandName) + "_FLOW = json.loads(r'''"
+// readCommandIdentity reads the name and description an app command decorator registers with,
+// ok is false with a warning when they are not string literals or the function is not a coroutine
+func readCommandIdentity(parsed *ParsedCogInfo, fn *pyFunc, decorator pyExpr) (name, description string, ok bool) {
+	name, _ = decorator.stringKeyword("name")
+	description, _ = decorator.stringKeyword("description")
+	if name == "" || description == "" {
+		parsed.warn(decorator.Line, decorator.Col, DiagUnnamedCommand,
+			"%s on %s needs name and description written as string literals, the command is left out of botbox.conf", decorator.Callee(), fn.Name)
+		return "", "", false
+	}
+	if !fn.Async {
+		parsed.warn(fn.Line, fn.Col, DiagNotCoroutine, "command %s is not defined with async def, it is left out of botbox.conf", name)
+		return "", "", false
+	}
+	return name, description, true
+}
 
-	// Find the line that opens the raw triple quoted JSON string
-	start := -1
-	for i, line := range lines {
-		if strings.TrimSpace(line) == marker {
-			start = i + 1
-			break
+// commandScope is guild for a command registered to the project's guild and global otherwise
+func commandScope(fn *pyFunc) string {
+	for _, decorator := range fn.Decorators {
+		if decorator.Callee() == "app_commands.guilds" {
+			return "guild"
 		}
 	}
-	if start == -1 {
-		return nil, false
+	return "global"
+}
+
+// commandArg finds an argument of the command by name
+func commandArg(cmd *CommandInfo, name string) *ArgInfo {
+	for i := range cmd.Args {
+		if cmd.Args[i].Name == name {
+			return &cmd.Args[i]
+		}
+	}
+	return nil
+}
+
+// Autocomplete callback body shape the generator writes for suggestions read from a method
+var autocompleteSourceRegex = regexp.MustCompile(`^await self\.autocomplete_(\w+)\(interaction\)$`)
+
+// parseArgAutocomplete marks the arguments that have a generated autocomplete callback and reads where it suggests from,
+// the callbacks register on method, the command's function
+func parseArgAutocomplete(parsed *ParsedCogInfo, stmts []pyStmt, method string, cmd *CommandInfo) {
+
+	for _, stmt := range stmts {
+		if stmt.Func == nil {
+			continue
+		}
+		for _, decorator := range stmt.Func.Decorators {
+			owner, attr, ok := decoratorMethod(decorator)
+			if !ok || owner != method || attr != "autocomplete" || len(decorator.Args) != 1 || decorator.Args[0].Kind != pyExprString {
+				continue
+			}
+
+			arg := commandArg(cmd, decorator.Args[0].Text)
+			if arg == nil {
+				parsed.warn(decorator.Line, decorator.Col, DiagUnknownArgument, "autocomplete for %s names %q, which is not an argument of the command", cmd.Name, decorator.Args[0].Text)
+				continue
+			}
+			arg.Autocomplete = true
+
+			for _, bodyStmt := range flattenStatements(stmt.Func.Body) {
+				target, value, ok := bodyStmt.assignment()
+				if !ok || target.Source != "suggestions" {
+					continue
+				}
+				if sourceMatches := autocompleteSourceRegex.FindStringSubmatch(value.Source); sourceMatches != nil {
+					arg.AutocompleteSource = sourceMatches[1]
+				} else {
+					for _, item := range value.Args {
+						arg.Suggestions = append(arg.Suggestions, item.literal())
+					}
+				}
+				break
+			}
+		}
 	}
+}
 
-	// Collect every line until the closing quotes so the whole blob can be unmarshaled at once
-	var jsonLines []string
-	end := -1
-	for j := start; j < len(lines); j++ {
-		if strings.TrimSpace(lines[j]) == "''')" {
-			end = j
+// parseCommandChecks reads the permission, context, and cooldown decorators the generator writes for app and prefix commands back onto the command
+func parseCommandChecks(decorators []pyExpr, cmd *CommandInfo) {
+	for _, decorator := range decorators {
+		switch decorator.Callee() {
+		case "app_commands.default_permissions", "commands.has_permissions":
+			cmd.Permissions = parseFlagArgs(decorator, nil)
+		case "app_commands.checks.has_any_role", "commands.has_any_role":
+			cmd.Roles = nil
+			for _, role := range decorator.Args {
+				cmd.Roles = append(cmd.Roles, role.literal())
+			}
+		case "app_commands.guild_only", "commands.guild_only":
+			cmd.GuildOnly = true
+		case "app_commands.allowed_installs":
+			cmd.AllowedInstalls = parseFlagArgs(decorator, installKeywords)
+		case "app_commands.allowed_contexts":
+			cmd.AllowedContexts = parseFlagArgs(decorator, contextKeywords)
+		case "app_commands.checks.cooldown":
+			key, _ := decorator.Keyword("key")
+			if len(decorator.Args) != 2 {
+				continue
+			}
+			for bucket, keyFunc := range cooldownKeys {
+				if key.Source == "lambda i: "+keyFunc {
+					cmd.Cooldown = parseCooldown(decorator.Args[0].Source, decorator.Args[1].Source, bucket)
+				}
+			}
+		case "commands.cooldown":
+			if len(decorator.Args) != 3 {
+				continue
+			}
+			bucket, found := strings.CutPrefix(decorator.Args[2].Source, "commands.BucketType.")
+			if found && (bucket == "user" || bucket == "guild" || bucket == "channel") {
+				cmd.Cooldown = parseCooldown(decorator.Args[0].Source, decorator.Args[1].Source, bucket)
+			}
+		}
+	}
+}
+
+// parseFlagArgs reads keyword=True flags back into settings, mapping Python keywords back through keywords
+func parseFlagArgs(decorator pyExpr, keywords map[string]string) []string {
+	var settings []string
+	for _, flag := range decorator.Keywords {
+		if flag.Value.Source != "True" {
+			continue
+		}
+		name := flag.Name
+		for setting, keyword := range keywords {
+			if keyword == name {
+				name = setting
+				break
+			}
+		}
+		settings = append(settings, name)
+	}
+	return settings
+}
+
+// parseCooldown builds a cooldown from the rate and period a decorator was written with
+func parseCooldown(rate, per, bucket string) *CooldownInfo {
+	rateValue, err := strconv.Atoi(rate)
+	if err != nil {
+		return nil
+	}
+	perValue, err := strconv.ParseFloat(per, 64)
+	if err != nil {
+		return nil
+	}
+	return &CooldownInfo{Rate: rateValue, Per: perValue, Bucket: bucket}
+}
+
+// parseContextMenuCommand reads a ContextMenu assigned to an attribute in __init__ and the callback method it points at
+func parseContextMenuCommand(parsed *ParsedCogInfo, stmts []pyStmt, stmt pyStmt) *CommandInfo {
+	target, value, ok := stmt.assignment()
+	if !ok || target.Kind != pyExprAttr || value.Kind != pyExprCall || value.Callee() != "app_commands.ContextMenu" {
+		return nil
+	}
+	name, hasName := value.stringKeyword("name")
+	callbackExpr, _ := value.Keyword("callback")
+	callback, isMethod := strings.CutPrefix(callbackExpr.Source, "self.")
+	if !hasName || !isMethod {
+		parsed.warn(value.Line, value.Col, DiagContextMenu, "context menu needs a name string literal and a self method callback, it is left out of botbox.conf")
+		return nil
+	}
+
+	cmd := &CommandInfo{
+		Name:       name,
+		Scope:      "global",
+		ReturnType: "None",
+	}
+
+	// The registration call carries the guild when the menu is guild scoped
+	for _, other := range stmts {
+		call := other.expression()
+		guild, hasGuild := call.Keyword("guild")
+		if call.Kind == pyExprCall && strings.HasSuffix(call.Callee(), "tree.add_command") &&
+			len(call.Args) > 0 && call.Args[0].Source == target.Source && hasGuild && guild.Source == "GUILD" {
+			cmd.Scope = "guild"
 			break
 		}
-		jsonLines = append(jsonLines, lines[j])
 	}
-	if end == -1 {
-		return nil, false
+
+	// The callback annotation decides between a user and a message menu
+	var fn *pyFunc
+	for _, other := range stmts {
+		if other.Func != nil && other.Func.Name == callback {
+			fn = other.Func
+			break
+		}
+	}
+	if fn == nil {
+		parsed.warn(value.Line, value.Col, DiagContextMenu, "context menu %s calls back %s, which is not a method of this cog", name, callback)
+		return nil
+	}
+	if !fn.Async {
+		parsed.warn(fn.Line, fn.Col, DiagNotCoroutine, "context menu callback %s is not defined with async def, it is left out of botbox.conf", fn.Name)
+		return nil
 	}
+	annotation := ""
+	if len(fn.Params) == 3 && fn.Params[1].Name == "interaction" && fn.Params[2].Annotation != nil {
+		annotation = fn.Params[2].Annotation.Source
+	}
+	switch annotation {
+	case "discord.Message":
+		cmd.Type = "message_context"
+	case "discord.Member", "discord.User":
+		cmd.Type = "user_context"
+	default:
+		parsed.warn(fn.Line, fn.Col, DiagContextMenu,
+			"context menu callback %s must take an interaction and a discord.Member, discord.User, or discord.Message, it is left out of botbox.conf", fn.Name)
+		return nil
+	}
+
+	// Context menus have no registering decorator, their checks sit directly on the callback
+	parseCommandChecks(fn.Decorators, cmd)
+
+	parseCommandDocstring(fn, cmd)
+
+	// The generator appends this phrase to the docstring, stripping it keeps descriptions round trip stable.
+	// A menu without a description gets only the phrase
+	generatedSuffix := fmt.Sprintf(" when the user opens the \"%s\" context menu", cmd.Name)
+	if cmd.Description == "Runs"+generatedSuffix {
+		cmd.Description = ""
+	} else {
+		cmd.Description = strings.TrimSuffix(cmd.Description, generatedSuffix)
+	}
+
+	parseCommandResponse(fn, cmd, slashResponseSyntax)
 
+	return cmd
+}
+
+// parseCommandFlow reads the FLOW JSON blob generated next to a multi page modal command, named after its method
+func parseCommandFlow(stmts []pyStmt, method string) (*commandFlow, bool) {
 	var flow commandFlow
-	if err := json.Unmarshal([]byte(strings.Join(jsonLines, "\n")), &flow); err != nil {
+	if !readJSONBlob(stmts, CommandConstName(method)+"_FLOW", &flow) {
 		return nil, false
 	}
-
 	return &flow, true
 }
 
+// readJSONBlob unmarshals the raw JSON string the generator assigns to a module constant through json.loads into target
+func readJSONBlob(stmts []pyStmt, constant string, target any) bool {
+	for _, stmt := range stmts {
+		name, value, ok := stmt.assignment()
+		if !ok || name.Source != constant || value.Callee() != "json.loads" || len(value.Args) != 1 || value.Args[0].Kind != pyExprString {
+			continue
+		}
+		return json.Unmarshal([]byte(value.Args[0].Text), target) == nil
+	}
+	return false
+}
+
+// responseSyntax holds the call shapes one kind of command body uses to send each response type,
+// every content group captures the f-string text and every ephemeral group captures True or False
+type responseSyntax struct {
+	// reply captures content and ephemeral of a plain message
+	reply *regexp.Regexp
+	// embedReply captures the ephemeral flag of the call that sends the built embed
+	embedReply *regexp.Regexp
+	// deferReply marks a deferred response, its ephemeral flag is captured when the syntax has one
+	deferReply *regexp.Regexp
+	// followUp captures content and ephemeral of the message sent after deferring
+	followUp *regexp.Regexp
+	// dm captures the content sent to the invoking user
+	dm *regexp.Regexp
+	// ephemeral is false when replies carry no ephemeral flag, they are never ephemeral and the default reply has none
+	ephemeral bool
+}
+
+// responseText matches a generated python string literal and captures its text
+const responseText = `f?"((?:[^"\\]|\\.)*)"`
+
 // Reply call shapes the generator writes into slash and prefix command bodies
 var (
-	slashResponseRegex  = regexp.MustCompile(`await interaction\.response\.send_message\(f?"((?:[^"\\]|\\.)*)"\s*,\s*ephemeral\s*=\s*(True|False)\s*\)`)
-	prefixResponseRegex = regexp.MustCompile(`await ctx\.send\(f?"((?:[^"\\]|\\.)*)"\s*,\s*ephemeral\s*=\s*(True|False)\s*\)`)
+	slashResponseRegex  = regexp.MustCompile(`await interaction\.response\.send_message\(` + responseText + `\s*,\s*ephemeral\s*=\s*(True|False)\s*(?:,\s*view\s*=\s*view\s*)?\)`)
+	prefixResponseRegex = regexp.MustCompile(`await ctx\.send\(` + responseText + `\s*,\s*ephemeral\s*=\s*(True|False)\s*\)`)
+
+	slashResponseSyntax = responseSyntax{
+		reply:      slashResponseRegex,
+		embedReply: regexp.MustCompile(`^await interaction\.response\.send_message\(embed=embed, ephemeral=(True|False)(?:, view=view)?\)$`),
+		deferReply: regexp.MustCompile(`^await interaction\.response\.defer\(ephemeral=(True|False), thinking=True\)$`),
+		followUp:   regexp.MustCompile(`^await interaction\.followup\.send\(` + responseText + `, ephemeral=(True|False)\)$`),
+		dm:         regexp.MustCompile(`^await interaction\.user\.send\(` + responseText + `\)$`),
+		ephemeral:  true,
+	}
+	prefixResponseSyntax = responseSyntax{
+		reply:      prefixResponseRegex,
+		embedReply: regexp.MustCompile(`^await ctx\.send\(embed=embed, ephemeral=(True|False)\)$`),
+		deferReply: regexp.MustCompile(`^async with ctx\.typing\(\):$`),
+		followUp:   prefixResponseRegex,
+		dm:         regexp.MustCompile(`^await ctx\.author\.send\(` + responseText + `\)$`),
+		ephemeral:  true,
+	}
+
+	// Embed and channel statements read the same in both kinds of command body, the Embed class comes from the library's module
+	embedRegex          = regexp.MustCompile(`^embed = \w+\.Embed\((?:title=` + responseText + `)?(?:, )?(?:description=` + responseText + `)?(?:, )?(?:colour=\w+\.Colour\(0x([0-9A-Fa-f]{6})\))?\)$`)
+	embedFieldRegex     = regexp.MustCompile(`^embed\.add_field\(name=` + responseText + `, value=` + responseText + `, inline=(True|False)\)$`)
+	embedFooterRegex    = regexp.MustCompile(`^embed\.set_footer\(text=` + responseText + `\)$`)
+	embedThumbnailRegex = regexp.MustCompile(`^embed\.set_thumbnail\(url="([^"]*)"\)$`)
+	channelRegex        = regexp.MustCompile(`^channel = \S+\.get_channel\((\d+)\)`)
+	channelSendRegex    = regexp.MustCompile(`^await channel\.send\(` + responseText + `\)$`)
 )
 
-// parseCommandResponse reads the generated reply call in a command body into the expected responses
-// Only the generated shape counts, the first statement after the docstring must be a try block whose first line is the reply
-func parseCommandResponse(lines []string, funcIndex int, cmd *CommandInfo, replyRegex *regexp.Regexp) {
-	inDocstring := false
-	sawTry := false
-
-	for j := funcIndex + 1; j < len(lines) && j < funcIndex+maxCommandBodyLines; j++ {
-		line := strings.TrimSpace(lines[j])
-
-		// Track the docstring so its text is never mistaken for code
-		if strings.HasPrefix(line, `"""`) {
-			if !inDocstring {
-				inDocstring = strings.Count(line, `"""`) == 1
-			} else {
-				inDocstring = false
-			}
+// parseCommandResponse reads the generated reply statements in a command body into the expected responses
+// Only the generated shape counts, the first statement after the docstring must be a try block that opens with the reply
+func parseCommandResponse(fn *pyFunc, cmd *CommandInfo, syntax responseSyntax) {
+	body := bodyWithoutDocstring(fn.Body)
+	if len(body) == 0 || body[0].keyword() != "try" {
+		return
+	}
+
+	response := ResponseInfo{Type: "message"}
+
+	for _, stmt := range flattenStatements(body[0].Body) {
+		line := stmt.Source()
+
+		// Component commands build their view right before the reply
+		if strings.HasPrefix(line, "view = ") {
 			continue
 		}
-		if inDocstring || line == "" {
+
+		// Statements that build up the response come first, the call that sends it ends the scan
+		if matches := embedRegex.FindStringSubmatch(line); matches != nil {
+			response.Type = "embed"
+			response.Content = matches[2]
+			response.Embed = &EmbedInfo{Title: matches[1]}
+			if matches[3] != "" {
+				response.Embed.Color = "#" + matches[3]
+			}
 			continue
 		}
-
-		// The first real statement has to open the try block or the body is not the generated shape
-		if !sawTry {
-			if line == "try:" {
-				sawTry = true
+		if response.Embed != nil {
+			if matches := embedFieldRegex.FindStringSubmatch(line); matches != nil {
+				response.Embed.Fields = append(response.Embed.Fields, EmbedFieldInfo{Name: matches[1], Value: matches[2], Inline: matches[3] == "True"})
 				continue
 			}
+			if matches := embedFooterRegex.FindStringSubmatch(line); matches != nil {
+				response.Embed.Footer = matches[1]
+				continue
+			}
+			if matches := embedThumbnailRegex.FindStringSubmatch(line); matches != nil {
+				response.Embed.Thumbnail = matches[1]
+				continue
+			}
+			if matches := syntax.embedReply.FindStringSubmatch(line); matches != nil {
+				response.Ephemeral = syntax.ephemeral && matches[1] == "True"
+				cmd.Responses = []ResponseInfo{response}
+			}
+			return
+		}
+		if response.Type == "defer" {
+			if matches := syntax.followUp.FindStringSubmatch(line); matches != nil {
+				response.Content = matches[1]
+				response.Ephemeral = syntax.ephemeral && matches[2] == "True"
+				cmd.Responses = []ResponseInfo{response}
+			}
+			return
+		}
+		if matches := syntax.deferReply.FindStringSubmatch(line); matches != nil {
+			response.Type = "defer"
+			continue
+		}
+		if response.Type == "channel" {
+			if matches := channelSendRegex.FindStringSubmatch(line); matches != nil {
+				response.Content = matches[1]
+				cmd.Responses = []ResponseInfo{response}
+			}
+			return
+		}
+		if matches := channelRegex.FindStringSubmatch(line); matches != nil {
+			response.Type = "channel"
+			response.ChannelID = matches[1]
+			continue
+		}
+		if matches := syntax.dm.FindStringSubmatch(line); matches != nil {
+			cmd.Responses = []ResponseInfo{{Type: "dm", Content: matches[1]}}
 			return
 		}
 
-		matches := replyRegex.FindStringSubmatch(line)
+		matches := syntax.reply.FindStringSubmatch(line)
 		if matches == nil {
 			return
 		}
 
 		content := matches[1]
-		ephemeral := matches[2] == "True"
+		ephemeral := syntax.ephemeral && matches[2] == "True"
 
 		// The default generated reply echoes the command name, that exact shape means no expected responses
-		if content == cmd.Name && ephemeral {
+		if content == cmd.Name && (ephemeral || !syntax.ephemeral) {
 			return
 		}
 
@@ -584,22 +1326,13 @@ func parseCommandResponse(lines []string, funcIndex int, cmd *CommandInfo, reply
 	}
 }
 
-// Line budget for scanning a command body for a send_modal call
-const maxCommandBodyLines = 30
+// Generated cogs pass the modal through localize_modal first, older cogs send it directly
+var sendModalRegex = regexp.MustCompile(`send_modal\((?:await localize_modal\(interaction, )?(\w+)\(`)
 
 // findSendModal reports whether the function body calls send_modal and which modal class it opens
-func findSendModal(lines []string, funcIndex int) (string, bool) {
-	sendModalRegex := regexp.MustCompile(`send_modal\(\s*(\w+)\s*\(`)
-
-	for j := funcIndex + 1; j < len(lines) && j < funcIndex+maxCommandBodyLines; j++ {
-		line := strings.TrimSpace(lines[j])
-
-		// Stop before crossing into the next decorator, function, or class
-		if strings.HasPrefix(line, "@") || strings.HasPrefix(line, "async def ") ||
-			strings.HasPrefix(line, "def ") || strings.HasPrefix(line, "class ") {
-			break
-		}
-
+func findSendModal(fn *pyFunc) (string, bool) {
+	for _, stmt := range flattenStatements(bodyWithoutDocstring(fn.Body)) {
+		line := stmt.Source()
 		if !strings.Contains(line, "send_modal(") {
 			continue
 		}
@@ -615,262 +1348,292 @@ func findSendModal(lines []string, funcIndex int) (string, bool) {
 	return "", false
 }
 
-// parseModalFields reads the TextInput assignments out of the named modal class body
-func parseModalFields(lines []string, modalClass string) []FieldInfo {
-	classRegex := regexp.MustCompile(`^class\s+` + regexp.QuoteMeta(modalClass) + `\s*\(.*discord\.ui\.Modal`)
-	textInputRegex := regexp.MustCompile(`^(\w+)\s*=\s*discord\.ui\.TextInput\((.*)\)\s*$`)
-	labelRegex := regexp.MustCompile(`label\s*=\s*["']([^"']*)["']`)
-	styleRegex := regexp.MustCompile(`style\s*=\s*discord\.TextStyle\.(\w+)`)
-	requiredRegex := regexp.MustCompile(`required\s*=\s*(True|False)`)
-	placeholderRegex := regexp.MustCompile(`placeholder\s*=\s*["']([^"']*)["']`)
-
-	classIndex := -1
-	for i, line := range lines {
-		if classRegex.MatchString(strings.TrimSpace(line)) {
-			classIndex = i
-			break
+// parseModalFields reads the TextInput attributes out of the named modal class body
+func parseModalFields(stmts []pyStmt, modalClass string) []FieldInfo {
+	for _, stmt := range stmts {
+		class := stmt.Class
+		if class == nil || class.Name != modalClass || !classHasBase(class, "discord.ui.Modal") {
+			continue
 		}
-	}
-
-	if classIndex == -1 {
-		return nil
-	}
-
-	var fields []FieldInfo
-	for j := classIndex + 1; j < len(lines); j++ {
-		trimmed := strings.TrimSpace(lines[j])
 
-		// A non-indented statement ends the class body
-		if trimmed != "" && !strings.HasPrefix(lines[j], " ") && !strings.HasPrefix(lines[j], "\t") {
-			break
-		}
+		var fields []FieldInfo
+		for _, member := range class.Body {
+			target, value, ok := member.assignment()
+			if !ok || target.Kind != pyExprName || value.Kind != pyExprCall || value.Callee() != "discord.ui.TextInput" {
+				continue
+			}
 
-		matches := textInputRegex.FindStringSubmatch(trimmed)
-		if matches == nil {
-			continue
-		}
+			field := FieldInfo{
+				Name: target.Name,
+				// TextInput defaults to a required short style input when the arguments are absent
+				Style:    "short",
+				Required: true,
+			}
 
-		field := FieldInfo{
-			Name: matches[1],
-			// TextInput defaults to a required short style input when the arguments are absent
-			Style:    "short",
-			Required: true,
-		}
+			if label, ok := value.stringKeyword("label"); ok {
+				field.Label = label
+			}
+			if style, ok := value.Keyword("style"); ok {
+				if styleName, found := strings.CutPrefix(style.Source, "discord.TextStyle."); found {
+					field.Style = styleName
+				}
+			}
+			if required, ok := value.Keyword("required"); ok && (required.Source == "True" || required.Source == "False") {
+				field.Required = required.Source == "True"
+			}
+			if placeholder, ok := value.stringKeyword("placeholder"); ok {
+				field.Placeholder = placeholder
+			}
 
-		callArgs := matches[2]
-		if labelMatch := labelRegex.FindStringSubmatch(callArgs); labelMatch != nil {
-			field.Label = labelMatch[1]
+			fields = append(fields, field)
 		}
-		if styleMatch := styleRegex.FindStringSubmatch(callArgs); styleMatch != nil {
-			field.Style = styleMatch[1]
-		}
-		if requiredMatch := requiredRegex.FindStringSubmatch(callArgs); requiredMatch != nil {
-			field.Required = requiredMatch[1] == "True"
-		}
-		if placeholderMatch := placeholderRegex.FindStringSubmatch(callArgs); placeholderMatch != nil {
-			field.Placeholder = placeholderMatch[1]
-		}
-
-		fields = append(fields, field)
+		return fields
 	}
 
-	return fields
+	return nil
 }
 
-// scanDecoratorBlock walks the decorators between startIndex and the function they decorate
-func scanDecoratorBlock(lines []string, startIndex int, cmd *CommandInfo) (funcIndex, describeIndex int) {
-	funcIndex = -1
-	describeIndex = -1
-
-	for j := startIndex + 1; j < len(lines) && j < startIndex+maxSlashDecoratorLines; j++ {
-		line := strings.TrimSpace(lines[j])
-
-		if strings.HasPrefix(line, "async def ") {
-			funcIndex = j
-			break
+// restoreTransformArgs maps converter class annotations back to the Transform the config records,
+// prefix and hybrid commands annotate with the converter class itself
+func restoreTransformArgs(cmd *CommandInfo, converters map[string]string) {
+	for j := range cmd.Args {
+		if valueType, ok := converters[cmd.Args[j].Type]; ok {
+			cmd.Args[j].Type = TransformArgType(valueType, cmd.Args[j].Type)
 		}
+	}
+}
 
-		if strings.Contains(line, "@app_commands.guilds(GUILD)") {
-			cmd.Scope = "guild"
-		}
+// parseHybridCommand reads a hybrid command, it is declared like a slash command but its body replies through ctx
+func parseHybridCommand(parsed *ParsedCogInfo, fn *pyFunc, decorator pyExpr) *CommandInfo {
+	// Without the name and description the slash half cannot be regenerated
+	name, description, ok := readCommandIdentity(parsed, fn, decorator)
+	if !ok {
+		return nil
+	}
 
-		if describeIndex == -1 && strings.Contains(line, "@app_commands.describe") {
-			describeIndex = j
-		}
+	cmd := &CommandInfo{
+		Type:        "hybrid",
+		Scope:       commandScope(fn),
+		Name:        name,
+		Description: description,
 	}
 
-	return funcIndex, describeIndex
-}
+	parseCommandFunction(parsed, fn, cmd)
+	parseCommandChecks(fn.Decorators, cmd)
+	parseDescribeDecorator(parsed, fn.Decorators, cmd)
+	parseDocstringArgDescriptions(fn, cmd)
+	parseCommandResponse(fn, cmd, prefixResponseSyntax)
 
-func parsePrefixCommand(lines []string, startIndex int) *CommandInfo {
-	cmd := &CommandInfo{
-		Type:  "prefix",
-		Scope: "global"}
+	return cmd
+}
 
-	funcIndex := -1
-	for j := startIndex + 1; j < len(lines) && j < startIndex+maxPrefixDecoratorLines; j++ {
-		line := strings.TrimSpace(lines[j])
-		if strings.HasPrefix(line, "async def ") {
-			funcIndex = j
-			break
+// parsePrefixDecorator reads the aliases, hidden flag, and invoke_without_command setting of a prefix decorator
+func parsePrefixDecorator(decorator pyExpr, cmd *CommandInfo) {
+	// A written name replaces the method's, subcommands are written that way since their methods carry the group
+	if name, ok := decorator.stringKeyword("name"); ok {
+		cmd.Name = name
+	}
+	if aliases, ok := decorator.Keyword("aliases"); ok {
+		for _, alias := range aliases.Args {
+			cmd.Aliases = append(cmd.Aliases, alias.literal())
 		}
 	}
+	if hidden, ok := decorator.Keyword("hidden"); ok {
+		cmd.Hidden = hidden.Source == "True"
+	}
+	if invoke, ok := decorator.Keyword("invoke_without_command"); ok {
+		cmd.InvokeWithoutCommand = invoke.Source == "True"
+	}
+}
 
-	// Without a function definition there is no command name to record
-	if funcIndex == -1 {
+func parsePrefixCommand(parsed *ParsedCogInfo, fn *pyFunc, decorator pyExpr, group string, syntax responseSyntax) *CommandInfo {
+	// A plain function is never registered, so there is no command to record
+	if !fn.Async {
+		parsed.warn(fn.Line, fn.Col, DiagNotCoroutine, "command %s is not defined with async def, it is left out of botbox.conf", fn.Name)
 		return nil
 	}
 
-	funcLine := strings.TrimSpace(lines[funcIndex])
-	parseCommandFunction(funcLine, cmd)
+	cmd := &CommandInfo{
+		Type:  "prefix",
+		Scope: "global",
+		Name:  fn.Name,
+		Group: group}
 
-	funcRegex := regexp.MustCompile(`async def (\w+)\s*\(`)
-	if matches := funcRegex.FindStringSubmatch(funcLine); matches != nil {
-		cmd.Name = matches[1]
-	}
+	parsePrefixDecorator(decorator, cmd)
 
-	if cmd.Name == "" {
-		return nil
-	}
+	parseCommandFunction(parsed, fn, cmd)
+	parseCommandChecks(fn.Decorators, cmd)
 
-	parseCommandDocstring(lines, funcIndex, cmd)
+	parseCommandDocstring(fn, cmd)
 
 	// The generator appends this phrase to the docstring, stripping it keeps descriptions round trip stable
-	generatedSuffix := fmt.Sprintf(" when the user types \"/%s\"", cmd.Name)
+	generatedSuffix := fmt.Sprintf(" when the user types \"/%s\"", CommandPath(*cmd))
 	cmd.Description = strings.TrimSuffix(cmd.Description, generatedSuffix)
 
-	parseDocstringArgDescriptions(lines, funcIndex, cmd)
+	parseDocstringArgDescriptions(fn, cmd)
 
-	parseCommandResponse(lines, funcIndex, cmd, prefixResponseRegex)
+	parseCommandResponse(fn, cmd, syntax)
 
 	return cmd
 }
 
+// Argument line shape of the Parameters block in a generated docstring
+var docstringArgRegex = regexp.MustCompile(`^(\w+)\s*\(([^)]*)\):\s*(.+)$`)
+
 // parseDocstringArgDescriptions fills empty arg descriptions from the docstring Parameters block,
 // prefix commands have no describe decorator so the docstring is their only source
-func parseDocstringArgDescriptions(lines []string, funcIndex int, cmd *CommandInfo) {
-	if funcIndex < 0 || len(cmd.Args) == 0 {
+func parseDocstringArgDescriptions(fn *pyFunc, cmd *CommandInfo) {
+	if len(cmd.Args) == 0 {
 		return
 	}
 
-	argLineRegex := regexp.MustCompile(`^(\w+)\s*\(([^)]*)\):\s*(.+)$`)
+	for _, line := range strings.Split(fn.Docstring, "\n") {
+		matches := docstringArgRegex.FindStringSubmatch(strings.TrimSpace(line))
+		if matches == nil {
+			continue
+		}
+
+		if arg := commandArg(cmd, matches[1]); arg != nil && arg.Description == "" {
+			arg.Description = strings.TrimSpace(matches[3])
+		}
+	}
+}
+
+func parseCommandFunction(parsed *ParsedCogInfo, fn *pyFunc, cmd *CommandInfo) {
+	cmd.ReturnType = "None"
+	if fn.Returns != nil {
+		cmd.ReturnType = fn.Returns.Source
+	}
 
-	inDocstring := false
-	for j := funcIndex + 1; j < len(lines); j++ {
-		line := strings.TrimSpace(lines[j])
-		if strings.HasPrefix(line, `"""`) {
-			if inDocstring {
-				return
-			}
-			inDocstring = true
+	rest := false
+	for _, param := range fn.Params {
+		// A bare * makes the argument after it keyword only, prefix commands read that as the rest of the message
+		if param.Star == "*" && param.Name == "" {
+			rest = true
 			continue
 		}
-		if !inDocstring {
+		if param.Star != "" || param.Name == "self" || param.Name == "interaction" || param.Name == "ctx" || param.Name == "inter" {
 			continue
 		}
-
-		matches := argLineRegex.FindStringSubmatch(line)
-		if matches == nil {
+		if param.Annotation == nil {
+			parsed.warn(param.Line, param.Col, DiagUntypedArgument, "argument %s of %s has no type annotation, it is left out of botbox.conf", param.Name, fn.Name)
 			continue
 		}
 
-		for i := range cmd.Args {
-			if cmd.Args[i].Name == matches[1] && cmd.Args[i].Description == "" {
-				cmd.Args[i].Description = strings.TrimSpace(matches[3])
-				break
-			}
+		annotation := *param.Annotation
+		arg := ArgInfo{
+			Name: param.Name,
+			Type: annotation.Source,
+			Rest: rest,
 		}
-	}
-}
+		rest = false
 
-func parseCommandFunction(line string, cmd *CommandInfo) {
-	returnRegex := regexp.MustCompile(`->\s*([^:]+):`)
-	if matches := returnRegex.FindStringSubmatch(line); matches != nil {
-		cmd.ReturnType = strings.TrimSpace(matches[1])
-	} else {
-		cmd.ReturnType = "None"
-	}
+		if annotation.Kind == pyExprSubscript && annotation.Value.Source == "commands.Greedy" && len(annotation.Args) == 1 {
+			annotation = annotation.Args[0]
+			arg.Type = annotation.Source
+			arg.Greedy = true
+		}
 
-	paramRegex := regexp.MustCompile(`\(([^)]+)\)`)
-	if matches := paramRegex.FindStringSubmatch(line); matches != nil {
-		for param := range strings.SplitSeq(matches[1], ",") {
-			param = strings.TrimSpace(param)
-			if param == "self" || strings.HasPrefix(param, "interaction:") || strings.HasPrefix(param, "ctx:") {
-				continue
+		// A bounded argument keeps its plain type, the bounds move onto the arg
+		if annotation.Kind == pyExprSubscript && annotation.Value.Source == "app_commands.Range" && len(annotation.Args) == 3 {
+			arg.Type = annotation.Args[0].Source
+			if bound := annotation.Args[1].Source; bound != "None" {
+				arg.Min = bound
 			}
+			if bound := annotation.Args[2].Source; bound != "None" {
+				arg.Max = bound
+			}
+		}
 
-			parts := strings.Split(param, ":")
-			if len(parts) >= 2 {
-				name := strings.TrimSpace(parts[0])
-				typeAndDefault := strings.TrimSpace(parts[1])
+		if param.Default != nil {
+			arg.Optional = true
+			arg.Default = param.Default.literal()
+		}
 
-				typePart := strings.Split(typeAndDefault, "=")[0]
-				typePart = strings.TrimSpace(typePart)
+		cmd.Args = append(cmd.Args, arg)
+	}
+}
 
-				cmd.Args = append(cmd.Args, ArgInfo{
-					Name: name,
-					Type: typePart,
-				})
+// warnUnsupportedArgs warns about the arguments of a parsed command that botbox add and edit would reject, a type
+// botbox cannot generate or a missing description, at the parameter that declares them
+func warnUnsupportedArgs(parsed *ParsedCogInfo, fn *pyFunc, cmd *CommandInfo) {
+	for _, arg := range cmd.Args {
+		line, column := fn.Line, fn.Col
+		for _, param := range fn.Params {
+			if param.Name == arg.Name {
+				line, column = param.Line, param.Col
 			}
 		}
+		if err := ValidateArgType(arg.Type); err != nil {
+			parsed.warn(line, column, DiagArgumentType, "argument %s of %s has the type %s, which botbox cannot generate", arg.Name, CommandPath(*cmd), arg.Type)
+		}
+		if arg.Description == "" {
+			parsed.warn(line, column, DiagUndescribedArgument, "argument %s of %s has no description", arg.Name, CommandPath(*cmd))
+		}
 	}
 }
 
-func parseDescribeDecorator(lines []string, startIndex int, cmd *CommandInfo) {
-	fullDecorator := strings.TrimSpace(lines[startIndex])
-
-	// Only a decorator left open on its first line continues onto the following lines
-	if !strings.HasSuffix(fullDecorator, ")") {
-		for j := startIndex + 1; j < len(lines); j++ {
-			nextLine := strings.TrimSpace(lines[j])
-			if strings.HasSuffix(nextLine, ")") {
-				fullDecorator += " " + nextLine
-				break
-			} else if nextLine != "" {
-				fullDecorator += " " + nextLine
+// parseChoicesDecorator reads the fixed choices of each argument from an app_commands.choices decorator
+func parseChoicesDecorator(parsed *ParsedCogInfo, decorators []pyExpr, cmd *CommandInfo) {
+	for _, decorator := range decorators {
+		if decorator.Callee() != "app_commands.choices" {
+			continue
+		}
+		for _, keyword := range decorator.Keywords {
+			arg := commandArg(cmd, keyword.Name)
+			if arg == nil {
+				parsed.warn(decorator.Line, decorator.Col, DiagUnknownArgument, "choices for %s name %q, which is not an argument of the command", cmd.Name, keyword.Name)
+				continue
+			}
+			if keyword.Value.Kind != pyExprList {
+				continue
+			}
+			for _, choice := range keyword.Value.Args {
+				name, hasName := choice.stringKeyword("name")
+				value, hasValue := choice.Keyword("value")
+				if choice.Callee() != "app_commands.Choice" || !hasName || !hasValue {
+					continue
+				}
+				arg.Choices = append(arg.Choices, ChoiceInfo{Name: name, Value: value.literal()})
 			}
 		}
 	}
+}
 
-	argRegex := regexp.MustCompile(`(\w+)\s*=\s*["']([^"']+)["']`)
-	matches := argRegex.FindAllStringSubmatch(fullDecorator, -1)
-
-	for _, match := range matches {
-		argName := match[1]
-		description := match[2]
-
-		for i := range cmd.Args {
-			if cmd.Args[i].Name == argName {
-				cmd.Args[i].Description = description
-				break
+// parseDescribeDecorator reads the argument descriptions of an app_commands.describe decorator
+func parseDescribeDecorator(parsed *ParsedCogInfo, decorators []pyExpr, cmd *CommandInfo) {
+	for _, decorator := range decorators {
+		if decorator.Callee() != "app_commands.describe" {
+			continue
+		}
+		for _, keyword := range decorator.Keywords {
+			arg := commandArg(cmd, keyword.Name)
+			if arg == nil {
+				parsed.warn(decorator.Line, decorator.Col, DiagUnknownArgument, "describe for %s names %q, which is not an argument of the command", cmd.Name, keyword.Name)
+				continue
+			}
+			if description, ok := decorator.stringKeyword(keyword.Name); ok {
+				arg.Description = description
 			}
 		}
 	}
 }
 
 // parseCommandDocstring fills in the description from the function docstring when nothing else supplied one
-func parseCommandDocstring(lines []string, funcIndex int, cmd *CommandInfo) {
-	if funcIndex < 0 || cmd.Description != "" {
+func parseCommandDocstring(fn *pyFunc, cmd *CommandInfo) {
+	if cmd.Description != "" || fn.Docstring == "" {
 		return
 	}
 
-	for j := funcIndex + 1; j < len(lines) && j < funcIndex+10; j++ {
-		line := strings.TrimSpace(lines[j])
-		if !strings.HasPrefix(line, `"""`) {
-			continue
-		}
-
-		content := strings.TrimSuffix(strings.TrimPrefix(line, `"""`), `"""`)
-		content = strings.TrimSpace(content)
+	lines := strings.Split(fn.Docstring, "\n")
+	content := strings.TrimSpace(lines[0])
 
-		// Generated docstrings open on their own line and carry the summary on the next one
-		if content == "" && j+1 < len(lines) {
-			content = strings.TrimSpace(lines[j+1])
-		}
-
-		cmd.Description = content
-		break
+	// Generated docstrings open on their own line and carry the summary on the next one
+	if content == "" && len(lines) > 1 {
+		content = strings.TrimSpace(lines[1])
 	}
+
+	cmd.Description = content
 }
 
 func updateCogConfig(existing *CogConfig, parsed ParsedCogInfo) bool {
@@ -891,6 +1654,16 @@ func updateCogConfig(existing *CogConfig, parsed ParsedCogInfo) bool {
 		updated = true
 	}
 
+	if !slices.Equal(existing.Listeners, parsed.Listeners) {
+		existing.Listeners = parsed.Listeners
+		updated = true
+	}
+
+	if !slices.EqualFunc(existing.Tasks, parsed.Tasks, taskEqual) {
+		existing.Tasks = parsed.Tasks
+		updated = true
+	}
+
 	return updated
 }
 
@@ -900,6 +1673,8 @@ func createCogConfigFromParsed(parsed ParsedCogInfo) CogConfig {
 		File: parsed.FileName,
 		Env:  "development", SlashCommands: parsed.SlashCommands,
 		PrefixCommands: parsed.PrefixCommands,
+		Listeners:      parsed.Listeners,
+		Tasks:          parsed.Tasks,
 	}
 }
 
@@ -925,6 +1700,39 @@ func checkHeaderIssues(parsed ParsedCogInfo, botInfo *BotConfig) string {
 	return ""
 }
 
+// commandChanges describes how the commands of a cog differ between botbox.conf and the cog file, commands are
+// matched by type and path and compared with commandEqual. Each line reads like "admin: slash command "config sync" changed"
+func commandChanges(cogFile string, configured, parsed []CommandInfo) []string {
+	var changes []string
+
+	used := make([]bool, len(configured))
+	for _, cmd := range parsed {
+		match := -1
+		for i, existing := range configured {
+			if !used[i] && existing.Type == cmd.Type && CommandPath(existing) == CommandPath(cmd) {
+				match = i
+				break
+			}
+		}
+		if match < 0 {
+			changes = append(changes, fmt.Sprintf("%s: %s command %q added", cogFile, cmd.Type, CommandPath(cmd)))
+			continue
+		}
+		used[match] = true
+		if !commandEqual(configured[match], cmd) {
+			changes = append(changes, fmt.Sprintf("%s: %s command %q changed", cogFile, cmd.Type, CommandPath(cmd)))
+		}
+	}
+
+	for i, cmd := range configured {
+		if !used[i] {
+			changes = append(changes, fmt.Sprintf("%s: %s command %q removed", cogFile, cmd.Type, CommandPath(cmd)))
+		}
+	}
+
+	return changes
+}
+
 func commandsEqual(a, b []CommandInfo) bool {
 	if len(a) != len(b) {
 		return false
@@ -954,7 +1762,26 @@ func commandsEqual(a, b []CommandInfo) bool {
 
 func commandEqual(a, b CommandInfo) bool {
 	if a.Name != b.Name || a.Type != b.Type || a.Scope != b.Scope ||
-		a.Description != b.Description || a.ReturnType != b.ReturnType {
+		a.Description != b.Description || a.ReturnType != b.ReturnType ||
+		a.Group != b.Group || a.GroupDescription != b.GroupDescription || a.GuildOnly != b.GuildOnly ||
+		a.Hidden != b.Hidden || a.InvokeWithoutCommand != b.InvokeWithoutCommand || !slices.Equal(a.Aliases, b.Aliases) {
+		return false
+	}
+
+	if !maps.Equal(a.NameLocalizations, b.NameLocalizations) || !maps.Equal(a.DescriptionLocalizations, b.DescriptionLocalizations) {
+		return false
+	}
+
+	if !slices.Equal(a.Permissions, b.Permissions) || !slices.Equal(a.Roles, b.Roles) ||
+		!slices.Equal(a.AllowedInstalls, b.AllowedInstalls) || !slices.Equal(a.AllowedContexts, b.AllowedContexts) {
+		return false
+	}
+
+	if (a.Cooldown == nil) != (b.Cooldown == nil) || a.Cooldown != nil && *a.Cooldown != *b.Cooldown {
+		return false
+	}
+
+	if !componentsEqual(a.Components, b.Components) {
 		return false
 	}
 
@@ -962,9 +1789,8 @@ func commandEqual(a, b CommandInfo) bool {
 		return false
 	}
 
-	for i, argA := range a.Args {
-		argB := b.Args[i]
-		if argA.Name != argB.Name || argA.Type != argB.Type || argA.Description != argB.Description {
+	for i := range a.Args {
+		if !argEqual(a.Args[i], b.Args[i]) {
 			return false
 		}
 	}
@@ -973,8 +1799,8 @@ func commandEqual(a, b CommandInfo) bool {
 		return false
 	}
 
-	for i, fieldA := range a.Fields {
-		if fieldA != b.Fields[i] {
+	for i := range a.Fields {
+		if !fieldEqual(a.Fields[i], b.Fields[i]) {
 			return false
 		}
 	}
@@ -994,7 +1820,72 @@ func commandEqual(a, b CommandInfo) bool {
 	}
 
 	for i, responseA := range a.Responses {
-		if responseA != b.Responses[i] {
+		if !responseEqual(responseA, b.Responses[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// responseEqual compares two responses including their embeds
+func responseEqual(a, b ResponseInfo) bool {
+	if a.Type != b.Type || a.Content != b.Content || a.Ephemeral != b.Ephemeral || a.ChannelID != b.ChannelID {
+		return false
+	}
+	if a.Embed == nil || b.Embed == nil {
+		return a.Embed == b.Embed
+	}
+	return a.Embed.Title == b.Embed.Title && a.Embed.Color == b.Embed.Color && a.Embed.Footer == b.Embed.Footer &&
+		a.Embed.Thumbnail == b.Embed.Thumbnail && slices.Equal(a.Embed.Fields, b.Embed.Fields)
+}
+
+// componentsEqual compares two component views including every item and select option
+func componentsEqual(a, b *ComponentsInfo) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.Timeout != b.Timeout || a.AuthorOnly != b.AuthorOnly {
+		return false
+	}
+	return slices.EqualFunc(a.Items, b.Items, func(x, y ComponentInfo) bool {
+		return x.Name == y.Name && x.Type == y.Type && x.Label == y.Label && x.Style == y.Style &&
+			responseEqual(x.Response, y.Response) &&
+			slices.EqualFunc(x.Options, y.Options, func(p, q SelectOptionInfo) bool {
+				return p.Label == q.Label && p.Value == q.Value && responseEqual(p.Response, q.Response)
+			})
+	})
+}
+
+// argEqual compares two arguments including their optional settings, choices, and autocomplete
+func argEqual(a, b ArgInfo) bool {
+	if a.Name != b.Name || a.Type != b.Type || a.Description != b.Description ||
+		a.Optional != b.Optional || a.Default != b.Default || a.Min != b.Min || a.Max != b.Max ||
+		a.Autocomplete != b.Autocomplete || a.AutocompleteSource != b.AutocompleteSource ||
+		a.Greedy != b.Greedy || a.Rest != b.Rest {
+		return false
+	}
+
+	if !maps.Equal(a.NameLocalizations, b.NameLocalizations) || !maps.Equal(a.DescriptionLocalizations, b.DescriptionLocalizations) {
+		return false
+	}
+
+	if len(a.Suggestions) != len(b.Suggestions) {
+		return false
+	}
+
+	for i, suggestionA := range a.Suggestions {
+		if suggestionA != b.Suggestions[i] {
+			return false
+		}
+	}
+
+	if len(a.Choices) != len(b.Choices) {
+		return false
+	}
+
+	for i, choiceA := range a.Choices {
+		if choiceA != b.Choices[i] {
 			return false
 		}
 	}
@@ -1002,6 +1893,12 @@ func commandEqual(a, b CommandInfo) bool {
 	return true
 }
 
+// fieldEqual compares two modal fields including their label translations
+func fieldEqual(a, b FieldInfo) bool {
+	return a.Name == b.Name && a.Label == b.Label && a.Style == b.Style && a.Required == b.Required &&
+		a.Placeholder == b.Placeholder && maps.Equal(a.LabelLocalizations, b.LabelLocalizations)
+}
+
 // pageEqual compares two flow pages including their fields and branch rules
 func pageEqual(a, b PageInfo) bool {
 	if a.Name != b.Name || a.Title != b.Title || a.Next != b.Next {
@@ -1012,8 +1909,8 @@ func pageEqual(a, b PageInfo) bool {
 		return false
 	}
 
-	for i, fieldA := range a.Fields {
-		if fieldA != b.Fields[i] {
+	for i := range a.Fields {
+		if !fieldEqual(a.Fields[i], b.Fields[i]) {
 			return false
 		}
 	}
@@ -1032,25 +1929,21 @@ func pageEqual(a, b PageInfo) bool {
 }
 
 func saveConfig(rootDir string, config Config) error {
-	configPath := filepath.Join(rootDir, "botbox.conf")
+	// Applying writes to a sibling temp file first so a failed write cannot destroy the existing config
+	changes := &ChangeSet{}
+	if err := StageConfig(changes, rootDir, config); err != nil {
+		return err
+	}
+	return changes.Apply()
+}
 
+// StageConfig stages botbox.conf with the given config, serialized the way every command writes it
+func StageConfig(changes *ChangeSet, rootDir string, config Config) error {
 	jsonData, err := json.MarshalIndent(config, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
-
-	// Write to a sibling temp file first so a failed write cannot destroy the existing config
-	tempPath := configPath + ".tmp"
-	if err := os.WriteFile(tempPath, jsonData, 0644); err != nil {
-		return fmt.Errorf("failed to write temp config file: %w", err)
-	}
-
-	if err := os.Rename(tempPath, configPath); err != nil {
-		os.Remove(tempPath)
-		return fmt.Errorf("failed to replace config file: %w", err)
-	}
-
-	return nil
+	return changes.WriteFile(filepath.Join(rootDir, "botbox.conf"), jsonData)
 }
 
 func SyncGlobalConfigVersion() error {
@@ -49,9 +49,20 @@ func RunHeadless(m Model) []error {
 		return m.Error
 	}
 
-	// Run the main callback that does the actual work
+	// Run the main callback that does the actual work, the files it stages are written once it succeeds
+	if m.Changes == nil {
+		m.Changes = &ChangeSet{}
+	}
 	if m.callback != nil {
-		return m.callback(&m)
+		if errs := m.callback(&m); len(errs) > 0 {
+			return errs
+		}
+	}
+	if m.DryRun {
+		return nil
+	}
+	if err := m.Changes.Apply(); err != nil {
+		return []error{err}
 	}
 	return nil
 }
@@ -13,6 +13,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -181,6 +182,14 @@ func ValidateFileName(fileName string) error {
 	if fileExists(fileName) {
 		return fmt.Errorf("file with name '%s' already exists", fileName)
 	}
+	return validateFileNameCharacters(fileName)
+}
+
+// validateFileNameCharacters checks a cog file name without looking at the files already in the project
+func validateFileNameCharacters(fileName string) error {
+	if fileName == "" {
+		return fmt.Errorf("filename cannot be empty")
+	}
 	if strings.Contains(fileName, " ") {
 		return fmt.Errorf("filename cannot contain spaces")
 	}
@@ -198,7 +207,14 @@ func fileExists(fileName string) bool {
 	if err != nil {
 		return false
 	}
-	filePath := filepath.Join(rootDir, "src", "cogs", fileName+".py")
+	config, err := LoadConfig()
+	if err != nil {
+		return false
+	}
+	filePath, err := CogFilePath(rootDir, config.BotInfo.Library, fileName)
+	if err != nil {
+		return false
+	}
 	_, err = os.Stat(filePath)
 	if err == nil {
 		return true
@@ -209,49 +225,127 @@ func fileExists(fileName string) bool {
 	return false
 }
 
-// RegenerateCogFile rewrites a cog's .py file from its config definition,
-// writing a .py.bak copy of the current file first when backup is true
-func RegenerateCogFile(rootDir string, config Config, cog CogConfig, backup bool) error {
-	filePath := filepath.Join(rootDir, "src", "cogs", cog.File+".py")
+// RegenerateResult reports what RegenerateCogFile carried over from the cog file it replaced
+type RegenerateResult struct {
+	// Path is the cog file that was staged
+	Path string
+	// Unprotected is true when the replaced file had no protected region markers, so none of its code was kept
+	Unprotected bool
+	// Preserved names the protected regions whose hand written code was kept, like "body hello" or "class"
+	Preserved []string
+	// BackupID names the backup of the replaced file and botbox.conf, empty when no backup is written
+	BackupID string
+}
 
-	if backup {
-		existing, err := os.ReadFile(filePath)
-		if err == nil {
-			if err := os.WriteFile(filePath+".bak", existing, 0644); err != nil {
-				return fmt.Errorf("failed to write backup file: %w", err)
+// RegenerateCogFile stages a cog's file rendered from its config definition, carrying the hand written code in
+// the protected regions of the current file over, and staging a backup of the current file and botbox.conf when backup is true.
+// The new file is rendered from config, while previous still holds the cog's previous definition and the bot info the
+// current file was generated from, so regions that only hold its generated code follow the new one.
+// Damaged region markers, and hand written code the new definition has no region for, fail before anything is staged
+func RegenerateCogFile(changes *ChangeSet, rootDir string, previous Config, config Config, cog CogConfig, backup bool) (RegenerateResult, error) {
+	var result RegenerateResult
+	generator, err := GeneratorFor(config.BotInfo.Library)
+	if err != nil {
+		return result, err
+	}
+	filePath := filepath.Join(rootDir, generator.CogsDir(), generator.CogFileName(cog.File))
+	result.Path = filePath
+
+	existing, err := os.ReadFile(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return result, fmt.Errorf("failed to read cog file: %w", err)
+	}
+
+	content, err := renderCogFile(config, cog)
+	if err != nil {
+		return result, err
+	}
+
+	if existing != nil {
+		previousContent := ""
+		for _, previousCog := range previous.Cogs {
+			if previousCog.File != cog.File {
+				continue
+			}
+			if previousContent, err = renderCogFile(previous, previousCog); err != nil {
+				return result, err
+			}
+			break
+		}
+
+		content, result.Preserved, err = mergeProtectedRegions(string(existing), previousContent, content, generator.RegionMarker())
+		if err != nil {
+			return result, fmt.Errorf("protected regions of %s cannot be carried over: %w", filePath, err)
+		}
+		result.Unprotected = !strings.Contains(string(existing), generator.RegionMarker())
+
+		if backup {
+			result.BackupID, err = StageBackup(changes, rootDir, "edit "+cog.Name, filePath, filepath.Join(rootDir, "botbox.conf"))
+			if err != nil {
+				return result, fmt.Errorf("failed to back up cog file: %w", err)
 			}
-		} else if !os.IsNotExist(err) {
-			return fmt.Errorf("failed to read cog file for backup: %w", err)
 		}
 	}
 
-	content, err := RenderTemplate("cog.py.tmpl", CogTemplateData{
-		Author:         config.BotInfo.Author,
-		BotName:        config.BotInfo.Name,
-		BotDescription: config.BotInfo.Description,
-		ClassName:      cog.Name,
-		Filename:       cog.File,
-		SlashCommands:  cog.SlashCommands,
-		PrefixCommands: cog.PrefixCommands,
-	})
+	if err := changes.WriteFile(filePath, []byte(content)); err != nil {
+		return result, fmt.Errorf("failed to write cog file: %w", err)
+	}
+
+	return result, nil
+}
+
+// renderCogFile renders a cog's .py file from its config definition with the generator of the project's library
+func renderCogFile(config Config, cog CogConfig) (string, error) {
+	generator, err := GeneratorFor(config.BotInfo.Library)
 	if err != nil {
-		return fmt.Errorf("failed to render cog template: %w", err)
+		return "", err
 	}
+	return generator.RenderCog(config.BotInfo, cog)
+}
+
+// commandExists reports whether a command with the full path, like ping or ticket list, is in the list
+func commandExists(path string, commandList []CommandInfo) bool {
+	return slices.ContainsFunc(commandList, func(cmd CommandInfo) bool {
+		return CommandPath(cmd) == path
+	})
+}
 
-	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
-		return fmt.Errorf("failed to write cog file: %w", err)
+// RemoveCommand removes the command at a full path like ping or ticket list, a prefix group command takes its subcommands with it
+func RemoveCommand(commands []CommandInfo, path string) ([]CommandInfo, bool) {
+	index := slices.IndexFunc(commands, func(cmd CommandInfo) bool {
+		return CommandPath(cmd) == path
+	})
+	if index == -1 {
+		return commands, false
 	}
 
-	return nil
+	removed := commands[index]
+	commands = slices.Delete(slices.Clone(commands), index, index+1)
+	if removed.Type != "prefix" {
+		return commands, true
+	}
+	return slices.DeleteFunc(commands, func(cmd CommandInfo) bool {
+		return cmd.Type == "prefix" && (cmd.Group == path || strings.HasPrefix(cmd.Group, path+" "))
+	}), true
 }
 
-func commandExists(commandName string, commandList []CommandInfo) bool {
-	for _, cmd := range commandList {
-		if cmd.Name == commandName {
-			return true
+// renamePrefixGroup moves the subcommands of a prefix group command whose path changed from oldPath to newPath
+func renamePrefixGroup(commands []CommandInfo, oldPath, newPath string) []CommandInfo {
+	if oldPath == newPath {
+		return commands
+	}
+	renamed := slices.Clone(commands)
+	for i, cmd := range renamed {
+		if cmd.Type != "prefix" {
+			continue
+		}
+		if cmd.Group == oldPath {
+			renamed[i].Group = newPath
+		} else if rest, found := strings.CutPrefix(cmd.Group, oldPath+" "); found {
+			renamed[i].Group = newPath + " " + rest
 		}
 	}
-	return false
+	return renamed
 }
 
 func argExists(argName string, args []ArgInfo) bool {
@@ -364,6 +458,9 @@ func GetLocalConfigValue(key string) (any, error) {
 	case "bot.help_style":
 		// Projects created before this key existed report the default
 		return NormalizeHelpStyle(config.BotInfo.HelpStyle), nil
+	case "bot.library":
+		// Projects created before this key existed were generated for discord.py
+		return NormalizeLibrary(config.BotInfo.Library), nil
 	case "bot.env_provider":
 		// Projects created before this key existed report the detected provider
 		rootDir, err := FindBotConf()
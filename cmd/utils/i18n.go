@@ -0,0 +1,293 @@
+/*
+Copyright © 2025 Austin "Choice404" Choi
+See end of file for extended copyright information
+*/
+
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// localeEntry is one translatable string of a cog, Source is the text the cog falls back to and
+// Translations points at the config map holding its translations, nil for group strings the config does not keep
+type localeEntry struct {
+	Key          string
+	Source       string
+	Translations *map[string]string
+}
+
+// localeKeyPrefix builds the key prefix of a command or group path, the generated translator builds the same keys
+func localeKeyPrefix(path string) string {
+	return "commands." + strings.ReplaceAll(path, " ", ".")
+}
+
+// localeKey is the key prefix of a command's strings, modal labels are looked up under it when the modal is sent
+func localeKey(cmd CommandInfo) string {
+	return localeKeyPrefix(CommandPath(cmd))
+}
+
+// localeEntries lists every translatable string of a cog's app commands, the entries point into commands
+func localeEntries(commands []CommandInfo) []localeEntry {
+	var entries []localeEntry
+
+	for _, group := range slashGroups(commands) {
+		prefix := localeKeyPrefix(group.Path)
+		entries = append(entries,
+			localeEntry{Key: prefix + ".name", Source: group.Name},
+			localeEntry{Key: prefix + ".description", Source: group.Description},
+		)
+	}
+
+	for i := range commands {
+		cmd := &commands[i]
+		// Prefix commands are matched by the text users type, Discord never translates them
+		if cmd.Type == "prefix" {
+			continue
+		}
+		prefix := localeKey(*cmd)
+		entries = append(entries, localeEntry{Key: prefix + ".name", Source: cmd.Name, Translations: &cmd.NameLocalizations})
+		if !IsContextMenuType(cmd.Type) {
+			entries = append(entries, localeEntry{Key: prefix + ".description", Source: cmd.Description, Translations: &cmd.DescriptionLocalizations})
+		}
+		for j := range cmd.Args {
+			arg := &cmd.Args[j]
+			argPrefix := prefix + ".params." + arg.Name
+			entries = append(entries,
+				localeEntry{Key: argPrefix + ".name", Source: arg.Name, Translations: &arg.NameLocalizations},
+				localeEntry{Key: argPrefix + ".description", Source: arg.Description, Translations: &arg.DescriptionLocalizations},
+			)
+		}
+		for j := range cmd.Fields {
+			field := &cmd.Fields[j]
+			entries = append(entries, localeEntry{Key: prefix + ".fields." + field.Name + ".label", Source: field.Label, Translations: &field.LabelLocalizations})
+		}
+		for p := range cmd.Pages {
+			page := &cmd.Pages[p]
+			for j := range page.Fields {
+				field := &page.Fields[j]
+				key := prefix + ".pages." + page.Name + ".fields." + field.Name + ".label"
+				entries = append(entries, localeEntry{Key: key, Source: field.Label, Translations: &field.LabelLocalizations})
+			}
+		}
+	}
+
+	return entries
+}
+
+// dropUntranslatedLocalizations clears translations a command type cannot use,
+// prefix commands are never translated and context menus have no description
+func dropUntranslatedLocalizations(cmd *CommandInfo) {
+	if cmd.Type == "prefix" {
+		cmd.NameLocalizations = nil
+		cmd.DescriptionLocalizations = nil
+		for i := range cmd.Args {
+			cmd.Args[i].NameLocalizations = nil
+			cmd.Args[i].DescriptionLocalizations = nil
+		}
+	}
+	if IsContextMenuType(cmd.Type) {
+		cmd.DescriptionLocalizations = nil
+	}
+}
+
+// loadLocaleFiles reads the locale files in a directory into a map of locale to translation key to text,
+// files not named after a Discord locale are left alone
+func loadLocaleFiles(dir string) (map[string]map[string]string, error) {
+	translations := map[string]map[string]string{}
+
+	files, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return translations, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read locales directory: %w", err)
+	}
+
+	for _, file := range files {
+		locale, isJSON := strings.CutSuffix(file.Name(), ".json")
+		if file.IsDir() || !isJSON || ValidateLocale(locale) != nil {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read locale file %s: %w", file.Name(), err)
+		}
+		texts := map[string]string{}
+		if err := json.Unmarshal(data, &texts); err != nil {
+			return nil, fmt.Errorf("failed to parse locale file %s: %w", file.Name(), err)
+		}
+		translations[locale] = texts
+	}
+
+	return translations, nil
+}
+
+// UpdateLocaleFiles stages every translation key missing from the locale files, filled from the config's
+// localizations or left empty for a translator to fill in, overwrite also lets the config's localizations
+// replace text already in the files. Files are staged for the given locales, the locale files that
+// already exist, and every locale the config translates into. It returns how many keys each of those locales gained or changed
+func UpdateLocaleFiles(changes *ChangeSet, rootDir string, config Config, locales []string, overwrite bool) (map[string]int, error) {
+	generator, err := GeneratorFor(config.BotInfo.Library)
+	if err != nil {
+		return nil, err
+	}
+	translations, err := loadLocaleFiles(filepath.Join(rootDir, generator.LocalesDir()))
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []localeEntry
+	for _, cog := range config.Cogs {
+		entries = append(entries, localeEntries(cog.SlashCommands)...)
+	}
+
+	for _, locale := range locales {
+		if err := ValidateLocale(locale); err != nil {
+			return nil, err
+		}
+		if translations[locale] == nil {
+			translations[locale] = map[string]string{}
+		}
+	}
+	for _, entry := range entries {
+		if entry.Translations == nil {
+			continue
+		}
+		for locale := range *entry.Translations {
+			if translations[locale] == nil {
+				translations[locale] = map[string]string{}
+			}
+		}
+	}
+
+	changed := map[string]int{}
+	for _, locale := range slices.Sorted(maps.Keys(translations)) {
+		texts := translations[locale]
+		changed[locale] = 0
+		for _, entry := range entries {
+			text := ""
+			if entry.Translations != nil {
+				text = (*entry.Translations)[locale]
+			}
+			current, exists := texts[entry.Key]
+			if exists && (!overwrite || text == "" || text == current) {
+				continue
+			}
+			texts[entry.Key] = text
+			changed[locale]++
+		}
+		if changed[locale] == 0 {
+			continue
+		}
+
+		// Translations are shown to translators as written, so characters like < and & stay unescaped
+		var jsonData bytes.Buffer
+		encoder := json.NewEncoder(&jsonData)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(texts); err != nil {
+			return nil, fmt.Errorf("failed to marshal locale %s: %w", locale, err)
+		}
+		if err := changes.WriteFile(LocaleFilePath(generator, rootDir, locale), jsonData.Bytes()); err != nil {
+			return nil, fmt.Errorf("failed to write locale file: %w", err)
+		}
+	}
+
+	return changed, nil
+}
+
+// LocaleFilePath is where the translations for a locale are kept, in the generator's locales directory
+func LocaleFilePath(generator Generator, rootDir string, locale string) string {
+	return filepath.Join(rootDir, generator.LocalesDir(), locale+".json")
+}
+
+// applyLocaleFiles reads the translations in the locale files of a directory back onto the parsed cogs' commands
+func applyLocaleFiles(localesDir string, parsedCogs []ParsedCogInfo) error {
+	translations, err := loadLocaleFiles(localesDir)
+	if err != nil {
+		return err
+	}
+
+	for i := range parsedCogs {
+		for _, entry := range localeEntries(parsedCogs[i].SlashCommands) {
+			if entry.Translations == nil {
+				continue
+			}
+			for locale, texts := range translations {
+				// Empty keys are placeholders written by the extract command
+				if texts[entry.Key] == "" {
+					continue
+				}
+				if *entry.Translations == nil {
+					*entry.Translations = map[string]string{}
+				}
+				(*entry.Translations)[locale] = texts[entry.Key]
+			}
+		}
+	}
+
+	return nil
+}
+
+// WriteTranslator writes the generator's translator file into projects created before it existed,
+// it returns the path it wrote, empty when the project already has one
+func WriteTranslator(rootDir string, config Config) (string, error) {
+	generator, err := GeneratorFor(config.BotInfo.Library)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(rootDir, generator.TranslatorFile())
+	if _, err := os.Stat(path); err == nil {
+		return "", nil
+	}
+	data := projectTemplateData{
+		Name:        config.BotInfo.Name,
+		Author:      config.BotInfo.Author,
+		Description: config.BotInfo.Description,
+	}
+	if err := renderToFile(path, "translator.py.tmpl", data); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return path, nil
+}
+
+/*
+Copyright © 2025 Austin "Choice404" Choi
+
+https://github.com/choice404/botbox
+
+Bot Box
+A discord bot template generator to help create discord bots quickly and easily
+
+This code is licensed under the MIT License.
+
+MIT License
+
+Copyright (c) 2025 Austin Choi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
@@ -0,0 +1,556 @@
+/*
+Copyright © 2025 Austin "Choice404" Choi
+See end of file for extended copyright information
+*/
+
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Values of bot.library, the Discord library a project's generated code is written for
+const (
+	LibraryDiscordPy = "discord.py"
+	LibraryPycord    = "py-cord"
+	LibraryNextcord  = "nextcord"
+	LibraryDisnake   = "disnake"
+)
+
+// DefaultLibrary is used when a project predates the library key or leaves it unset
+const DefaultLibrary = LibraryDiscordPy
+
+// discordPyTemplates import discord.py, another library uses the version in its own template set
+// and cannot use the template at all when its set has none
+var discordPyTemplates = []string{
+	"main.py.tmpl", "cog.py.tmpl", "cogs.py.tmpl", "help.py.tmpl", "admin.py.tmpl", "helloworld.py.tmpl",
+	"moderation.py.tmpl", "utility.py.tmpl", "translator.py.tmpl",
+}
+
+// libraryDialect is how one library spells the app command code the generator writes and the cog parser reads back
+type libraryDialect struct {
+	// Module is the package generated code imports, py-cord installs under discord like discord.py does
+	Module string
+	// Decorator registers a slash command, Interaction names the first parameter of its callback
+	Decorator   string
+	Interaction string
+	// Option wraps an argument's description, choices, and bounds in its default. Choice builds one choice,
+	// choices are written as a dict of name to value when it is empty. MinKeyword and MaxKeyword bound numbers
+	Option     string
+	Choice     string
+	MinKeyword string
+	MaxKeyword string
+	// Required is set when an optional argument is marked required=False as well as given a default
+	Required bool
+	// GuildOnly is the decorator keyword, with its value, that keeps a command out of direct messages
+	GuildOnly string
+	// Reply, User, and Client are attributes of the interaction parameter responses are sent with,
+	// Defer is the deferring call formatted with the ephemeral flag
+	Reply  string
+	Defer  string
+	User   string
+	Client string
+	// PrefixEphemeral is set when ctx.send takes an ephemeral flag, the other libraries' prefix replies are always public
+	PrefixEphemeral bool
+}
+
+// libraryDialects holds the dialect of each library, discord.py's decorators are read by the rest of the cog parser
+var libraryDialects = map[string]libraryDialect{
+	LibraryDiscordPy: {
+		Module:          "discord",
+		Decorator:       "app_commands.command",
+		Interaction:     "interaction",
+		Reply:           "response.send_message",
+		Defer:           "response.defer(ephemeral=%s, thinking=True)",
+		User:            "user",
+		Client:          "client",
+		PrefixEphemeral: true,
+	},
+	LibraryPycord: {
+		Module:      "discord",
+		Decorator:   "discord.slash_command",
+		Interaction: "ctx",
+		Option:      "discord.Option",
+		Choice:      "discord.OptionChoice",
+		MinKeyword:  "min_value",
+		MaxKeyword:  "max_value",
+		Required:    true,
+		GuildOnly:   "guild_only=True",
+		Reply:       "respond",
+		Defer:       "defer(ephemeral=%s)",
+		User:        "author",
+		Client:      "bot",
+	},
+	LibraryNextcord: {
+		Module:      "nextcord",
+		Decorator:   "nextcord.slash_command",
+		Interaction: "interaction",
+		Option:      "nextcord.SlashOption",
+		MinKeyword:  "min_value",
+		MaxKeyword:  "max_value",
+		Required:    true,
+		GuildOnly:   "dm_permission=False",
+		Reply:       "response.send_message",
+		Defer:       "response.defer(ephemeral=%s, with_message=True)",
+		User:        "user",
+		Client:      "client",
+	},
+	LibraryDisnake: {
+		Module:      "disnake",
+		Decorator:   "commands.slash_command",
+		Interaction: "inter",
+		Option:      "commands.Param",
+		Choice:      "disnake.OptionChoice",
+		MinKeyword:  "ge",
+		MaxKeyword:  "le",
+		GuildOnly:   "dm_permission=False",
+		Reply:       "response.send_message",
+		Defer:       "response.defer(ephemeral=%s, with_message=True)",
+		User:        "author",
+		Client:      "bot",
+	},
+}
+
+// dialectFor is the dialect of a bot.library value, projects without one are discord.py projects
+func dialectFor(library string) libraryDialect {
+	return libraryDialects[NormalizeLibrary(library)]
+}
+
+/**
+ * LibraryTemplate
+ * Names the embedded template that renders a template for a library, the library's own set replaces
+ * the templates written for discord.py
+ * @param library {string} - the bot.library value
+ * @param name {string} - the discord.py template, like cog.py.tmpl
+ * @return string - the template to render, like nextcord/cog.py.tmpl
+ * @return error - a discord.py template the library's set has no version of
+ **/
+func LibraryTemplate(library string, name string) (string, error) {
+	library = NormalizeLibrary(library)
+	if library == LibraryDiscordPy || !slices.Contains(discordPyTemplates, name) {
+		return name, nil
+	}
+	if isEmbeddedTemplate(library + "/" + name) {
+		return library + "/" + name, nil
+	}
+	return "", fmt.Errorf("%s is only written for discord.py, there is no %s version of it", name, library)
+}
+
+// sourceType spells an argument type the config writes with discord, like discord.Member, in the library's module
+func (d libraryDialect) sourceType(configType string) string {
+	return strings.ReplaceAll(configType, "discord.", d.Module+".")
+}
+
+// restoreArgTypes spells the parsed argument types of a command with discord again, the way the config writes them
+func (d libraryDialect) restoreArgTypes(cmd *CommandInfo) {
+	for i := range cmd.Args {
+		cmd.Args[i].Type = strings.ReplaceAll(cmd.Args[i].Type, d.Module+".", "discord.")
+	}
+}
+
+// slashDecorator renders the decorator registering a slash command, the scope, permissions,
+// and direct message setting are keywords of the decorator rather than decorators of their own
+func (d libraryDialect) slashDecorator(cmd CommandInfo) string {
+	keywords := []string{fmt.Sprintf(`name="%s"`, cmd.Name), fmt.Sprintf(`description="%s"`, cmd.Description)}
+	if cmd.Scope == "guild" {
+		keywords = append(keywords, "guild_ids=GUILD_IDS")
+	}
+	if len(cmd.Permissions) > 0 {
+		keywords = append(keywords, fmt.Sprintf("default_member_permissions=%s.Permissions(%s)", d.Module, flagArgs(cmd.Permissions, nil)))
+	}
+	if cmd.GuildOnly {
+		keywords = append(keywords, d.GuildOnly)
+	}
+	return fmt.Sprintf("%s(%s)", d.Decorator, strings.Join(keywords, ", "))
+}
+
+// optionParams renders the arguments of a slash command callback, each defaulting to the library's Option call
+// that carries its description, choices, bounds, and default
+func (d libraryDialect) optionParams(args []ArgInfo) string {
+	params := make([]string, len(args))
+	for i, arg := range args {
+		keywords := []string{fmt.Sprintf(`description="%s"`, arg.Description)}
+		if len(arg.Choices) > 0 {
+			keywords = append(keywords, "choices="+d.optionChoices(arg))
+		}
+		// Bounds on a string limit its length
+		low, high := d.MinKeyword, d.MaxKeyword
+		if arg.Type == "str" {
+			low, high = "min_length", "max_length"
+		}
+		if arg.Min != "" {
+			keywords = append(keywords, low+"="+arg.Min)
+		}
+		if arg.Max != "" {
+			keywords = append(keywords, high+"="+arg.Max)
+		}
+		if arg.Optional {
+			if d.Required {
+				keywords = append(keywords, "required=False")
+			}
+			keywords = append(keywords, "default="+argDefault(arg))
+		}
+		params[i] = fmt.Sprintf("%s: %s = %s(%s)", arg.Name, d.sourceType(arg.Type), d.Option, strings.Join(keywords, ", "))
+	}
+	return strings.Join(params, ", ")
+}
+
+// optionChoices renders the fixed choices of an argument as a list of Choice calls, or a dict when the library takes one
+func (d libraryDialect) optionChoices(arg ArgInfo) string {
+	items := make([]string, len(arg.Choices))
+	for i, choice := range arg.Choices {
+		if d.Choice == "" {
+			items[i] = fmt.Sprintf(`"%s": %s`, choice.Name, choiceValue(arg, choice))
+		} else {
+			items[i] = fmt.Sprintf(`%s(name="%s", value=%s)`, d.Choice, choice.Name, choiceValue(arg, choice))
+		}
+	}
+	if d.Choice == "" {
+		return "{" + strings.Join(items, ", ") + "}"
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+// prefixParams renders the arguments of a prefix command callback with the library's types
+func (d libraryDialect) prefixParams(args []ArgInfo) string {
+	params := slices.Clone(args)
+	for i := range params {
+		params[i].Type = d.sourceType(params[i].Type)
+	}
+	return BuildPrefixArgString(params)
+}
+
+// slashResponse renders the statements a slash command body uses to send its first response,
+// a component command also builds its view and attaches it to the reply
+func (d libraryDialect) slashResponse(cmd CommandInfo) []string {
+	response := firstResponse(cmd)
+	ephemeral := pythonBool(response.Ephemeral)
+	reply := fmt.Sprintf("await %s.%s", d.Interaction, d.Reply)
+
+	var lines []string
+	view := ""
+	if cmd.Type == "component" {
+		lines = append(lines, fmt.Sprintf("view = %sView(%s.%s.id)", pascalName(CommandPath(cmd)), d.Interaction, d.User))
+		view = ", view=view"
+	}
+
+	switch response.Type {
+	case "embed":
+		lines = append(lines, embedLines(response, d.Module)...)
+		lines = append(lines, fmt.Sprintf("%s(embed=embed, ephemeral=%s%s)", reply, ephemeral, view))
+	case "dm":
+		lines = append(lines,
+			fmt.Sprintf(`await %s.%s.send(f"%s")`, d.Interaction, d.User, response.Content),
+			fmt.Sprintf(`%s("Sent you a direct message", ephemeral=True)`, reply))
+	case "channel":
+		client := d.Interaction + "." + d.Client
+		lines = append(lines,
+			fmt.Sprintf("channel = %s.get_channel(%s) or await %s.fetch_channel(%s)", client, response.ChannelID, client, response.ChannelID),
+			fmt.Sprintf(`await channel.send(f"%s")`, response.Content),
+			fmt.Sprintf(`%s("Posted in <#%s>", ephemeral=True)`, reply, response.ChannelID))
+	case "defer":
+		// Deferring shows the bot as thinking so the handler has up to 15 minutes before the follow up
+		lines = append(lines,
+			fmt.Sprintf("await %s.%s", d.Interaction, fmt.Sprintf(d.Defer, ephemeral)),
+			fmt.Sprintf(`await %s.followup.send(f"%s", ephemeral=%s)`, d.Interaction, response.Content, ephemeral))
+	default:
+		lines = append(lines, fmt.Sprintf(`%s(f"%s", ephemeral=%s%s)`, reply, response.Content, ephemeral, view))
+	}
+
+	if cmd.Type == "component" {
+		lines = append(lines, fmt.Sprintf("view.message = await %s.original_response()", d.Interaction))
+	}
+	return lines
+}
+
+// prefixResponse renders the statements a prefix or hybrid command body uses to send its first response,
+// a hybrid command invoked as a slash command must answer its interaction so it confirms direct messages and channel posts
+func (d libraryDialect) prefixResponse(cmd CommandInfo) []string {
+	response := firstResponse(cmd)
+	ephemeral := ""
+	if d.PrefixEphemeral {
+		ephemeral = ", ephemeral=" + pythonBool(response.Ephemeral)
+	}
+
+	switch response.Type {
+	case "embed":
+		return append(embedLines(response, d.Module), fmt.Sprintf("await ctx.send(embed=embed%s)", ephemeral))
+	case "dm":
+		lines := []string{fmt.Sprintf(`await ctx.author.send(f"%s")`, response.Content)}
+		if cmd.Type == "hybrid" {
+			lines = append(lines, `await ctx.send("Sent you a direct message", ephemeral=True)`)
+		}
+		return lines
+	case "channel":
+		lines := []string{
+			fmt.Sprintf("channel = self.bot.get_channel(%s) or await self.bot.fetch_channel(%s)", response.ChannelID, response.ChannelID),
+			fmt.Sprintf(`await channel.send(f"%s")`, response.Content),
+		}
+		if cmd.Type == "hybrid" {
+			lines = append(lines, fmt.Sprintf(`await ctx.send("Posted in <#%s>", ephemeral=True)`, response.ChannelID))
+		}
+		return lines
+	case "defer":
+		// A prefix context has no interaction to defer, so the typing indicator stands in while the handler works
+		return []string{
+			"async with ctx.typing():",
+			fmt.Sprintf(`    await ctx.send(f"%s"%s)`, response.Content, ephemeral),
+		}
+	default:
+		return []string{fmt.Sprintf(`await ctx.send(f"%s"%s)`, response.Content, ephemeral)}
+	}
+}
+
+// slashSyntax is the response syntax of the dialect's slash command bodies
+func (d libraryDialect) slashSyntax() responseSyntax {
+	interaction := `await ` + regexp.QuoteMeta(d.Interaction) + `\.`
+	reply := interaction + regexp.QuoteMeta(d.Reply) + `\(`
+	deferCall := strings.ReplaceAll(regexp.QuoteMeta(d.Defer), "%s", "(True|False)")
+	return responseSyntax{
+		reply:      regexp.MustCompile(reply + responseText + `\s*,\s*ephemeral\s*=\s*(True|False)\s*(?:,\s*view\s*=\s*view\s*)?\)`),
+		embedReply: regexp.MustCompile(`^` + reply + `embed=embed, ephemeral=(True|False)(?:, view=view)?\)$`),
+		deferReply: regexp.MustCompile(`^` + interaction + deferCall + `$`),
+		followUp:   regexp.MustCompile(`^` + interaction + `followup\.send\(` + responseText + `, ephemeral=(True|False)\)$`),
+		dm:         regexp.MustCompile(`^` + interaction + regexp.QuoteMeta(d.User) + `\.send\(` + responseText + `\)$`),
+		ephemeral:  true,
+	}
+}
+
+// prefixSyntax is the response syntax of the dialect's prefix command bodies
+func (d libraryDialect) prefixSyntax() responseSyntax {
+	if d.PrefixEphemeral {
+		return prefixResponseSyntax
+	}
+	reply := regexp.MustCompile(`await ctx\.send\(` + responseText + `\s*\)`)
+	return responseSyntax{
+		reply:      reply,
+		embedReply: regexp.MustCompile(`^await ctx\.send\(embed=embed\)$`),
+		deferReply: prefixResponseSyntax.deferReply,
+		followUp:   reply,
+		dm:         prefixResponseSyntax.dm,
+	}
+}
+
+// libraryDecorator is the template helper rendering a slash command's decorator for a library
+func libraryDecorator(library string, cmd CommandInfo) string {
+	return dialectFor(library).slashDecorator(cmd)
+}
+
+// libraryParams is the template helper rendering a slash command's arguments for a library
+func libraryParams(library string, args []ArgInfo) string {
+	return dialectFor(library).optionParams(args)
+}
+
+// libraryPrefixParams is the template helper rendering a prefix command's arguments for a library
+func libraryPrefixParams(library string, args []ArgInfo) string {
+	return dialectFor(library).prefixParams(args)
+}
+
+// librarySlashResponse is the template helper rendering a slash command's first response for a library
+func librarySlashResponse(library string, cmd CommandInfo) []string {
+	return dialectFor(library).slashResponse(cmd)
+}
+
+// libraryPrefixResponse is the template helper rendering a prefix command's first response for a library
+func libraryPrefixResponse(library string, cmd CommandInfo) []string {
+	return dialectFor(library).prefixResponse(cmd)
+}
+
+// libraryListenerParams is the template helper rendering a listener's parameters with a library's types
+func libraryListenerParams(library string, listener ListenerInfo) string {
+	return dialectFor(library).sourceType(listenerParams(listener))
+}
+
+// ValidateLibraryCog checks a cog only uses what Bot Box generates for the project's library,
+// every library but discord.py is limited to slash and prefix commands, listeners, and tasks
+func ValidateLibraryCog(library string, cog CogConfig) error {
+	library = NormalizeLibrary(library)
+	if library == LibraryDiscordPy {
+		return nil
+	}
+	for _, cmd := range slices.Concat(cog.SlashCommands, cog.PrefixCommands) {
+		if err := validateLibraryCommand(library, cmd); err != nil {
+			return fmt.Errorf("cog %s: %w", cog.Name, err)
+		}
+	}
+	return nil
+}
+
+// validateLibraryCommand checks a command of a project whose library is not discord.py
+func validateLibraryCommand(library string, cmd CommandInfo) error {
+	feature := ""
+	switch {
+	case cmd.Type != "slash" && cmd.Type != "prefix":
+		feature = cmd.Type + " commands"
+	case cmd.Type == "slash" && cmd.Group != "":
+		feature = "slash command groups"
+	case cmd.Type == "slash" && len(cmd.Roles) > 0:
+		feature = "role checks on slash commands"
+	case cmd.Type == "slash" && cmd.Cooldown != nil:
+		feature = "cooldowns on slash commands"
+	case len(cmd.AllowedInstalls) > 0 || len(cmd.AllowedContexts) > 0:
+		feature = "allowed installs and contexts"
+	case len(cmd.NameLocalizations) > 0 || len(cmd.DescriptionLocalizations) > 0:
+		feature = "localizations"
+	}
+	for _, arg := range cmd.Args {
+		if feature != "" {
+			break
+		}
+		switch {
+		case arg.Autocomplete:
+			feature = "autocomplete"
+		case strings.HasPrefix(arg.Type, "app_commands.Transform["):
+			feature = "transform arguments"
+		case cmd.Type == "prefix" && (arg.Min != "" || arg.Max != ""):
+			feature = "bounded prefix arguments"
+		case len(arg.NameLocalizations) > 0 || len(arg.DescriptionLocalizations) > 0:
+			feature = "localizations"
+		}
+	}
+	if feature != "" {
+		return fmt.Errorf("command %s uses %s, which is only generated for discord.py and not %s", CommandPath(cmd), feature, library)
+	}
+
+	if cmd.Type == "prefix" && slices.ContainsFunc(cmd.Responses, func(response ResponseInfo) bool { return response.Ephemeral }) {
+		return fmt.Errorf("command %s has an ephemeral response, %s prefix commands always reply where everyone can see", CommandPath(cmd), library)
+	}
+	return nil
+}
+
+// isLibrarySlashDecorator reports whether a decorator registers a slash command in the dialect,
+// the decorator may also be imported on its own and written as slash_command
+func isLibrarySlashDecorator(decorator pyExpr, d libraryDialect) bool {
+	callee := decorator.Callee()
+	return d.Option != "" && (callee == d.Decorator || callee == "slash_command")
+}
+
+// parseLibrarySlashCommand reads a slash command written for py-cord, nextcord, or disnake back into a command
+func parseLibrarySlashCommand(parsed *ParsedCogInfo, fn *pyFunc, decorator pyExpr, d libraryDialect, syntax responseSyntax) *CommandInfo {
+	name, description, ok := readCommandIdentity(parsed, fn, decorator)
+	if !ok {
+		return nil
+	}
+
+	cmd := &CommandInfo{
+		Type:        "slash",
+		Scope:       "global",
+		Name:        name,
+		Description: description,
+	}
+
+	// The generator writes the project's guild list, any list registers the command to guilds
+	if _, ok := decorator.Keyword("guild_ids"); ok {
+		cmd.Scope = "guild"
+	}
+	if permissions, ok := decorator.Keyword("default_member_permissions"); ok && permissions.Callee() == d.Module+".Permissions" {
+		cmd.Permissions = parseFlagArgs(permissions, nil)
+	}
+	keyword, value, _ := strings.Cut(d.GuildOnly, "=")
+	if guildOnly, ok := decorator.Keyword(keyword); ok && guildOnly.Source == value {
+		cmd.GuildOnly = true
+	}
+
+	parseCommandFunction(parsed, fn, cmd)
+	d.restoreArgTypes(cmd)
+	parseOptionDefaults(fn, cmd, d)
+	parseCommandDocstring(fn, cmd)
+	parseCommandResponse(fn, cmd, syntax)
+
+	return cmd
+}
+
+// parseOptionDefaults reads each argument's description, choices, bounds, and default out of the Option call it
+// defaults to. Such an argument is optional when the call has a default, unless it also says required=True
+func parseOptionDefaults(fn *pyFunc, cmd *CommandInfo, d libraryDialect) {
+	for _, param := range fn.Params {
+		arg := commandArg(cmd, param.Name)
+		if arg == nil || param.Default == nil || param.Default.Callee() != d.Option {
+			continue
+		}
+		option := *param.Default
+
+		arg.Optional, arg.Default = false, ""
+		if value, ok := option.Keyword("default"); ok {
+			arg.Optional = true
+			arg.Default = value.literal()
+		}
+		if required, ok := option.Keyword("required"); ok {
+			arg.Optional = required.Source == "False"
+		}
+		if description, ok := option.stringKeyword("description"); ok {
+			arg.Description = description
+		}
+		for _, keyword := range []string{d.MinKeyword, "min_length"} {
+			if bound, ok := option.Keyword(keyword); ok {
+				arg.Min = bound.Source
+			}
+		}
+		for _, keyword := range []string{d.MaxKeyword, "max_length"} {
+			if bound, ok := option.Keyword(keyword); ok {
+				arg.Max = bound.Source
+			}
+		}
+		if choices, ok := option.Keyword("choices"); ok {
+			arg.Choices = parseOptionChoices(choices, d)
+		}
+	}
+}
+
+// parseOptionChoices reads the choices of an Option call, written as Choice calls or as a dict of name to value
+func parseOptionChoices(choices pyExpr, d libraryDialect) []ChoiceInfo {
+	var parsed []ChoiceInfo
+	switch choices.Kind {
+	case pyExprDict:
+		for i := 0; i+1 < len(choices.Args); i += 2 {
+			if choices.Args[i].Kind == pyExprString {
+				parsed = append(parsed, ChoiceInfo{Name: choices.Args[i].Text, Value: choices.Args[i+1].literal()})
+			}
+		}
+	case pyExprList:
+		for _, choice := range choices.Args {
+			name, hasName := choice.stringKeyword("name")
+			value, hasValue := choice.Keyword("value")
+			if choice.Callee() == d.Choice && hasName && hasValue {
+				parsed = append(parsed, ChoiceInfo{Name: name, Value: value.literal()})
+			}
+		}
+	}
+	return parsed
+}
+
+/*
+Copyright © 2025 Austin "Choice404" Choi
+
+https://github.com/choice404/botbox
+
+Bot Box
+A discord bot template generator to help create discord bots quickly and easily
+
+This code is licensed under the MIT License.
+
+MIT License
+
+Copyright (c) 2025 Austin Choi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
@@ -0,0 +1,288 @@
+/*
+Copyright © 2025 Austin "Choice404" Choi
+See end of file for extended copyright information
+*/
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// libraryTestCog uses everything the library template sets generate
+func libraryTestCog() CogConfig {
+	return CogConfig{
+		Name: "DiceCog",
+		File: "diceCog",
+		SlashCommands: []CommandInfo{
+			{
+				Name:        "roll",
+				Scope:       "guild",
+				Type:        "slash",
+				Description: "Rolls a die",
+				ReturnType:  "None",
+				Args: []ArgInfo{
+					{Name: "sides", Type: "int", Description: "Sides on the die", Optional: true, Default: "6", Min: "2", Max: "100"},
+					{Name: "mode", Type: "str", Description: "How to roll", Choices: []ChoiceInfo{{Name: "Fast", Value: "fast"}, {Name: "Slow", Value: "slow"}}},
+					{Name: "label", Type: "str", Description: "Text shown with the roll", Optional: true, Max: "20"},
+					{Name: "target", Type: "discord.Member", Description: "Who the roll is for"},
+				},
+				Permissions: []string{"manage_messages"},
+				GuildOnly:   true,
+				Responses:   []ResponseInfo{{Type: "message", Content: "Rolled a d{sides}", Ephemeral: true}},
+			},
+			{
+				Name:        "stats",
+				Scope:       "global",
+				Type:        "slash",
+				Description: "Shows roll stats",
+				ReturnType:  "None",
+				Responses:   []ResponseInfo{{Type: "embed", Content: "No rolls yet", Embed: &EmbedInfo{Title: "Stats", Color: "#5865F2"}}},
+			},
+			{
+				Name:        "later",
+				Scope:       "global",
+				Type:        "slash",
+				Description: "Rolls after thinking",
+				ReturnType:  "None",
+				Responses:   []ResponseInfo{{Type: "defer", Content: "Rolled", Ephemeral: true}},
+			},
+			{
+				Name:        "ping",
+				Scope:       "global",
+				Type:        "slash",
+				Description: "Replies with the default reply",
+				ReturnType:  "None",
+			},
+		},
+		PrefixCommands: []CommandInfo{
+			{
+				Name:        "echo",
+				Scope:       "global",
+				Type:        "prefix",
+				Description: "Repeats the text",
+				ReturnType:  "None",
+				Args:        []ArgInfo{{Name: "text", Type: "str", Description: "What to repeat"}},
+				Responses:   []ResponseInfo{{Type: "message", Content: "You said {text}"}},
+			},
+			{
+				Name:        "whisper",
+				Scope:       "global",
+				Type:        "prefix",
+				Description: "Whispers to the author",
+				ReturnType:  "None",
+				Args:        []ArgInfo{{Name: "member", Type: "discord.Member", Description: "Who is asking"}},
+				Responses:   []ResponseInfo{{Type: "dm", Content: "Psst"}},
+			},
+		},
+		Listeners: []ListenerInfo{{Event: "on_member_join", Action: "log"}},
+		Tasks:     []TaskInfo{{Name: "reminder", Interval: 5, Unit: "minutes", ChannelID: "123456789012345678", Content: "Roll!"}},
+	}
+}
+
+// TestLibraryCogTemplateParseRoundTrip renders a cog with each library's template set and checks the parser reads
+// back the same cog in that library's dialect
+func TestLibraryCogTemplateParseRoundTrip(t *testing.T) {
+	for _, library := range []string{LibraryPycord, LibraryNextcord, LibraryDisnake} {
+		t.Run(library, func(t *testing.T) {
+			cog := libraryTestCog()
+			config := Config{BotInfo: BotConfig{Name: "DiceBot", Author: "tester", Description: "Rolls dice", Library: library}}
+			content, err := renderCogFile(config, cog)
+			if err != nil {
+				t.Fatalf("renderCogFile returned error: %v", err)
+			}
+			if strings.Contains(content, "app_commands") {
+				t.Errorf("%s cog uses discord.py's app_commands:\n%s", library, content)
+			}
+
+			parsed, err := parseCogSource(content, cog.File, library)
+			if err != nil {
+				t.Fatalf("parseCogSource returned error: %v", err)
+			}
+			if len(parsed.Diagnostics) > 0 {
+				t.Errorf("parser reported diagnostics: %+v", parsed.Diagnostics)
+			}
+			if !commandsEqual(parsed.SlashCommands, cog.SlashCommands) {
+				t.Errorf("round trip changed the slash commands\ngot:  %+v\nwant: %+v", parsed.SlashCommands, cog.SlashCommands)
+			}
+			if !commandsEqual(parsed.PrefixCommands, cog.PrefixCommands) {
+				t.Errorf("round trip changed the prefix commands\ngot:  %+v\nwant: %+v", parsed.PrefixCommands, cog.PrefixCommands)
+			}
+			if !slices.Equal(parsed.Listeners, cog.Listeners) {
+				t.Errorf("round trip changed the listeners\ngot:  %+v\nwant: %+v", parsed.Listeners, cog.Listeners)
+			}
+			if !slices.EqualFunc(parsed.Tasks, cog.Tasks, taskEqual) {
+				t.Errorf("round trip changed the tasks\ngot:  %+v\nwant: %+v", parsed.Tasks, cog.Tasks)
+			}
+		})
+	}
+}
+
+func TestValidateLibraryCog(t *testing.T) {
+	tests := []struct {
+		name    string
+		library string
+		cmd     CommandInfo
+		wantErr string
+	}{
+		{"discord.py takes anything", LibraryDiscordPy, CommandInfo{Name: "menu", Type: "user_context"}, ""},
+		{"unset library is discord.py", "", CommandInfo{Name: "menu", Type: "user_context"}, ""},
+		{"plain slash command", LibraryNextcord, CommandInfo{Name: "roll", Type: "slash", Permissions: []string{"administrator"}}, ""},
+		{"context menu", LibraryPycord, CommandInfo{Name: "menu", Type: "user_context"}, "user_context commands"},
+		{"slash group", LibraryDisnake, CommandInfo{Name: "add", Type: "slash", Group: "tag"}, "slash command groups"},
+		{"slash cooldown", LibraryNextcord, CommandInfo{Name: "roll", Type: "slash", Cooldown: &CooldownInfo{Rate: 1, Per: 5, Bucket: "user"}}, "cooldowns on slash commands"},
+		{"autocomplete", LibraryDisnake, CommandInfo{Name: "tag", Type: "slash", Args: []ArgInfo{{Name: "name", Type: "str", Autocomplete: true}}}, "autocomplete"},
+		{"bounded prefix argument", LibraryPycord, CommandInfo{Name: "roll", Type: "prefix", Args: []ArgInfo{{Name: "sides", Type: "int", Min: "2"}}}, "bounded prefix arguments"},
+		{"ephemeral prefix reply", LibraryNextcord, CommandInfo{Name: "echo", Type: "prefix", Responses: []ResponseInfo{{Type: "message", Content: "hi", Ephemeral: true}}}, "ephemeral response"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateLibraryCog(tt.library, CogConfig{Name: "Cog", SlashCommands: []CommandInfo{tt.cmd}})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateLibraryCog returned error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateLibraryCog error = %v, want one mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateProjectLibraries(t *testing.T) {
+	HeadlessMode = true
+	t.Cleanup(func() { HeadlessMode = false })
+
+	tests := []struct {
+		library     string
+		requirement string
+	}{
+		{LibraryDiscordPy, "discord.py>=2.3"},
+		{LibraryPycord, "py-cord>=2.6"},
+		{LibraryNextcord, "nextcord>=2.6"},
+		{LibraryDisnake, "disnake>=2.9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.library, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			rootDir := t.TempDir()
+
+			values := projectTestValues("default", "")
+			library := tt.library
+			values.Map["library"] = &library
+			if _, err := CreateProject(rootDir, values, false); err != nil {
+				t.Fatalf("CreateProject returned error: %v", err)
+			}
+
+			var config Config
+			readJSONFile(t, filepath.Join(rootDir, "botbox.conf"), &config)
+			if config.BotInfo.Library != tt.library {
+				t.Errorf("botbox.conf library = %q, want %q", config.BotInfo.Library, tt.library)
+			}
+			if requirements := readOutput(t, filepath.Join(rootDir, "requirements.txt")); !strings.Contains(requirements, tt.requirement) {
+				t.Errorf("requirements.txt = %q, want it to require %s", requirements, tt.requirement)
+			}
+			// The translator is only written for discord.py's command tree
+			_, err := os.Stat(filepath.Join(rootDir, "src", "utils", "translator.py"))
+			if written := err == nil; written != (tt.library == LibraryDiscordPy) {
+				t.Errorf("translator.py written = %v for %s", written, tt.library)
+			}
+
+			// Every cog the template writes reads back in the library's dialect, so the project starts in sync
+			t.Chdir(rootDir)
+			result, err := StageCogSync(&ChangeSet{})
+			if err != nil {
+				t.Fatalf("StageCogSync returned error: %v", err)
+			}
+			if len(result.UpdatedCogs) != 0 || len(result.AddedCogs) != 0 || len(result.RemovedCogs) != 0 {
+				t.Errorf("new project drifted from its cog files: %+v", result)
+			}
+			if len(result.Diagnostics) != 0 {
+				t.Errorf("new project cog files should read back without diagnostics: %+v", result.Diagnostics)
+			}
+			for _, cog := range config.Cogs {
+				if len(cog.SlashCommands) == 0 && cog.File != "helloWorld" {
+					t.Errorf("cog %s read back without slash commands", cog.File)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateProjectRejectsDiscordPyOnlyTemplates(t *testing.T) {
+	HeadlessMode = true
+	t.Cleanup(func() { HeadlessMode = false })
+	t.Setenv("HOME", t.TempDir())
+
+	values := projectTestValues("moderation", "")
+	library := LibraryNextcord
+	values.Map["library"] = &library
+	rootDir := t.TempDir()
+	_, err := CreateProject(rootDir, values, false)
+	if err == nil || !strings.Contains(err.Error(), "moderation.py.tmpl is only written for discord.py") {
+		t.Errorf("CreateProject error = %v, want the moderation cog reported as discord.py only", err)
+	}
+	// The pair is refused before anything is written, so no half created project is left behind
+	if entries, _ := os.ReadDir(rootDir); len(entries) != 0 {
+		t.Errorf("CreateProject left %d entries in the project directory", len(entries))
+	}
+
+	moderation, err := FindProjectTemplate("moderation")
+	if err != nil {
+		t.Fatalf("FindProjectTemplate returned error: %v", err)
+	}
+	if libraries := moderation.SupportedLibraries(); !slices.Equal(libraries, []string{LibraryDiscordPy}) {
+		t.Errorf("moderation supported libraries = %v, want only discord.py", libraries)
+	}
+	if note := moderation.LibraryNote(); note != "discord.py only" {
+		t.Errorf("moderation library note = %q, want discord.py only", note)
+	}
+	minimal, err := FindProjectTemplate("minimal")
+	if err != nil {
+		t.Fatalf("FindProjectTemplate returned error: %v", err)
+	}
+	if note := minimal.LibraryNote(); note != "" {
+		t.Errorf("minimal library note = %q, want none", note)
+	}
+}
+
+/*
+Copyright © 2025 Austin "Choice404" Choi
+
+https://github.com/choice404/botbox
+
+Bot Box
+A discord bot template generator to help create discord bots quickly and easily
+
+This code is licensed under the MIT License.
+
+MIT License
+
+Copyright (c) 2025 Austin Choi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
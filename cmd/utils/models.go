@@ -32,7 +32,10 @@ func CreateModel(callback func(*Model) []error) Model {
 		"botGuildDopplerEnv":     new(string),
 		"licenseType":            new(string),
 		"helpStyle":              new(string),
+		"library":                new(string),
 		"dockerize":              new(string),
+		"template":               new(string),
+		"templateVars":           new(string),
 	}
 
 	m.ModelValues = Values{
@@ -49,6 +52,8 @@ func CreateModel(callback func(*Model) []error) Model {
 		display.WriteString("  - " + s.KeyText.Render("Project Author: ") + s.ValueText.Render(*m.ModelValues.Map["botAuthor"]) + "\n")
 		display.WriteString("  - " + s.KeyText.Render("Bot Prefix: ") + s.ValueText.Render(*m.ModelValues.Map["botPrefix"]) + "\n")
 		display.WriteString("  - " + s.KeyText.Render("Environment: ") + s.ValueText.Render(*m.ModelValues.Map["envChoice"]) + "\n")
+		display.WriteString("  - " + s.KeyText.Render("Library: ") + s.ValueText.Render(*m.ModelValues.Map["library"]) + "\n")
+		display.WriteString("  - " + s.KeyText.Render("Template: ") + s.ValueText.Render(*m.ModelValues.Map["template"]) + "\n")
 		return display.String()
 	}
 
@@ -76,6 +81,7 @@ func InitModel(callback func(*Model) []error) Model {
 		"botGuildDopplerEnv":     new(string),
 		"licenseType":            new(string),
 		"helpStyle":              new(string),
+		"library":                new(string),
 		"dockerize":              new(string),
 	}
 
@@ -106,6 +112,8 @@ func AddModel(callback func(*Model) []error, initCallback func(*Model, []Values)
 	m.currentFormPtr = 0
 	m.callback = callback
 	m.initCallback = initCallback
+	m.Changes = &ChangeSet{}
+	m.reviewChanges = true
 	m.ModelValues.Map = map[string]*string{
 		"filename":       new(string),
 		"currentCommand": new(string),
@@ -113,14 +121,20 @@ func AddModel(callback func(*Model) []error, initCallback func(*Model, []Values)
 		"pages":          new(string),
 		"slashCommands":  new(string),
 		"prefixCommands": new(string),
+		"listeners":      new(string),
+		"tasks":          new(string),
 	}
 
 	emptySlash := "[]"
 	emptyPrefix := "[]"
 	emptyPages := "[]"
+	emptyListeners := "[]"
+	emptyTasks := "[]"
 	m.ModelValues.Map["slashCommands"] = &emptySlash
 	m.ModelValues.Map["prefixCommands"] = &emptyPrefix
 	m.ModelValues.Map["pages"] = &emptyPages
+	m.ModelValues.Map["listeners"] = &emptyListeners
+	m.ModelValues.Map["tasks"] = &emptyTasks
 
 	addForms := AddFormWrapperGenerator()
 
@@ -132,6 +146,10 @@ func AddModel(callback func(*Model) []error, initCallback func(*Model, []Values)
 		slashCommands, _ := JSONToCmdInfoSlice(*m.ModelValues.Map["slashCommands"])
 		prefixCommands, _ := JSONToCmdInfoSlice(*m.ModelValues.Map["prefixCommands"])
 		writeCommandLists(s, &display, slashCommands, prefixCommands)
+		listeners, _ := JSONToListenerInfoSlice(*m.ModelValues.Map["listeners"])
+		writeListenerList(s, &display, listeners)
+		taskList, _ := JSONToTaskInfoSlice(*m.ModelValues.Map["tasks"])
+		writeTaskList(s, &display, taskList)
 		return display.String()
 	}
 
@@ -165,18 +183,36 @@ func writeCommandLists(s *Styles, display *strings.Builder, slashCommands, prefi
 					modalStr = strings.Join(fields, ", ")
 				}
 
-				commandLine := slashCommand.Name + " [modal: " + modalStr + "] -> " + slashCommand.ReturnType + responsesMark(slashCommand)
+				commandLine := CommandPath(slashCommand) + " [modal: " + modalStr + "] -> " + slashCommand.ReturnType + responsesMark(slashCommand)
+				display.WriteString("    - " + s.ValueText.Render(commandLine) + "\n")
+				continue
+			}
+
+			// Context menu commands take the clicked user or message instead of arguments
+			if IsContextMenuType(slashCommand.Type) {
+				target := "user"
+				if slashCommand.Type == "message_context" {
+					target = "message"
+				}
+				commandLine := slashCommand.Name + " [" + target + " context menu] -> " + slashCommand.ReturnType + responsesMark(slashCommand)
 				display.WriteString("    - " + s.ValueText.Render(commandLine) + "\n")
 				continue
 			}
 
 			var args []string
 			for _, command := range slashCommand.Args {
-				args = append(args, command.Name+": "+command.Type)
+				arg := command.Name + ": " + command.Type
+				if command.Optional {
+					arg += " = " + argDefault(command)
+				}
+				args = append(args, arg)
 			}
 			argsStr := strings.Join(args, ", ")
 
-			commandLine := slashCommand.Name + "(" + argsStr + ") -> " + slashCommand.ReturnType + responsesMark(slashCommand)
+			commandLine := CommandPath(slashCommand) + "(" + argsStr + ") -> " + slashCommand.ReturnType + responsesMark(slashCommand)
+			if slashCommand.Components != nil {
+				commandLine += fmt.Sprintf(" [components: %d]", len(slashCommand.Components.Items))
+			}
 			display.WriteString("    - " + s.ValueText.Render(commandLine) + "\n")
 		}
 	}
@@ -185,16 +221,52 @@ func writeCommandLists(s *Styles, display *strings.Builder, slashCommands, prefi
 		for _, prefixCommand := range prefixCommands {
 			var args []string
 			for _, command := range prefixCommand.Args {
-				args = append(args, command.Name+": "+command.Type)
+				arg := command.Name + ": " + command.Type
+				if command.Optional {
+					arg += " = " + argDefault(command)
+				}
+				args = append(args, arg)
 			}
 			argsStr := strings.Join(args, ", ")
 
-			commandLine := prefixCommand.Name + "(" + argsStr + ") -> " + prefixCommand.ReturnType + responsesMark(prefixCommand)
+			commandLine := CommandPath(prefixCommand) + "(" + argsStr + ") -> " + prefixCommand.ReturnType + responsesMark(prefixCommand)
+			if len(prefixCommand.Aliases) > 0 {
+				commandLine += " [aliases: " + strings.Join(prefixCommand.Aliases, ", ") + "]"
+			}
+			if prefixCommand.Hidden {
+				commandLine += " [hidden]"
+			}
 			display.WriteString("    - " + s.ValueText.Render(commandLine) + "\n")
 		}
 	}
 }
 
+// writeListenerList renders the event listener lines shared by the add and edit summaries
+func writeListenerList(s *Styles, display *strings.Builder, listeners []ListenerInfo) {
+	if len(listeners) == 0 {
+		return
+	}
+	display.WriteString(s.KeyText.Render("Listeners:") + "\n")
+	for _, listener := range listeners {
+		listenerLine := listener.Event
+		if listener.Action != "" {
+			listenerLine += " [" + listener.Action + "]"
+		}
+		display.WriteString("    - " + s.ValueText.Render(listenerLine) + "\n")
+	}
+}
+
+// writeTaskList renders the scheduled task lines shared by the add and edit summaries
+func writeTaskList(s *Styles, display *strings.Builder, tasks []TaskInfo) {
+	if len(tasks) == 0 {
+		return
+	}
+	display.WriteString(s.KeyText.Render("Tasks:") + "\n")
+	for _, task := range tasks {
+		display.WriteString("    - " + s.ValueText.Render(task.Name+" ["+taskSchedule(task)+"]") + "\n")
+	}
+}
+
 func EditModel(callback func(*Model) []error, initCallback func(*Model, []Values)) Model {
 	m := Model{width: maxWidth}
 	m.title = "Edit a Cog"
@@ -205,6 +277,8 @@ func EditModel(callback func(*Model) []error, initCallback func(*Model, []Values
 	m.callback = callback
 	m.initCallback = initCallback
 	m.forms = EditFormWrapperGenerator()
+	m.Changes = &ChangeSet{}
+	m.reviewChanges = true
 
 	m.ModelValues.Map = map[string]*string{
 		"cogName":         new(string),
@@ -216,6 +290,8 @@ func EditModel(callback func(*Model) []error, initCallback func(*Model, []Values
 		"pages":           new(string),
 		"slashCommands":   new(string),
 		"prefixCommands":  new(string),
+		"listeners":       new(string),
+		"tasks":           new(string),
 	}
 
 	emptyPages := "[]"
@@ -232,6 +308,10 @@ func EditModel(callback func(*Model) []error, initCallback func(*Model, []Values
 		slashCommands, _ := JSONToCmdInfoSlice(*m.ModelValues.Map["slashCommands"])
 		prefixCommands, _ := JSONToCmdInfoSlice(*m.ModelValues.Map["prefixCommands"])
 		writeCommandLists(s, &display, slashCommands, prefixCommands)
+		listeners, _ := JSONToListenerInfoSlice(*m.ModelValues.Map["listeners"])
+		writeListenerList(s, &display, listeners)
+		taskList, _ := JSONToTaskInfoSlice(*m.ModelValues.Map["tasks"])
+		writeTaskList(s, &display, taskList)
 		return display.String()
 	}
 
@@ -249,6 +329,7 @@ func RemoveModel(callback func(*Model) []error, initCallback func(*Model, []Valu
 	removeForms := RemoveFormWrapperGenerator()
 	m.forms = removeForms
 	m.initCallback = initCallback
+	m.Changes = &ChangeSet{}
 
 	values := map[string]*string{
 		"cogName": new(string),
@@ -326,11 +407,15 @@ func LocalConfigModel(callback func(*Model) []error, initCallback func(*Model, [
 					for _, slashCommand := range slashCommands {
 						var args []string
 						for _, command := range slashCommand.Args {
-							args = append(args, command.Name+": "+command.Type)
+							arg := command.Name + ": " + command.Type
+							if command.Optional {
+								arg += " = " + argDefault(command)
+							}
+							args = append(args, arg)
 						}
 						argsStr := strings.Join(args, ", ")
 
-						commandLine := slashCommand.Name + "(" + argsStr + ") -> " + slashCommand.ReturnType
+						commandLine := CommandPath(slashCommand) + "(" + argsStr + ") -> " + slashCommand.ReturnType
 						display.WriteString("      - " + s.ValueText.Render(commandLine) + "\n")
 					}
 				}
@@ -339,7 +424,11 @@ func LocalConfigModel(callback func(*Model) []error, initCallback func(*Model, [
 					for _, prefixCommand := range prefixCommands {
 						var args []string
 						for _, command := range prefixCommand.Args {
-							args = append(args, command.Name+": "+command.Type)
+							arg := command.Name + ": " + command.Type
+							if command.Optional {
+								arg += " = " + argDefault(command)
+							}
+							args = append(args, arg)
 						}
 						argsStr := strings.Join(args, ", ")
 
@@ -435,6 +524,8 @@ func ConfigSyncModel(callback func(*Model) []error, initCallback func(*Model, []
 	localConfigForms := ConfigSyncFormWrapperGenerator()
 	m.forms = localConfigForms
 	m.initCallback = initCallback
+	m.Changes = &ChangeSet{}
+	m.reviewChanges = true
 
 	m.ModelValues = Values{
 		Map: map[string]*string{
@@ -443,6 +534,7 @@ func ConfigSyncModel(callback func(*Model) []error, initCallback func(*Model, []
 			"removedCogs":  new(string),
 			"headerIssues": new(string),
 			"noChanges":    new(string),
+			"diagnostics":  new(string),
 		},
 		Name: "ModelValues",
 	}
@@ -467,6 +559,12 @@ func ConfigSyncModel(callback func(*Model) []error, initCallback func(*Model, []
 				display.WriteString(s.KeyText.Render("Header Issues: ") + s.ValueText.Render(*m.ModelValues.Map["headerIssues"]) + "\n")
 			}
 		}
+		if *m.ModelValues.Map["diagnostics"] != "" {
+			display.WriteString("\n" + s.KeyText.Render("Warnings:") + "\n")
+			for _, warning := range strings.Split(*m.ModelValues.Map["diagnostics"], "\n") {
+				display.WriteString(s.ValueText.Render(warning) + "\n")
+			}
+		}
 
 		return display.String()
 	}
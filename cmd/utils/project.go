@@ -6,9 +6,11 @@ See end of file for extended copyright information
 package utils
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 )
 
 // projectTemplateData holds the values rendered into the project templates
@@ -29,6 +31,12 @@ type projectTemplateData struct {
 	HelpStyle string
 	// EnvProvider is written into botbox.conf so later commands know how secrets are supplied
 	EnvProvider string
+	// Library is written into botbox.conf and picks the template set of main.py and the cogs
+	Library string
+	// SlashOnly drops the command prefix and the message content intent from main.py
+	SlashOnly bool
+	// Vars holds the values of the project template's variables
+	Vars map[string]string
 }
 
 // dockerTemplateData holds the values rendered into the docker templates
@@ -54,7 +62,34 @@ func renderToFile(path string, templateName string, data any) error {
 	return os.WriteFile(path, []byte(content), 0644)
 }
 
-func CreateProject(rootDir string, values Values, force bool) error {
+/**
+ * CreateProject
+ * Writes the files of a new project into rootDir with the generator of the chosen library, existing files are
+ * only replaced when force is set or the user agrees
+ * @param rootDir {string} - the project root
+ * @param values {Values} - the project values collected by the create and init forms
+ * @param force {bool} - overwrite existing files without asking
+ * @return []string - the paths that were written
+ * @return error - an unknown library or the first file that could not be written
+ **/
+func CreateProject(rootDir string, values Values, force bool) ([]string, error) {
+	generator, err := GeneratorFor(optionalValue(values, "library", DefaultLibrary))
+	if err != nil {
+		return nil, err
+	}
+	return generator.CreateProject(rootDir, values, force)
+}
+
+// CreateProject renders the embedded templates, swapping in the library's own set for the discord.py templates
+func (g pythonGenerator) CreateProject(rootDir string, values Values, force bool) ([]string, error) {
+	projectTemplate, err := FindProjectTemplate(optionalValue(values, "template", DefaultProjectTemplate))
+	if err != nil {
+		return nil, err
+	}
+	if err := projectTemplate.SupportsLibrary(g.library); err != nil {
+		return nil, err
+	}
+
 	directories := []string{
 		"src",
 		"src/cogs",
@@ -65,7 +100,7 @@ func CreateProject(rootDir string, values Values, force bool) error {
 		fullPath := filepath.Join(rootDir, dir)
 		err := os.MkdirAll(fullPath, os.ModePerm)
 		if err != nil {
-			return fmt.Errorf("error creating directory %s: %w", fullPath, err)
+			return nil, fmt.Errorf("error creating directory %s: %w", fullPath, err)
 		}
 	}
 
@@ -90,187 +125,118 @@ func CreateProject(rootDir string, values Values, force bool) error {
 		Config:      *values.Map["botGuildDopplerEnv"],
 		HelpStyle:   NormalizeHelpStyle(optionalValue(values, "helpStyle", DefaultHelpStyle)),
 		EnvProvider: envProvider,
+		Library:     g.library,
 	}
 
-	if confOpt, err := CreateFileOption(filepath.Join(rootDir, "botbox.conf"), force); err == nil && confOpt {
-		err := renderToFile(filepath.Join(rootDir, "botbox.conf"), "botbox.conf.tmpl", data)
-		if err != nil {
-			return fmt.Errorf("error creating botbox.conf file: %w", err)
-		}
-	} else if err == nil && !confOpt {
-		fmt.Println("Not overriding botbox.conf file.")
-	} else {
-		return fmt.Errorf("error creating botbox.conf file: %w", err)
+	given := map[string]string{}
+	if err := json.Unmarshal([]byte(optionalValue(values, "templateVars", "{}")), &given); err != nil {
+		return nil, fmt.Errorf("invalid template variables: %w", err)
 	}
-
-	if readmeOpt, err := CreateFileOption(filepath.Join(rootDir, "README.md"), force); err == nil && readmeOpt {
-		err := renderToFile(filepath.Join(rootDir, "README.md"), "readme.md.tmpl", data)
-		if err != nil {
-			return fmt.Errorf("error creating README.md file: %w", err)
-		}
-	} else if err == nil && !readmeOpt {
-		fmt.Println("Not overriding README.md file.")
-	} else {
-		return fmt.Errorf("error creating README.md file: %w", err)
+	if data.Vars, err = projectTemplate.ResolveVariables(given); err != nil {
+		return nil, err
 	}
+	data.SlashOnly = projectTemplate.slashOnly
 
-	if data.HasLicense {
-		if licenseOpt, err := CreateFileOption(filepath.Join(rootDir, "LICENSE"), force); err == nil && licenseOpt {
-			licenseText, err := FetchLicense(licenseType)
-			if err != nil {
-				return fmt.Errorf("Error fetching license %s: %v\n", licenseType, err)
-			}
-
-			err = os.WriteFile(filepath.Join(rootDir, "LICENSE"), []byte(licenseText), 0644)
-			if err != nil {
-				return fmt.Errorf("Error writing to LICENSE file: %v\n", err)
-			}
-		} else if err == nil && !licenseOpt {
-			fmt.Println("Not overriding LICENSE file.")
-		} else {
-			return fmt.Errorf("Error creating LICENSE file: %v\n", err)
-		}
+	// Each output file pairs with the template that renders it, the license has no template and is fetched instead
+	files := []TemplateFile{
+		{Path: "botbox.conf", Template: "botbox.conf.tmpl"},
+		{Path: "README.md", Template: "readme.md.tmpl"},
 	}
-
-	if *values.Map["envChoice"] == "doppler" {
-		if dopplerOpt, err := CreateFileOption(filepath.Join(rootDir, "doppler.yaml"), force); err == nil && dopplerOpt {
-			err := renderToFile(filepath.Join(rootDir, "doppler.yaml"), "doppler.yaml.tmpl", data)
-			if err != nil {
-				return fmt.Errorf("Error creating doppler.yaml file: %v\n", err)
-			}
-		} else if err == nil && !dopplerOpt {
-			fmt.Println("Not overriding doppler.yaml file.")
-		} else {
-			return fmt.Errorf("Error creating doppler.yaml file: %v\n", err)
-		}
-	} else if *values.Map["envChoice"] == "env" {
-		if envOpt, err := CreateFileOption(filepath.Join(rootDir, ".env"), force); err == nil && envOpt {
-			err := renderToFile(filepath.Join(rootDir, ".env"), "env.tmpl", data)
-			if err != nil {
-				return fmt.Errorf("Error creating .env file: %v\n", err)
-			}
-		} else if err == nil && !envOpt {
-			fmt.Println("Not overriding .env file.")
-		} else {
-			return fmt.Errorf("Error creating .env file: %v\n", err)
-		}
-	} else if *values.Map["envChoice"] == "none" {
-		fmt.Println("No environment file will be created.")
-	} else {
-		return fmt.Errorf("Invalid environment choice: %s", *values.Map["envChoice"])
-	}
-
-	if reqOpt, err := CreateFileOption(filepath.Join(rootDir, "requirements.txt"), force); err == nil && reqOpt {
-		err := renderToFile(filepath.Join(rootDir, "requirements.txt"), "requirements.txt.tmpl", data)
-		if err != nil {
-			return fmt.Errorf("error creating requirements.txt file: %w", err)
-		}
-	} else if err == nil && !reqOpt {
-		fmt.Println("Not overriding requirements.txt file.")
-	} else {
-		return fmt.Errorf("error creating requirements.txt file: %w", err)
+	if data.HasLicense {
+		files = append(files, TemplateFile{Path: "LICENSE"})
 	}
-
-	if gitignoreOpt, err := CreateFileOption(filepath.Join(rootDir, ".gitignore"), force); err == nil && gitignoreOpt {
-		err := renderToFile(filepath.Join(rootDir, ".gitignore"), "gitignore.tmpl", data)
-		if err != nil {
-			return fmt.Errorf("error creating .gitignore file: %w", err)
-		}
-	} else if err == nil && !gitignoreOpt {
-		fmt.Println("Not overriding .gitignore file.")
-	} else {
-		return fmt.Errorf("error creating .gitignore file: %w", err)
+	switch *values.Map["envChoice"] {
+	case "doppler":
+		files = append(files, TemplateFile{Path: "doppler.yaml", Template: "doppler.yaml.tmpl"})
+	case "env":
+		files = append(files, TemplateFile{Path: ".env", Template: "env.tmpl"})
+	case "none":
+		fmt.Fprintln(os.Stderr, "No environment file will be created.")
+	default:
+		return nil, fmt.Errorf("Invalid environment choice: %s", *values.Map["envChoice"])
 	}
-
-	if runOpt, err := CreateFileOption(filepath.Join(rootDir, "run.sh"), force); err == nil && runOpt {
-		err := renderToFile(filepath.Join(rootDir, "run.sh"), "run.sh.tmpl", data)
-		if err != nil {
-			return fmt.Errorf("Error creating run.sh file: %v\n", err)
-		}
-		err = os.Chmod(filepath.Join(rootDir, "run.sh"), 0755)
-		if err != nil {
-			return fmt.Errorf("Error setting permissions for run.sh file: %v\n", err)
-		}
-	} else if err == nil && !runOpt {
-		fmt.Println("Not overriding run.sh file.")
-	} else {
-		return fmt.Errorf("Error creating run.sh file: %v\n", err)
+	files = append(files,
+		TemplateFile{Path: "requirements.txt", Template: "requirements.txt.tmpl"},
+		TemplateFile{Path: ".gitignore", Template: "gitignore.tmpl"},
+		TemplateFile{Path: "run.sh", Template: "run.sh.tmpl", Executable: true},
+		TemplateFile{Path: filepath.ToSlash(g.MainFile()), Template: "main.py.tmpl", Executable: true},
+		TemplateFile{Path: "src/cogs/__init__.py", Template: "init.py.tmpl"},
+		TemplateFile{Path: "src/utils/logger.py", Template: "logger.py.tmpl"},
+		TemplateFile{Path: "src/utils/__init__.py", Template: "utils_init.py.tmpl"},
+	)
+	// The translator plugs into discord.py's command tree, the other libraries have no translator to install it into
+	if data.Library == LibraryDiscordPy {
+		files = append(files, TemplateFile{Path: filepath.ToSlash(g.TranslatorFile()), Template: "translator.py.tmpl"})
 	}
-
-	if mainOpt, err := CreateFileOption(filepath.Join(rootDir, "src", "main.py"), force); err == nil && mainOpt {
-		err := renderToFile(filepath.Join(rootDir, "src", "main.py"), "main.py.tmpl", data)
-		if err != nil {
-			return fmt.Errorf("Error creating main.py file: %v\n", err)
-		}
-		err = os.Chmod(filepath.Join(rootDir, "src", "main.py"), 0755)
-		if err != nil {
-			return fmt.Errorf("Error setting permissions for main.py file: %v\n", err)
-		}
+	// The template's cogs and files come last, a user template file replaces a file of the same path
+	for _, file := range projectTemplate.Files {
+		files = mergeTemplateFile(files, file)
 	}
-
-	if helloWorldOpt, err := CreateFileOption(filepath.Join(rootDir, "src", "cogs", "helloWorld.py"), force); err == nil && helloWorldOpt {
-		err := renderToFile(filepath.Join(rootDir, "src", "cogs", "helloWorld.py"), "helloworld.py.tmpl", data)
-		if err != nil {
-			return fmt.Errorf("Error creating helloWorld.py file: %v\n", err)
+	// Embedded templates written for discord.py are swapped for the version in the library's template set
+	for i, file := range files {
+		if file.dir != "" || file.Template == "" {
+			continue
 		}
-		err = os.Chmod(filepath.Join(rootDir, "src", "cogs", "helloWorld.py"), 0755)
-		if err != nil {
-			return fmt.Errorf("Error setting permissions for helloWorld.py file: %v\n", err)
+		if files[i].Template, err = LibraryTemplate(data.Library, file.Template); err != nil {
+			return nil, fmt.Errorf("error creating %s file: the %s template cannot be used: %w", file.Path, projectTemplate.Name, err)
 		}
 	}
 
-	if helpOpt, err := CreateFileOption(filepath.Join(rootDir, "src", "cogs", "help.py"), force); err == nil && helpOpt {
-		err := renderToFile(filepath.Join(rootDir, "src", "cogs", "help.py"), "help.py.tmpl", data)
-		if err != nil {
-			return fmt.Errorf("Error creating help.py file: %v\n", err)
+	// Everything is rendered before the first write so a broken template writes no files
+	rendered := map[string]string{}
+	for _, file := range files {
+		if file.Template == "" {
+			continue
 		}
-		err = os.Chmod(filepath.Join(rootDir, "src", "cogs", "help.py"), 0755)
+		content, err := file.render(data)
 		if err != nil {
-			return fmt.Errorf("Error setting permissions for help.py file: %v\n", err)
+			return nil, fmt.Errorf("error creating %s file: %w", file.Path, err)
 		}
+		rendered[file.Path] = content
 	}
-
-	if adminOpt, err := CreateFileOption(filepath.Join(rootDir, "src", "cogs", "admin.py"), force); err == nil && adminOpt {
-		err := renderToFile(filepath.Join(rootDir, "src", "cogs", "admin.py"), "admin.py.tmpl", data)
-		if err != nil {
-			return fmt.Errorf("Error creating admin.py file: %v\n", err)
-		}
-		err = os.Chmod(filepath.Join(rootDir, "src", "cogs", "admin.py"), 0755)
+	if i := slices.IndexFunc(files, func(f TemplateFile) bool { return f.Path == "botbox.conf" }); files[i].dir == "" {
+		config, err := composeProjectConfig(data, files, rendered)
 		if err != nil {
-			return fmt.Errorf("Error setting permissions for admin.py file: %v\n", err)
+			return nil, fmt.Errorf("error creating botbox.conf file: %w", err)
 		}
+		rendered["botbox.conf"] = string(config)
 	}
 
-	if cogsOpt, err := CreateFileOption(filepath.Join(rootDir, "src", "cogs", "cogs.py"), force); err == nil && cogsOpt {
-		err := renderToFile(filepath.Join(rootDir, "src", "cogs", "cogs.py"), "cogs.py.tmpl", data)
-		if err != nil {
-			return fmt.Errorf("Error creating cogs.py file: %v\n", err)
+	var written []string
+	for _, file := range files {
+		path := filepath.Join(rootDir, filepath.FromSlash(file.Path))
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			return written, fmt.Errorf("error creating directory %s: %w", filepath.Dir(path), err)
 		}
-		err = os.Chmod(filepath.Join(rootDir, "src", "cogs", "cogs.py"), 0755)
+		opt, err := CreateFileOption(path, force)
 		if err != nil {
-			return fmt.Errorf("Error setting permissions for cogs.py file: %v\n", err)
+			return written, fmt.Errorf("error creating %s file: %w", file.Path, err)
 		}
-	}
-
-	if initOpt, err := CreateFileOption(filepath.Join(rootDir, "src", "cogs", "__init__.py"), force); err == nil && initOpt {
-		err := renderToFile(filepath.Join(rootDir, "src", "cogs", "__init__.py"), "init.py.tmpl", data)
-		if err != nil {
-			return fmt.Errorf("error creating __init__.py file: %w", err)
+		// Skipped files are reported on stderr so stdout only carries results
+		if !opt {
+			if !HeadlessMode {
+				fmt.Fprintf(os.Stderr, "Not overriding %s file.\n", file.Path)
+			}
+			continue
 		}
-	}
 
-	if loggerOpt, err := CreateFileOption(filepath.Join(rootDir, "src", "utils", "logger.py"), force); err == nil && loggerOpt {
-		err := renderToFile(filepath.Join(rootDir, "src", "utils", "logger.py"), "logger.py.tmpl", data)
-		if err != nil {
-			return fmt.Errorf("error creating logger.py file: %w", err)
+		if file.Template == "" {
+			licenseText, err := FetchLicense(licenseType)
+			if err != nil {
+				return written, fmt.Errorf("error fetching license %s: %w", licenseType, err)
+			}
+			if err := os.WriteFile(path, []byte(licenseText), 0644); err != nil {
+				return written, fmt.Errorf("error writing to LICENSE file: %w", err)
+			}
+		} else if err := os.WriteFile(path, []byte(rendered[file.Path]), 0644); err != nil {
+			return written, fmt.Errorf("error creating %s file: %w", file.Path, err)
 		}
-	}
-
-	if utilsInitOpt, err := CreateFileOption(filepath.Join(rootDir, "src", "utils", "__init__.py"), force); err == nil && utilsInitOpt {
-		err := renderToFile(filepath.Join(rootDir, "src", "utils", "__init__.py"), "utils_init.py.tmpl", data)
-		if err != nil {
-			return fmt.Errorf("error creating utils __init__.py file: %w", err)
+		if file.Executable {
+			if err := os.Chmod(path, 0755); err != nil {
+				return written, fmt.Errorf("error setting permissions for %s file: %w", file.Path, err)
+			}
 		}
+		written = append(written, path)
 	}
 
 	// Docker files are opt in, the tui confirm and the --docker flag both store yes here
@@ -280,17 +246,24 @@ func CreateProject(rootDir string, values Values, force bool) error {
 		if conf, err := LoadGlobalConfig(); err == nil && conf.Defaults.PythonVersion != "" {
 			pythonVersion = conf.Defaults.PythonVersion
 		}
-		if _, err := GenerateDockerFiles(rootDir, pythonVersion, envProvider, force); err != nil {
-			return fmt.Errorf("error creating docker files: %w", err)
+		dockerFiles, err := g.GenerateDockerFiles(rootDir, pythonVersion, envProvider, force)
+		written = append(written, dockerFiles...)
+		if err != nil {
+			return written, fmt.Errorf("error creating docker files: %w", err)
 		}
 	}
 
-	return nil
+	return written, nil
 }
 
 // DefaultPythonVersion seeds the docker base image when the global config has no default
 const DefaultPythonVersion = "3.11"
 
+// GenerateDockerFiles writes the Python image's docker files, the library only changes what requirements.txt installs
+func (g pythonGenerator) GenerateDockerFiles(rootDir string, pythonVersion string, envProvider string, force bool) ([]string, error) {
+	return GenerateDockerFiles(rootDir, pythonVersion, envProvider, force)
+}
+
 // GenerateDockerFiles renders the Dockerfile, docker-compose.yml, and .dockerignore
 // into rootDir and returns the paths it actually wrote
 func GenerateDockerFiles(rootDir string, pythonVersion string, envProvider string, force bool) ([]string, error) {
@@ -59,6 +59,9 @@ func UpgradeConfig() (*UpgradeResult, error) {
 	// Configs written before help_style existed get the default so the key is always present
 	upgradedConfig.BotInfo.HelpStyle = NormalizeHelpStyle(upgradedConfig.BotInfo.HelpStyle)
 
+	// Configs written before library existed were all generated for discord.py
+	upgradedConfig.BotInfo.Library = NormalizeLibrary(upgradedConfig.BotInfo.Library)
+
 	// Configs written before env_provider existed get the detected provider so the key is always present
 	if upgradedConfig.BotInfo.EnvProvider == "" {
 		upgradedConfig.BotInfo.EnvProvider = DetectEnvProvider(rootDir)
@@ -69,7 +72,11 @@ func UpgradeConfig() (*UpgradeResult, error) {
 		Errors:       []string{},
 	}
 
-	cogsDir := filepath.Join(rootDir, "src", "cogs")
+	generator, err := GeneratorFor(legacyConfig.BotInfo.Library)
+	if err != nil {
+		return nil, err
+	}
+	cogsDir := filepath.Join(rootDir, generator.CogsDir())
 
 	for _, legacyCog := range legacyConfig.Cogs {
 		fmt.Printf("📝 Upgrading cog: %s\n", legacyCog.Name)
@@ -82,9 +89,9 @@ func UpgradeConfig() (*UpgradeResult, error) {
 			PrefixCommands: []CommandInfo{},
 		}
 
-		cogFilePath := filepath.Join(cogsDir, legacyCog.File+".py")
+		cogFilePath := filepath.Join(cogsDir, generator.CogFileName(legacyCog.File))
 		if _, err := os.Stat(cogFilePath); err == nil {
-			if parsedCog, err := parseCogFile(cogFilePath, legacyCog.File); err == nil {
+			if parsedCog, err := parseCogFile(cogFilePath, legacyCog.File, legacyConfig.BotInfo.Library); err == nil {
 				upgradedCog.SlashCommands = parsedCog.SlashCommands
 				upgradedCog.PrefixCommands = parsedCog.PrefixCommands
 			} else {
@@ -93,7 +100,7 @@ func UpgradeConfig() (*UpgradeResult, error) {
 				upgradedCog.PrefixCommands = convertLegacyCommands(legacyCog.PrefixCommands, "prefix")
 			}
 		} else {
-			result.Errors = append(result.Errors, fmt.Sprintf("Cog file %s.py not found, using legacy command names", legacyCog.File))
+			result.Errors = append(result.Errors, fmt.Sprintf("Cog file %s not found, using legacy command names", generator.CogFileName(legacyCog.File)))
 			upgradedCog.SlashCommands = convertLegacyCommands(legacyCog.SlashCommands, "slash")
 			upgradedCog.PrefixCommands = convertLegacyCommands(legacyCog.PrefixCommands, "prefix")
 		}
@@ -102,15 +109,18 @@ func UpgradeConfig() (*UpgradeResult, error) {
 		result.UpgradedCogs = append(result.UpgradedCogs, legacyCog.Name)
 	}
 
-	backupPath := configPath + ".backup"
-	if err := os.WriteFile(backupPath, jsonData, 0644); err != nil {
+	changes := &ChangeSet{}
+	if backupID, err := StageBackup(changes, rootDir, "project upgrade", configPath); err != nil {
 		result.Errors = append(result.Errors, fmt.Sprintf("Failed to create backup: %v", err))
 	} else {
 		result.BackupCreated = true
-		result.BackupPath = backupPath
+		result.BackupID = backupID
 	}
 
-	if err := saveConfig(rootDir, upgradedConfig); err != nil {
+	if err := StageConfig(changes, rootDir, upgradedConfig); err != nil {
+		return nil, fmt.Errorf("failed to save upgraded config: %w", err)
+	}
+	if err := changes.Apply(); err != nil {
 		return nil, fmt.Errorf("failed to save upgraded config: %w", err)
 	}
 
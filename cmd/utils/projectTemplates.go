@@ -0,0 +1,495 @@
+/*
+Copyright © 2025 Austin "Choice404" Choi
+See end of file for extended copyright information
+*/
+
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// DefaultProjectTemplate is the starter botbox create builds from when no template is picked
+const DefaultProjectTemplate = "default"
+
+// TemplateManifest describes a user template, it sits in ~/.config/botbox/templates/<name>/
+const TemplateManifest = "template.json"
+
+// BuiltinTemplateSource is the Source of the templates compiled into botbox
+const BuiltinTemplateSource = "built-in"
+
+// ProjectTemplate is a starter for botbox create, built in or loaded from the user's templates directory
+type ProjectTemplate struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Source is BuiltinTemplateSource or the directory a user template was loaded from
+	Source string `json:"source"`
+	// Extends names the built in template a user template adds its files to
+	Extends   string             `json:"extends,omitempty"`
+	Files     []TemplateFile     `json:"files"`
+	Variables []TemplateVariable `json:"variables"`
+	// Libraries is filled in by template list from SupportedLibraries
+	Libraries []string `json:"libraries,omitempty"`
+	// slashOnly renders a main.py without a command prefix or the message content intent
+	slashOnly bool
+}
+
+// TemplateFile is one file a project template writes, on top of the files every project gets
+type TemplateFile struct {
+	// Path is where the file goes relative to the project root, like src/cogs/tickets.py
+	Path string `json:"path"`
+	// Template is the file rendered into it, an embedded template name for built in templates,
+	// or a path relative to the template directory for user templates
+	Template   string `json:"template"`
+	Executable bool   `json:"executable,omitempty"`
+	// dir is the user template directory Template is read from, empty for embedded templates
+	dir string
+	// env is the environment a cog file loads in, empty for production
+	env string
+}
+
+// TemplateVariable is a value a user template asks for, its files read it as <<.Vars.name>>
+type TemplateVariable struct {
+	Name     string `json:"name"`
+	Prompt   string `json:"prompt"`
+	Default  string `json:"default,omitempty"`
+	Required bool   `json:"required,omitempty"`
+}
+
+// templateManifest is the content of a user template's template.json
+type templateManifest struct {
+	Description string             `json:"description"`
+	Extends     string             `json:"extends"`
+	Files       []TemplateFile     `json:"files"`
+	Variables   []TemplateVariable `json:"variables"`
+}
+
+var templateVariableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// coreCogFiles are the cogs of the default project, keyed by file name, with the embedded templates that render them
+var coreCogFiles = map[string]TemplateFile{
+	"admin":      {Path: "src/cogs/admin.py", Template: "admin.py.tmpl", Executable: true},
+	"cogs":       {Path: "src/cogs/cogs.py", Template: "cogs.py.tmpl", Executable: true},
+	"help":       {Path: "src/cogs/help.py", Template: "help.py.tmpl", Executable: true},
+	"helloWorld": {Path: "src/cogs/helloWorld.py", Template: "helloworld.py.tmpl", Executable: true, env: "development"},
+}
+
+// builtinCogFiles are the files of the cogs botbox renders from their own templates and keeps running the bot,
+// cog.py.tmpl cannot regenerate them, so a spec never replaces or removes them
+var builtinCogFiles = []string{"admin", "cogs", "help"}
+
+// IsBuiltinCog reports whether a cog is one of the admin, cog management, and help cogs of a new project
+func IsBuiltinCog(cog CogConfig) bool {
+	return slices.Contains(builtinCogFiles, cog.File)
+}
+
+// coreCogs lists the template files of some of the default project's cogs
+func coreCogs(names ...string) []TemplateFile {
+	files := make([]TemplateFile, 0, len(names))
+	for _, name := range names {
+		files = append(files, coreCogFiles[name])
+	}
+	return files
+}
+
+// builtinProjectTemplates are the starters compiled into botbox, default is the project botbox has always created
+func builtinProjectTemplates() []ProjectTemplate {
+	templates := []ProjectTemplate{
+		{
+			Name:        "default",
+			Description: "Admin, help, and cog management cogs with a HelloWorld demo cog",
+			Files:       coreCogs("admin", "cogs", "help", "helloWorld"),
+		},
+		{
+			Name:        "minimal",
+			Description: "Only the help and cog management cogs",
+			Files:       coreCogs("cogs", "help"),
+		},
+		{
+			Name:        "moderation",
+			Description: "Admin, help, and cog management cogs with kick, ban, timeout, and purge commands",
+			Files: append(coreCogs("admin", "cogs", "help"),
+				TemplateFile{Path: "src/cogs/moderation.py", Template: "moderation.py.tmpl", Executable: true}),
+		},
+		{
+			Name:        "utility",
+			Description: "Admin, help, and cog management cogs with ping, avatar, userinfo, and serverinfo commands",
+			Files: append(coreCogs("admin", "cogs", "help"),
+				TemplateFile{Path: "src/cogs/utility.py", Template: "utility.py.tmpl", Executable: true}),
+		},
+		{
+			Name:        "slash-only",
+			Description: "The default cogs on a bot without prefix commands or the message content intent",
+			Files:       coreCogs("admin", "cogs", "help", "helloWorld"),
+			slashOnly:   true,
+		},
+	}
+	for i := range templates {
+		templates[i].Source = BuiltinTemplateSource
+		templates[i].Variables = []TemplateVariable{}
+	}
+	return templates
+}
+
+// SupportsLibrary reports the first embedded file of the template the library has no version of,
+// so a template written only for discord.py is refused before any project file is written
+func (t ProjectTemplate) SupportsLibrary(library string) error {
+	for _, file := range t.Files {
+		if file.dir != "" || file.Template == "" {
+			continue
+		}
+		if _, err := LibraryTemplate(library, file.Template); err != nil {
+			return fmt.Errorf("the %s template cannot be used with %s: %w", t.Name, NormalizeLibrary(library), err)
+		}
+	}
+	return nil
+}
+
+// SupportedLibraries names the registered libraries the template can create a project for
+func (t ProjectTemplate) SupportedLibraries() []string {
+	var libraries []string
+	for _, library := range GeneratorNames() {
+		if t.SupportsLibrary(library) == nil {
+			libraries = append(libraries, library)
+		}
+	}
+	return libraries
+}
+
+// LibraryNote is shown next to a template that cannot be used with every library, like "discord.py only"
+func (t ProjectTemplate) LibraryNote() string {
+	libraries := t.SupportedLibraries()
+	if len(libraries) == len(GeneratorNames()) {
+		return ""
+	}
+	return strings.Join(libraries, ", ") + " only"
+}
+
+// UserTemplatesDir is where user templates and template overrides are kept, next to the global config
+func UserTemplatesDir() (string, error) {
+	configPath, err := GetConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), "templates"), nil
+}
+
+/**
+ * ListProjectTemplates
+ * Lists the built in templates followed by the user templates, sorted by name. A user template named like a
+ * built in one replaces it. Directories without a template.json are left out, they can hold template overrides
+ * @return []ProjectTemplate - the templates that loaded
+ * @return []error - one error for each user template that could not be loaded
+ **/
+func ListProjectTemplates() ([]ProjectTemplate, []error) {
+	templates := builtinProjectTemplates()
+
+	dir, err := UserTemplatesDir()
+	if err != nil {
+		return templates, []error{err}
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return templates, nil
+	}
+	if err != nil {
+		return templates, []error{fmt.Errorf("failed to read templates directory: %w", err)}
+	}
+
+	var errs []error
+	var user []ProjectTemplate
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, entry.Name(), TemplateManifest)); err != nil {
+			continue
+		}
+		loaded, err := loadUserTemplate(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		user = append(user, loaded)
+	}
+	slices.SortFunc(user, func(a, b ProjectTemplate) int { return strings.Compare(a.Name, b.Name) })
+
+	for _, loaded := range user {
+		if i := slices.IndexFunc(templates, func(t ProjectTemplate) bool { return t.Name == loaded.Name }); i >= 0 {
+			templates[i] = loaded
+		} else {
+			templates = append(templates, loaded)
+		}
+	}
+	return templates, errs
+}
+
+/**
+ * FindProjectTemplate
+ * Looks a project template up by name, an empty name is the default template
+ * @param name {string} - the template name
+ * @return ProjectTemplate - the template
+ * @return error - an unknown name, or a user template that could not be loaded
+ **/
+func FindProjectTemplate(name string) (ProjectTemplate, error) {
+	if name == "" {
+		name = DefaultProjectTemplate
+	}
+
+	if dir, err := UserTemplatesDir(); err == nil {
+		userDir := filepath.Join(dir, name)
+		if _, err := os.Stat(filepath.Join(userDir, TemplateManifest)); err == nil {
+			return loadUserTemplate(userDir)
+		}
+	}
+
+	var names []string
+	for _, builtin := range builtinProjectTemplates() {
+		if builtin.Name == name {
+			return builtin, nil
+		}
+		names = append(names, builtin.Name)
+	}
+	return ProjectTemplate{}, fmt.Errorf("unknown template '%s', use one of %s or a directory in ~/.config/botbox/templates, see botbox template list", name, strings.Join(names, ", "))
+}
+
+/**
+ * loadUserTemplate
+ * Reads a user template's manifest and merges its files over the built in template it extends
+ * @param dir {string} - the template directory, its name is the template name
+ * @return ProjectTemplate - the template
+ * @return error - a missing or invalid manifest
+ **/
+func loadUserTemplate(dir string) (ProjectTemplate, error) {
+	name := filepath.Base(dir)
+	loaded := ProjectTemplate{Name: name, Source: dir, Variables: []TemplateVariable{}}
+
+	data, err := os.ReadFile(filepath.Join(dir, TemplateManifest))
+	if err != nil {
+		return loaded, fmt.Errorf("template '%s': failed to read %s: %w", name, TemplateManifest, err)
+	}
+	var manifest templateManifest
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&manifest); err != nil {
+		return loaded, fmt.Errorf("template '%s': invalid %s: %w", name, TemplateManifest, err)
+	}
+
+	// User templates build on a built in one, so botbox.conf, main.py, and the core cogs come along unless replaced
+	loaded.Extends = manifest.Extends
+	if loaded.Extends == "" {
+		loaded.Extends = DefaultProjectTemplate
+	}
+	baseIndex := slices.IndexFunc(builtinProjectTemplates(), func(t ProjectTemplate) bool { return t.Name == loaded.Extends })
+	if baseIndex < 0 {
+		return loaded, fmt.Errorf("template '%s': extends unknown built in template '%s'", name, loaded.Extends)
+	}
+	base := builtinProjectTemplates()[baseIndex]
+	loaded.slashOnly = base.slashOnly
+	loaded.Description = manifest.Description
+
+	loaded.Files = base.Files
+	for _, file := range manifest.Files {
+		if err := validateTemplatePath(file.Path); err != nil {
+			return loaded, fmt.Errorf("template '%s': file path %w", name, err)
+		}
+		if err := validateTemplatePath(file.Template); err != nil {
+			return loaded, fmt.Errorf("template '%s': template %w", name, err)
+		}
+		file.dir = dir
+		file.Path = path.Clean(file.Path)
+		loaded.Files = mergeTemplateFile(loaded.Files, file)
+	}
+
+	for i, variable := range manifest.Variables {
+		if !templateVariableName.MatchString(variable.Name) {
+			return loaded, fmt.Errorf("template '%s': variable name '%s' must be a letter or underscore followed by letters, digits, or underscores", name, variable.Name)
+		}
+		if slices.ContainsFunc(manifest.Variables[:i], func(v TemplateVariable) bool { return v.Name == variable.Name }) {
+			return loaded, fmt.Errorf("template '%s': variable '%s' is listed more than once", name, variable.Name)
+		}
+		if variable.Prompt == "" {
+			variable.Prompt = variable.Name
+		}
+		loaded.Variables = append(loaded.Variables, variable)
+	}
+
+	return loaded, nil
+}
+
+// validateTemplatePath keeps the paths of a manifest inside the project or template directory
+func validateTemplatePath(p string) error {
+	if p == "" {
+		return fmt.Errorf("cannot be empty")
+	}
+	clean := path.Clean(filepath.ToSlash(p))
+	if path.IsAbs(clean) || filepath.IsAbs(p) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return fmt.Errorf("'%s' must be relative and stay inside its directory", p)
+	}
+	return nil
+}
+
+// mergeTemplateFile adds a file to a template's files, replacing the file already written to the same path
+func mergeTemplateFile(files []TemplateFile, file TemplateFile) []TemplateFile {
+	merged := slices.Clone(files)
+	if i := slices.IndexFunc(merged, func(f TemplateFile) bool { return f.Path == file.Path }); i >= 0 {
+		merged[i] = file
+		return merged
+	}
+	return append(merged, file)
+}
+
+/**
+ * ResolveVariables
+ * Checks values given for a template's variables and fills in the defaults of the rest
+ * @param given {map[string]string} - the values given, like the --var flags
+ * @return map[string]string - a value for every variable of the template
+ * @return error - a value for a variable the template does not have, or a required variable left empty
+ **/
+func (t ProjectTemplate) ResolveVariables(given map[string]string) (map[string]string, error) {
+	for _, name := range slices.Sorted(maps.Keys(given)) {
+		if !slices.ContainsFunc(t.Variables, func(v TemplateVariable) bool { return v.Name == name }) {
+			return nil, fmt.Errorf("template '%s' has no variable '%s'", t.Name, name)
+		}
+	}
+
+	resolved := map[string]string{}
+	for _, variable := range t.Variables {
+		value, ok := given[variable.Name]
+		if !ok || value == "" {
+			value = variable.Default
+		}
+		if value == "" && variable.Required {
+			return nil, fmt.Errorf("template '%s' needs a value for '%s' (%s)", t.Name, variable.Name, variable.Prompt)
+		}
+		resolved[variable.Name] = value
+	}
+	return resolved, nil
+}
+
+// render renders a template file with the project data, user template files use the same << >> delimiters and helpers
+func (f TemplateFile) render(data projectTemplateData) (string, error) {
+	if f.dir == "" {
+		return RenderTemplate(f.Template, data)
+	}
+
+	source := filepath.Join(f.dir, filepath.FromSlash(f.Template))
+	tmpl, err := parseTemplateFile(source, reflect.TypeOf(data))
+	if err != nil {
+		return "", err
+	}
+	var content strings.Builder
+	if err := tmpl.Execute(&content, data); err != nil {
+		return "", fmt.Errorf("failed to render template %s: %w", source, err)
+	}
+	return content.String(), nil
+}
+
+// cogFileName is the cog file name of a template file in the generator's cogs directory, false for other files
+func (f TemplateFile) cogFileName(generator Generator) (string, bool) {
+	dir, file := path.Split(f.Path)
+	if dir != filepath.ToSlash(generator.CogsDir())+"/" {
+		return "", false
+	}
+	return generator.CogFile(file)
+}
+
+/**
+ * composeProjectConfig
+ * Builds the botbox.conf of a new project with one cog for each cog file the template writes, read from the
+ * rendered source the way config sync reads it so a new project starts in sync with its cog files
+ * @param data {projectTemplateData} - the project values
+ * @param files {[]TemplateFile} - the template's files
+ * @param rendered {map[string]string} - the rendered content of each file by path
+ * @return []byte - the botbox.conf content
+ * @return error - a template that failed to render or a cog file that failed to parse
+ **/
+func composeProjectConfig(data projectTemplateData, files []TemplateFile, rendered map[string]string) ([]byte, error) {
+	content, err := RenderTemplate("botbox.conf.tmpl", data)
+	if err != nil {
+		return nil, err
+	}
+	var config Config
+	if err := json.Unmarshal([]byte(content), &config); err != nil {
+		return nil, fmt.Errorf("failed to parse botbox.conf template: %w", err)
+	}
+
+	generator := pythonGenerator{library: data.Library}
+	config.Cogs = []CogConfig{}
+	for _, file := range files {
+		name, isCog := file.cogFileName(generator)
+		if !isCog {
+			continue
+		}
+		parsed, err := generator.ParseCog(rendered[file.Path], name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cog %s: %w", file.Path, err)
+		}
+		if errs := filterDiagnostics(parsed.Diagnostics, SeverityError); len(errs) > 0 {
+			return nil, fmt.Errorf("failed to read cog %s: %s", file.Path, errs[0].Message)
+		}
+
+		cog := createCogConfigFromParsed(*parsed)
+		// Starter cogs are part of the bot so they load in production, only demo cogs like HelloWorld stay in development
+		cog.Env = "production"
+		if file.env != "" {
+			cog.Env = file.env
+		}
+		if cog.SlashCommands == nil {
+			cog.SlashCommands = []CommandInfo{}
+		}
+		if cog.PrefixCommands == nil {
+			cog.PrefixCommands = []CommandInfo{}
+		}
+		config.Cogs = append(config.Cogs, cog)
+	}
+
+	jsonData, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	return jsonData, nil
+}
+
+/*
+Copyright © 2025 Austin "Choice404" Choi
+
+https://github.com/choice404/botbox
+
+Bot Box
+A discord bot template generator to help create discord bots quickly and easily
+
+This code is licensed under the MIT License.
+
+MIT License
+
+Copyright (c) 2025 Austin Choi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
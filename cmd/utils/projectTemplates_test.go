@@ -0,0 +1,318 @@
+/*
+Copyright © 2025 Austin "Choice404" Choi
+See end of file for extended copyright information
+*/
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// projectTestValues are the values the create form collects for a project without a license or env file
+func projectTestValues(template string, vars string) Values {
+	values := map[string]string{
+		"botName":                "Tester",
+		"botDescription":         "A test bot",
+		"botAuthor":              "tester",
+		"botPrefix":              "!",
+		"envChoice":              "none",
+		"botTokenDopplerProject": "",
+		"botGuildDopplerEnv":     "",
+		"licenseType":            "no-license",
+		"template":               template,
+		"templateVars":           vars,
+	}
+	m := map[string]*string{}
+	for key, value := range values {
+		m[key] = &value
+	}
+	return Values{Map: m, Name: "ModelValues"}
+}
+
+// writeUserTemplate writes a user template into the templates directory of a temporary home
+func writeUserTemplate(t *testing.T, home string, name string, files map[string]string) {
+	t.Helper()
+	dir := filepath.Join(home, ".config", "botbox", "templates", name)
+	for file, content := range files {
+		path := filepath.Join(dir, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+}
+
+const ticketsManifest = `{
+  "description": "Support ticket bot",
+  "extends": "minimal",
+  "files": [
+    {"path": "src/cogs/tickets.py", "template": "tickets.py.tmpl", "executable": true},
+    {"path": "docs/tickets.md", "template": "docs/tickets.md.tmpl"}
+  ],
+  "variables": [
+    {"name": "channel", "prompt": "Ticket channel name", "default": "tickets"},
+    {"name": "role", "prompt": "Support role", "required": true}
+  ]
+}`
+
+const ticketsCog = `import discord
+from discord import app_commands
+from discord.ext import commands
+
+class Tickets(commands.Cog):
+    def __init__(self, bot):
+        self.bot = bot
+
+    @app_commands.command(name="open", description="Opens a ticket in <<.Vars.channel>>")
+    async def open(self, interaction: discord.Interaction):
+        await interaction.response.send_message("<<.Vars.role>> will help")
+
+async def setup(bot):
+    await bot.add_cog(Tickets(bot))
+`
+
+func TestCreateProjectBuiltinTemplates(t *testing.T) {
+	HeadlessMode = true
+	t.Cleanup(func() { HeadlessMode = false })
+
+	tests := []struct {
+		template  string
+		cogs      []string
+		slashOnly bool
+	}{
+		{"default", []string{"admin", "cogs", "help", "helloWorld"}, false},
+		{"minimal", []string{"cogs", "help"}, false},
+		{"moderation", []string{"admin", "cogs", "help", "moderation"}, false},
+		{"utility", []string{"admin", "cogs", "help", "utility"}, false},
+		{"slash-only", []string{"admin", "cogs", "help", "helloWorld"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			rootDir := t.TempDir()
+
+			if _, err := CreateProject(rootDir, projectTestValues(tt.template, ""), false); err != nil {
+				t.Fatalf("CreateProject returned error: %v", err)
+			}
+
+			var config Config
+			readJSONFile(t, filepath.Join(rootDir, "botbox.conf"), &config)
+			var cogs []string
+			for _, cog := range config.Cogs {
+				cogs = append(cogs, cog.File)
+			}
+			if !slices.Equal(cogs, tt.cogs) {
+				t.Errorf("botbox.conf cogs = %v, want %v", cogs, tt.cogs)
+			}
+			files, _ := filepath.Glob(filepath.Join(rootDir, "src", "cogs", "*.py"))
+			if len(files) != len(tt.cogs)+1 {
+				t.Errorf("wrote %d cog files, want %d and __init__.py", len(files), len(tt.cogs))
+			}
+
+			mainFile := readOutput(t, filepath.Join(rootDir, "src", "main.py"))
+			if got := strings.Contains(mainFile, "commands.when_mentioned"); got != tt.slashOnly {
+				t.Errorf("main.py uses when_mentioned = %v, want %v", got, tt.slashOnly)
+			}
+			if got := strings.Contains(mainFile, "message_content = True"); got == tt.slashOnly {
+				t.Errorf("main.py enables message content = %v, want %v", got, !tt.slashOnly)
+			}
+
+			// A new project starts in sync with its cog files
+			t.Chdir(rootDir)
+			result, err := StageCogSync(&ChangeSet{})
+			if err != nil {
+				t.Fatalf("StageCogSync returned error: %v", err)
+			}
+			if len(result.UpdatedCogs) != 0 || len(result.AddedCogs) != 0 || len(result.RemovedCogs) != 0 {
+				t.Errorf("new project drifted from its cog files: %+v", result)
+			}
+		})
+	}
+}
+
+func TestCreateProjectUserTemplate(t *testing.T) {
+	HeadlessMode = true
+	t.Cleanup(func() { HeadlessMode = false })
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeUserTemplate(t, home, "tickets", map[string]string{
+		TemplateManifest:       ticketsManifest,
+		"tickets.py.tmpl":      ticketsCog,
+		"docs/tickets.md.tmpl": "<<.Name>> tickets go to <<.Vars.channel>>\n",
+	})
+
+	ticketsTemplate, err := FindProjectTemplate("tickets")
+	if err != nil {
+		t.Fatalf("FindProjectTemplate returned error: %v", err)
+	}
+	if ticketsTemplate.Extends != "minimal" || len(ticketsTemplate.Files) != 4 {
+		t.Errorf("template should add its two files to minimal's, got %+v", ticketsTemplate)
+	}
+	if _, err := ticketsTemplate.ResolveVariables(map[string]string{}); err == nil {
+		t.Error("expected an error for the missing required variable")
+	}
+	if _, err := ticketsTemplate.ResolveVariables(map[string]string{"role": "Staff", "color": "red"}); err == nil {
+		t.Error("expected an error for a variable the template does not have")
+	}
+
+	rootDir := t.TempDir()
+	if _, err := CreateProject(rootDir, projectTestValues("tickets", `{"role": "Staff"}`), false); err != nil {
+		t.Fatalf("CreateProject returned error: %v", err)
+	}
+
+	if got := readOutput(t, filepath.Join(rootDir, "docs", "tickets.md")); got != "Tester tickets go to tickets\n" {
+		t.Errorf("docs/tickets.md = %q, the default channel should be rendered", got)
+	}
+	if got := readOutput(t, filepath.Join(rootDir, "src", "cogs", "tickets.py")); !strings.Contains(got, "Staff will help") {
+		t.Errorf("tickets.py should render the role variable:\n%s", got)
+	}
+	info, err := os.Stat(filepath.Join(rootDir, "src", "cogs", "tickets.py"))
+	if err != nil || info.Mode().Perm() != 0755 {
+		t.Errorf("tickets.py should be executable, got %v %v", info.Mode(), err)
+	}
+
+	var config Config
+	readJSONFile(t, filepath.Join(rootDir, "botbox.conf"), &config)
+	i := slices.IndexFunc(config.Cogs, func(cog CogConfig) bool { return cog.File == "tickets" })
+	if i < 0 {
+		t.Fatalf("botbox.conf should list the tickets cog, got %+v", config.Cogs)
+	}
+	if cog := config.Cogs[i]; cog.Name != "Tickets" || cog.Env != "production" || len(cog.SlashCommands) != 1 {
+		t.Errorf("tickets cog = %+v, want the open command read from the cog file", cog)
+	}
+}
+
+func TestListProjectTemplatesReportsBrokenUserTemplates(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeUserTemplate(t, home, "tickets", map[string]string{TemplateManifest: ticketsManifest})
+	writeUserTemplate(t, home, "broken", map[string]string{TemplateManifest: `{"descripton": "typo"}`})
+	// Directories without a manifest and loose files are template overrides, not project templates
+	writeUserTemplate(t, home, "partials", map[string]string{"cog.py.tmpl": ""})
+	writeUserTemplate(t, home, ".", map[string]string{"cog.py.tmpl": ""})
+
+	templates, errs := ListProjectTemplates()
+	var names []string
+	for _, projectTemplate := range templates {
+		names = append(names, projectTemplate.Name)
+	}
+	want := []string{"default", "minimal", "moderation", "utility", "slash-only", "tickets"}
+	if !slices.Equal(names, want) {
+		t.Errorf("ListProjectTemplates() = %v, want %v", names, want)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "broken") {
+		t.Errorf("expected one error naming the broken template, got %v", errs)
+	}
+}
+
+func TestLoadUserTemplateRejectsBadManifests(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		wantErr  string
+	}{
+		{"unknown field", `{"descripton": "typo"}`, "unknown field"},
+		{"unknown base", `{"extends": "huge"}`, "unknown built in template"},
+		{"absolute path", `{"files": [{"path": "/etc/passwd", "template": "a.tmpl"}]}`, "must be relative"},
+		{"escaping path", `{"files": [{"path": "../outside.py", "template": "a.tmpl"}]}`, "must be relative"},
+		{"escaping template", `{"files": [{"path": "a.py", "template": "../../a.tmpl"}]}`, "must be relative"},
+		{"bad variable name", `{"variables": [{"name": "bot-name"}]}`, "variable name"},
+		{"duplicate variable", `{"variables": [{"name": "a"}, {"name": "a"}]}`, "more than once"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "bad")
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				t.Fatalf("failed to create template directory: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, TemplateManifest), []byte(tt.manifest), 0644); err != nil {
+				t.Fatalf("failed to write manifest: %v", err)
+			}
+			_, err := loadUserTemplate(dir)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("loadUserTemplate() error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateTemplateVariablesForm(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeUserTemplate(t, home, "tickets", map[string]string{TemplateManifest: ticketsManifest})
+
+	forms := CreateFormWrapperGenerator()
+	index := slices.IndexFunc(forms, func(form FormWrapper) bool { return form.Name == "Template Variables" })
+	if index < 0 {
+		t.Fatal("the create flow should have a template variables form")
+	}
+	modelValues := CreateModel(nil).ModelValues
+
+	// The picker starts on the default template
+	forms[0].Form(forms[0].Values, modelValues)
+	if got := *forms[0].Values.Map["template"]; got != DefaultProjectTemplate {
+		t.Errorf("template picker starts on %q, want %q", got, DefaultProjectTemplate)
+	}
+
+	*modelValues.Map["template"] = "moderation"
+	if !forms[index].SkipCondition(modelValues, forms, index) {
+		t.Error("a template without variables should skip the variables form")
+	}
+
+	*modelValues.Map["template"] = "tickets"
+	if forms[index].SkipCondition(modelValues, forms, index) {
+		t.Error("a template with variables should show the variables form")
+	}
+	forms[index].Form(forms[index].Values, modelValues)
+	if got := *forms[index].Values.Map["channel"]; got != "tickets" {
+		t.Errorf("channel should start at its default, got %q", got)
+	}
+	*forms[index].Values.Map["role"] = "Staff"
+	forms[index].Callback(forms[index].Values, modelValues, forms)
+	if got := *modelValues.Map["templateVars"]; got != `{"channel":"tickets","role":"Staff"}` {
+		t.Errorf("templateVars = %s", got)
+	}
+}
+
+/*
+Copyright © 2025 Austin "Choice404" Choi
+
+https://github.com/choice404/botbox
+
+Bot Box
+A discord bot template generator to help create discord bots quickly and easily
+
+This code is licensed under the MIT License.
+
+MIT License
+
+Copyright (c) 2025 Austin Choi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
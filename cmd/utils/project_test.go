@@ -62,6 +62,10 @@ func TestGenerateDockerFilesEnv(t *testing.T) {
 	if !strings.Contains(ignore, ".env") || !strings.Contains(ignore, "venv/") {
 		t.Errorf(".dockerignore missing expected entries:\n%s", ignore)
 	}
+	// Template overrides in .botbox/templates are part of the build, only the backups stay out
+	if !strings.Contains(ignore, ".botbox/backups/") || strings.Contains(ignore, ".botbox/\n") {
+		t.Errorf(".dockerignore should leave out .botbox/backups/ and nothing else of .botbox:\n%s", ignore)
+	}
 }
 
 func TestGenerateDockerFilesDoppler(t *testing.T) {
@@ -0,0 +1,828 @@
+/*
+Copyright © 2025 Austin "Choice404" Choi
+See end of file for extended copyright information
+*/
+
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// pyModule is a parsed Python file
+type pyModule struct {
+	Docstring string
+	Body      []pyStmt
+}
+
+// pyStmt is one statement, Tokens holds a simple statement whole or the header of a compound
+// statement through its colon, Body holds the indented block of a compound statement
+type pyStmt struct {
+	Tokens []pyToken
+	Body   []pyStmt
+	// Func and Class are set for function and class definitions
+	Func  *pyFunc
+	Class *pyClass
+}
+
+// Source is the statement, or the header of a compound statement, rendered by tokensSource
+func (s pyStmt) Source() string {
+	return tokensSource(s.Tokens)
+}
+
+// Line is the line the statement starts on
+func (s pyStmt) Line() int {
+	if len(s.Tokens) == 0 {
+		return 0
+	}
+	return s.Tokens[0].Line
+}
+
+// keyword returns the first word of the statement, like if, try, or return
+func (s pyStmt) keyword() string {
+	if len(s.Tokens) == 0 || s.Tokens[0].Kind != pyName {
+		return ""
+	}
+	return s.Tokens[0].Text
+}
+
+// expression parses a simple statement as an expression, like a call made for what it does
+func (s pyStmt) expression() pyExpr {
+	if s.Body != nil {
+		return pyExpr{}
+	}
+	return parseExpr(s.Tokens)
+}
+
+// assignment splits a simple target = value statement, ok is false for any other statement
+func (s pyStmt) assignment() (target, value pyExpr, ok bool) {
+	if s.Body != nil {
+		return target, value, false
+	}
+	depth := 0
+	for i, tok := range s.Tokens {
+		switch {
+		case tok.is("(") || tok.is("[") || tok.is("{"):
+			depth++
+		case tok.is(")") || tok.is("]") || tok.is("}"):
+			depth--
+		case depth == 0 && tok.is("="):
+			if i == 0 || i == len(s.Tokens)-1 {
+				return target, value, false
+			}
+			return parseExpr(s.Tokens[:i]), parseExpr(s.Tokens[i+1:]), true
+		}
+	}
+	return target, value, false
+}
+
+// pyFunc is a function definition
+type pyFunc struct {
+	Name       string
+	Async      bool
+	Params     []pyParam
+	Returns    *pyExpr
+	Decorators []pyExpr
+	Docstring  string
+	Body       []pyStmt
+	Line       int
+	Col        int
+}
+
+// pyParam is one parameter of a function, Star is * or ** for *args and **kwargs,
+// a bare * separating keyword only parameters has no Name
+type pyParam struct {
+	Name       string
+	Star       string
+	Annotation *pyExpr
+	Default    *pyExpr
+	Line       int
+	Col        int
+}
+
+// pyClass is a class definition
+type pyClass struct {
+	Name       string
+	Bases      []pyExpr
+	Keywords   []pyKeyword
+	Decorators []pyExpr
+	Docstring  string
+	Body       []pyStmt
+	Line       int
+	Col        int
+}
+
+// pyExprKind is the shape of an expression, anything the cog parser never looks into is pyExprOther
+type pyExprKind int
+
+const (
+	pyExprOther pyExprKind = iota
+	pyExprName
+	pyExprAttr
+	pyExprCall
+	pyExprSubscript
+	pyExprString
+	pyExprNumber
+	pyExprList
+	pyExprTuple
+	pyExprDict
+)
+
+// pyExpr is an expression. Name is set for names and attributes, Text for decoded strings and numbers,
+// Value is what an attribute, call, or subscript applies to, Args holds call arguments, subscripts,
+// list and tuple items, and the keys and values of a dict in turn. Source is the expression rendered by tokensSource
+type pyExpr struct {
+	Kind     pyExprKind
+	Name     string
+	Text     string
+	Value    *pyExpr
+	Args     []pyExpr
+	Keywords []pyKeyword
+	Source   string
+	Line     int
+	Col      int
+}
+
+// pyKeyword is a keyword argument of a call or class definition
+type pyKeyword struct {
+	Name  string
+	Value pyExpr
+}
+
+// Keyword returns the keyword argument of a call
+func (e pyExpr) Keyword(name string) (pyExpr, bool) {
+	for _, keyword := range e.Keywords {
+		if keyword.Name == name {
+			return keyword.Value, true
+		}
+	}
+	return pyExpr{}, false
+}
+
+// stringKeyword returns the text of a keyword argument written as a string literal
+func (e pyExpr) stringKeyword(name string) (string, bool) {
+	value, ok := e.Keyword(name)
+	if !ok || value.Kind != pyExprString {
+		return "", false
+	}
+	return value.Text, true
+}
+
+// Callee is the source of what a call calls, like app_commands.command, or the source of any other expression
+func (e pyExpr) Callee() string {
+	if e.Kind == pyExprCall {
+		return e.Value.Source
+	}
+	return e.Source
+}
+
+// literal reads an expression the way the config stores it, None is empty, booleans are lowercase,
+// strings are decoded, and anything else keeps its source
+func (e pyExpr) literal() string {
+	switch {
+	case e.Kind == pyExprString:
+		return e.Text
+	case e.Kind == pyExprName && e.Name == "None":
+		return ""
+	case e.Kind == pyExprName && e.Name == "True":
+		return "true"
+	case e.Kind == pyExprName && e.Name == "False":
+		return "false"
+	}
+	return e.Source
+}
+
+// pyParser builds statements from the tokens of a file
+type pyParser struct {
+	tokens []pyToken
+	pos    int
+}
+
+/**
+ * parsePython
+ * Parses Python source into its statements, function and class definitions, and docstrings.
+ * Expressions are only taken apart as far as the cog parser reads them
+ * @param src {string} - the Python source
+ * @return *pyModule - the parsed module
+ * @return error - a *PySyntaxError with the line and column of the problem
+ **/
+func parsePython(src string) (*pyModule, error) {
+	tokens, err := tokenizePython(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &pyParser{tokens: tokens}
+	body, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.Kind != pyEOF {
+		return nil, p.errorAt(tok, "unindent does not match any outer indentation level")
+	}
+	return &pyModule{Docstring: docstring(body), Body: body}, nil
+}
+
+func (p *pyParser) peek() pyToken {
+	return p.tokens[p.pos]
+}
+
+func (p *pyParser) next() pyToken {
+	tok := p.tokens[p.pos]
+	if tok.Kind != pyEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *pyParser) errorAt(tok pyToken, format string, args ...any) error {
+	return &PySyntaxError{Line: tok.Line, Column: tok.Col, Message: fmt.Sprintf(format, args...)}
+}
+
+// block reads statements until the end of the current indented block
+func (p *pyParser) block() ([]pyStmt, error) {
+	var body []pyStmt
+	for {
+		tok := p.peek()
+		switch tok.Kind {
+		case pyEOF, pyDedent:
+			return body, nil
+		case pyIndent:
+			return nil, p.errorAt(tok, "unexpected indent")
+		}
+		stmts, err := p.statement()
+		if err != nil {
+			return nil, err
+		}
+		body = append(body, stmts...)
+	}
+}
+
+// logicalLine reads the tokens up to the end of the logical line, consuming its newline
+func (p *pyParser) logicalLine() []pyToken {
+	start := p.pos
+	for p.peek().Kind != pyNewline && p.peek().Kind != pyEOF {
+		p.pos++
+	}
+	line := p.tokens[start:p.pos]
+	p.next()
+	return line
+}
+
+// statement reads one logical line, decorators included, and the block that belongs to it.
+// Semicolons make one line hold several statements
+func (p *pyParser) statement() ([]pyStmt, error) {
+	var decorators []pyExpr
+	for p.peek().is("@") {
+		at := p.next()
+		line := p.logicalLine()
+		if len(line) == 0 {
+			return nil, p.errorAt(at, "expected an expression after '@'")
+		}
+		decorators = append(decorators, parseExpr(line))
+		if p.peek().Kind == pyIndent || p.peek().Kind == pyDedent {
+			return nil, p.errorAt(p.peek(), "expected a function or class definition after the decorator on line %d", at.Line)
+		}
+	}
+
+	first := p.peek()
+	line := p.logicalLine()
+
+	isDef := len(line) > 0 && (line[0].is("def") || line[0].is("class") || line[0].is("async") && len(line) > 1 && line[1].is("def"))
+	if len(decorators) > 0 && !isDef {
+		return nil, p.errorAt(first, "expected a function or class definition after a decorator")
+	}
+
+	colon := headerColon(line)
+	if colon < 0 || !isCompoundKeyword(line[0]) {
+		if isDef {
+			return nil, p.errorAt(line[len(line)-1], "expected ':'")
+		}
+		return splitSimpleStatements(line), nil
+	}
+
+	stmt := pyStmt{Tokens: line[:colon+1]}
+	if colon < len(line)-1 {
+		// A body on the same line as its header, like if x: return
+		stmt.Body = splitSimpleStatements(line[colon+1:])
+	} else {
+		if p.peek().Kind != pyIndent {
+			return nil, p.errorAt(p.peek(), "expected an indented block after '%s' statement on line %d", line[0].Text, line[0].Line)
+		}
+		p.next()
+		body, err := p.block()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().Kind == pyDedent {
+			p.next()
+		}
+		stmt.Body = body
+	}
+
+	var err error
+	switch {
+	case line[0].is("class"):
+		stmt.Class, err = parseClassHeader(line[:colon], decorators, stmt.Body)
+	case isDef:
+		stmt.Func, err = parseFuncHeader(line[:colon], decorators, stmt.Body)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []pyStmt{stmt}, nil
+}
+
+// isCompoundKeyword reports whether a line starting with tok opens a block
+func isCompoundKeyword(tok pyToken) bool {
+	if tok.Kind != pyName {
+		return false
+	}
+	switch tok.Text {
+	case "if", "elif", "else", "for", "while", "try", "except", "finally", "with", "def", "class", "async", "match", "case":
+		return true
+	}
+	return false
+}
+
+// headerColon finds the colon ending a block header, colons inside brackets and lambdas are skipped
+func headerColon(line []pyToken) int {
+	depth, lambdas := 0, 0
+	for i, tok := range line {
+		switch {
+		case tok.Kind != pyOp && tok.Kind != pyName:
+		case tok.is("(") || tok.is("[") || tok.is("{"):
+			depth++
+		case tok.is(")") || tok.is("]") || tok.is("}"):
+			depth--
+		case depth == 0 && tok.is("lambda"):
+			lambdas++
+		case depth == 0 && tok.is(":"):
+			if lambdas > 0 {
+				lambdas--
+				continue
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+// splitSimpleStatements splits a line on its semicolons
+func splitSimpleStatements(line []pyToken) []pyStmt {
+	var stmts []pyStmt
+	for _, part := range splitTopLevel(line, ";") {
+		stmts = append(stmts, pyStmt{Tokens: part})
+	}
+	return stmts
+}
+
+// splitTopLevel splits tokens on a separator outside any brackets, a trailing separator adds no empty part
+func splitTopLevel(tokens []pyToken, sep string) [][]pyToken {
+	var parts [][]pyToken
+	depth, start := 0, 0
+	for i, tok := range tokens {
+		switch {
+		case tok.Kind != pyOp:
+		case tok.Text == "(" || tok.Text == "[" || tok.Text == "{":
+			depth++
+		case tok.Text == ")" || tok.Text == "]" || tok.Text == "}":
+			depth--
+		case depth == 0 && tok.Text == sep:
+			parts = append(parts, tokens[start:i])
+			start = i + 1
+		}
+	}
+	if start < len(tokens) {
+		parts = append(parts, tokens[start:])
+	}
+	return parts
+}
+
+// matchingBracket returns the index of the bracket closing the one at open, -1 when it is never closed
+func matchingBracket(tokens []pyToken, open int) int {
+	depth := 0
+	for i := open; i < len(tokens); i++ {
+		tok := tokens[i]
+		if tok.Kind != pyOp {
+			continue
+		}
+		switch tok.Text {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// parseFuncHeader reads the name, parameters, and return annotation of a def header
+func parseFuncHeader(header []pyToken, decorators []pyExpr, body []pyStmt) (*pyFunc, error) {
+	fn := &pyFunc{Decorators: decorators, Body: body, Docstring: docstring(body), Line: header[0].Line, Col: header[0].Col}
+	i := 0
+	if header[i].is("async") {
+		fn.Async = true
+		i++
+	}
+	i++ // def
+	if i >= len(header) || header[i].Kind != pyName || pyKeywords[header[i].Text] {
+		return nil, syntaxErrorAfter(header, i, "expected a function name")
+	}
+	fn.Name = header[i].Text
+	i++
+	if i >= len(header) || !header[i].is("(") {
+		return nil, syntaxErrorAfter(header, i, "expected '(' after the function name")
+	}
+	closeParen := matchingBracket(header, i)
+	for _, part := range splitTopLevel(header[i+1:closeParen], ",") {
+		param, ok := parseParam(part)
+		if !ok {
+			return nil, &PySyntaxError{Line: part[0].Line, Column: part[0].Col, Message: "invalid parameter"}
+		}
+		if param.Name != "" || param.Star != "" {
+			fn.Params = append(fn.Params, param)
+		}
+	}
+	rest := header[closeParen+1:]
+	if len(rest) > 0 {
+		if !rest[0].is("->") || len(rest) == 1 {
+			return nil, &PySyntaxError{Line: rest[0].Line, Column: rest[0].Col, Message: "expected ':'"}
+		}
+		returns := parseExpr(rest[1:])
+		fn.Returns = &returns
+	}
+	return fn, nil
+}
+
+// parseParam reads one parameter, the / ending positional only parameters has neither name nor star
+func parseParam(tokens []pyToken) (pyParam, bool) {
+	var param pyParam
+	if len(tokens) == 1 && tokens[0].is("/") {
+		return param, true
+	}
+	if tokens[0].is("*") || tokens[0].is("**") {
+		param.Star = tokens[0].Text
+		tokens = tokens[1:]
+		if len(tokens) == 0 {
+			return param, param.Star == "*"
+		}
+	}
+	if tokens[0].Kind != pyName || pyKeywords[tokens[0].Text] {
+		return param, false
+	}
+	param.Name = tokens[0].Text
+	param.Line, param.Col = tokens[0].Line, tokens[0].Col
+	tokens = tokens[1:]
+
+	equals := -1
+	depth := 0
+	for i, tok := range tokens {
+		switch {
+		case tok.is("(") || tok.is("[") || tok.is("{"):
+			depth++
+		case tok.is(")") || tok.is("]") || tok.is("}"):
+			depth--
+		case depth == 0 && tok.is("="):
+			equals = i
+		}
+		if equals >= 0 {
+			break
+		}
+	}
+	annotation := tokens
+	if equals >= 0 {
+		if equals == len(tokens)-1 {
+			return param, false
+		}
+		value := parseExpr(tokens[equals+1:])
+		param.Default = &value
+		annotation = tokens[:equals]
+	}
+	if len(annotation) > 0 {
+		if !annotation[0].is(":") || len(annotation) == 1 {
+			return param, false
+		}
+		value := parseExpr(annotation[1:])
+		param.Annotation = &value
+	}
+	return param, true
+}
+
+// parseClassHeader reads the name, bases, and keywords of a class header
+func parseClassHeader(header []pyToken, decorators []pyExpr, body []pyStmt) (*pyClass, error) {
+	class := &pyClass{Decorators: decorators, Body: body, Docstring: docstring(body), Line: header[0].Line, Col: header[0].Col}
+	if len(header) < 2 || header[1].Kind != pyName || pyKeywords[header[1].Text] {
+		return nil, syntaxErrorAfter(header, 1, "expected a class name")
+	}
+	class.Name = header[1].Text
+	if len(header) == 2 {
+		return class, nil
+	}
+	if !header[2].is("(") || matchingBracket(header, 2) != len(header)-1 {
+		return nil, &PySyntaxError{Line: header[2].Line, Column: header[2].Col, Message: "expected ':'"}
+	}
+	class.Bases, class.Keywords = parseArguments(header[3 : len(header)-1])
+	return class, nil
+}
+
+// syntaxErrorAfter reports a problem at header[i], or just past the header when it ends early
+func syntaxErrorAfter(header []pyToken, i int, message string) error {
+	if i < len(header) {
+		return &PySyntaxError{Line: header[i].Line, Column: header[i].Col, Message: message}
+	}
+	last := header[len(header)-1]
+	return &PySyntaxError{Line: last.Line, Column: last.Col + utf8.RuneCountInString(last.Text), Message: message}
+}
+
+// docstring returns the text of the string a block starts with, empty when it starts with anything else
+func docstring(body []pyStmt) string {
+	if len(body) == 0 || body[0].Func != nil || body[0].Class != nil || body[0].Body != nil {
+		return ""
+	}
+	text, ok := stringTokens(body[0].Tokens)
+	if !ok {
+		return ""
+	}
+	return text
+}
+
+// bodyWithoutDocstring returns a block without its docstring
+func bodyWithoutDocstring(body []pyStmt) []pyStmt {
+	if len(body) > 0 && body[0].Body == nil && body[0].Func == nil && body[0].Class == nil {
+		if _, ok := stringTokens(body[0].Tokens); ok {
+			return body[1:]
+		}
+	}
+	return body
+}
+
+// walkStatements visits every statement of a block and of the blocks nested in it, in source order
+func walkStatements(body []pyStmt, visit func(stmt pyStmt)) {
+	for _, stmt := range body {
+		visit(stmt)
+		walkStatements(stmt.Body, visit)
+	}
+}
+
+// flattenStatements lists every statement of a block and of the blocks nested in it, in source order
+func flattenStatements(body []pyStmt) []pyStmt {
+	var stmts []pyStmt
+	walkStatements(body, func(stmt pyStmt) {
+		stmts = append(stmts, stmt)
+	})
+	return stmts
+}
+
+// stringTokens decodes a run of adjacent string literals, ok is false when tokens holds anything else
+func stringTokens(tokens []pyToken) (string, bool) {
+	if len(tokens) == 0 {
+		return "", false
+	}
+	var b strings.Builder
+	for _, tok := range tokens {
+		if tok.Kind != pyString {
+			return "", false
+		}
+		b.WriteString(decodePyString(tok.Text))
+	}
+	return b.String(), true
+}
+
+/**
+ * parseExpr
+ * Parses the tokens of an expression, names, attributes, calls, subscripts, and literals are
+ * taken apart and any other expression is kept whole as pyExprOther
+ * @param tokens {[]pyToken} - the tokens of the expression
+ * @return pyExpr - the parsed expression
+ **/
+func parseExpr(tokens []pyToken) pyExpr {
+	if len(tokens) == 0 {
+		return pyExpr{}
+	}
+	if expr, n, ok := parsePrimary(tokens); ok && n == len(tokens) {
+		return expr
+	}
+	return pyExpr{Kind: pyExprOther, Source: tokensSource(tokens), Line: tokens[0].Line, Col: tokens[0].Col}
+}
+
+// parsePrimary parses the atom tokens start with and every attribute, call, and subscript after it,
+// n is how many tokens it used
+func parsePrimary(tokens []pyToken) (expr pyExpr, n int, ok bool) {
+	first := tokens[0]
+	expr = pyExpr{Line: first.Line, Col: first.Col}
+
+	switch {
+	case first.Kind == pyName && (!pyKeywords[first.Text] || first.Text == "await"):
+		if first.Text == "await" {
+			return expr, 0, false
+		}
+		expr.Kind, expr.Name, n = pyExprName, first.Text, 1
+	case first.Kind == pyNumber:
+		expr.Kind, expr.Text, n = pyExprNumber, first.Text, 1
+	case first.is("-") && len(tokens) > 1 && tokens[1].Kind == pyNumber:
+		expr.Kind, expr.Text, n = pyExprNumber, "-"+tokens[1].Text, 2
+	case first.Kind == pyString:
+		for n < len(tokens) && tokens[n].Kind == pyString {
+			n++
+		}
+		expr.Kind = pyExprString
+		expr.Text, _ = stringTokens(tokens[:n])
+	case first.is("(") || first.is("["):
+		closing := matchingBracket(tokens, 0)
+		if closing < 0 {
+			return expr, 0, false
+		}
+		inner := tokens[1:closing]
+		n = closing + 1
+		items := splitTopLevel(inner, ",")
+		if first.is("(") && len(items) == 1 && !inner[len(inner)-1].is(",") {
+			// Parentheses around a single expression only group it
+			expr = parseExpr(inner)
+			break
+		}
+		expr.Kind = pyExprTuple
+		if first.is("[") {
+			expr.Kind = pyExprList
+		}
+		for _, item := range items {
+			expr.Args = append(expr.Args, parseExpr(item))
+		}
+	case first.is("{"):
+		closing := matchingBracket(tokens, 0)
+		if closing < 0 {
+			return expr, 0, false
+		}
+		n = closing + 1
+		expr.Kind = pyExprDict
+		// Sets and comprehensions have items that are not a single key: value pair, only dicts are read
+		for _, item := range splitTopLevel(tokens[1:closing], ",") {
+			pair := splitTopLevel(item, ":")
+			if len(pair) != 2 || len(pair[0]) == 0 || len(pair[1]) == 0 {
+				return expr, 0, false
+			}
+			expr.Args = append(expr.Args, parseExpr(pair[0]), parseExpr(pair[1]))
+		}
+	default:
+		return expr, 0, false
+	}
+	expr.Source = tokensSource(tokens[:n])
+
+	for n < len(tokens) {
+		tok := tokens[n]
+		inner := expr
+		switch {
+		case tok.is(".") && n+1 < len(tokens) && tokens[n+1].Kind == pyName:
+			expr = pyExpr{Kind: pyExprAttr, Name: tokens[n+1].Text, Value: &inner}
+			n += 2
+		case tok.is("(") || tok.is("["):
+			closing := matchingBracket(tokens, n)
+			if closing < 0 {
+				return expr, n, true
+			}
+			if tok.is("(") {
+				expr = pyExpr{Kind: pyExprCall, Value: &inner}
+				expr.Args, expr.Keywords = parseArguments(tokens[n+1 : closing])
+			} else {
+				expr = pyExpr{Kind: pyExprSubscript, Value: &inner}
+				for _, item := range splitTopLevel(tokens[n+1:closing], ",") {
+					expr.Args = append(expr.Args, parseExpr(item))
+				}
+			}
+			n = closing + 1
+		default:
+			return expr, n, true
+		}
+		expr.Line, expr.Col = first.Line, first.Col
+		expr.Source = tokensSource(tokens[:n])
+	}
+	return expr, n, true
+}
+
+// parseArguments splits the arguments of a call into positional and keyword arguments
+func parseArguments(tokens []pyToken) ([]pyExpr, []pyKeyword) {
+	var args []pyExpr
+	var keywords []pyKeyword
+	for _, part := range splitTopLevel(tokens, ",") {
+		if len(part) > 2 && part[0].Kind == pyName && part[1].is("=") {
+			keywords = append(keywords, pyKeyword{Name: part[0].Text, Value: parseExpr(part[2:])})
+			continue
+		}
+		args = append(args, parseExpr(part))
+	}
+	return args, keywords
+}
+
+/**
+ * decodePyString
+ * Decodes a string literal to its text, raw strings keep their backslashes and f-strings keep their
+ * replacement fields as written
+ * @param raw {string} - the literal as written, prefix and quotes included
+ * @return string - the text of the string
+ **/
+func decodePyString(raw string) string {
+	quoteAt := strings.IndexAny(raw, `"'`)
+	if quoteAt < 0 {
+		return raw
+	}
+	prefix := strings.ToLower(raw[:quoteAt])
+	quote := raw[quoteAt : quoteAt+1]
+	if strings.HasPrefix(raw[quoteAt:], strings.Repeat(quote, 3)) && len(raw)-quoteAt >= 6 {
+		quote = strings.Repeat(quote, 3)
+	}
+	body := raw[quoteAt+len(quote) : len(raw)-len(quote)]
+	if strings.Contains(prefix, "r") {
+		return body
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		if c != '\\' || i == len(body)-1 {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		switch esc := body[i]; esc {
+		case '\n':
+		case '\\', '\'', '"':
+			b.WriteByte(esc)
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case 'a':
+			b.WriteByte('\a')
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'v':
+			b.WriteByte('\v')
+		case '0', '1', '2', '3', '4', '5', '6', '7':
+			end := i + 1
+			for end < len(body) && end < i+3 && body[end] >= '0' && body[end] <= '7' {
+				end++
+			}
+			value, _ := strconv.ParseUint(body[i:end], 8, 32)
+			b.WriteRune(rune(value))
+			i = end - 1
+		case 'x', 'u', 'U':
+			width := map[byte]int{'x': 2, 'u': 4, 'U': 8}[esc]
+			if i+1+width <= len(body) {
+				if value, err := strconv.ParseUint(body[i+1:i+1+width], 16, 32); err == nil {
+					b.WriteRune(rune(value))
+					i += width
+					continue
+				}
+			}
+			b.WriteByte('\\')
+			b.WriteByte(esc)
+		default:
+			// Unknown escapes keep their backslash, like Python does
+			b.WriteByte('\\')
+			b.WriteByte(esc)
+		}
+	}
+	return b.String()
+}
+
+/*
+Copyright © 2025 Austin "Choice404" Choi
+
+https://github.com/choice404/botbox
+
+Bot Box
+A discord bot template generator to help create discord bots quickly and easily
+
+This code is licensed under the MIT License.
+
+MIT License
+
+Copyright (c) 2025 Austin Choi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
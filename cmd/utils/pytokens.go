@@ -0,0 +1,578 @@
+/*
+Copyright © 2025 Austin "Choice404" Choi
+See end of file for extended copyright information
+*/
+
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PySyntaxError is a problem the cog parser found in a Python file, Line and Column are 1 based
+type PySyntaxError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *PySyntaxError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Message)
+}
+
+// pyTokenKind is the kind of a Python token
+type pyTokenKind int
+
+const (
+	pyName pyTokenKind = iota
+	pyNumber
+	pyString
+	pyOp
+	// pyNewline ends a logical line, lines joined inside brackets or by a backslash end with a single one
+	pyNewline
+	pyIndent
+	pyDedent
+	pyEOF
+)
+
+// pyToken is one token of a Python file, Text is its source text and strings keep their prefix and quotes
+type pyToken struct {
+	Kind pyTokenKind
+	Text string
+	Line int
+	Col  int
+}
+
+// is reports whether the token is the given operator or name
+func (t pyToken) is(text string) bool {
+	return (t.Kind == pyOp || t.Kind == pyName) && t.Text == text
+}
+
+// Operators, longest first so a longer operator is never read as two shorter ones
+var pyOperators = []string{
+	"**=", "//=", ">>=", "<<=", "...",
+	"->", "**", "//", "<<", ">>", "<=", ">=", "==", "!=", ":=",
+	"+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=", "@=",
+	"+", "-", "*", "/", "%", "@", "&", "|", "^", "~", "<", ">",
+	"(", ")", "[", "]", "{", "}", ",", ":", ".", ";", "=",
+}
+
+// pyKeywords are the reserved words of Python, a keyword is never a call or subscript target
+var pyKeywords = map[string]bool{
+	"and": true, "as": true, "assert": true, "async": true, "await": true, "break": true, "class": true,
+	"continue": true, "def": true, "del": true, "elif": true, "else": true, "except": true, "finally": true,
+	"for": true, "from": true, "global": true, "if": true, "import": true, "in": true, "is": true,
+	"lambda": true, "nonlocal": true, "not": true, "or": true, "pass": true, "raise": true, "return": true,
+	"try": true, "while": true, "with": true, "yield": true,
+}
+
+// closingBrackets pairs each closing bracket with the one that opens it
+var closingBrackets = map[string]string{")": "(", "]": "[", "}": "{"}
+
+// pyTokenizer splits Python source into tokens the way Python's own tokenizer does
+type pyTokenizer struct {
+	src    string
+	pos    int
+	line   int
+	col    int
+	tokens []pyToken
+	// indents holds the indentation width of every open block, the module level is 0
+	indents []int
+	// brackets holds every open bracket, newlines inside them do not end the logical line
+	brackets []pyToken
+}
+
+/**
+ * tokenizePython
+ * Splits Python source into tokens, comments and blank lines are dropped
+ * @param src {string} - the Python source
+ * @return []pyToken - the tokens, ending with pyEOF
+ * @return error - a *PySyntaxError for source that cannot be tokenized
+ **/
+func tokenizePython(src string) ([]pyToken, error) {
+	t := &pyTokenizer{src: src, line: 1, col: 1, indents: []int{0}}
+	if err := t.run(); err != nil {
+		return nil, err
+	}
+	return t.tokens, nil
+}
+
+func (t *pyTokenizer) errorf(line, col int, format string, args ...any) error {
+	return &PySyntaxError{Line: line, Column: col, Message: fmt.Sprintf(format, args...)}
+}
+
+// peek returns the byte at offset from the current position, 0 past the end
+func (t *pyTokenizer) peek(offset int) byte {
+	if t.pos+offset >= len(t.src) {
+		return 0
+	}
+	return t.src[t.pos+offset]
+}
+
+// advance moves past n bytes, keeping the line and column in step
+func (t *pyTokenizer) advance(n int) {
+	end := min(t.pos+n, len(t.src))
+	for t.pos < end {
+		r, size := utf8.DecodeRuneInString(t.src[t.pos:])
+		t.pos += size
+		if r == '\n' {
+			t.line++
+			t.col = 1
+		} else {
+			t.col++
+		}
+	}
+}
+
+func (t *pyTokenizer) emit(kind pyTokenKind, text string, line, col int) {
+	t.tokens = append(t.tokens, pyToken{Kind: kind, Text: text, Line: line, Col: col})
+}
+
+// lineHasTokens reports whether the current logical line produced a token yet
+func (t *pyTokenizer) lineHasTokens() bool {
+	if len(t.tokens) == 0 {
+		return false
+	}
+	last := t.tokens[len(t.tokens)-1].Kind
+	return last != pyNewline && last != pyIndent && last != pyDedent
+}
+
+func (t *pyTokenizer) run() error {
+	atLineStart := true
+	for {
+		if atLineStart {
+			done, err := t.indentation()
+			if err != nil {
+				return err
+			}
+			if done {
+				break
+			}
+			atLineStart = false
+		}
+		if t.pos >= len(t.src) {
+			break
+		}
+
+		c := t.src[t.pos]
+		switch {
+		case c == ' ' || c == '\t' || c == '\f' || c == '\r':
+			t.advance(1)
+		case c == '#':
+			for t.pos < len(t.src) && t.src[t.pos] != '\n' {
+				t.advance(1)
+			}
+		case c == '\n':
+			if len(t.brackets) == 0 {
+				t.emit(pyNewline, "", t.line, t.col)
+				atLineStart = true
+			}
+			t.advance(1)
+		case c == '\\':
+			line, col := t.line, t.col
+			t.advance(1)
+			if t.peek(0) == '\r' {
+				t.advance(1)
+			}
+			if t.pos >= len(t.src) {
+				return t.errorf(line, col, "unexpected end of file after line continuation")
+			}
+			if t.peek(0) != '\n' {
+				return t.errorf(line, col, "unexpected character after line continuation")
+			}
+			t.advance(1)
+		case c == '"' || c == '\'':
+			if err := t.str(0); err != nil {
+				return err
+			}
+		case c >= '0' && c <= '9' || c == '.' && t.peek(1) >= '0' && t.peek(1) <= '9':
+			t.number()
+		case c >= utf8.RuneSelf || c == '_' || unicode.IsLetter(rune(c)):
+			if prefix := t.stringPrefix(); prefix > 0 {
+				if err := t.str(prefix); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := t.name(); err != nil {
+				return err
+			}
+		default:
+			if err := t.operator(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(t.brackets) > 0 {
+		open := t.brackets[len(t.brackets)-1]
+		return t.errorf(open.Line, open.Col, "'%s' was never closed", open.Text)
+	}
+	if t.lineHasTokens() {
+		t.emit(pyNewline, "", t.line, t.col)
+	}
+	for len(t.indents) > 1 {
+		t.indents = t.indents[:len(t.indents)-1]
+		t.emit(pyDedent, "", t.line, t.col)
+	}
+	t.emit(pyEOF, "", t.line, t.col)
+	return nil
+}
+
+// indentation reads the indentation of a new line and emits the indents and dedents it implies,
+// blank and comment only lines are skipped whole. done reports the end of the source
+func (t *pyTokenizer) indentation() (done bool, err error) {
+	for {
+		width := 0
+		for t.pos < len(t.src) {
+			switch t.src[t.pos] {
+			case ' ':
+				width++
+			case '\t':
+				width = (width/8 + 1) * 8
+			case '\f':
+				width = 0
+			default:
+				goto measured
+			}
+			t.advance(1)
+		}
+	measured:
+		if t.pos >= len(t.src) {
+			return true, nil
+		}
+
+		// Lines holding nothing but a comment never change the indentation
+		switch t.src[t.pos] {
+		case '#':
+			for t.pos < len(t.src) && t.src[t.pos] != '\n' {
+				t.advance(1)
+			}
+			continue
+		case '\r':
+			if t.peek(1) == '\n' || t.peek(1) == 0 {
+				t.advance(1)
+				continue
+			}
+		case '\n':
+			t.advance(1)
+			continue
+		}
+
+		current := t.indents[len(t.indents)-1]
+		switch {
+		case width > current:
+			t.indents = append(t.indents, width)
+			t.emit(pyIndent, "", t.line, t.col)
+		case width < current:
+			for width < t.indents[len(t.indents)-1] {
+				t.indents = t.indents[:len(t.indents)-1]
+				t.emit(pyDedent, "", t.line, t.col)
+			}
+			if width != t.indents[len(t.indents)-1] {
+				return false, t.errorf(t.line, t.col, "unindent does not match any outer indentation level")
+			}
+		}
+		return false, nil
+	}
+}
+
+// stringPrefix returns the length of the string prefix at the current position, 0 when no string starts there
+func (t *pyTokenizer) stringPrefix() int {
+	for n := 1; n <= 2; n++ {
+		if t.pos+n >= len(t.src) {
+			return 0
+		}
+		quote := t.src[t.pos+n]
+		if quote != '"' && quote != '\'' {
+			continue
+		}
+		switch strings.ToLower(t.src[t.pos : t.pos+n]) {
+		case "r", "u", "f", "b", "br", "rb", "fr", "rf":
+			return n
+		}
+		return 0
+	}
+	return 0
+}
+
+// str reads a string literal whose prefix is prefixLen bytes long, f-string replacement fields may hold quotes of their own
+func (t *pyTokenizer) str(prefixLen int) error {
+	start, line, col := t.pos, t.line, t.col
+	prefix := strings.ToLower(t.src[t.pos : t.pos+prefixLen])
+	t.advance(prefixLen)
+
+	quote := t.src[t.pos]
+	triple := t.peek(1) == quote && t.peek(2) == quote
+	if triple {
+		t.advance(3)
+	} else {
+		t.advance(1)
+	}
+	formatted := strings.Contains(prefix, "f")
+
+	unterminated := func() error {
+		if triple {
+			return t.errorf(line, col, "unterminated triple-quoted string literal")
+		}
+		return t.errorf(line, col, "unterminated string literal")
+	}
+
+	for {
+		if t.pos >= len(t.src) {
+			return unterminated()
+		}
+		c := t.src[t.pos]
+		switch {
+		case c == '\\':
+			t.advance(2)
+		case c == '\n' && !triple:
+			return unterminated()
+		case c == quote && (!triple || t.peek(1) == quote && t.peek(2) == quote):
+			if triple {
+				t.advance(3)
+			} else {
+				t.advance(1)
+			}
+			t.emit(pyString, t.src[start:t.pos], line, col)
+			return nil
+		case c == '{' && formatted:
+			if t.peek(1) == '{' {
+				t.advance(2)
+				continue
+			}
+			if err := t.replacementField(line, col); err != nil {
+				return err
+			}
+		default:
+			t.advance(1)
+		}
+	}
+}
+
+// replacementField skips an f-string replacement field, strings and brackets nested in it are skipped whole
+func (t *pyTokenizer) replacementField(line, col int) error {
+	depth := 0
+	for t.pos < len(t.src) {
+		c := t.src[t.pos]
+		switch c {
+		case '{', '[', '(':
+			depth++
+		case '}', ']', ')':
+			depth--
+			if depth == 0 {
+				t.advance(1)
+				return nil
+			}
+		case '"', '\'':
+			quote := c
+			t.advance(1)
+			for t.pos < len(t.src) && t.src[t.pos] != quote && t.src[t.pos] != '\n' {
+				if t.src[t.pos] == '\\' {
+					t.advance(1)
+				}
+				t.advance(1)
+			}
+		}
+		t.advance(1)
+	}
+	return t.errorf(line, col, "unterminated string literal")
+}
+
+// number reads an integer, float, or imaginary literal in any base, underscores included
+func (t *pyTokenizer) number() {
+	start, line, col := t.pos, t.line, t.col
+	hex := t.peek(0) == '0' && (t.peek(1) == 'x' || t.peek(1) == 'X')
+	for t.pos < len(t.src) {
+		c := t.src[t.pos]
+		if c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == '.' {
+			t.advance(1)
+			// An exponent may carry a sign, hex digits never start one
+			if !hex && (c == 'e' || c == 'E') && (t.peek(0) == '+' || t.peek(0) == '-') {
+				t.advance(1)
+			}
+			continue
+		}
+		break
+	}
+	t.emit(pyNumber, t.src[start:t.pos], line, col)
+}
+
+// name reads an identifier or keyword
+func (t *pyTokenizer) name() error {
+	start, line, col := t.pos, t.line, t.col
+	for t.pos < len(t.src) {
+		r, size := utf8.DecodeRuneInString(t.src[t.pos:])
+		if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.Is(unicode.Mn, r) && !unicode.Is(unicode.Mc, r) {
+			break
+		}
+		t.advance(size)
+	}
+	if t.pos == start {
+		r, _ := utf8.DecodeRuneInString(t.src[t.pos:])
+		return t.errorf(line, col, "invalid character %q", r)
+	}
+	t.emit(pyName, t.src[start:t.pos], line, col)
+	return nil
+}
+
+// operator reads an operator or delimiter, keeping track of the brackets it opens and closes
+func (t *pyTokenizer) operator() error {
+	line, col := t.line, t.col
+	for _, op := range pyOperators {
+		if !strings.HasPrefix(t.src[t.pos:], op) {
+			continue
+		}
+		token := pyToken{Kind: pyOp, Text: op, Line: line, Col: col}
+		switch op {
+		case "(", "[", "{":
+			t.brackets = append(t.brackets, token)
+		case ")", "]", "}":
+			if len(t.brackets) == 0 {
+				return t.errorf(line, col, "unmatched '%s'", op)
+			}
+			open := t.brackets[len(t.brackets)-1]
+			if open.Text != closingBrackets[op] {
+				return t.errorf(line, col, "closing '%s' does not match '%s' on line %d", op, open.Text, open.Line)
+			}
+			t.brackets = t.brackets[:len(t.brackets)-1]
+		}
+		t.tokens = append(t.tokens, token)
+		t.advance(len(op))
+		return nil
+	}
+	r, _ := utf8.DecodeRuneInString(t.src[t.pos:])
+	return t.errorf(line, col, "invalid character %q", r)
+}
+
+/**
+ * tokensSource
+ * Renders tokens back into source text with the spacing PEP 8 uses, so differently formatted
+ * code reads the same: no space inside brackets or before a comma, keyword arguments without spaces
+ * around =, and no trailing comma before a closing bracket
+ * @param tokens {[]pyToken} - the tokens of one expression or logical line
+ * @return string - the rendered source
+ **/
+func tokensSource(tokens []pyToken) string {
+	var b strings.Builder
+	var brackets []string
+	// commas counts the commas written inside each open bracket
+	var commas []int
+	for i, tok := range tokens {
+		if tok.is(",") && len(brackets) > 0 && i+1 < len(tokens) && closingBrackets[tokens[i+1].Text] != "" && tokens[i+1].Kind == pyOp {
+			// A trailing comma only matters to a tuple of one item
+			if brackets[len(brackets)-1] != "(" || commas[len(commas)-1] > 0 {
+				continue
+			}
+		}
+		if i > 0 && spaceBetween(tokens, i, brackets) {
+			b.WriteByte(' ')
+		}
+		b.WriteString(tok.Text)
+		if tok.Kind != pyOp {
+			continue
+		}
+		switch tok.Text {
+		case "(", "[", "{":
+			brackets = append(brackets, tok.Text)
+			commas = append(commas, 0)
+		case ")", "]", "}":
+			if len(brackets) > 0 {
+				brackets = brackets[:len(brackets)-1]
+				commas = commas[:len(commas)-1]
+			}
+		case ",":
+			if len(commas) > 0 {
+				commas[len(commas)-1]++
+			}
+		}
+	}
+	return b.String()
+}
+
+// spaceBetween decides whether tokens[i] is separated from the token before it
+func spaceBetween(tokens []pyToken, i int, brackets []string) bool {
+	prev, cur := tokens[i-1], tokens[i]
+	inBrackets := len(brackets) > 0
+
+	if cur.Kind == pyOp {
+		switch cur.Text {
+		case ")", "]", "}", ",", ".", ";", ":":
+			return false
+		case "(", "[":
+			// Calls and subscripts sit right against what they apply to
+			if prev.Kind == pyName && !pyKeywords[prev.Text] || prev.Kind == pyString || prev.is(")") || prev.is("]") || prev.is("}") {
+				return false
+			}
+		case "=":
+			return !inBrackets
+		}
+	}
+
+	if prev.Kind == pyOp {
+		switch prev.Text {
+		case "(", "[", "{", ".":
+			return false
+		case "=":
+			return !inBrackets
+		case ":":
+			// Slices keep their colons tight, dict entries, lambdas, and annotations get a space after
+			return len(brackets) == 0 || brackets[len(brackets)-1] != "["
+		case "-", "+", "*", "**", "~":
+			return !isUnary(tokens, i-1)
+		}
+	}
+
+	return true
+}
+
+// isUnary reports whether the operator at tokens[i] applies to what follows it rather than joining two operands
+func isUnary(tokens []pyToken, i int) bool {
+	if i == 0 {
+		return true
+	}
+	before := tokens[i-1]
+	switch before.Kind {
+	case pyOp:
+		return before.Text != ")" && before.Text != "]" && before.Text != "}"
+	case pyName:
+		return pyKeywords[before.Text]
+	}
+	return false
+}
+
+/*
+Copyright © 2025 Austin "Choice404" Choi
+
+https://github.com/choice404/botbox
+
+Bot Box
+A discord bot template generator to help create discord bots quickly and easily
+
+This code is licensed under the MIT License.
+
+MIT License
+
+Copyright (c) 2025 Austin Choi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
@@ -0,0 +1,197 @@
+/*
+Copyright © 2025 Austin "Choice404" Choi
+See end of file for extended copyright information
+*/
+
+package utils
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestTokensSource makes sure differently formatted code renders the same
+func TestTokensSource(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`await interaction.response.send_message( "hi" ,ephemeral = True )`, `await interaction.response.send_message("hi", ephemeral=True)`},
+		{"embed = discord.Embed(\n    title=f'{x}',\n    colour=discord.Colour(0xFF0000),\n)", `embed = discord.Embed(title=f'{x}', colour=discord.Colour(0xFF0000))`},
+		{`x = items[1 : -1]`, `x = items[1:-1]`},
+		{`key = lambda i : i.user.id`, `key = lambda i: i.user.id`},
+		{`if not (a and b) : return -x`, `if not (a and b): return -x`},
+		{`f(*args, **kwargs, x=-1)`, `f(*args, **kwargs, x=-1)`},
+		{`total = a*b - c`, `total = a * b - c`},
+		{`data = {"a" : [1,2]}`, `data = {"a": [1, 2]}`},
+		{"x = 1 + \\\n    2  # comment", `x = 1 + 2`},
+		{`pair = (1,)`, `pair = (1,)`},
+		{"items = [\n    'a',\n    'b',\n]", `items = ['a', 'b']`},
+	}
+
+	for _, tt := range tests {
+		tokens, err := tokenizePython(tt.source)
+		if err != nil {
+			t.Fatalf("tokenizePython(%q) returned error: %v", tt.source, err)
+		}
+		// Leave out the newline and end of file tokens
+		var line []pyToken
+		for _, tok := range tokens {
+			if tok.Kind != pyNewline && tok.Kind != pyEOF {
+				line = append(line, tok)
+			}
+		}
+		if got := tokensSource(line); got != tt.want {
+			t.Errorf("tokensSource(%q) = %q, want %q", tt.source, got, tt.want)
+		}
+	}
+}
+
+// TestTokenizePythonStrings covers string prefixes, triple quotes, and f-strings with quotes inside their fields
+func TestTokenizePythonStrings(t *testing.T) {
+	tests := []struct {
+		source string
+		want   []string
+	}{
+		{`a = rb'\d' + B"x"`, []string{"a", "=", `rb'\d'`, "+", `B"x"`}},
+		{"doc = '''one\ntwo'''", []string{"doc", "=", "'''one\ntwo'''"}},
+		{`msg = f"{data["key"]} {{literal}}"`, []string{"msg", "=", `f"{data["key"]} {{literal}}"`}},
+		{`s = "escaped \" quote"`, []string{"s", "=", `"escaped \" quote"`}},
+		{"n = 1_000 + 0x1F + 1.5e-3j", []string{"n", "=", "1_000", "+", "0x1F", "+", "1.5e-3j"}},
+		{"naïve = 1", []string{"naïve", "=", "1"}},
+	}
+
+	for _, tt := range tests {
+		tokens, err := tokenizePython(tt.source)
+		if err != nil {
+			t.Fatalf("tokenizePython(%q) returned error: %v", tt.source, err)
+		}
+		var got []string
+		for _, tok := range tokens {
+			if tok.Kind != pyNewline && tok.Kind != pyEOF {
+				got = append(got, tok.Text)
+			}
+		}
+		if len(got) != len(tt.want) {
+			t.Fatalf("tokenizePython(%q) = %q, want %q", tt.source, got, tt.want)
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("tokenizePython(%q) = %q, want %q", tt.source, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+// TestTokenizePythonIndentation makes sure blank lines, comments, and bracketed lines never change the block structure
+func TestTokenizePythonIndentation(t *testing.T) {
+	source := "class A:\n\n    # comment at another depth\n  # and another\n    def f(self,\n  x):\n\tpass\n"
+	module, err := parsePython(source)
+	if err != nil {
+		t.Fatalf("parsePython returned error: %v", err)
+	}
+	if len(module.Body) != 1 || module.Body[0].Class == nil {
+		t.Fatalf("parsePython returned %+v, want one class", module.Body)
+	}
+	class := module.Body[0].Class
+	if len(class.Body) != 1 || class.Body[0].Func == nil || class.Body[0].Func.Name != "f" {
+		t.Fatalf("class body = %+v, want the method f", class.Body)
+	}
+	if params := class.Body[0].Func.Params; len(params) != 2 || params[1].Name != "x" {
+		t.Errorf("params = %+v, want self and x", params)
+	}
+}
+
+// TestTokenizePythonErrors pins the position reported for each kind of tokenizer error
+func TestTokenizePythonErrors(t *testing.T) {
+	tests := []struct {
+		source     string
+		wantLine   int
+		wantColumn int
+		wantMsg    string
+	}{
+		{"x = (1,\n     2]", 2, 7, "closing ']' does not match '(' on line 1"},
+		{"x = 1)", 1, 6, "unmatched ')'"},
+		{"x = [\n1,\n", 1, 5, "'[' was never closed"},
+		{`s = 'open`, 1, 5, "unterminated string literal"},
+		{"s = \"\"\"open\n\n", 1, 5, "unterminated triple-quoted string literal"},
+		{"x = 1 $ 2", 1, 7, "invalid character '$'"},
+		{"x = 1 \\ 2", 1, 7, "unexpected character after line continuation"},
+	}
+
+	for _, tt := range tests {
+		_, err := tokenizePython(tt.source)
+		var syntaxErr *PySyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Fatalf("tokenizePython(%q) returned %v, want a *PySyntaxError", tt.source, err)
+		}
+		if syntaxErr.Line != tt.wantLine || syntaxErr.Column != tt.wantColumn || syntaxErr.Message != tt.wantMsg {
+			t.Errorf("tokenizePython(%q) = %v, want line %d, column %d: %s", tt.source, err, tt.wantLine, tt.wantColumn, tt.wantMsg)
+		}
+	}
+}
+
+// TestDecodePyString covers escapes, raw strings, and implicit concatenation
+func TestDecodePyString(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{`"plain"`, "plain"},
+		{`'it\'s'`, "it's"},
+		{`"tab\there\n"`, "tab\there\n"},
+		{`r"C:\temp"`, `C:\temp`},
+		{`"\x41\u00e9\101"`, "Aé" + "A"},
+		{`"unknown \d escape"`, `unknown \d escape`},
+		{`"""triple "quoted" text"""`, `triple "quoted" text`},
+		{`f"{name}!"`, "{name}!"},
+	}
+
+	for _, tt := range tests {
+		if got := decodePyString(tt.raw); got != tt.want {
+			t.Errorf("decodePyString(%s) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+
+	tokens, err := tokenizePython(`"one " 'two'`)
+	if err != nil {
+		t.Fatalf("tokenizePython returned error: %v", err)
+	}
+	if got, ok := stringTokens(tokens[:2]); !ok || got != "one two" {
+		t.Errorf("stringTokens = %q, %v, want %q", got, ok, "one two")
+	}
+}
+
+/*
+Copyright © 2025 Austin "Choice404" Choi
+
+https://github.com/choice404/botbox
+
+Bot Box
+A discord bot template generator to help create discord bots quickly and easily
+
+This code is licensed under the MIT License.
+
+MIT License
+
+Copyright (c) 2025 Austin Choi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
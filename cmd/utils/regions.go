@@ -0,0 +1,217 @@
+/*
+Copyright © 2025 Austin "Choice404" Choi
+See end of file for extended copyright information
+*/
+
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Generated cogs mark the code botbox edit carries over from the file it replaces with begin and end
+// comments, "# botbox:begin <region>" and "# botbox:end <region>" in Python, the generator's RegionMarker
+// starts them. Command, listener, and task bodies are regions named "body <command>", "listener <event>",
+// and "task <task>", while the imports and class regions are empty spaces for hand written imports and methods
+const (
+	importsRegion = "imports"
+	classRegion   = "class"
+)
+
+// protectedRegion is one marked region of a cog file, Start and End are the lines of its markers
+type protectedRegion struct {
+	Name  string
+	Start int
+	End   int
+}
+
+// findProtectedRegions lists the regions of a cog file marked by comments starting with marker in order,
+// damaged markers are an error since the code between them could not be carried over safely
+func findProtectedRegions(lines []string, marker string) ([]protectedRegion, error) {
+	var regions []protectedRegion
+	seen := map[string]bool{}
+	open := -1
+	markerRegex := regexp.MustCompile(`^` + regexp.QuoteMeta(marker) + `(begin|end) (\S.*)$`)
+
+	for i, raw := range lines {
+		line := strings.TrimSpace(raw)
+		if !strings.HasPrefix(line, marker) {
+			continue
+		}
+		matches := markerRegex.FindStringSubmatch(line)
+		if matches == nil {
+			return nil, fmt.Errorf("line %d: unrecognized marker %q", i+1, line)
+		}
+		name := strings.TrimSpace(matches[2])
+
+		if matches[1] == "begin" {
+			if open != -1 {
+				return nil, fmt.Errorf("line %d: region %q begins inside region %q", i+1, name, regions[open].Name)
+			}
+			if seen[name] {
+				return nil, fmt.Errorf("line %d: region %q is marked twice", i+1, name)
+			}
+			seen[name] = true
+			regions = append(regions, protectedRegion{Name: name, Start: i, End: -1})
+			open = len(regions) - 1
+			continue
+		}
+
+		if open == -1 {
+			return nil, fmt.Errorf("line %d: region %q ends without a matching begin marker", i+1, name)
+		}
+		if regions[open].Name != name {
+			return nil, fmt.Errorf("line %d: region %q ends while region %q is open", i+1, name, regions[open].Name)
+		}
+		regions[open].End = i
+		open = -1
+	}
+
+	if open != -1 {
+		return nil, fmt.Errorf("line %d: region %q is never closed", regions[open].Start+1, regions[open].Name)
+	}
+	return regions, nil
+}
+
+// regionText is the code between a region's markers, trailing whitespace does not count as a change
+func regionText(lines []string, region protectedRegion) string {
+	body := make([]string, 0, region.End-region.Start-1)
+	for _, line := range lines[region.Start+1 : region.End] {
+		body = append(body, strings.TrimRight(line, " \t\r"))
+	}
+	return strings.Join(body, "\n")
+}
+
+// maskCustomRegions blanks the imports and class regions so the parser never mistakes the hand written
+// code in them for generated commands, a file with damaged markers is parsed as it is
+func maskCustomRegions(lines []string, marker string) []string {
+	regions, err := findProtectedRegions(lines, marker)
+	if err != nil || len(regions) == 0 {
+		return lines
+	}
+
+	masked := slices.Clone(lines)
+	for _, region := range regions {
+		if region.Name != importsRegion && region.Name != classRegion {
+			continue
+		}
+		for i := region.Start + 1; i < region.End; i++ {
+			masked[i] = ""
+		}
+	}
+	return masked
+}
+
+// mergeProtectedRegions carries the protected regions of the existing cog file over into the rendered one.
+// A region is kept when its code differs from what the previous definition rendered there, so hand written
+// code survives while regions still holding generated code follow the new definition. Kept code whose region the new
+// definition no longer renders, like the body of a renamed or removed command, fails like damaged markers do.
+// It returns the merged file and the names of the regions that were kept, a file without markers is replaced as a whole
+func mergeProtectedRegions(existing, previous, rendered string, marker string) (string, []string, error) {
+	existingLines := strings.Split(existing, "\n")
+	existingRegions, err := findProtectedRegions(existingLines, marker)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(existingRegions) == 0 {
+		return rendered, nil, nil
+	}
+
+	previousLines := strings.Split(previous, "\n")
+	previousRegions, err := findProtectedRegions(previousLines, marker)
+	if err != nil {
+		return "", nil, fmt.Errorf("previous definition: %w", err)
+	}
+
+	// Markers the previous definition generated must still be there, without them the code they held cannot be told apart
+	for _, region := range previousRegions {
+		if !slices.ContainsFunc(existingRegions, func(r protectedRegion) bool { return r.Name == region.Name }) {
+			return "", nil, fmt.Errorf("the markers of region %q are missing, restore the %sbegin %s and %send %s comments",
+				region.Name, marker, region.Name, marker, region.Name)
+		}
+	}
+
+	kept := map[string][]string{}
+	for _, region := range existingRegions {
+		text := regionText(existingLines, region)
+		previousIndex := slices.IndexFunc(previousRegions, func(r protectedRegion) bool { return r.Name == region.Name })
+		if previousIndex != -1 && regionText(previousLines, previousRegions[previousIndex]) == text {
+			continue
+		}
+		kept[region.Name] = existingLines[region.Start+1 : region.End]
+	}
+
+	renderedLines := strings.Split(rendered, "\n")
+	renderedRegions, err := findProtectedRegions(renderedLines, marker)
+	if err != nil {
+		return "", nil, fmt.Errorf("new definition: %w", err)
+	}
+
+	// Existing regions are walked in file order so the dropped ones are named in a stable order
+	var dropped []string
+	for _, region := range existingRegions {
+		if _, ok := kept[region.Name]; !ok {
+			continue
+		}
+		if !slices.ContainsFunc(renderedRegions, func(r protectedRegion) bool { return r.Name == region.Name }) {
+			dropped = append(dropped, fmt.Sprintf("%q", region.Name))
+		}
+	}
+	if len(dropped) > 0 {
+		return "", nil, fmt.Errorf("the hand written code in region %s has no place in the new definition, move it out or empty the region first",
+			strings.Join(dropped, ", "))
+	}
+
+	var merged []string
+	var preserved []string
+	next := 0
+	for _, region := range renderedRegions {
+		code, ok := kept[region.Name]
+		if !ok {
+			continue
+		}
+		merged = append(merged, renderedLines[next:region.Start+1]...)
+		merged = append(merged, code...)
+		next = region.End
+		preserved = append(preserved, region.Name)
+	}
+	merged = append(merged, renderedLines[next:]...)
+
+	return strings.Join(merged, "\n"), preserved, nil
+}
+
+/*
+Copyright © 2025 Austin "Choice404" Choi
+
+https://github.com/choice404/botbox
+
+Bot Box
+A discord bot template generator to help create discord bots quickly and easily
+
+This code is licensed under the MIT License.
+
+MIT License
+
+Copyright (c) 2025 Austin Choi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
@@ -0,0 +1,239 @@
+/*
+Copyright © 2025 Austin "Choice404" Choi
+See end of file for extended copyright information
+*/
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFindProtectedRegions(t *testing.T) {
+	content := `import discord
+# botbox:begin imports
+import random
+# botbox:end imports
+
+    # botbox:begin body hello
+    pass
+    # botbox:end body hello
+    # botbox:begin class
+    # botbox:end class`
+
+	regions, err := findProtectedRegions(strings.Split(content, "\n"), pythonRegionMarker)
+	if err != nil {
+		t.Fatalf("findProtectedRegions returned error: %v", err)
+	}
+	want := []protectedRegion{
+		{Name: "imports", Start: 1, End: 3},
+		{Name: "body hello", Start: 5, End: 7},
+		{Name: "class", Start: 8, End: 9},
+	}
+	if !slices.Equal(regions, want) {
+		t.Errorf("findProtectedRegions() = %+v, want %+v", regions, want)
+	}
+}
+
+func TestFindProtectedRegionsRejectsDamagedMarkers(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "unclosed region",
+			content: "# botbox:begin body hello\npass",
+			wantErr: `region "body hello" is never closed`,
+		},
+		{
+			name:    "end without begin",
+			content: "pass\n# botbox:end body hello",
+			wantErr: `region "body hello" ends without a matching begin marker`,
+		},
+		{
+			name:    "mismatched end",
+			content: "# botbox:begin body hello\n# botbox:end body bye",
+			wantErr: `region "body bye" ends while region "body hello" is open`,
+		},
+		{
+			name:    "nested begin",
+			content: "# botbox:begin body hello\n# botbox:begin body bye",
+			wantErr: `region "body bye" begins inside region "body hello"`,
+		},
+		{
+			name:    "duplicate region",
+			content: "# botbox:begin class\n# botbox:end class\n# botbox:begin class\n# botbox:end class",
+			wantErr: `region "class" is marked twice`,
+		},
+		{
+			name:    "mangled marker",
+			content: "# botbox:begn class\n# botbox:end class",
+			wantErr: "unrecognized marker",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := findProtectedRegions(strings.Split(tt.content, "\n"), pythonRegionMarker)
+			if err == nil {
+				t.Fatal("findProtectedRegions should reject damaged markers")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %q, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// renderRegionsCog renders a cog with a slash command and a listener for the merge tests
+func renderRegionsCog(t *testing.T, response string) string {
+	t.Helper()
+	content, err := RenderTemplate("cog.py.tmpl", CogTemplateData{
+		Author:         "Austin Choi",
+		BotName:        "TestBot",
+		BotDescription: "A discord bot used by the region tests",
+		ClassName:      "RegionCog",
+		Filename:       "regionCog",
+		SlashCommands: []CommandInfo{
+			{Name: "hello", Scope: "guild", Type: "slash", Description: "Says hello", ReturnType: "None",
+				Responses: []ResponseInfo{{Type: "message", Content: response}}},
+			{Name: "bye", Scope: "guild", Type: "slash", Description: "Says bye", ReturnType: "None",
+				Responses: []ResponseInfo{{Type: "message", Content: response}}},
+		},
+		Listeners: []ListenerInfo{{Event: "on_ready", Action: "log"}},
+	})
+	if err != nil {
+		t.Fatalf("RenderTemplate returned error: %v", err)
+	}
+	return content
+}
+
+func TestMergeProtectedRegions(t *testing.T) {
+	previous := renderRegionsCog(t, "first")
+	rendered := renderRegionsCog(t, "second")
+
+	existing := strings.Replace(previous, "        # botbox:begin body hello\n", "        # botbox:begin body hello\n        logger.info(\"custom\")\n", 1)
+	existing = strings.Replace(existing, "# botbox:begin imports\n", "# botbox:begin imports\nimport random\n", 1)
+	existing = strings.Replace(existing, "    # botbox:begin class\n", "    # botbox:begin class\n    def helper(self):\n        return random.random()\n", 1)
+
+	merged, preserved, err := mergeProtectedRegions(existing, previous, rendered, pythonRegionMarker)
+	if err != nil {
+		t.Fatalf("mergeProtectedRegions returned error: %v", err)
+	}
+	if want := []string{"imports", "body hello", "class"}; !slices.Equal(preserved, want) {
+		t.Errorf("preserved = %v, want %v", preserved, want)
+	}
+	for _, snippet := range []string{
+		"import random\n",
+		"        logger.info(\"custom\")\n",
+		"    def helper(self):\n        return random.random()\n",
+		`send_message(f"second", ephemeral=False)`,
+	} {
+		if !strings.Contains(merged, snippet) {
+			t.Errorf("merged cog is missing %q", snippet)
+		}
+	}
+	// The customized body keeps its old reply, the untouched body follows the new definition
+	if strings.Count(merged, `send_message(f"first"`) != 1 || strings.Count(merged, `send_message(f"second"`) != 1 {
+		t.Errorf("merged cog should keep the custom hello body and regenerate bye\n%s", merged)
+	}
+
+	t.Run("file without markers is replaced", func(t *testing.T) {
+		merged, preserved, err := mergeProtectedRegions("print('legacy')\n", previous, rendered, pythonRegionMarker)
+		if err != nil || merged != rendered || preserved != nil {
+			t.Errorf("mergeProtectedRegions() = %v, %v, want the rendered file unchanged", preserved, err)
+		}
+	})
+
+	t.Run("custom code without a region in the new definition fails", func(t *testing.T) {
+		withoutHello := strings.Replace(rendered, "        # botbox:begin body hello\n", "        # botbox:begin body hi\n", 1)
+		withoutHello = strings.Replace(withoutHello, "        # botbox:end body hello\n", "        # botbox:end body hi\n", 1)
+		if _, _, err := mergeProtectedRegions(existing, previous, withoutHello, pythonRegionMarker); err == nil || !strings.Contains(err.Error(), `"body hello"`) {
+			t.Errorf("error = %v, want the dropped hello body reported", err)
+		}
+
+		// A region still holding generated code is dropped without complaint
+		untouched := strings.Replace(existing, "        logger.info(\"custom\")\n", "", 1)
+		if _, _, err := mergeProtectedRegions(untouched, previous, withoutHello, pythonRegionMarker); err != nil {
+			t.Errorf("mergeProtectedRegions returned error for a generated body: %v", err)
+		}
+	})
+
+	t.Run("missing markers fail", func(t *testing.T) {
+		damaged := strings.Replace(existing, "        # botbox:begin listener on_ready\n", "", 1)
+		damaged = strings.Replace(damaged, "        # botbox:end listener on_ready\n", "", 1)
+		if _, _, err := mergeProtectedRegions(damaged, previous, rendered, pythonRegionMarker); err == nil || !strings.Contains(err.Error(), `"listener on_ready"`) {
+			t.Errorf("error = %v, want the missing listener region reported", err)
+		}
+	})
+}
+
+func TestParseCogFileMasksCustomRegions(t *testing.T) {
+	content := renderRegionsCog(t, "first")
+	content = strings.Replace(content, "    # botbox:begin class\n", `    # botbox:begin class
+    @commands.command(name="secret")
+    async def secret(self, ctx: commands.Context) -> None:
+        await ctx.send("shh")
+
+    @commands.Cog.listener()
+    async def on_guild_join(self, guild: discord.Guild) -> None:
+        pass
+`, 1)
+
+	path := filepath.Join(t.TempDir(), "regionCog.py")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write rendered cog: %v", err)
+	}
+
+	parsed, err := parseCogFile(path, "regionCog", DefaultLibrary)
+	if err != nil {
+		t.Fatalf("parseCogFile returned error: %v", err)
+	}
+	if len(parsed.PrefixCommands) != 0 {
+		t.Errorf("methods in the class region should not be synced, got prefix commands %+v", parsed.PrefixCommands)
+	}
+	if want := []ListenerInfo{{Event: "on_ready", Action: "log"}}; !slices.Equal(parsed.Listeners, want) {
+		t.Errorf("listeners = %+v, want %+v", parsed.Listeners, want)
+	}
+	if len(parsed.SlashCommands) != 2 || len(parsed.SlashCommands[0].Responses) != 1 || parsed.SlashCommands[0].Responses[0].Content != "first" {
+		t.Errorf("marked command bodies should still parse, got %+v", parsed.SlashCommands)
+	}
+}
+
+/*
+Copyright © 2025 Austin "Choice404" Choi
+
+https://github.com/choice404/botbox
+
+Bot Box
+A discord bot template generator to help create discord bots quickly and easily
+
+This code is licensed under the MIT License.
+
+MIT License
+
+Copyright (c) 2025 Austin Choi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
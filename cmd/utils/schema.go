@@ -59,6 +59,9 @@ type BotConfig struct {
 	// EnvProvider records how the project supplies environment variables, env or doppler,
 	// configs written before this key existed unmarshal to "" and are resolved by file detection
 	EnvProvider string `json:"env_provider"`
+	// Library is the Discord library generated code is written for, discord.py, py-cord, nextcord, or disnake,
+	// configs written before this key existed unmarshal to "" and are read as discord.py
+	Library string `json:"library"`
 }
 
 type CogConfig struct {
@@ -67,6 +70,66 @@ type CogConfig struct {
 	File           string        `json:"file"`
 	SlashCommands  []CommandInfo `json:"slash_commands"`
 	PrefixCommands []CommandInfo `json:"prefix_commands"`
+	// Listeners is left out of configs for cogs without any so older botbox.conf files stay unchanged
+	Listeners []ListenerInfo `json:"listeners,omitempty"`
+	Tasks     []TaskInfo     `json:"tasks,omitempty"`
+}
+
+// ListenerInfo is a @commands.Cog.listener() handler for a Discord event, Action picks a canned body:
+// log the event, send Content to ChannelID, assign RoleID to the event's member, or empty for a stub
+type ListenerInfo struct {
+	Event     string
+	Action    string
+	ChannelID string
+	Content   string
+	RoleID    string
+}
+
+// TaskInfo is a discord.ext.tasks loop the cog starts when it loads and cancels when it unloads.
+// It runs every Interval Unit, or daily at each of Times (24 hour HH:MM) in Timezone, an empty Timezone is UTC
+type TaskInfo struct {
+	Name     string
+	Interval int
+	Unit     string
+	Times    []string
+	Timezone string
+	// ChannelID is the channel the task posts to, Content is sent there on every run when set
+	ChannelID string
+	Content   string
+}
+
+func TaskInfoSliceToJSON(slice []TaskInfo) (string, error) {
+	jsonData, err := json.Marshal(slice)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal TaskInfo slice to JSON: %w", err)
+	}
+	return string(jsonData), nil
+}
+
+func JSONToTaskInfoSlice(jsonString string) ([]TaskInfo, error) {
+	var slice []TaskInfo
+	err := json.Unmarshal([]byte(jsonString), &slice)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON to TaskInfo slice: %w", err)
+	}
+	return slice, nil
+}
+
+func ListenerInfoSliceToJSON(slice []ListenerInfo) (string, error) {
+	jsonData, err := json.Marshal(slice)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal ListenerInfo slice to JSON: %w", err)
+	}
+	return string(jsonData), nil
+}
+
+func JSONToListenerInfoSlice(jsonString string) ([]ListenerInfo, error) {
+	var slice []ListenerInfo
+	err := json.Unmarshal([]byte(jsonString), &slice)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON to ListenerInfo slice: %w", err)
+	}
+	return slice, nil
 }
 
 func CogConfigSliceToJSON(slice []CogConfig) (string, error) {
@@ -109,16 +172,52 @@ type Config struct {
 	Cogs    []CogConfig  `json:"cogs"`
 }
 
+// CommandInfo is one command of a cog, the omitempty fields were added later and are left out of botbox.conf
+// when empty so configs written before they existed stay unchanged
 type CommandInfo struct {
 	Name        string
 	Scope       string
 	Type        string
 	Description string
-	Args        []ArgInfo
-	Fields      []FieldInfo
-	Pages       []PageInfo
-	Responses   []ResponseInfo
-	ReturnType  string
+	// Group is the slash group path the command is nested under, "ticket" or "ticket admin",
+	// empty for a top level command. A prefix command's group is the path of the prefix command it is a subcommand of
+	Group string `json:",omitempty"`
+	// GroupDescription describes the innermost group, commands sharing a group share it
+	GroupDescription string `json:",omitempty"`
+	// Permissions are the discord.Permissions flags a member needs by default, like manage_messages
+	Permissions []string `json:",omitempty"`
+	// Roles are role names or IDs, a member needs any one of them to run the command
+	Roles []string `json:",omitempty"`
+	// GuildOnly keeps the command out of direct messages
+	GuildOnly bool `json:",omitempty"`
+	// AllowedInstalls and AllowedContexts scope user installable apps, installs are guild or user
+	// and contexts are guild, dm, or private_channel
+	AllowedInstalls []string      `json:",omitempty"`
+	AllowedContexts []string      `json:",omitempty"`
+	Cooldown        *CooldownInfo `json:",omitempty"`
+	// Aliases are extra names a prefix command answers to, Hidden keeps it out of /help
+	Aliases []string `json:",omitempty"`
+	Hidden  bool     `json:",omitempty"`
+	// InvokeWithoutCommand makes a prefix group run its own body only when no subcommand was given
+	InvokeWithoutCommand bool `json:",omitempty"`
+	// NameLocalizations and DescriptionLocalizations map a Discord locale like de or pt-BR to the translated text,
+	// the generated translator serves them from the locale files
+	NameLocalizations        map[string]string `json:",omitempty"`
+	DescriptionLocalizations map[string]string `json:",omitempty"`
+	Args                     []ArgInfo
+	Fields                   []FieldInfo
+	Pages                    []PageInfo
+	// Components is the view of buttons and select menus a component command responds with
+	Components *ComponentsInfo `json:",omitempty"`
+	Responses  []ResponseInfo
+	ReturnType string
+}
+
+// CooldownInfo lets a command run Rate times every Per seconds for each user, guild, or channel Bucket
+type CooldownInfo struct {
+	Rate   int
+	Per    float64
+	Bucket string
 }
 
 func CmdInfoSliceToJSON(slice []CommandInfo) (string, error) {
@@ -159,6 +258,32 @@ type ArgInfo struct {
 	Name        string
 	Type        string
 	Description string
+	// Optional lets users leave the argument out, Default is the value it falls back to,
+	// an optional argument without a default falls back to None
+	Optional bool   `json:",omitempty"`
+	Default  string `json:",omitempty"`
+	// Choices pins a slash argument to fixed values, Min and Max bound numbers or string lengths
+	Choices []ChoiceInfo `json:",omitempty"`
+	Min     string       `json:",omitempty"`
+	Max     string       `json:",omitempty"`
+	// Autocomplete suggests values while the user types, either from the fixed Suggestions
+	// or from the cog method generated for AutocompleteSource
+	Autocomplete       bool     `json:",omitempty"`
+	Suggestions        []string `json:",omitempty"`
+	AutocompleteSource string   `json:",omitempty"`
+	// Greedy consumes as many words as convert to the type, Rest takes the rest of the message as one value,
+	// both only exist on prefix commands
+	Greedy bool `json:",omitempty"`
+	Rest   bool `json:",omitempty"`
+	// NameLocalizations and DescriptionLocalizations translate the option per Discord locale
+	NameLocalizations        map[string]string `json:",omitempty"`
+	DescriptionLocalizations map[string]string `json:",omitempty"`
+}
+
+// ChoiceInfo is one fixed value offered for a slash command argument
+type ChoiceInfo struct {
+	Name  string
+	Value string
 }
 
 func ArgInfoSliceToJSON(slice []ArgInfo) (string, error) {
@@ -185,6 +310,8 @@ type FieldInfo struct {
 	Style       string
 	Required    bool
 	Placeholder string
+	// LabelLocalizations translates the label per Discord locale when the modal is sent
+	LabelLocalizations map[string]string `json:",omitempty"`
 }
 
 func FieldInfoSliceToJSON(slice []FieldInfo) (string, error) {
@@ -237,11 +364,59 @@ func JSONToPageInfoSlice(jsonString string) ([]PageInfo, error) {
 	return slice, nil
 }
 
-// ResponseInfo describes an expected response a command sends when it finishes, only the message type exists today
+// ComponentsInfo is the view a component command attaches to its message
+type ComponentsInfo struct {
+	// Timeout is how many seconds the view accepts clicks, 0 keeps it active until the bot restarts
+	Timeout int
+	// AuthorOnly rejects clicks from anyone but the member who ran the command
+	AuthorOnly bool
+	Items      []ComponentInfo
+}
+
+// ComponentInfo is one button or select menu, a button answers with its own Response and a select
+// answers with the Response of the option that was picked
+type ComponentInfo struct {
+	Name string
+	Type string
+	// Label is the button text, or the placeholder shown on an empty select menu
+	Label    string
+	Style    string
+	Response ResponseInfo
+	Options  []SelectOptionInfo
+}
+
+// SelectOptionInfo is one option of a select menu and the response it sends when picked
+type SelectOptionInfo struct {
+	Label    string
+	Value    string
+	Response ResponseInfo
+}
+
+// ResponseInfo describes an expected response a command sends when it finishes.
+// Content is the message text, or the description of an embed, and a channel response is posted to ChannelID
 type ResponseInfo struct {
 	Type      string
 	Content   string
 	Ephemeral bool
+	Embed     *EmbedInfo `json:",omitempty"`
+	ChannelID string     `json:",omitempty"`
+}
+
+// EmbedInfo holds the parts of an embed response besides its description
+type EmbedInfo struct {
+	Title string
+	// Color is a hex color like #5865F2
+	Color     string
+	Fields    []EmbedFieldInfo
+	Footer    string
+	Thumbnail string
+}
+
+// EmbedFieldInfo is one name and value pair shown on an embed
+type EmbedFieldInfo struct {
+	Name   string
+	Value  string
+	Inline bool
 }
 
 func ResponseInfoSliceToJSON(slice []ResponseInfo) (string, error) {
@@ -273,14 +448,32 @@ type ParsedCogInfo struct {
 	Description    string
 	SlashCommands  []CommandInfo
 	PrefixCommands []CommandInfo
+	Listeners      []ListenerInfo
+	Tasks          []TaskInfo
+	// Diagnostics holds the warnings about code that looks like a command but could not be fully read
+	Diagnostics []Diagnostic
 }
 
+// SyncResult reports what a sync changed, Diagnostics lists the problems found in the cog files in file order.
+// It is also the result --output json prints for a sync, so the json names are kept stable
 type SyncResult struct {
-	UpdatedCogs  []string
-	AddedCogs    []string
-	RemovedCogs  []string
-	Errors       []string
-	HeaderIssues []string
+	UpdatedCogs  []string     `json:"updated_cogs"`
+	AddedCogs    []string     `json:"added_cogs"`
+	RemovedCogs  []string     `json:"removed_cogs"`
+	Diagnostics  []Diagnostic `json:"diagnostics"`
+	HeaderIssues []string     `json:"header_issues"`
+	// CommandChanges lists every command the sync adds, changes, or removes, one line per command
+	CommandChanges []string `json:"command_changes"`
+}
+
+// Errors returns the diagnostics that stopped the sync from staging botbox.conf
+func (r SyncResult) Errors() []Diagnostic {
+	return filterDiagnostics(r.Diagnostics, SeverityError)
+}
+
+// Warnings returns the diagnostics about code the sync could only partly read
+func (r SyncResult) Warnings() []Diagnostic {
+	return filterDiagnostics(r.Diagnostics, SeverityWarning)
 }
 
 type GitHubRelease struct {
@@ -305,13 +498,13 @@ type LegacyCogConfig struct {
 }
 
 type UpgradeResult struct {
-	Success         bool
-	AlreadyUpgraded bool
-	Message         string
-	UpgradedCogs    []string
-	Errors          []string
-	BackupCreated   bool
-	BackupPath      string
+	Success         bool     `json:"success"`
+	AlreadyUpgraded bool     `json:"already_upgraded"`
+	Message         string   `json:"message"`
+	UpgradedCogs    []string `json:"upgraded_cogs"`
+	Errors          []string `json:"errors"`
+	BackupCreated   bool     `json:"backup_created"`
+	BackupID        string   `json:"backup_id"`
 }
 
 /*
@@ -0,0 +1,389 @@
+/*
+Copyright © 2025 Austin "Choice404" Choi
+See end of file for extended copyright information
+*/
+
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BotSpec describes a whole bot in a version controlled file, written in YAML with the keys of botbox.conf.
+// Bot fields left empty keep the project's values, and Cogs is the complete list, project cogs missing from it are removed
+type BotSpec struct {
+	// BotBox is read so a copy of botbox.conf is a valid spec, the project keeps its own version
+	BotBox  BotBoxConfig `json:"botbox"`
+	BotInfo BotConfig    `json:"bot"`
+	Cogs    []CogConfig  `json:"cogs"`
+}
+
+// SpecPlan is what applying a spec changes in a project, cogs are listed by name in spec order
+type SpecPlan struct {
+	BotChanges     []string `json:"bot_changes"`
+	AddedCogs      []string `json:"added_cogs"`
+	UpdatedCogs    []string `json:"updated_cogs"`
+	RemovedCogs    []string `json:"removed_cogs"`
+	CommandChanges []string `json:"command_changes"`
+	// SkippedCogs are the built-in cogs the spec lists, apply leaves them as they are
+	SkippedCogs []string `json:"skipped_cogs"`
+	// Config is botbox.conf once the spec is applied, Previous is botbox.conf as it is now
+	Config   Config `json:"-"`
+	Previous Config `json:"-"`
+}
+
+// Empty reports whether the project already matches the spec
+func (p SpecPlan) Empty() bool {
+	return len(p.BotChanges) == 0 && len(p.AddedCogs) == 0 && len(p.UpdatedCogs) == 0 && len(p.RemovedCogs) == 0
+}
+
+/**
+ * LoadSpec
+ * Reads a bot spec file and fills in the defaults of every cog and command, a spec that would not pass
+ * botbox add is rejected the same way, as are keys botbox.conf does not know
+ * @param path {string} - the spec file, YAML or JSON
+ * @return BotSpec - the spec
+ * @return error - any read, parse, or validation failure
+ **/
+func LoadSpec(path string) (BotSpec, error) {
+	var spec BotSpec
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return spec, fmt.Errorf("failed to read spec: %w", err)
+	}
+
+	// YAML is read into plain values and decoded as JSON, so the spec takes the exact keys of botbox.conf
+	var document any
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return spec, fmt.Errorf("failed to parse spec %s: %w", path, err)
+	}
+	if document == nil {
+		return spec, fmt.Errorf("spec %s is empty", path)
+	}
+	jsonData, err := json.Marshal(document)
+	if err != nil {
+		return spec, fmt.Errorf("failed to parse spec %s: keys must be strings", path)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&spec); err != nil {
+		return spec, fmt.Errorf("invalid spec %s: %w", path, err)
+	}
+
+	if err := normalizeSpecBot(&spec.BotInfo); err != nil {
+		return spec, fmt.Errorf("invalid spec %s: %w", path, err)
+	}
+	seen := map[string]bool{}
+	for i := range spec.Cogs {
+		cog := &spec.Cogs[i]
+		if err := normalizeSpecCog(cog); err != nil {
+			return spec, fmt.Errorf("invalid spec %s: cog %d: %w", path, i+1, err)
+		}
+		if seen[cog.Name] || seen[cog.File] {
+			return spec, fmt.Errorf("invalid spec %s: cog '%s' is listed twice", path, cog.Name)
+		}
+		seen[cog.Name] = true
+		seen[cog.File] = true
+	}
+
+	return spec, nil
+}
+
+// normalizeSpecBot validates the bot fields a spec sets, empty fields are left for the project's values
+func normalizeSpecBot(bot *BotConfig) error {
+	checks := []struct {
+		value    string
+		validate func(string) error
+	}{
+		{bot.Name, ValidateBotName},
+		{bot.CommandPrefix, ValidateBotPrefix},
+		{bot.Author, ValidateBotAuthor},
+		{bot.Description, ValidateBotDescription},
+		{bot.HelpStyle, ValidateHelpStyle},
+		{bot.Library, ValidateLibrary},
+		{bot.EnvProvider, ValidateEnvChoice},
+	}
+	for _, check := range checks {
+		if check.value == "" {
+			continue
+		}
+		if err := check.validate(check.value); err != nil {
+			return fmt.Errorf("bot: %w", err)
+		}
+	}
+	return nil
+}
+
+/**
+ * normalizeSpecCog
+ * Fills in what botbox add would for a cog of a spec and validates it, the file defaults to the name with a
+ * lower case first letter, the environment to development, command scopes to guild, and return types to None.
+ * Commands are sorted into slash and prefix commands by type, whichever list the spec put them in
+ * @param cog {*CogConfig} - the cog to normalize in place
+ * @return error - the first problem found
+ **/
+func normalizeSpecCog(cog *CogConfig) error {
+	if cog.Name == "" && cog.File == "" {
+		return fmt.Errorf("a cog needs a name")
+	}
+	if cog.Name == "" {
+		cog.Name = cog.File
+	}
+	cog.Name = strings.ToUpper(cog.Name[:1]) + cog.Name[1:]
+	if cog.File == "" {
+		cog.File = strings.ToLower(cog.Name[:1]) + cog.Name[1:]
+	}
+	if err := validateFileNameCharacters(cog.File); err != nil {
+		return err
+	}
+	if cog.Env == "" {
+		cog.Env = "development"
+	}
+	if cog.Env != "development" && cog.Env != "production" {
+		return fmt.Errorf("cog '%s': env must be development or production", cog.Name)
+	}
+
+	commands := append(slices.Clone(cog.SlashCommands), cog.PrefixCommands...)
+	cog.SlashCommands = []CommandInfo{}
+	cog.PrefixCommands = []CommandInfo{}
+	for i := range commands {
+		command := &commands[i]
+		if command.Scope == "" {
+			command.Scope = "guild"
+		}
+		if command.ReturnType == "" || HasFixedReturnType(command.Type) {
+			command.ReturnType = "None"
+		}
+		if err := ValidateCommand(*command, commands[:i]); err != nil {
+			return fmt.Errorf("cog '%s': command '%s': %w", cog.Name, command.Name, err)
+		}
+		if IsAppCommand(command.Type) {
+			cog.SlashCommands = append(cog.SlashCommands, *command)
+		} else {
+			// Prefix commands have no guild scope in Discord, the parser always reads them back as global
+			command.Scope = "global"
+			cog.PrefixCommands = append(cog.PrefixCommands, *command)
+		}
+	}
+	if err := ValidatePrefixGroups(commands); err != nil {
+		return fmt.Errorf("cog '%s': %w", cog.Name, err)
+	}
+	if err := ValidateListeners(cog.Listeners, commands, nil); err != nil {
+		return fmt.Errorf("cog '%s': %w", cog.Name, err)
+	}
+	if err := ValidateTasks(cog.Tasks, commands, cog.Listeners); err != nil {
+		return fmt.Errorf("cog '%s': %w", cog.Name, err)
+	}
+	return nil
+}
+
+/**
+ * PlanSpec
+ * Compares a spec with the project's config and works out the config applying it leaves behind.
+ * Cogs are matched by name, so a cog whose file changed is updated in place. The project's built-in cogs
+ * are kept whether the spec lists them or not, and a spec that lists one has it reported as skipped
+ * @param config {Config} - the project's current config
+ * @param spec {BotSpec} - the spec to apply
+ * @return SpecPlan - what applying the spec changes
+ * @return error - the spec asks for another library, which only botbox create can choose
+ **/
+func PlanSpec(config Config, spec BotSpec) (SpecPlan, error) {
+	plan := SpecPlan{
+		BotChanges:     []string{},
+		AddedCogs:      []string{},
+		UpdatedCogs:    []string{},
+		RemovedCogs:    []string{},
+		CommandChanges: []string{},
+		SkippedCogs:    []string{},
+		Previous:       config,
+	}
+
+	// Every generated file is written for the project's library, so a spec cannot switch it
+	library := NormalizeLibrary(config.BotInfo.Library)
+	if spec.BotInfo.Library != "" && spec.BotInfo.Library != library {
+		return plan, fmt.Errorf("the spec sets bot.library to %s but the project uses %s, the library can only be chosen when the project is created", spec.BotInfo.Library, library)
+	}
+
+	next := config
+	fields := []struct {
+		key     string
+		current *string
+		value   string
+	}{
+		{"name", &next.BotInfo.Name, spec.BotInfo.Name},
+		{"command_prefix", &next.BotInfo.CommandPrefix, spec.BotInfo.CommandPrefix},
+		{"author", &next.BotInfo.Author, spec.BotInfo.Author},
+		{"description", &next.BotInfo.Description, spec.BotInfo.Description},
+		{"help_style", &next.BotInfo.HelpStyle, spec.BotInfo.HelpStyle},
+		{"env_provider", &next.BotInfo.EnvProvider, spec.BotInfo.EnvProvider},
+	}
+	for _, field := range fields {
+		if field.value != "" && field.value != *field.current {
+			*field.current = field.value
+			plan.BotChanges = append(plan.BotChanges, field.key)
+		}
+	}
+
+	next.Cogs = make([]CogConfig, 0, len(spec.Cogs))
+	matched := make([]bool, len(config.Cogs))
+	for i, cog := range config.Cogs {
+		if IsBuiltinCog(cog) {
+			next.Cogs = append(next.Cogs, cog)
+			matched[i] = true
+		}
+	}
+	for _, cog := range spec.Cogs {
+		builtin := slices.ContainsFunc(config.Cogs, func(existing CogConfig) bool {
+			return IsBuiltinCog(existing) && (existing.Name == cog.Name || existing.File == cog.File)
+		})
+		if builtin {
+			plan.SkippedCogs = append(plan.SkippedCogs, cog.Name)
+			continue
+		}
+		next.Cogs = append(next.Cogs, cog)
+		index := slices.IndexFunc(config.Cogs, func(existing CogConfig) bool { return existing.Name == cog.Name })
+		if index < 0 {
+			plan.AddedCogs = append(plan.AddedCogs, cog.Name)
+			continue
+		}
+		matched[index] = true
+		if !cogEqual(config.Cogs[index], cog) {
+			plan.UpdatedCogs = append(plan.UpdatedCogs, cog.Name)
+			existing := append(slices.Clone(config.Cogs[index].SlashCommands), config.Cogs[index].PrefixCommands...)
+			wanted := append(slices.Clone(cog.SlashCommands), cog.PrefixCommands...)
+			plan.CommandChanges = append(plan.CommandChanges, commandChanges(cog.File, existing, wanted)...)
+		}
+	}
+	for i, cog := range config.Cogs {
+		if !matched[i] {
+			plan.RemovedCogs = append(plan.RemovedCogs, cog.Name)
+		}
+	}
+
+	plan.Config = next
+	return plan, nil
+}
+
+// cogEqual compares everything of two cogs that ends up in the cog file or botbox.conf
+func cogEqual(a, b CogConfig) bool {
+	return a.Name == b.Name && a.File == b.File && a.Env == b.Env &&
+		commandsEqual(a.SlashCommands, b.SlashCommands) && commandsEqual(a.PrefixCommands, b.PrefixCommands) &&
+		slices.Equal(a.Listeners, b.Listeners) && slices.EqualFunc(a.Tasks, b.Tasks, taskEqual)
+}
+
+/**
+ * StageSpec
+ * Stages the files of a plan: the cog files of added and updated cogs are regenerated, keeping the code in
+ * their protected regions, removed cogs lose their files, and botbox.conf and the locale files are rewritten.
+ * Bot info changes leave the other cogs alone, like config set does, and config sync reports their stale headers
+ * @param changes {*ChangeSet} - the change set to stage in
+ * @param rootDir {string} - the project root
+ * @param plan {SpecPlan} - the plan to stage
+ * @param backup {bool} - stage one backup of every file the plan replaces or removes
+ * @return string - the id of the backup, empty when none was staged
+ * @return error - any failure rendering or staging a file
+ **/
+func StageSpec(changes *ChangeSet, rootDir string, plan SpecPlan, backup bool) (string, error) {
+	if plan.Empty() {
+		return "", nil
+	}
+
+	var regenerate []CogConfig
+	for _, cog := range plan.Config.Cogs {
+		if slices.Contains(plan.AddedCogs, cog.Name) || slices.Contains(plan.UpdatedCogs, cog.Name) {
+			regenerate = append(regenerate, cog)
+		}
+	}
+
+	// Cogs removed from the spec, and the old files of cogs that moved, are deleted
+	generator, err := GeneratorFor(plan.Config.BotInfo.Library)
+	if err != nil {
+		return "", err
+	}
+	cogPath := func(cog CogConfig) string {
+		return filepath.Join(rootDir, generator.CogsDir(), generator.CogFileName(cog.File))
+	}
+	var removed []string
+	for _, cog := range plan.Previous.Cogs {
+		kept := slices.ContainsFunc(plan.Config.Cogs, func(next CogConfig) bool { return next.File == cog.File })
+		if !kept {
+			removed = append(removed, cogPath(cog))
+		}
+	}
+
+	backupID := ""
+	if backup {
+		paths := slices.Clone(removed)
+		for _, cog := range regenerate {
+			paths = append(paths, cogPath(cog))
+		}
+		paths = append(paths, filepath.Join(rootDir, "botbox.conf"))
+		if backupID, err = StageBackup(changes, rootDir, "apply spec", paths...); err != nil {
+			return "", fmt.Errorf("failed to back up project files: %w", err)
+		}
+	}
+
+	for _, cog := range regenerate {
+		// The previous config renders the code the current files were generated from, so their regions merge cleanly,
+		// and the new one renders the files with the spec's bot info in their headers
+		if _, err := RegenerateCogFile(changes, rootDir, plan.Previous, plan.Config, cog, false); err != nil {
+			return "", fmt.Errorf("cog '%s': %w", cog.Name, err)
+		}
+	}
+	for _, path := range removed {
+		if err := changes.RemoveFile(path); err != nil {
+			return "", fmt.Errorf("failed to remove cog file: %w", err)
+		}
+	}
+
+	if err := StageConfig(changes, rootDir, plan.Config); err != nil {
+		return "", err
+	}
+	if _, err := UpdateLocaleFiles(changes, rootDir, plan.Config, nil, true); err != nil {
+		return "", fmt.Errorf("failed to write locale files: %w", err)
+	}
+
+	return backupID, nil
+}
+
+/*
+Copyright © 2025 Austin "Choice404" Choi
+
+https://github.com/choice404/botbox
+
+Bot Box
+A discord bot template generator to help create discord bots quickly and easily
+
+This code is licensed under the MIT License.
+
+MIT License
+
+Copyright (c) 2025 Austin Choi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
@@ -0,0 +1,307 @@
+/*
+Copyright © 2025 Austin "Choice404" Choi
+See end of file for extended copyright information
+*/
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// writeSpec writes a spec file into a temp dir and returns its path
+func writeSpec(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "bot.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write spec: %v", err)
+	}
+	return path
+}
+
+// specTestConfig is a project with a greet cog and a cog the spec leaves out
+func specTestConfig() Config {
+	return Config{
+		BotBox:  BotBoxConfig{Version: "2.11.0"},
+		BotInfo: BotConfig{Name: "TestBot", CommandPrefix: "!", Author: "Tester", Description: "A test bot", HelpStyle: "compact", EnvProvider: "env"},
+		Cogs: []CogConfig{
+			{
+				Name:           "Greet",
+				Env:            "development",
+				File:           "greet",
+				SlashCommands:  []CommandInfo{{Name: "hello", Scope: "guild", Type: "slash", Description: "Says hello", ReturnType: "None"}},
+				PrefixCommands: []CommandInfo{},
+			},
+			{Name: "Old", Env: "development", File: "old", SlashCommands: []CommandInfo{}, PrefixCommands: []CommandInfo{}},
+		},
+	}
+}
+
+const specTestYAML = `bot:
+  description: Greets people
+cogs:
+  - name: greet
+    slash_commands:
+      - Name: hello
+        Type: slash
+        Description: Says hello warmly
+      - Name: wave
+        Type: prefix
+        Description: Waves back
+  - name: tickets
+    env: production
+    slash_commands:
+      - Name: open
+        Type: slash
+        Description: Opens a ticket
+        Args:
+          - Name: reason
+            Type: str
+            Description: Why the ticket is opened
+    tasks:
+      - Name: digest
+        Interval: 6
+        Unit: hours
+`
+
+func TestLoadSpecFillsDefaults(t *testing.T) {
+	spec, err := LoadSpec(writeSpec(t, specTestYAML))
+	if err != nil {
+		t.Fatalf("LoadSpec returned error: %v", err)
+	}
+
+	if spec.BotInfo.Description != "Greets people" || spec.BotInfo.Name != "" {
+		t.Errorf("bot info not read as written: %+v", spec.BotInfo)
+	}
+	if len(spec.Cogs) != 2 {
+		t.Fatalf("expected 2 cogs, got %d", len(spec.Cogs))
+	}
+
+	greet := spec.Cogs[0]
+	if greet.Name != "Greet" || greet.File != "greet" || greet.Env != "development" {
+		t.Errorf("greet cog defaults wrong: name %q file %q env %q", greet.Name, greet.File, greet.Env)
+	}
+	if len(greet.SlashCommands) != 1 || len(greet.PrefixCommands) != 1 {
+		t.Fatalf("commands not sorted by type: %d slash, %d prefix", len(greet.SlashCommands), len(greet.PrefixCommands))
+	}
+	if hello := greet.SlashCommands[0]; hello.Scope != "guild" || hello.ReturnType != "None" {
+		t.Errorf("slash command defaults wrong: scope %q return %q", hello.Scope, hello.ReturnType)
+	}
+	if wave := greet.PrefixCommands[0]; wave.Name != "wave" || wave.Scope != "global" {
+		t.Errorf("prefix command should be moved and made global: %+v", wave)
+	}
+
+	tickets := spec.Cogs[1]
+	if tickets.Name != "Tickets" || tickets.Env != "production" || len(tickets.Tasks) != 1 || tickets.Tasks[0].Interval != 6 {
+		t.Errorf("tickets cog not read as written: %+v", tickets)
+	}
+}
+
+func TestLoadSpecRejectsBadSpecs(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		want string
+	}{
+		{"empty", "", "empty"},
+		{"unknown key", "cogs:\n  - name: greet\n    command: hello\n", "unknown field"},
+		{"bad command", "cogs:\n  - name: greet\n    slash_commands:\n      - Name: hello\n        Type: shout\n        Description: Hi\n", "command 'hello'"},
+		{"bad env", "cogs:\n  - name: greet\n    env: staging\n", "env must be"},
+		{"bad file", "cogs:\n  - name: greet\n    file: greet.py\n", "filename cannot contain"},
+		{"duplicate cog", "cogs:\n  - name: greet\n  - name: Greet\n", "listed twice"},
+		{"nameless cog", "cogs:\n  - env: production\n", "needs a name"},
+		{"bad bot", "bot:\n  command_prefix: a\n", "bot:"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := LoadSpec(writeSpec(t, test.spec))
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if !strings.Contains(err.Error(), test.want) {
+				t.Errorf("error %q does not mention %q", err, test.want)
+			}
+		})
+	}
+}
+
+func TestPlanSpec(t *testing.T) {
+	spec, err := LoadSpec(writeSpec(t, specTestYAML))
+	if err != nil {
+		t.Fatalf("LoadSpec returned error: %v", err)
+	}
+
+	plan, err := PlanSpec(specTestConfig(), spec)
+	if err != nil {
+		t.Fatalf("PlanSpec returned error: %v", err)
+	}
+
+	if !slices.Equal(plan.BotChanges, []string{"description"}) {
+		t.Errorf("bot changes = %v", plan.BotChanges)
+	}
+	if !slices.Equal(plan.AddedCogs, []string{"Tickets"}) || !slices.Equal(plan.UpdatedCogs, []string{"Greet"}) || !slices.Equal(plan.RemovedCogs, []string{"Old"}) {
+		t.Errorf("cogs planned wrong: added %v updated %v removed %v", plan.AddedCogs, plan.UpdatedCogs, plan.RemovedCogs)
+	}
+	wantChanges := []string{`greet: slash command "hello" changed`, `greet: prefix command "wave" added`}
+	if !slices.Equal(plan.CommandChanges, wantChanges) {
+		t.Errorf("command changes = %q, want %q", plan.CommandChanges, wantChanges)
+	}
+	if plan.Config.BotInfo.Name != "TestBot" || plan.Config.BotInfo.Description != "Greets people" {
+		t.Errorf("bot fields the spec leaves empty should be kept: %+v", plan.Config.BotInfo)
+	}
+	if len(plan.Config.Cogs) != 2 || plan.Config.Cogs[1].Name != "Tickets" {
+		t.Errorf("planned config should hold the spec's cogs in order: %+v", plan.Config.Cogs)
+	}
+}
+
+func TestPlanSpecKeepsBuiltinCogs(t *testing.T) {
+	config := specTestConfig()
+	help := CogConfig{Name: "Help", Env: "development", File: "help", SlashCommands: []CommandInfo{}, PrefixCommands: []CommandInfo{}}
+	config.Cogs = append(config.Cogs, help)
+
+	spec, err := LoadSpec(writeSpec(t, specTestYAML+`  - name: help
+    slash_commands:
+      - Name: help
+        Type: slash
+        Description: Lists commands
+`))
+	if err != nil {
+		t.Fatalf("LoadSpec returned error: %v", err)
+	}
+
+	plan, err := PlanSpec(config, spec)
+	if err != nil {
+		t.Fatalf("PlanSpec returned error: %v", err)
+	}
+	if !slices.Equal(plan.SkippedCogs, []string{"Help"}) {
+		t.Errorf("skipped cogs = %v, want [Help]", plan.SkippedCogs)
+	}
+	if slices.Contains(plan.UpdatedCogs, "Help") || slices.Contains(plan.RemovedCogs, "Help") {
+		t.Errorf("the built-in help cog should be left alone: updated %v removed %v", plan.UpdatedCogs, plan.RemovedCogs)
+	}
+	index := slices.IndexFunc(plan.Config.Cogs, func(cog CogConfig) bool { return cog.Name == "Help" })
+	if index < 0 || len(plan.Config.Cogs[index].SlashCommands) != 0 {
+		t.Errorf("the built-in help cog should be kept as the project has it: %+v", plan.Config.Cogs)
+	}
+
+	// A spec leaving the built-in cog out does not remove it either
+	spec, err = LoadSpec(writeSpec(t, specTestYAML))
+	if err != nil {
+		t.Fatalf("LoadSpec returned error: %v", err)
+	}
+	if plan, err = PlanSpec(config, spec); err != nil {
+		t.Fatalf("PlanSpec returned error: %v", err)
+	}
+	if slices.Contains(plan.RemovedCogs, "Help") || len(plan.SkippedCogs) != 0 {
+		t.Errorf("the built-in help cog should not be removed: removed %v skipped %v", plan.RemovedCogs, plan.SkippedCogs)
+	}
+}
+
+func TestPlanSpecRejectsLibraryChange(t *testing.T) {
+	spec, err := LoadSpec(writeSpec(t, "bot:\n  library: disnake\ncogs: []\n"))
+	if err != nil {
+		t.Fatalf("LoadSpec returned error: %v", err)
+	}
+	if _, err := PlanSpec(specTestConfig(), spec); err == nil || !strings.Contains(err.Error(), "bot.library") {
+		t.Errorf("expected a library change to be refused, got %v", err)
+	}
+
+	spec.BotInfo.Library = LibraryDiscordPy
+	if _, err := PlanSpec(specTestConfig(), spec); err != nil {
+		t.Errorf("the project's own library should be accepted, got %v", err)
+	}
+}
+
+func TestStageSpecIsIdempotent(t *testing.T) {
+	rootDir := newTestProject(t, specTestConfig())
+	oldPath := filepath.Join(rootDir, "src", "cogs", "old.py")
+	if err := os.WriteFile(oldPath, []byte("# old cog\n"), 0644); err != nil {
+		t.Fatalf("failed to write old cog: %v", err)
+	}
+
+	spec, err := LoadSpec(writeSpec(t, specTestYAML))
+	if err != nil {
+		t.Fatalf("LoadSpec returned error: %v", err)
+	}
+
+	var backupID string
+	stageAndApply(t, func(changes *ChangeSet) error {
+		plan, err := PlanSpec(specTestConfig(), spec)
+		if err != nil {
+			return err
+		}
+		backupID, err = StageSpec(changes, rootDir, plan, true)
+		return err
+	})
+
+	if backupID == "" {
+		t.Error("expected a backup of the replaced files")
+	}
+	if _, err := os.Stat(oldPath); !os.IsNotExist(err) {
+		t.Error("the cog missing from the spec should be removed")
+	}
+	for _, file := range []string{"greet.py", "tickets.py"} {
+		content, err := os.ReadFile(filepath.Join(rootDir, "src", "cogs", file))
+		if err != nil {
+			t.Errorf("expected %s to be generated: %v", file, err)
+			continue
+		}
+		// Both the regenerated and the new cog carry the spec's bot description in their headers
+		if !strings.Contains(string(content), "Greets people") || strings.Contains(string(content), "A test bot") {
+			t.Errorf("%s should be rendered with the spec's bot info:\n%s", file, content)
+		}
+	}
+
+	var config Config
+	readJSONFile(t, filepath.Join(rootDir, "botbox.conf"), &config)
+	if plan, err := PlanSpec(config, spec); err != nil || !plan.Empty() {
+		t.Errorf("applying the spec twice should change nothing, got %+v", plan)
+	}
+
+	// The generated cog files read back as the spec describes them
+	result, err := StageCogSync(&ChangeSet{})
+	if err != nil {
+		t.Fatalf("StageCogSync returned error: %v", err)
+	}
+	if len(result.UpdatedCogs) != 0 || len(result.AddedCogs) != 0 || len(result.RemovedCogs) != 0 {
+		t.Errorf("cog files drifted from the applied spec: %+v", result)
+	}
+}
+
+/*
+Copyright © 2025 Austin "Choice404" Choi
+
+https://github.com/choice404/botbox
+
+Bot Box
+A discord bot template generator to help create discord bots quickly and easily
+
+This code is licensed under the MIT License.
+
+MIT License
+
+Copyright (c) 2025 Austin Choi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
@@ -177,6 +177,13 @@ type Model struct {
 	ModelValues       Values
 	displayKeys       []string
 	Error             []error
+	// Changes collects the files the callback writes, they reach the disk once the callback succeeds
+	Changes *ChangeSet
+	// DryRun keeps the staged changes off the disk, they are only shown
+	DryRun bool
+	// reviewChanges shows the staged changes as a diff before they are written, reviewing is set while they are shown
+	reviewChanges bool
+	reviewing     bool
 }
 
 func (m *Model) resetViewport() {
@@ -190,7 +197,9 @@ func (m *Model) getMaxViewportOffset() int {
 
 	var content string
 
-	if m.Error != nil {
+	if m.reviewing {
+		content = m.reviewContent()
+	} else if m.Error != nil {
 		var b strings.Builder
 		b.WriteString("Errors:\n\n")
 		for _, err := range m.Error {
@@ -269,6 +278,9 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "enter":
 			if m.state == stateDone {
 				if m.forms[m.currentFormPtr].ShowStatus || m.currentFormPtr >= len(m.forms)-1 {
+					if m.reviewChanges {
+						return m.reviewEnter()
+					}
 					m.executeCallback()
 					return m, tea.Quit
 				}
@@ -405,7 +417,10 @@ func (m Model) View() string {
 			var content string
 			var header string
 
-			if m.Error != nil {
+			if m.reviewing {
+				content = m.reviewContent()
+				header = m.appBoundaryView(m.title)
+			} else if m.Error != nil {
 				var b strings.Builder
 				b.WriteString("Errors:\n\n")
 				for _, err := range m.Error {
@@ -479,6 +494,14 @@ func (m Model) View() string {
 			}
 
 			confirmPrompt := s.Highlight.Render("Press Enter to submit, or Esc/Q to cancel.")
+			switch {
+			case m.reviewing && m.DryRun:
+				confirmPrompt = s.Highlight.Render("Dry run, nothing is written. Press Enter or Esc/Q to exit.")
+			case m.reviewing:
+				confirmPrompt = s.Highlight.Render("Press Enter to write these changes, or Esc/Q to cancel.")
+			case m.reviewChanges && m.Error == nil:
+				confirmPrompt = s.Highlight.Render("Press Enter to review the changes, or Esc/Q to cancel.")
+			}
 
 			message := header + "\n" + statusBox
 			if scrollIndicator != "" {
@@ -594,9 +617,83 @@ func CupSleeve(cup Model) {
 }
 
 func (m *Model) executeCallback() {
+	if m.Changes == nil {
+		m.Changes = &ChangeSet{}
+	}
+	if m.callback == nil {
+		return
+	}
+	errs := m.callback(m)
+	if len(errs) == 0 && !m.DryRun {
+		if err := m.Changes.Apply(); err != nil {
+			errs = []error{err}
+		}
+	}
+	m.HandleError(errs)
+}
+
+// reviewEnter handles enter on the final screen of a model that reviews its changes, the first enter
+// runs the callback and shows the files it staged as a diff, the next one writes them
+func (m *Model) reviewEnter() (tea.Model, tea.Cmd) {
+	if m.Error != nil {
+		return m, tea.Quit
+	}
+
+	if m.reviewing {
+		m.reviewing = false
+		if !m.DryRun {
+			if err := m.Changes.Apply(); err != nil {
+				m.HandleError([]error{err})
+				return m, nil
+			}
+		}
+		return m, tea.Quit
+	}
+
+	if m.Changes == nil {
+		m.Changes = &ChangeSet{}
+	}
 	if m.callback != nil {
-		m.HandleError(m.callback(m))
+		if errs := m.callback(m); len(errs) > 0 {
+			m.HandleError(errs)
+			return m, nil
+		}
+	}
+	if m.Changes.Empty() {
+		return m, tea.Quit
+	}
+	m.reviewing = true
+	m.resetViewport()
+	return m, nil
+}
+
+// reviewContent renders the staged changes as a colored unified diff for the review screen
+func (m *Model) reviewContent() string {
+	s := m.styles
+	rootDir, _ := FindBotConf()
+
+	var display strings.Builder
+	header := "Review changes"
+	if m.DryRun {
+		header += " (dry run)"
+	}
+	display.WriteString(s.HeaderText.Render(header) + "\n\n")
+
+	for _, line := range strings.Split(strings.TrimSuffix(m.Changes.Diff(rootDir), "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++ "), strings.HasPrefix(line, "--- "), strings.HasPrefix(line, "Backup "):
+			display.WriteString(s.KeyText.Render(line) + "\n")
+		case strings.HasPrefix(line, "@@"):
+			display.WriteString(s.Help.Render(line) + "\n")
+		case strings.HasPrefix(line, "+"):
+			display.WriteString(s.DiffAdded.Render(line) + "\n")
+		case strings.HasPrefix(line, "-"):
+			display.WriteString(s.DiffRemoved.Render(line) + "\n")
+		default:
+			display.WriteString(line + "\n")
+		}
 	}
+	return display.String()
 }
 
 func (m *Model) SetTitle(title string) {
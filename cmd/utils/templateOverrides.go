@@ -0,0 +1,385 @@
+/*
+Copyright © 2025 Austin "Choice404" Choi
+See end of file for extended copyright information
+*/
+
+package utils
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// TemplateOverridesDir holds a project's template overrides, relative to the project root
+var TemplateOverridesDir = filepath.Join(".botbox", "templates")
+
+// Scopes of a template override, a project override wins over a user override
+const (
+	OverrideScopeProject = "project"
+	OverrideScopeUser    = "user"
+)
+
+// TemplateOverride is a file replacing the embedded template of the same name
+type TemplateOverride struct {
+	Name  string `json:"name"`
+	Path  string `json:"path"`
+	Scope string `json:"scope"`
+	// Problem says why the override cannot be used, empty when it can
+	Problem string `json:"problem,omitempty"`
+}
+
+// templateDataTypes are the data structs the embedded templates render, the rest render projectTemplateData
+var templateDataTypes = map[string]reflect.Type{
+	"cog.py.tmpl":             reflect.TypeFor[CogTemplateData](),
+	"dockerfile.tmpl":         reflect.TypeFor[dockerTemplateData](),
+	"docker-compose.yml.tmpl": reflect.TypeFor[dockerTemplateData](),
+	"dockerignore.tmpl":       reflect.TypeFor[dockerTemplateData](),
+}
+
+// templateDataType is the data struct an embedded template renders, a library set's template renders the same
+// data as the discord.py template of its name
+func templateDataType(name string) reflect.Type {
+	if dataType, ok := templateDataTypes[path.Base(name)]; ok {
+		return dataType
+	}
+	return reflect.TypeFor[projectTemplateData]()
+}
+
+// EmbeddedTemplates lists the names of the templates compiled into botbox, the names overrides use.
+// Templates of a library set are named with their directory, like nextcord/cog.py.tmpl
+func EmbeddedTemplates() []string {
+	var names []string
+	fs.WalkDir(templateFS, "templates", func(name string, entry fs.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() {
+			names = append(names, strings.TrimPrefix(name, "templates/"))
+		}
+		return nil
+	})
+	return names
+}
+
+// isEmbeddedTemplate reports whether name is one of the templates compiled into botbox
+func isEmbeddedTemplate(name string) bool {
+	return slices.Contains(EmbeddedTemplates(), name)
+}
+
+// templateOverrideDirs are the directories searched for overrides in order, the project's first when inside one
+func templateOverrideDirs() []struct{ dir, scope string } {
+	var dirs []struct{ dir, scope string }
+	if rootDir, err := FindBotConf(); err == nil {
+		dirs = append(dirs, struct{ dir, scope string }{filepath.Join(rootDir, TemplateOverridesDir), OverrideScopeProject})
+	}
+	if userDir, err := UserTemplatesDir(); err == nil {
+		dirs = append(dirs, struct{ dir, scope string }{userDir, OverrideScopeUser})
+	}
+	return dirs
+}
+
+// findTemplateOverride is the path of the override used for an embedded template, false when it is not overridden.
+// Only files count, directories in the user templates directory are project templates
+func findTemplateOverride(name string) (string, bool) {
+	for _, dir := range templateOverrideDirs() {
+		path := filepath.Join(dir.dir, filepath.FromSlash(name))
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+/**
+ * ListTemplateOverrides
+ * Lists the override files in the project and user template directories, checking each one against the data
+ * its embedded template renders. An override shadowed by a project override of the same name is left out
+ * @return []TemplateOverride - the overrides, project overrides first
+ * @return error - a template directory that could not be read
+ **/
+func ListTemplateOverrides() ([]TemplateOverride, error) {
+	var overrides []TemplateOverride
+	for _, dir := range templateOverrideDirs() {
+		entries, err := os.ReadDir(dir.dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read templates directory: %w", err)
+		}
+		names, err := overrideFileNames(dir.dir, entries)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			if slices.ContainsFunc(overrides, func(o TemplateOverride) bool { return o.Name == name }) {
+				continue
+			}
+			override := TemplateOverride{Name: name, Path: filepath.Join(dir.dir, filepath.FromSlash(name)), Scope: dir.scope}
+			if !isEmbeddedTemplate(override.Name) {
+				override.Problem = "no embedded template has this name, it is never used"
+			} else if _, err := parseTemplateFile(override.Path, templateDataType(override.Name)); err != nil {
+				override.Problem = err.Error()
+			}
+			overrides = append(overrides, override)
+		}
+	}
+	return overrides, nil
+}
+
+// overrideFileNames names the files of a template directory, the files inside a library set's directory like
+// nextcord/cog.py.tmpl included. Other directories are project templates and are left out
+func overrideFileNames(dir string, entries []os.DirEntry) ([]string, error) {
+	var names []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() {
+			names = append(names, entry.Name())
+			continue
+		}
+		if !entry.IsDir() || !slices.Contains(GeneratorNames(), entry.Name()) {
+			continue
+		}
+		files, err := os.ReadDir(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read templates directory: %w", err)
+		}
+		for _, file := range files {
+			if file.Type().IsRegular() {
+				names = append(names, entry.Name()+"/"+file.Name())
+			}
+		}
+	}
+	return names, nil
+}
+
+/**
+ * EjectTemplate
+ * Copies an embedded template into a template directory so it can be customised as an override
+ * @param name {string} - the embedded template, like cog.py.tmpl
+ * @param dir {string} - the project or user template directory
+ * @param force {bool} - replace an override that already exists
+ * @return string - the path written
+ * @return error - an unknown template or an existing override
+ **/
+func EjectTemplate(name string, dir string, force bool) (string, error) {
+	if !isEmbeddedTemplate(name) {
+		return "", fmt.Errorf("unknown template '%s', use one of %s", name, strings.Join(EmbeddedTemplates(), ", "))
+	}
+	content, err := templateFS.ReadFile("templates/" + name)
+	if err != nil {
+		return "", fmt.Errorf("failed to read template %s: %w", name, err)
+	}
+
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if _, err := os.Stat(path); err == nil && !force {
+		return "", fmt.Errorf("%s already exists, use --force to replace it", path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return "", fmt.Errorf("failed to create templates directory: %w", err)
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return path, nil
+}
+
+/**
+ * parseTemplateFile
+ * Parses a template file with the << >> delimiters and helpers of the embedded templates, and checks every field
+ * it reads exists on the data it renders, so a typo fails before anything is written instead of in a branch
+ * no test took
+ * @param path {string} - the template file
+ * @param dataType {reflect.Type} - the type of the data it renders
+ * @return *template.Template - the parsed template
+ * @return error - a parse error or a field the data does not have
+ **/
+func parseTemplateFile(path string, dataType reflect.Type) (*template.Template, error) {
+	tmpl, err := template.New(filepath.Base(path)).Delims("<<", ">>").Funcs(templateFuncs).ParseFiles(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", path, err)
+	}
+
+	checker := fieldChecker{root: dataType}
+	checker.walk(tmpl.Tree.Root, dataType)
+	if checker.err != nil {
+		return nil, fmt.Errorf("template %s does not match %s: %w", path, dataType.Name(), checker.err)
+	}
+	return tmpl, nil
+}
+
+// fieldChecker walks a template's parse tree, checking each field against the type dot has at that point.
+// A nil type is one it cannot know, like a variable or the result of a builtin, and is not checked
+type fieldChecker struct {
+	root reflect.Type
+	err  error
+}
+
+func (c *fieldChecker) walk(node parse.Node, dot reflect.Type) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			c.walk(child, dot)
+		}
+	case *parse.ActionNode:
+		c.pipe(n.Pipe, dot)
+	case *parse.IfNode:
+		c.pipe(n.Pipe, dot)
+		c.walk(n.List, dot)
+		c.walk(n.ElseList, dot)
+	case *parse.WithNode:
+		c.walk(n.List, c.pipe(n.Pipe, dot))
+		c.walk(n.ElseList, dot)
+	case *parse.RangeNode:
+		c.walk(n.List, elemType(c.pipe(n.Pipe, dot)))
+		c.walk(n.ElseList, dot)
+	case *parse.TemplateNode:
+		c.pipe(n.Pipe, dot)
+	}
+}
+
+// pipe checks a pipeline and returns the type it evaluates to
+func (c *fieldChecker) pipe(pipe *parse.PipeNode, dot reflect.Type) reflect.Type {
+	if pipe == nil {
+		return nil
+	}
+	var result reflect.Type
+	for _, cmd := range pipe.Cmds {
+		result = c.command(cmd, dot)
+	}
+	return result
+}
+
+// command checks a command's arguments and returns its type, a helper returns its first result
+func (c *fieldChecker) command(cmd *parse.CommandNode, dot reflect.Type) reflect.Type {
+	var result reflect.Type
+	for i, arg := range cmd.Args {
+		if argType := c.arg(arg, dot); i == 0 {
+			result = argType
+		}
+	}
+	if ident, ok := cmd.Args[0].(*parse.IdentifierNode); ok {
+		if fn, ok := templateFuncs[ident.Ident]; ok && reflect.TypeOf(fn).NumOut() > 0 {
+			return reflect.TypeOf(fn).Out(0)
+		}
+		return nil
+	}
+	return result
+}
+
+func (c *fieldChecker) arg(node parse.Node, dot reflect.Type) reflect.Type {
+	switch n := node.(type) {
+	case *parse.DotNode:
+		return dot
+	case *parse.FieldNode:
+		return c.field(n, dot, n.Ident)
+	case *parse.VariableNode:
+		// Only $ is known, it is the data the template was executed with
+		if n.Ident[0] == "$" {
+			return c.field(n, c.root, n.Ident[1:])
+		}
+	case *parse.ChainNode:
+		return c.field(n, c.arg(n.Node, dot), n.Field)
+	case *parse.PipeNode:
+		return c.pipe(n, dot)
+	}
+	return nil
+}
+
+// field follows a chain of field or method names from a type, recording the first name the type does not have
+func (c *fieldChecker) field(node parse.Node, t reflect.Type, names []string) reflect.Type {
+	for _, name := range names {
+		for t != nil && t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() == reflect.Interface {
+			return nil
+		}
+		if method, ok := reflect.PointerTo(t).MethodByName(name); ok {
+			if method.Type.NumOut() == 0 {
+				return nil
+			}
+			t = method.Type.Out(0)
+			continue
+		}
+		switch t.Kind() {
+		case reflect.Struct:
+			field, ok := t.FieldByName(name)
+			if !ok || !field.IsExported() {
+				c.fail(node, fmt.Errorf("%s has no field %s", t.Name(), name))
+				return nil
+			}
+			t = field.Type
+		case reflect.Map:
+			t = t.Elem()
+		default:
+			c.fail(node, fmt.Errorf("can't read field %s of %s", name, t))
+			return nil
+		}
+	}
+	return t
+}
+
+// fail keeps the first error with the line it was found on
+func (c *fieldChecker) fail(node parse.Node, err error) {
+	if c.err != nil {
+		return
+	}
+	location, _ := (*parse.Tree)(nil).ErrorContext(node)
+	c.err = fmt.Errorf("%s: %w", location, err)
+}
+
+// elemType is the type range sets dot to when ranging over a value of type t
+func elemType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil {
+		return nil
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Chan:
+		return t.Elem()
+	}
+	return nil
+}
+
+/*
+Copyright © 2025 Austin "Choice404" Choi
+
+https://github.com/choice404/botbox
+
+Bot Box
+A discord bot template generator to help create discord bots quickly and easily
+
+This code is licensed under the MIT License.
+
+MIT License
+
+Copyright (c) 2025 Austin Choi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
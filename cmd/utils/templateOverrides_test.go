@@ -0,0 +1,194 @@
+/*
+Copyright © 2025 Austin "Choice404" Choi
+See end of file for extended copyright information
+*/
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeOverride writes a template override file into dir
+func writeOverride(t *testing.T, dir string, name string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("failed to create %s: %v", dir, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write override %s: %v", name, err)
+	}
+}
+
+func TestEmbeddedTemplatesMatchTheirData(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range EmbeddedTemplates() {
+		path, err := EjectTemplate(name, dir, false)
+		if err != nil {
+			t.Fatalf("EjectTemplate(%s) returned error: %v", name, err)
+		}
+		if _, err := parseTemplateFile(path, templateDataType(name)); err != nil {
+			t.Errorf("embedded template %s fails its own check: %v", name, err)
+		}
+	}
+}
+
+func TestRenderTemplateOverrides(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	rootDir := newTestProject(t, Config{})
+	data := CogTemplateData{ClassName: "Greeter"}
+
+	embedded, err := RenderTemplate("cog.py.tmpl", data)
+	if err != nil {
+		t.Fatalf("RenderTemplate returned error: %v", err)
+	}
+	if !strings.Contains(embedded, "class Greeter") {
+		t.Fatalf("expected the embedded cog template, got:\n%s", embedded)
+	}
+
+	userDir := filepath.Join(home, ".config", "botbox", "templates")
+	writeOverride(t, userDir, "cog.py.tmpl", "user <<.ClassName>>\n")
+	if got, _ := RenderTemplate("cog.py.tmpl", data); got != "user Greeter\n" {
+		t.Errorf("expected the user override, got %q", got)
+	}
+
+	writeOverride(t, filepath.Join(rootDir, TemplateOverridesDir), "cog.py.tmpl", "project <<.ClassName>>\n")
+	if got, _ := RenderTemplate("cog.py.tmpl", data); got != "project Greeter\n" {
+		t.Errorf("expected the project override to win, got %q", got)
+	}
+
+	// A user project template directory is not an override even when named like one
+	if err := os.MkdirAll(filepath.Join(userDir, "help.py.tmpl"), 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if got, err := RenderTemplate("help.py.tmpl", projectTemplateData{}); err != nil || strings.HasPrefix(got, "user") {
+		t.Errorf("expected the embedded help template, got %q, %v", got, err)
+	}
+
+	writeOverride(t, filepath.Join(rootDir, TemplateOverridesDir), "cog.py.tmpl", "<<if false>><<.ClassNam>><<end>>\n")
+	if _, err := RenderTemplate("cog.py.tmpl", data); err == nil || !strings.Contains(err.Error(), "CogTemplateData has no field ClassNam") {
+		t.Errorf("expected the override to be checked against CogTemplateData, got %v", err)
+	}
+}
+
+func TestParseTemplateFileChecksFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		wantErr  string
+	}{
+		{"known fields", "<<.ClassName>> <<.BotName>>", ""},
+		{"range over commands", "<<range .SlashCommands>><<.Name>> <<$.ClassName>><<end>>", ""},
+		{"range over a helper result", "<<range groups .SlashCommands>><<.Path>><<end>>", ""},
+		{"variables are not checked", "<<range $i, $cmd := .SlashCommands>><<$cmd.Anything>><<end>>", ""},
+		{"unknown field", "line one\n<<.ClassNam>>", "2:"},
+		{"unknown field in a branch", "<<if false>><<.Bogus>><<end>>", "has no field Bogus"},
+		{"unknown field of a range element", "<<range .SlashCommands>><<.Nme>><<end>>", "CommandInfo has no field Nme"},
+		{"unknown root field", "<<range .Tasks>><<$.Class>><<end>>", "CogTemplateData has no field Class"},
+		{"field of a string", "<<.ClassName.Upper>>", "can't read field Upper"},
+		{"unknown helper", "<<shout .ClassName>>", "function \"shout\" not defined"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeOverride(t, dir, "cog.py.tmpl", tt.template)
+			_, err := parseTemplateFile(filepath.Join(dir, "cog.py.tmpl"), reflect.TypeFor[CogTemplateData]())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("parseTemplateFile() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseTemplateFile() error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEjectAndListTemplateOverrides(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	rootDir := newTestProject(t, Config{})
+	projectDir := filepath.Join(rootDir, TemplateOverridesDir)
+	userDir := filepath.Join(home, ".config", "botbox", "templates")
+
+	if _, err := EjectTemplate("nope.py.tmpl", projectDir, false); err == nil {
+		t.Error("expected an error for a template botbox does not have")
+	}
+	path, err := EjectTemplate("cog.py.tmpl", projectDir, false)
+	if err != nil {
+		t.Fatalf("EjectTemplate returned error: %v", err)
+	}
+	if embedded, _ := templateFS.ReadFile("templates/cog.py.tmpl"); readOutput(t, path) != string(embedded) {
+		t.Error("the ejected template should match the embedded one")
+	}
+	if _, err := EjectTemplate("cog.py.tmpl", projectDir, false); err == nil {
+		t.Error("expected an error replacing an override without force")
+	}
+	if _, err := EjectTemplate("cog.py.tmpl", projectDir, true); err != nil {
+		t.Errorf("EjectTemplate with force returned error: %v", err)
+	}
+
+	writeOverride(t, userDir, "cog.py.tmpl", "shadowed by the project override")
+	writeOverride(t, userDir, "help.py.tmpl", "<<.Nam>>")
+	writeOverride(t, userDir, "notes.txt", "")
+
+	overrides, err := ListTemplateOverrides()
+	if err != nil {
+		t.Fatalf("ListTemplateOverrides returned error: %v", err)
+	}
+	want := []TemplateOverride{
+		{Name: "cog.py.tmpl", Path: path, Scope: OverrideScopeProject},
+		{Name: "help.py.tmpl", Path: filepath.Join(userDir, "help.py.tmpl"), Scope: OverrideScopeUser, Problem: "projectTemplateData has no field Nam"},
+		{Name: "notes.txt", Path: filepath.Join(userDir, "notes.txt"), Scope: OverrideScopeUser, Problem: "no embedded template"},
+	}
+	if len(overrides) != len(want) {
+		t.Fatalf("ListTemplateOverrides() = %+v, want %d overrides", overrides, len(want))
+	}
+	for i, override := range overrides {
+		if override.Name != want[i].Name || override.Path != want[i].Path || override.Scope != want[i].Scope ||
+			!strings.Contains(override.Problem, want[i].Problem) || (want[i].Problem == "") != (override.Problem == "") {
+			t.Errorf("override %d = %+v, want %+v", i, override, want[i])
+		}
+	}
+}
+
+/*
+Copyright © 2025 Austin "Choice404" Choi
+
+https://github.com/choice404/botbox
+
+Bot Box
+A discord bot template generator to help create discord bots quickly and easily
+
+This code is licensed under the MIT License.
+
+MIT License
+
+Copyright (c) 2025 Austin Choi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
@@ -9,8 +9,14 @@ import (
 	"embed"
 	"encoding/json"
 	"fmt"
+	"path"
+	"reflect"
+	"slices"
+	"strconv"
 	"strings"
 	"text/template"
+	"unicode"
+	"unicode/utf8"
 )
 
 //go:embed all:templates
@@ -25,30 +31,78 @@ type CogTemplateData struct {
 	Filename       string
 	SlashCommands  []CommandInfo
 	PrefixCommands []CommandInfo
+	Listeners      []ListenerInfo
+	Tasks          []TaskInfo
 }
 
 // templateFuncs holds the helpers available inside all templates
 var templateFuncs = template.FuncMap{
-	"returnValue":       GetReturnValue,
-	"argString":         BuildArgString,
-	"underscore":        underscoreName,
-	"modalClass":        ModalClassName,
-	"modalTitle":        modalTitle,
-	"pyBool":            pythonBool,
-	"pascal":            pascalName,
-	"cmdConst":          CommandConstName,
-	"pageModal":         pageModalClass,
-	"flowJSON":          flowJSON,
-	"responseContent":   responseContent,
-	"responseEphemeral": responseEphemeral,
-}
-
-// RenderTemplate renders the named embedded template with the given data
+	"returnValue":         GetReturnValue,
+	"argString":           BuildArgString,
+	"prefixArgString":     BuildPrefixArgString,
+	"transformers":        transformers,
+	"usesUnion":           usesUnion,
+	"optionType":          optionType,
+	"underscore":          underscoreName,
+	"modalClass":          ModalClassName,
+	"modalTitle":          modalTitle,
+	"pyBool":              pythonBool,
+	"pascal":              pascalName,
+	"cmdConst":            CommandConstName,
+	"pageModal":           pageModalClass,
+	"flowJSON":            flowJSON,
+	"componentsJSON":      componentsJSON,
+	"slashResponse":       slashResponse,
+	"prefixResponse":      prefixResponse,
+	"usesSendResponse":    usesSendResponse,
+	"hasModals":           hasModals,
+	"localeKey":           localeKey,
+	"contextMenu":         IsContextMenuType,
+	"contextParam":        contextMenuParam,
+	"hasContextMenus":     hasContextMenus,
+	"groups":              slashGroups,
+	"commandDecorator":    commandDecorator,
+	"commandPath":         CommandPath,
+	"commandMethod":       commandMethod,
+	"appCommandChecks":    appCommandChecks,
+	"prefixCommandChecks": prefixCommandChecks,
+	"prefixDecorator":     prefixDecorator,
+	"prefixOrder":         prefixOrder,
+	"hasChoices":          hasChoices,
+	"choiceValue":         choiceValue,
+	"suggestionList":      suggestionList,
+	"autocompleteSources": autocompleteSources,
+	"maxAutocomplete":     maxAutocompleteResults,
+	"listenerParams":      listenerParams,
+	"listenerBody":        listenerBody,
+	"taskLoopArgs":        taskLoopArgs,
+	"taskSchedule":        taskSchedule,
+	"taskBody":            taskBody,
+	"usesTaskTimes":       usesTaskTimes,
+	"usesZoneInfo":        usesZoneInfo,
+	// The library template sets pass the bot.library value they are written for
+	"libraryDecorator":      libraryDecorator,
+	"libraryParams":         libraryParams,
+	"libraryPrefixParams":   libraryPrefixParams,
+	"librarySlashResponse":  librarySlashResponse,
+	"libraryPrefixResponse": libraryPrefixResponse,
+	"libraryListenerParams": libraryListenerParams,
+}
+
+// RenderTemplate renders the named template with the given data, an override in the project's .botbox/templates
+// or in ~/.config/botbox/templates replaces the embedded template and is checked against the data first.
 // Templates use << >> delimiters so Python brace syntax stays untouched
 func RenderTemplate(name string, data any) (string, error) {
-	tmpl, err := template.New(name).Delims("<<", ">>").Funcs(templateFuncs).ParseFS(templateFS, "templates/"+name)
+	var tmpl *template.Template
+	var err error
+	// ParseFS names a template after its file, so a template of a library set like nextcord/cog.py.tmpl goes by its base name
+	if overridePath, ok := findTemplateOverride(name); ok {
+		tmpl, err = parseTemplateFile(overridePath, reflect.TypeOf(data))
+	} else if tmpl, err = template.New(path.Base(name)).Delims("<<", ">>").Funcs(templateFuncs).ParseFS(templateFS, "templates/"+name); err != nil {
+		err = fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
 	if err != nil {
-		return "", fmt.Errorf("failed to parse template %s: %w", name, err)
+		return "", err
 	}
 
 	var content strings.Builder
@@ -82,7 +136,14 @@ func BuildArgString(args []ArgInfo) string {
 	}
 	var argBuilder strings.Builder
 	for i, arg := range args {
-		fmt.Fprintf(&argBuilder, "%s: %s", arg.Name, arg.Type)
+		// A bare * makes the next argument keyword only, which ext.commands fills with the rest of the message
+		if arg.Rest {
+			argBuilder.WriteString("*, ")
+		}
+		fmt.Fprintf(&argBuilder, "%s: %s", arg.Name, argAnnotation(arg))
+		if arg.Optional {
+			fmt.Fprintf(&argBuilder, " = %s", argDefault(arg))
+		}
 		if i < len(args)-1 {
 			argBuilder.WriteString(", ")
 		}
@@ -90,15 +151,175 @@ func BuildArgString(args []ArgInfo) string {
 	return argBuilder.String()
 }
 
-// underscoreName converts dashes in a command name to underscores
+// BuildPrefixArgString joins prefix command args into a Python parameter list,
+// prefix commands take a transform's converter class directly since ext.commands has no Transform
+func BuildPrefixArgString(args []ArgInfo) string {
+	converted := make([]ArgInfo, len(args))
+	for i, arg := range args {
+		if _, transformer, ok := ParseTransformType(arg.Type); ok {
+			arg.Type = transformer
+		}
+		converted[i] = arg
+	}
+	return BuildArgString(converted)
+}
+
+// transformer is one converter class generated for app_commands.Transform arguments
+type transformer struct {
+	Name      string
+	ValueType string
+}
+
+// transformers lists each converter class the commands use once, in first use order
+func transformers(commandLists ...[]CommandInfo) []transformer {
+	var found []transformer
+	seen := map[string]bool{}
+	for _, commands := range commandLists {
+		for _, command := range commands {
+			for _, arg := range command.Args {
+				valueType, name, ok := ParseTransformType(arg.Type)
+				if !ok || seen[name] {
+					continue
+				}
+				seen[name] = true
+				found = append(found, transformer{Name: name, ValueType: valueType})
+			}
+		}
+	}
+	return found
+}
+
+// optionType maps a raw transform value type to the Discord option type it is sent as
+func optionType(valueType string) string {
+	switch valueType {
+	case "int":
+		return "integer"
+	case "float":
+		return "number"
+	default:
+		return "string"
+	}
+}
+
+// usesUnion reports whether any argument needs typing.Union imported
+func usesUnion(commandLists ...[]CommandInfo) bool {
+	for _, commands := range commandLists {
+		for _, command := range commands {
+			for _, arg := range command.Args {
+				if strings.HasPrefix(arg.Type, "Union[") {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// argAnnotation renders the parameter type, bounded args become an app_commands.Range
+func argAnnotation(arg ArgInfo) string {
+	if arg.Greedy {
+		return fmt.Sprintf("commands.Greedy[%s]", arg.Type)
+	}
+	if arg.Min == "" && arg.Max == "" {
+		return arg.Type
+	}
+	low, high := arg.Min, arg.Max
+	if low == "" {
+		low = "None"
+	}
+	if high == "" {
+		high = "None"
+	}
+	return fmt.Sprintf("app_commands.Range[%s, %s, %s]", arg.Type, low, high)
+}
+
+// argDefault renders the default of an optional arg as a Python literal, falling back to None
+func argDefault(arg ArgInfo) string {
+	if arg.Default == "" {
+		return "None"
+	}
+	return argLiteral(arg.Type, arg.Default)
+}
+
+// argLiteral renders a validated value as a Python literal of the argument type
+func argLiteral(argType string, value string) string {
+	switch argType {
+	case "str":
+		return `"` + value + `"`
+	case "bool":
+		if value == "true" {
+			return "True"
+		}
+		return "False"
+	default:
+		return value
+	}
+}
+
+// choiceValue renders the value of one fixed choice for the argument it belongs to
+func choiceValue(arg ArgInfo, choice ChoiceInfo) string {
+	return argLiteral(arg.Type, choice.Value)
+}
+
+// suggestionList renders the fixed autocomplete suggestions of an argument as a Python list
+func suggestionList(arg ArgInfo) string {
+	literals := make([]string, len(arg.Suggestions))
+	for i, suggestion := range arg.Suggestions {
+		literals[i] = argLiteral(arg.Type, suggestion)
+	}
+	return "[" + strings.Join(literals, ", ") + "]"
+}
+
+// maxAutocompleteResults exposes the Discord result cap to the templates
+func maxAutocompleteResults() int {
+	return MaxAutocompleteResults
+}
+
+// autocompleteSources lists each named autocomplete source once, a cog method is generated per source
+func autocompleteSources(commands []CommandInfo) []string {
+	var sources []string
+	seen := map[string]bool{}
+	for _, command := range commands {
+		for _, arg := range command.Args {
+			if arg.AutocompleteSource == "" || seen[arg.AutocompleteSource] {
+				continue
+			}
+			seen[arg.AutocompleteSource] = true
+			sources = append(sources, arg.AutocompleteSource)
+		}
+	}
+	return sources
+}
+
+// hasChoices reports whether any argument needs the app_commands.choices decorator
+func hasChoices(args []ArgInfo) bool {
+	for _, arg := range args {
+		if len(arg.Choices) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// underscoreName turns a command name into a Python identifier, dashes, the spaces of context menu names, and
+// anything else a Python name cannot hold become underscores
 func underscoreName(name string) string {
-	return strings.ReplaceAll(name, "-", "_")
+	identifier := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+	if r, _ := utf8.DecodeRuneInString(identifier); unicode.IsDigit(r) {
+		return "_" + identifier
+	}
+	return identifier
 }
 
-// pascalName turns a dashed or underscored name into PascalCase for generated class names
+// pascalName turns a dashed, underscored, or spaced name into PascalCase for generated class names
 func pascalName(name string) string {
 	parts := strings.FieldsFunc(name, func(r rune) bool {
-		return r == '-' || r == '_'
+		return r == '-' || r == '_' || r == ' '
 	})
 
 	var builder strings.Builder
@@ -120,9 +341,9 @@ func pageModalClass(commandName, pageName string) string {
 	return pascalName(commandName) + pascalName(pageName) + "Modal"
 }
 
-// CommandConstName turns a command name into the prefix of its generated flow constants
+// CommandConstName turns a command path or method name into the prefix of its generated flow constants
 func CommandConstName(name string) string {
-	return strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+	return strings.ToUpper(underscoreName(name))
 }
 
 // commandFlow mirrors the JSON blob rendered next to a multi page modal command
@@ -133,7 +354,16 @@ type commandFlow struct {
 
 // flowJSON renders the pages and responses of a multi page modal command as an indented JSON blob
 func flowJSON(cmd CommandInfo) (string, error) {
-	flow := commandFlow{Pages: cmd.Pages, Responses: cmd.Responses}
+	// Label translations are served from the locale files, the cog only carries the default labels
+	pages := make([]PageInfo, len(cmd.Pages))
+	for i, page := range cmd.Pages {
+		page.Fields = slices.Clone(page.Fields)
+		for j := range page.Fields {
+			page.Fields[j].LabelLocalizations = nil
+		}
+		pages[i] = page
+	}
+	flow := commandFlow{Pages: pages, Responses: cmd.Responses}
 	jsonData, err := json.MarshalIndent(flow, "", "    ")
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal flow for command %s: %w", cmd.Name, err)
@@ -141,20 +371,431 @@ func flowJSON(cmd CommandInfo) (string, error) {
 	return string(jsonData), nil
 }
 
-// responseContent returns the first expected response content or falls back to echoing the command name
-func responseContent(cmd CommandInfo) string {
-	if len(cmd.Responses) > 0 {
-		return cmd.Responses[0].Content
+// componentsJSON renders the view of a component command as an indented JSON blob
+func componentsJSON(cmd CommandInfo) (string, error) {
+	jsonData, err := json.MarshalIndent(cmd.Components, "", "    ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal components for command %s: %w", cmd.Name, err)
 	}
-	return cmd.Name
+	return string(jsonData), nil
 }
 
-// responseEphemeral renders the ephemeral flag of the first expected response, defaulting to True
-func responseEphemeral(cmd CommandInfo) string {
+// firstResponse returns the response a generated command body sends, falling back to echoing the command name
+func firstResponse(cmd CommandInfo) ResponseInfo {
 	if len(cmd.Responses) > 0 {
-		return pythonBool(cmd.Responses[0].Ephemeral)
+		return cmd.Responses[0]
+	}
+	return ResponseInfo{Type: "message", Content: cmd.Name, Ephemeral: true}
+}
+
+// embedLines renders the statements that build the embed of an embed response into a local named embed,
+// module is the package the library's Embed class lives in
+func embedLines(response ResponseInfo, module string) []string {
+	embed := response.Embed
+	if embed == nil {
+		embed = &EmbedInfo{}
+	}
+
+	var kwargs []string
+	if embed.Title != "" {
+		kwargs = append(kwargs, fmt.Sprintf(`title=f"%s"`, embed.Title))
+	}
+	if response.Content != "" {
+		kwargs = append(kwargs, fmt.Sprintf(`description=f"%s"`, response.Content))
+	}
+	if embed.Color != "" {
+		kwargs = append(kwargs, fmt.Sprintf("colour=%s.Colour(0x%s)", module, strings.TrimPrefix(embed.Color, "#")))
+	}
+
+	lines := []string{fmt.Sprintf("embed = %s.Embed(%s)", module, strings.Join(kwargs, ", "))}
+	for _, field := range embed.Fields {
+		lines = append(lines, fmt.Sprintf(`embed.add_field(name=f"%s", value=f"%s", inline=%s)`, field.Name, field.Value, pythonBool(field.Inline)))
 	}
-	return "True"
+	if embed.Footer != "" {
+		lines = append(lines, fmt.Sprintf(`embed.set_footer(text=f"%s")`, embed.Footer))
+	}
+	if embed.Thumbnail != "" {
+		lines = append(lines, fmt.Sprintf(`embed.set_thumbnail(url="%s")`, embed.Thumbnail))
+	}
+	return lines
+}
+
+// slashResponse renders the statements an interaction based command body uses to send its first response,
+// a component command also builds its view and attaches it to the reply
+func slashResponse(cmd CommandInfo) []string {
+	return libraryDialects[LibraryDiscordPy].slashResponse(cmd)
+}
+
+// prefixResponse renders the statements a prefix or hybrid command body uses to send its first response
+func prefixResponse(cmd CommandInfo) []string {
+	return libraryDialects[LibraryDiscordPy].prefixResponse(cmd)
+}
+
+// usesSendResponse reports whether any command sends responses described by a JSON blob,
+// multi page modal flows and component views share the generated send_response helper
+func usesSendResponse(commands []CommandInfo) bool {
+	for _, cmd := range commands {
+		if len(cmd.Pages) > 0 || cmd.Type == "component" {
+			return true
+		}
+	}
+	return false
+}
+
+// hasModals reports whether any command opens a modal, their labels are translated when the modal is sent
+func hasModals(commands []CommandInfo) bool {
+	return slices.ContainsFunc(commands, func(cmd CommandInfo) bool {
+		return cmd.Type == "modal"
+	})
+}
+
+// contextMenuParam renders the target parameter Discord passes to a context menu callback
+func contextMenuParam(commandType string) string {
+	if commandType == "message_context" {
+		return "message: discord.Message"
+	}
+	return "member: discord.Member"
+}
+
+// hasContextMenus reports whether any command needs the tree registration and cog_unload cleanup
+func hasContextMenus(commands []CommandInfo) bool {
+	for _, command := range commands {
+		if IsContextMenuType(command.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+// slashGroup is one app_commands.Group a cog declares as a class attribute
+type slashGroup struct {
+	Attr        string
+	Path        string
+	Name        string
+	Description string
+	Parent      string
+	Guild       bool
+}
+
+// groupAttr names the class attribute holding a group, the suffix keeps it clear of command methods
+func groupAttr(group string) string {
+	return underscoreName(strings.ReplaceAll(group, " ", "_")) + "_group"
+}
+
+// defaultGroupDescription is rendered for a group nobody described, Discord requires one
+func defaultGroupDescription(name string) string {
+	return name + " commands"
+}
+
+// slashGroups lists every group the commands are nested under, parents before their subgroups
+func slashGroups(commands []CommandInfo) []slashGroup {
+	var groups []slashGroup
+	index := map[string]int{}
+
+	for _, command := range commands {
+		if command.Group == "" {
+			continue
+		}
+		segments := strings.Fields(command.Group)
+		for i, segment := range segments {
+			path := strings.Join(segments[:i+1], " ")
+			if _, seen := index[path]; !seen {
+				group := slashGroup{Attr: groupAttr(path), Path: path, Name: segment}
+				if i > 0 {
+					group.Parent = groupAttr(strings.Join(segments[:i], " "))
+				} else {
+					// Only a top level group can be registered to a guild, subgroups follow their parent
+					group.Guild = command.Scope == "guild"
+				}
+				index[path] = len(groups)
+				groups = append(groups, group)
+			}
+			if path == command.Group && groups[index[path]].Description == "" {
+				groups[index[path]].Description = command.GroupDescription
+			}
+		}
+	}
+
+	for i := range groups {
+		if groups[i].Description == "" {
+			groups[i].Description = defaultGroupDescription(groups[i].Name)
+		}
+	}
+
+	return groups
+}
+
+// commandDecorator renders the decorator that registers an app command, grouped commands register on their group
+func commandDecorator(cmd CommandInfo) string {
+	if cmd.Group == "" {
+		return "app_commands.command"
+	}
+	return groupAttr(cmd.Group) + ".command"
+}
+
+// Python keyword names for the allowed installs and allowed contexts settings
+var (
+	installKeywords = map[string]string{"guild": "guilds", "user": "users"}
+	contextKeywords = map[string]string{"guild": "guilds", "dm": "dms", "private_channel": "private_channels"}
+)
+
+// Cooldown key lambdas for app commands, prefix commands use commands.BucketType instead
+var cooldownKeys = map[string]string{
+	"user":    "i.user.id",
+	"guild":   "i.guild_id",
+	"channel": "i.channel_id",
+}
+
+// flagArgs renders settings as keyword flags, "manage_messages=True, kick_members=True"
+func flagArgs(settings []string, keywords map[string]string) string {
+	flags := make([]string, len(settings))
+	for i, setting := range settings {
+		if keyword, ok := keywords[setting]; ok {
+			setting = keyword
+		}
+		flags[i] = setting + "=True"
+	}
+	return strings.Join(flags, ", ")
+}
+
+// roleArgs renders role checks arguments, IDs stay numbers and names become strings
+func roleArgs(roles []string) string {
+	literals := make([]string, len(roles))
+	for i, role := range roles {
+		if _, err := strconv.ParseUint(role, 10, 64); err == nil {
+			literals[i] = role
+		} else {
+			literals[i] = fmt.Sprintf("%q", role)
+		}
+	}
+	return strings.Join(literals, ", ")
+}
+
+// formatSeconds writes a cooldown period without a trailing .0
+func formatSeconds(seconds float64) string {
+	return strconv.FormatFloat(seconds, 'f', -1, 64)
+}
+
+// appCommandChecks renders the permission, context, and cooldown decorators of a slash, modal, or context menu command
+func appCommandChecks(cmd CommandInfo) []string {
+	var decorators []string
+	if len(cmd.Permissions) > 0 {
+		decorators = append(decorators, fmt.Sprintf("@app_commands.default_permissions(%s)", flagArgs(cmd.Permissions, nil)))
+	}
+	if cmd.GuildOnly {
+		decorators = append(decorators, "@app_commands.guild_only()")
+	}
+	if len(cmd.AllowedInstalls) > 0 {
+		decorators = append(decorators, fmt.Sprintf("@app_commands.allowed_installs(%s)", flagArgs(cmd.AllowedInstalls, installKeywords)))
+	}
+	if len(cmd.AllowedContexts) > 0 {
+		decorators = append(decorators, fmt.Sprintf("@app_commands.allowed_contexts(%s)", flagArgs(cmd.AllowedContexts, contextKeywords)))
+	}
+	if len(cmd.Roles) > 0 {
+		decorators = append(decorators, fmt.Sprintf("@app_commands.checks.has_any_role(%s)", roleArgs(cmd.Roles)))
+	}
+	if cmd.Cooldown != nil {
+		decorators = append(decorators, fmt.Sprintf("@app_commands.checks.cooldown(%d, %s, key=lambda i: %s)",
+			cmd.Cooldown.Rate, formatSeconds(cmd.Cooldown.Per), cooldownKeys[cmd.Cooldown.Bucket]))
+	}
+	return decorators
+}
+
+// prefixCommandChecks renders the ext.commands equivalents of the app command checks for prefix and hybrid commands,
+// prefix commands have no default permissions so the permissions become a has_permissions check
+func prefixCommandChecks(cmd CommandInfo) []string {
+	var decorators []string
+	if len(cmd.Permissions) > 0 {
+		decorators = append(decorators, fmt.Sprintf("@commands.has_permissions(%s)", flagArgs(cmd.Permissions, nil)))
+	}
+	if cmd.GuildOnly {
+		decorators = append(decorators, "@commands.guild_only()")
+	}
+	if len(cmd.Roles) > 0 {
+		decorators = append(decorators, fmt.Sprintf("@commands.has_any_role(%s)", roleArgs(cmd.Roles)))
+	}
+	if cmd.Cooldown != nil {
+		decorators = append(decorators, fmt.Sprintf("@commands.cooldown(%d, %s, commands.BucketType.%s)",
+			cmd.Cooldown.Rate, formatSeconds(cmd.Cooldown.Per), cmd.Cooldown.Bucket))
+	}
+	return decorators
+}
+
+// isPrefixGroup reports whether a prefix command is registered as a group, either because it has
+// subcommands or because it only runs its own body when no subcommand is given
+func isPrefixGroup(cmd CommandInfo, commands []CommandInfo) bool {
+	if cmd.InvokeWithoutCommand {
+		return true
+	}
+	path := CommandPath(cmd)
+	return slices.ContainsFunc(commands, func(other CommandInfo) bool {
+		return other.Group == path
+	})
+}
+
+// prefixDecorator renders the decorator that registers a prefix command,
+// subcommands register on the method of their group command
+func prefixDecorator(cmd CommandInfo, commands []CommandInfo) string {
+	// A subcommand registers on its parent's method, which is named after the parent's path
+	owner := "commands"
+	if cmd.Group != "" {
+		owner = underscoreName(cmd.Group)
+	}
+	kind := "command"
+	if isPrefixGroup(cmd, commands) {
+		kind = "group"
+	}
+
+	// discord.py names a prefix command after its method unless it is given a name
+	var kwargs []string
+	if commandMethod(cmd) != cmd.Name {
+		kwargs = append(kwargs, fmt.Sprintf("name=%q", cmd.Name))
+	}
+	if len(cmd.Aliases) > 0 {
+		aliases := make([]string, len(cmd.Aliases))
+		for i, alias := range cmd.Aliases {
+			aliases[i] = fmt.Sprintf("%q", alias)
+		}
+		kwargs = append(kwargs, fmt.Sprintf("aliases=[%s]", strings.Join(aliases, ", ")))
+	}
+	if cmd.Hidden {
+		kwargs = append(kwargs, "hidden=True")
+	}
+	if cmd.InvokeWithoutCommand {
+		kwargs = append(kwargs, "invoke_without_command=True")
+	}
+	return fmt.Sprintf("%s.%s(%s)", owner, kind, strings.Join(kwargs, ", "))
+}
+
+// prefixOrder sorts prefix commands so every group command is defined before the subcommands that register on it
+func prefixOrder(commands []CommandInfo) []CommandInfo {
+	depth := func(cmd CommandInfo) int {
+		if cmd.Group == "" {
+			return 0
+		}
+		return strings.Count(cmd.Group, " ") + 1
+	}
+	ordered := slices.Clone(commands)
+	slices.SortStableFunc(ordered, func(a, b CommandInfo) int {
+		return depth(a) - depth(b)
+	})
+	return ordered
+}
+
+// listenerParams renders the parameters discord.py passes to a listener for its event
+func listenerParams(listener ListenerInfo) string {
+	event, _ := findListenerEvent(listener.Event)
+	return strings.Join(append([]string{"self"}, event.Params...), ", ")
+}
+
+// listenerBody renders the statements of a listener for its canned action, nested lines carry their extra indent
+func listenerBody(listener ListenerInfo) []string {
+	event, _ := findListenerEvent(listener.Event)
+
+	var lines []string
+	// Replying to every message would also answer the bot's own posts
+	if listener.Event == "on_message" {
+		lines = append(lines, "if message.author.bot:", "    return")
+	}
+
+	switch listener.Action {
+	case "log":
+		parts := []string{listener.Event}
+		for _, param := range event.Params {
+			name, _, _ := strings.Cut(param, ":")
+			parts = append(parts, fmt.Sprintf("%s={%s}", name, name))
+		}
+		lines = append(lines, fmt.Sprintf(`logger.info(f"%s")`, strings.Join(parts, " ")))
+	case "message":
+		lines = append(lines,
+			fmt.Sprintf("channel = self.bot.get_channel(%s) or await self.bot.fetch_channel(%s)", listener.ChannelID, listener.ChannelID),
+			fmt.Sprintf(`await channel.send(f"%s")`, listener.Content))
+	case "role":
+		lines = append(lines,
+			fmt.Sprintf("member = %s", event.Member),
+			"if member is not None:",
+			fmt.Sprintf("    role = member.guild.get_role(%s)", listener.RoleID),
+			"    if role is not None:",
+			"        await member.add_roles(role)")
+	default:
+		lines = append(lines, "pass")
+	}
+	return lines
+}
+
+// taskTimezone renders the tzinfo a task's times of day are in
+func taskTimezone(task TaskInfo) string {
+	if task.Timezone == "" {
+		return "datetime.timezone.utc"
+	}
+	return fmt.Sprintf(`ZoneInfo("%s")`, task.Timezone)
+}
+
+// taskLoopArgs renders the tasks.loop arguments for a task's interval or times of day
+func taskLoopArgs(task TaskInfo) string {
+	if len(task.Times) == 0 {
+		return fmt.Sprintf("%s=%d", task.Unit, task.Interval)
+	}
+	times := make([]string, len(task.Times))
+	for i, t := range task.Times {
+		hour, minute, _ := strings.Cut(t, ":")
+		hourValue, _ := strconv.Atoi(hour)
+		minuteValue, _ := strconv.Atoi(minute)
+		times[i] = fmt.Sprintf("datetime.time(hour=%d, minute=%d, tzinfo=%s)", hourValue, minuteValue, taskTimezone(task))
+	}
+	return "time=[" + strings.Join(times, ", ") + "]"
+}
+
+// taskSchedule describes when a task runs for its docstring
+func taskSchedule(task TaskInfo) string {
+	if len(task.Times) == 0 {
+		return fmt.Sprintf("every %d %s", task.Interval, task.Unit)
+	}
+	timezone := task.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	return fmt.Sprintf("daily at %s %s", strings.Join(task.Times, ", "), timezone)
+}
+
+// taskBody renders the statements of a task, posting to its channel when it has one
+func taskBody(task TaskInfo) []string {
+	if task.ChannelID == "" {
+		return []string{"pass"}
+	}
+	lines := []string{fmt.Sprintf("channel = self.bot.get_channel(%s) or await self.bot.fetch_channel(%s)", task.ChannelID, task.ChannelID)}
+	if task.Content != "" {
+		lines = append(lines, fmt.Sprintf(`await channel.send(f"%s")`, task.Content))
+	}
+	return lines
+}
+
+// usesTaskTimes reports whether any task runs at times of day and needs the datetime import
+func usesTaskTimes(tasks []TaskInfo) bool {
+	return slices.ContainsFunc(tasks, func(task TaskInfo) bool {
+		return len(task.Times) > 0
+	})
+}
+
+// usesZoneInfo reports whether any task runs at times of day outside UTC
+func usesZoneInfo(tasks []TaskInfo) bool {
+	return slices.ContainsFunc(tasks, func(task TaskInfo) bool {
+		return len(task.Times) > 0 && task.Timezone != ""
+	})
+}
+
+// CommandPath returns the name a user types for a command, including its groups
+func CommandPath(cmd CommandInfo) string {
+	if cmd.Group == "" {
+		return cmd.Name
+	}
+	return cmd.Group + " " + cmd.Name
+}
+
+// commandMethod names the cog method of a command after its full path, so /ticket list and /tag list
+// become ticket_list and tag_list instead of two methods called list
+func commandMethod(cmd CommandInfo) string {
+	return underscoreName(CommandPath(cmd))
 }
 
 // modalTitle trims a command description down to Discord's 45 character modal title limit
@@ -7,21 +7,58 @@ package utils
 
 import (
 	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 // Valid option sets shared by the forms and the headless flag parsing
 var (
-	validCommandTypes  = []string{"slash", "prefix", "modal"}
+	validCommandTypes  = []string{"slash", "prefix", "hybrid", "modal", "user_context", "message_context", "component"}
 	validCommandScopes = []string{"guild", "global"}
 	validReturnTypes   = []string{"str", "int", "float", "bool", "None"}
-	validArgTypes      = []string{"str", "int", "float", "bool", "discord.Member", "discord.Role"}
-	validFieldStyles   = []string{"short", "paragraph"}
-	validLicenses      = []string{"mit", "apache-2.0", "gpl-3.0", "bsd-3-clause", "unlicense", "no-license"}
-	validHelpStyles    = []string{"compact", "detailed"}
+	validArgTypes      = []string{
+		"str", "int", "float", "bool",
+		"discord.Member", "discord.User", "discord.Role", MentionableArgType,
+		"discord.TextChannel", "discord.VoiceChannel", "discord.abc.GuildChannel", "discord.Thread",
+		"discord.Attachment",
+	}
+	validFieldStyles = []string{"short", "paragraph"}
+	validLicenses    = []string{"mit", "apache-2.0", "gpl-3.0", "bsd-3-clause", "unlicense", "no-license"}
+	validHelpStyles  = []string{"compact", "detailed"}
+)
+
+// Access settings a command can declare, installs and contexts follow Discord's user installable app model
+var (
+	validInstallTypes    = []string{"guild", "user"}
+	validInstallContexts = []string{"guild", "dm", "private_channel"}
+	validCooldownBuckets = []string{"user", "guild", "channel"}
+	validPermissions     = []string{
+		"add_reactions", "administrator", "attach_files", "ban_members", "change_nickname", "connect",
+		"create_events", "create_expressions", "create_instant_invite", "create_polls", "create_private_threads",
+		"create_public_threads", "deafen_members", "embed_links", "external_emojis", "external_stickers",
+		"kick_members", "manage_channels", "manage_emojis", "manage_emojis_and_stickers", "manage_events",
+		"manage_expressions", "manage_guild", "manage_messages", "manage_nicknames", "manage_permissions",
+		"manage_roles", "manage_threads", "manage_webhooks", "mention_everyone", "moderate_members",
+		"move_members", "mute_members", "priority_speaker", "read_message_history", "read_messages",
+		"request_to_speak", "send_messages", "send_messages_in_threads", "send_polls", "send_tts_messages",
+		"send_voice_messages", "speak", "stream", "use_application_commands", "use_embedded_activities",
+		"use_external_apps", "use_external_emojis", "use_external_sounds", "use_external_stickers",
+		"use_soundboard", "use_voice_activation", "view_audit_log", "view_channel", "view_creator_monetization_analytics",
+		"view_guild_insights",
+	}
 )
 
+// Discord locales commands can be translated into, spelled the way discord.Locale values are
+var validLocales = []string{
+	"bg", "cs", "da", "de", "el", "en-GB", "en-US", "es-419", "es-ES", "fi", "fr", "hi", "hr", "hu", "id", "it",
+	"ja", "ko", "lt", "nl", "no", "pl", "pt-BR", "ro", "ru", "sv-SE", "th", "tr", "uk", "vi", "zh-CN", "zh-TW",
+}
+
 // DefaultHelpStyle is used when a project predates the help_style key or leaves it unset
 const DefaultHelpStyle = "compact"
 
@@ -34,8 +71,132 @@ const MaxFlowPages = 10
 // MaxCommandResponses caps how many expected responses a command can declare
 const MaxCommandResponses = 3
 
-// Valid response types, only plain messages exist today
-var validResponseTypes = []string{"message"}
+// Context menu command types, both are app commands that take the clicked user or message instead of arguments
+var contextMenuTypes = []string{"user_context", "message_context"}
+
+// IsContextMenuType reports whether a command type is a right click context menu command
+func IsContextMenuType(s string) bool {
+	return contains(contextMenuTypes, s)
+}
+
+// IsAppCommand reports whether a command type registers an app command, every type but prefix does.
+// botbox.conf keeps app commands in a cog's slash commands, with modal, component, hybrid, and context menu
+// commands next to the plain slash commands, and only prefix commands in its prefix commands
+func IsAppCommand(s string) bool {
+	return s != "prefix"
+}
+
+// TakesArguments reports whether a command type is asked for arguments, context menu commands get the
+// clicked user or message instead so their forms go straight to the responses
+func TakesArguments(s string) bool {
+	return !IsContextMenuType(s)
+}
+
+// HasFixedReturnType reports whether a command type's return type is fixed to None, modal, context menu,
+// and component commands only respond through the interaction so whatever return type was given is replaced
+func HasFixedReturnType(s string) bool {
+	return s == "modal" || s == "component" || IsContextMenuType(s)
+}
+
+// HasSlashOptions reports whether a command type registers its arguments as slash command options,
+// only those can carry choices, ranges, and autocomplete
+func HasSlashOptions(s string) bool {
+	return s == "slash" || s == "component"
+}
+
+// UsesContext reports whether a command type is invoked with a commands.Context,
+// prefix and hybrid commands share the ext.commands checks and reply through ctx
+func UsesContext(s string) bool {
+	return s == "prefix" || s == "hybrid"
+}
+
+// Discord caps slash command names and descriptions, hybrid commands register a slash command so they share the limits
+const (
+	maxSlashNameLength        = 32
+	maxSlashDescriptionLength = 100
+)
+
+// Discord nests slash commands at most two groups deep and caps each group at 25 children
+const (
+	maxGroupDepth             = 2
+	MaxGroupCommands          = 25
+	maxGroupNameLength        = 32
+	maxGroupDescriptionLength = 100
+)
+
+// CanBeGrouped reports whether a command type can be nested under a slash group,
+// only commands registered through a command decorator have a group to live in
+func CanBeGrouped(s string) bool {
+	return s == "slash" || s == "modal" || s == "component"
+}
+
+// isTopLevelAppCommand reports whether a command type registers a top level slash command when it has no group
+func isTopLevelAppCommand(s string) bool {
+	return CanBeGrouped(s) || s == "hybrid"
+}
+
+// rootGroup returns the top level group of a group path
+func rootGroup(group string) string {
+	root, _, _ := strings.Cut(group, " ")
+	return root
+}
+
+// Discord caps an argument at 25 fixed choices and a string argument at 6000 characters
+const (
+	MaxArgChoices       = 25
+	maxChoiceNameLength = 100
+	maxStringArgLength  = 6000
+)
+
+// MentionableArgType accepts either a member or a role, Discord shows it as a mentionable option
+const MentionableArgType = "Union[discord.Member, discord.Role]"
+
+// Transform arguments run the raw str, int, or float value through a generated converter class
+var transformTypeRegex = regexp.MustCompile(`^app_commands\.Transform\[(str|int|float), ([A-Z]\w*)\]$`)
+
+// ParseTransformType splits an app_commands.Transform type into its raw value type and converter class
+func ParseTransformType(s string) (valueType, transformer string, ok bool) {
+	matches := transformTypeRegex.FindStringSubmatch(s)
+	if matches == nil {
+		return "", "", false
+	}
+	return matches[1], matches[2], true
+}
+
+// TransformArgType builds the app_commands.Transform type for a raw value type and converter class
+func TransformArgType(valueType, transformer string) string {
+	return fmt.Sprintf("app_commands.Transform[%s, %s]", valueType, transformer)
+}
+
+// transformArgOption is the argument type select value that asks for a custom transformer
+const transformArgOption = "transform"
+
+// Argument types that can carry choices and ranges, Discord only offers them on plain values
+var valueArgTypes = []string{"str", "int", "float"}
+
+// Valid response types, dm and channel responses are sent elsewhere and defer follows up after thinking
+var validResponseTypes = []string{"message", "embed", "dm", "channel", "defer"}
+
+// Discord caps the parts of an embed at these lengths
+const (
+	maxEmbedTitleLength       = 256
+	maxEmbedDescriptionLength = 4096
+	MaxEmbedFields            = 25
+	maxEmbedFieldNameLength   = 256
+	maxEmbedFieldValueLength  = 1024
+	maxEmbedFooterLength      = 2048
+)
+
+var (
+	embedColorRegex = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+	// Discord snowflakes are 17 to 20 digit IDs
+	snowflakeRegex = regexp.MustCompile(`^[0-9]{17,20}$`)
+	// Task names become cog method names
+	taskNameRegex = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+	taskTimeRegex = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+	// IANA zone names like America/New_York or Etc/GMT+5
+	timezoneRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_+-]*(/[A-Za-z0-9_+-]+)*$`)
+)
 
 // Discord caps text input labels at 45 characters
 const maxFieldLabelLength = 45
@@ -144,6 +305,25 @@ func NormalizeHelpStyle(s string) string {
 	return s
 }
 
+func ValidateLibrary(s string) error {
+	if s == "" {
+		return fmt.Errorf("Please select a library")
+	}
+	// Any library with a registered generator is valid, see RegisterGenerator
+	if !contains(GeneratorNames(), s) {
+		return fmt.Errorf("library must be one of %s", strings.Join(GeneratorNames(), ", "))
+	}
+	return nil
+}
+
+// NormalizeLibrary turns an unset library into discord.py, which every project used before the key existed
+func NormalizeLibrary(s string) string {
+	if s == "" {
+		return DefaultLibrary
+	}
+	return s
+}
+
 func ValidateCommandName(s string, existing []CommandInfo) error {
 	if s == "" {
 		return fmt.Errorf("command name cannot be empty")
@@ -157,6 +337,99 @@ func ValidateCommandName(s string, existing []CommandInfo) error {
 	return nil
 }
 
+// ValidateContextMenuName checks the name of a user or message context menu, Discord shows it as typed so
+// unlike slash command names it can hold spaces and capitals
+func ValidateContextMenuName(s string, existing []CommandInfo) error {
+	if strings.TrimSpace(s) == "" {
+		return fmt.Errorf("context menu name cannot be empty")
+	}
+	if len([]rune(s)) > maxSlashNameLength {
+		return fmt.Errorf("context menu name must be %d characters or less", maxSlashNameLength)
+	}
+	if strings.TrimSpace(s) != s {
+		return fmt.Errorf("context menu name cannot start or end with a space")
+	}
+	// The name is written into a Python string literal
+	if strings.ContainsAny(s, "\"\\\n") {
+		return fmt.Errorf("context menu name cannot contain quotes, backslashes, or line breaks")
+	}
+	if commandExists(s, existing) {
+		return fmt.Errorf("command name already exists")
+	}
+	return nil
+}
+
+// ValidateCommandGroup checks a group path, an empty path means a top level command
+func ValidateCommandGroup(s string) error {
+	if s == "" {
+		return nil
+	}
+	segments := strings.Fields(s)
+	if strings.Join(segments, " ") != s {
+		return fmt.Errorf("command group must be group names separated by single spaces")
+	}
+	if len(segments) > maxGroupDepth {
+		return fmt.Errorf("command group can be nested at most %d levels deep", maxGroupDepth)
+	}
+	for _, segment := range segments {
+		if len(segment) > maxGroupNameLength {
+			return fmt.Errorf("group name '%s' must be %d characters or less", segment, maxGroupNameLength)
+		}
+		// Discord only accepts lowercase names made of letters, numbers, dashes, and underscores
+		for _, r := range segment {
+			if unicode.IsUpper(r) || !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_') {
+				return fmt.Errorf("group name '%s' must be lowercase letters, numbers, dashes, or underscores", segment)
+			}
+		}
+	}
+	return nil
+}
+
+func ValidateGroupDescription(s string) error {
+	if len(s) > maxGroupDescriptionLength {
+		return fmt.Errorf("group description must be %d characters or less", maxGroupDescriptionLength)
+	}
+	return nil
+}
+
+// validateGroupPlacement checks a grouped or top level command against the groups the existing commands declare
+func validateGroupPlacement(command CommandInfo, existing []CommandInfo) error {
+	// Prefix commands and their groups live in the ext.commands name space, apart from the app commands
+	if command.Type == "prefix" {
+		return nil
+	}
+	children := 0
+	for _, other := range existing {
+		if other.Type == "prefix" {
+			continue
+		}
+		// Top level app commands and root groups share one name space in Discord
+		if command.Group == "" && isTopLevelAppCommand(command.Type) && other.Group != "" && rootGroup(other.Group) == command.Name {
+			return fmt.Errorf("command name is already used by a group")
+		}
+		if command.Group != "" && other.Group == "" && isTopLevelAppCommand(other.Type) && other.Name == rootGroup(command.Group) {
+			return fmt.Errorf("group '%s' is already used by a command", rootGroup(command.Group))
+		}
+		if command.Group == "" || other.Group == "" {
+			continue
+		}
+		// Scope belongs to the top level group, so every command under it must agree
+		if rootGroup(other.Group) == rootGroup(command.Group) && other.Scope != command.Scope {
+			return fmt.Errorf("commands in group '%s' must share one scope", rootGroup(command.Group))
+		}
+		if other.Group == command.Group {
+			children++
+			if command.GroupDescription != "" && other.GroupDescription != "" && other.GroupDescription != command.GroupDescription {
+				return fmt.Errorf("group '%s' already has a different description", command.Group)
+			}
+		}
+	}
+	if command.Group != "" && children >= MaxGroupCommands {
+		return fmt.Errorf("group '%s' can hold at most %d commands", command.Group, MaxGroupCommands)
+	}
+	return nil
+}
+
 func ValidateCommandType(s string) error {
 	if s == "" {
 		return fmt.Errorf("command type cannot be empty")
@@ -184,6 +457,37 @@ func ValidateCommandDescription(s string) error {
 	return nil
 }
 
+// validateCommandNameFor checks a command name by the rules of its type, context menus follow their own
+func validateCommandNameFor(commandType string, s string, existing []CommandInfo) error {
+	if IsContextMenuType(commandType) {
+		return ValidateContextMenuName(s, existing)
+	}
+	return ValidateCommandName(s, existing)
+}
+
+// validateCommandPath checks no other command sits at the command's full path, /ticket list and /tag list can share
+// a name. Commands become methods named after their path, so paths that only differ in punctuation clash as well
+func validateCommandPath(command CommandInfo, existing []CommandInfo) error {
+	path, method := CommandPath(command), commandMethod(command)
+	for _, other := range existing {
+		if CommandPath(other) == path {
+			return fmt.Errorf("command '%s' already exists", path)
+		}
+		if commandMethod(other) == method {
+			return fmt.Errorf("command '%s' would share the method %s with command '%s'", path, method, CommandPath(other))
+		}
+	}
+	return nil
+}
+
+// validateCommandDescriptionFor checks a command description, Discord never shows one for a context menu so it is optional there
+func validateCommandDescriptionFor(commandType string, s string) error {
+	if IsContextMenuType(commandType) {
+		return nil
+	}
+	return ValidateCommandDescription(s)
+}
+
 func ValidateReturnType(s string) error {
 	if s == "" {
 		return fmt.Errorf("return type cannot be empty")
@@ -221,182 +525,1262 @@ func ValidateArgType(s string) error {
 	if s == "" {
 		return fmt.Errorf("argument type cannot be empty")
 	}
+	if _, _, ok := ParseTransformType(s); ok {
+		return nil
+	}
+	if strings.HasPrefix(s, "app_commands.Transform[") {
+		return fmt.Errorf("transform arguments must look like app_commands.Transform[str, MyTransformer] with a str, int, or float value")
+	}
 	if !contains(validArgTypes, s) {
-		return fmt.Errorf("argument type must be one of %s", strings.Join(validArgTypes, ", "))
+		return fmt.Errorf("argument type must be one of %s, or an app_commands.Transform", strings.Join(validArgTypes, ", "))
 	}
 	return nil
 }
 
-func fieldExists(fieldName string, fields []FieldInfo) bool {
-	for _, field := range fields {
-		if field.Name == fieldName {
-			return true
+// validateArgValue checks that a default or choice value can be written as a literal of the argument type
+func validateArgValue(argType string, s string) error {
+	switch argType {
+	case "str":
+		if strings.ContainsAny(s, "\"\\") {
+			return fmt.Errorf("value cannot contain double quotes or backslashes")
+		}
+	case "int":
+		if _, err := strconv.Atoi(s); err != nil {
+			return fmt.Errorf("value '%s' is not an int", s)
+		}
+	case "float":
+		if _, err := strconv.ParseFloat(s, 64); err != nil {
+			return fmt.Errorf("value '%s' is not a float", s)
+		}
+	case "bool":
+		if s != "true" && s != "false" {
+			return fmt.Errorf("value '%s' must be true or false", s)
 		}
+	default:
+		return fmt.Errorf("%s arguments cannot have a fixed value", argType)
 	}
-	return false
+	return nil
 }
 
-func ValidateFieldName(s string, existing []FieldInfo) error {
+// ValidateArgDefault checks a default value against the argument type, empty means no default
+func ValidateArgDefault(argType string, s string) error {
 	if s == "" {
-		return fmt.Errorf("field name cannot be empty")
-	}
-	if strings.Contains(s, " ") {
-		return fmt.Errorf("field name cannot contain spaces")
-	}
-	if strings.Contains(s, "-") {
-		return fmt.Errorf("field name cannot contain dashes")
+		return nil
 	}
-	if fieldExists(s, existing) {
-		return fmt.Errorf("field name already exists")
+	if err := validateArgValue(argType, s); err != nil {
+		return fmt.Errorf("default %w", err)
 	}
 	return nil
 }
 
-func ValidateFieldLabel(s string) error {
-	if s == "" {
-		return fmt.Errorf("field label cannot be empty")
+// ValidateArgChoices checks the fixed choices of an argument against Discord's limits
+func ValidateArgChoices(argType string, choices []ChoiceInfo) error {
+	if len(choices) == 0 {
+		return nil
 	}
-	if len([]rune(s)) > maxFieldLabelLength {
-		return fmt.Errorf("field label must be at most %d characters", maxFieldLabelLength)
+	if !contains(valueArgTypes, argType) {
+		return fmt.Errorf("choices are only supported on str, int, and float arguments")
 	}
-	return nil
-}
-
-func ValidateFieldStyle(s string) error {
-	if s == "" {
-		return fmt.Errorf("field style cannot be empty")
+	if len(choices) > MaxArgChoices {
+		return fmt.Errorf("an argument can have at most %d choices", MaxArgChoices)
 	}
-	if !contains(validFieldStyles, s) {
-		return fmt.Errorf("field style must be one of %s", strings.Join(validFieldStyles, ", "))
+	seen := map[string]bool{}
+	for _, choice := range choices {
+		if choice.Name == "" {
+			return fmt.Errorf("choice name cannot be empty")
+		}
+		if len(choice.Name) > maxChoiceNameLength {
+			return fmt.Errorf("choice name '%s' must be %d characters or less", choice.Name, maxChoiceNameLength)
+		}
+		if strings.ContainsAny(choice.Name, "\"\\") {
+			return fmt.Errorf("choice name '%s' cannot contain double quotes or backslashes", choice.Name)
+		}
+		if seen[choice.Name] {
+			return fmt.Errorf("choice name '%s' is used more than once", choice.Name)
+		}
+		seen[choice.Name] = true
+		if err := validateArgValue(argType, choice.Value); err != nil {
+			return fmt.Errorf("choice '%s': %w", choice.Name, err)
+		}
 	}
 	return nil
 }
 
-// validateFields runs the shared field checks used by single page modals and flow pages
-func validateFields(fields []FieldInfo) error {
-	if len(fields) == 0 {
-		return fmt.Errorf("modal commands need at least one field")
+// ValidateArgRange checks min and max bounds, numbers bound the value and strings bound the length
+func ValidateArgRange(argType string, min, max string) error {
+	if min == "" && max == "" {
+		return nil
 	}
-	if len(fields) > MaxModalFields {
-		return fmt.Errorf("modal commands can have at most %d fields", MaxModalFields)
+	if !contains(valueArgTypes, argType) {
+		return fmt.Errorf("min and max are only supported on str, int, and float arguments")
 	}
-	for i, field := range fields {
-		if err := ValidateFieldName(field.Name, fields[:i]); err != nil {
-			return fmt.Errorf("field '%s': %w", field.Name, err)
+
+	parse := func(s string) (float64, error) {
+		if argType == "float" {
+			return strconv.ParseFloat(s, 64)
 		}
-		if err := ValidateFieldLabel(field.Label); err != nil {
-			return fmt.Errorf("field '%s': %w", field.Name, err)
+		value, err := strconv.Atoi(s)
+		if argType == "str" && err == nil && (value < 0 || value > maxStringArgLength) {
+			return 0, fmt.Errorf("string lengths must be between 0 and %d", maxStringArgLength)
 		}
-		if err := ValidateFieldStyle(field.Style); err != nil {
-			return fmt.Errorf("field '%s': %w", field.Name, err)
+		return float64(value), err
+	}
+
+	var low, high float64
+	var err error
+	if min != "" {
+		if low, err = parse(min); err != nil {
+			return fmt.Errorf("min '%s' is not valid for a %s argument", min, argType)
+		}
+	}
+	if max != "" {
+		if high, err = parse(max); err != nil {
+			return fmt.Errorf("max '%s' is not valid for a %s argument", max, argType)
 		}
 	}
+	if min != "" && max != "" && low > high {
+		return fmt.Errorf("min cannot be greater than max")
+	}
 	return nil
 }
 
-// ValidatePages checks a multi page modal flow, the first page in the slice starts the flow
-func ValidatePages(pages []PageInfo) error {
-	if len(pages) == 0 {
-		return fmt.Errorf("multi page modal commands need at least one page")
+// Discord shows at most 25 autocomplete results at once
+const MaxAutocompleteResults = 25
+
+// ValidateAutocompleteSource checks a source name, it becomes part of a generated Python method name
+func ValidateAutocompleteSource(s string) error {
+	if s == "" {
+		return nil
 	}
-	if len(pages) > MaxFlowPages {
-		return fmt.Errorf("multi page modal commands can have at most %d pages", MaxFlowPages)
+	for i, r := range s {
+		if !(r >= 'a' && r <= 'z' || r == '_' || i > 0 && r >= '0' && r <= '9') {
+			return fmt.Errorf("autocomplete source must be lowercase letters, numbers, or underscores and cannot start with a number")
+		}
 	}
+	return nil
+}
 
-	// Collect every page name first so branches can point forward or backward
-	pageNames := make(map[string]bool)
-	for _, page := range pages {
-		if page.Name == "" {
-			return fmt.Errorf("page names cannot be empty")
-		}
-		if strings.Contains(page.Name, " ") {
-			return fmt.Errorf("page '%s': page names cannot contain spaces", page.Name)
+// validateArgAutocomplete checks that an autocomplete argument has exactly one place to take suggestions from
+func validateArgAutocomplete(arg ArgInfo) error {
+	if !arg.Autocomplete {
+		if len(arg.Suggestions) > 0 || arg.AutocompleteSource != "" {
+			return fmt.Errorf("suggestions and an autocomplete source need autocomplete turned on")
 		}
-		if pageNames[page.Name] {
-			return fmt.Errorf("page name '%s' is used more than once", page.Name)
+		return nil
+	}
+	if !contains(valueArgTypes, arg.Type) {
+		return fmt.Errorf("autocomplete is only supported on str, int, and float arguments")
+	}
+	if len(arg.Choices) > 0 {
+		return fmt.Errorf("an argument cannot have both choices and autocomplete")
+	}
+	if len(arg.Suggestions) > 0 && arg.AutocompleteSource != "" {
+		return fmt.Errorf("autocomplete takes either suggestions or a source, not both")
+	}
+	if len(arg.Suggestions) == 0 && arg.AutocompleteSource == "" {
+		return fmt.Errorf("autocomplete needs suggestions or a source")
+	}
+	for _, suggestion := range arg.Suggestions {
+		if err := validateArgValue(arg.Type, suggestion); err != nil {
+			return fmt.Errorf("suggestion %w", err)
 		}
-		pageNames[page.Name] = true
 	}
+	return ValidateAutocompleteSource(arg.AutocompleteSource)
+}
 
-	for _, page := range pages {
-		if err := validateFields(page.Fields); err != nil {
-			return fmt.Errorf("page '%s': %w", page.Name, err)
-		}
+// Discord caps role names at 100 characters
+const maxRoleNameLength = 100
 
-		// Branches can only test fields the user filled in on this page
-		for _, branch := range page.Branches {
-			if !fieldExists(branch.Field, page.Fields) {
-				return fmt.Errorf("page '%s': branch field '%s' is not a field on this page", page.Name, branch.Field)
-			}
-			if branch.Goto == "" {
-				return fmt.Errorf("page '%s': branch goto cannot be empty", page.Name)
-			}
-			if !pageNames[branch.Goto] {
-				return fmt.Errorf("page '%s': branch goto '%s' is not a page name", page.Name, branch.Goto)
-			}
+// ValidateCommandPermissions checks that every permission is a discord.Permissions flag
+func ValidateCommandPermissions(permissions []string) error {
+	for i, permission := range permissions {
+		if !contains(validPermissions, permission) {
+			return fmt.Errorf("'%s' is not a Discord permission, use a discord.Permissions flag like manage_messages", permission)
 		}
-
-		// An empty next ends the flow, anything else has to be a real page
-		if page.Next != "" && !pageNames[page.Next] {
-			return fmt.Errorf("page '%s': next page '%s' is not a page name", page.Name, page.Next)
+		if contains(permissions[:i], permission) {
+			return fmt.Errorf("permission '%s' is listed more than once", permission)
 		}
 	}
+	return nil
+}
 
+// ValidateCommandRoles checks role names and IDs, an all digit entry is treated as a role ID
+func ValidateCommandRoles(roles []string) error {
+	for i, role := range roles {
+		if role == "" {
+			return fmt.Errorf("role cannot be empty")
+		}
+		if len(role) > maxRoleNameLength {
+			return fmt.Errorf("role '%s' cannot be longer than %d characters", role, maxRoleNameLength)
+		}
+		// Role names are written into a Python string literal
+		if strings.ContainsAny(role, "\"\\") {
+			return fmt.Errorf("role '%s' cannot contain double quotes or backslashes", role)
+		}
+		if contains(roles[:i], role) {
+			return fmt.Errorf("role '%s' is listed more than once", role)
+		}
+	}
 	return nil
 }
 
-// ValidateResponses checks the expected responses a command declares
-func ValidateResponses(responses []ResponseInfo) error {
-	if len(responses) > MaxCommandResponses {
-		return fmt.Errorf("commands can have at most %d responses", MaxCommandResponses)
+// ValidateCooldown checks a cooldown rate, period, and bucket, a nil cooldown means none
+func ValidateCooldown(cooldown *CooldownInfo) error {
+	if cooldown == nil {
+		return nil
 	}
-	for i, response := range responses {
-		if !contains(validResponseTypes, response.Type) {
-			return fmt.Errorf("response %d: type must be one of %s", i+1, strings.Join(validResponseTypes, ", "))
-		}
-		if response.Content == "" {
-			return fmt.Errorf("response %d: content cannot be empty", i+1)
+	if cooldown.Rate < 1 {
+		return fmt.Errorf("cooldown rate must be at least 1")
+	}
+	if cooldown.Per <= 0 {
+		return fmt.Errorf("cooldown period must be greater than 0 seconds")
+	}
+	if !contains(validCooldownBuckets, cooldown.Bucket) {
+		return fmt.Errorf("cooldown bucket must be one of %s", strings.Join(validCooldownBuckets, ", "))
+	}
+	return nil
+}
+
+// validateInstallSettings checks one allowed installs or allowed contexts list against its valid values
+func validateInstallSettings(kind string, settings, valid []string) error {
+	for i, setting := range settings {
+		if !contains(valid, setting) {
+			return fmt.Errorf("allowed %s must be one of %s", kind, strings.Join(valid, ", "))
 		}
-		// The content lands inside a python string literal so these characters would break the generated file
-		if strings.ContainsAny(response.Content, "\"\\\n") {
-			return fmt.Errorf("response %d: content cannot contain double quotes, backslashes, or newlines", i+1)
+		if contains(settings[:i], setting) {
+			return fmt.Errorf("allowed %s '%s' is listed more than once", kind, setting)
 		}
 	}
 	return nil
 }
 
-// ValidateCommand checks a full command definition against the already accepted commands
-func ValidateCommand(command CommandInfo, existing []CommandInfo) error {
-	if err := ValidateCommandName(command.Name, existing); err != nil {
+// validateCommandAccess checks the permission, role, context, and cooldown settings of a command
+func validateCommandAccess(command CommandInfo) error {
+	if err := ValidateCommandPermissions(command.Permissions); err != nil {
 		return err
 	}
-	if err := ValidateCommandType(command.Type); err != nil {
+	if err := ValidateCommandRoles(command.Roles); err != nil {
 		return err
 	}
-	if err := ValidateCommandScope(command.Scope); err != nil {
+	if err := ValidateCooldown(command.Cooldown); err != nil {
 		return err
 	}
-	if err := ValidateCommandDescription(command.Description); err != nil {
+	if err := validateInstallSettings("install", command.AllowedInstalls, validInstallTypes); err != nil {
 		return err
 	}
-	if err := ValidateReturnType(command.ReturnType); err != nil {
+	if err := validateInstallSettings("context", command.AllowedContexts, validInstallContexts); err != nil {
 		return err
 	}
-	if err := ValidateResponses(command.Responses); err != nil {
-		return err
+	hasInstallSettings := len(command.AllowedInstalls) > 0 || len(command.AllowedContexts) > 0
+	// A hybrid command also runs from a message, where Discord has no install or context to check
+	if UsesContext(command.Type) && hasInstallSettings {
+		return fmt.Errorf("allowed installs and contexts only apply to application commands")
 	}
-	if command.Type == "modal" {
-		if len(command.Args) > 0 {
-			return fmt.Errorf("modal commands cannot have arguments")
-		}
-		if len(command.Fields) > 0 && len(command.Pages) > 0 {
-			return fmt.Errorf("modal commands cannot have both fields and pages")
-		}
-		if len(command.Pages) > 0 {
+	// Discord reads these from the top level command, a subcommand cannot override its group,
+	// ext.commands checks run on every prefix subcommand so those can set their own
+	if command.Group != "" && command.Type != "prefix" && (len(command.Permissions) > 0 || command.GuildOnly || hasInstallSettings) {
+		return fmt.Errorf("permissions, guild only, and allowed installs or contexts only apply to top level commands")
+	}
+	if command.GuildOnly && len(command.AllowedContexts) > 0 {
+		return fmt.Errorf("a guild only command cannot also set allowed contexts")
+	}
+	return nil
+}
+
+// validateArgOptions checks the optional, default, choice, range, and autocomplete settings of one argument
+func validateArgOptions(arg ArgInfo, commandType string) error {
+	if arg.Default != "" && !arg.Optional {
+		return fmt.Errorf("only optional arguments can have a default")
+	}
+	if (arg.Greedy || arg.Rest) && commandType != "prefix" {
+		return fmt.Errorf("greedy and rest of message arguments are only supported on prefix commands")
+	}
+	if arg.Greedy && arg.Rest {
+		return fmt.Errorf("an argument cannot be both greedy and take the rest of the message")
+	}
+	// discord.py refuses Greedy[str] since every word converts to a string
+	if arg.Greedy && arg.Type == "str" {
+		return fmt.Errorf("greedy arguments cannot be str")
+	}
+	if err := ValidateArgDefault(arg.Type, arg.Default); err != nil {
+		return err
+	}
+	if (len(arg.Choices) > 0 || arg.Min != "" || arg.Max != "") && !HasSlashOptions(commandType) {
+		return fmt.Errorf("choices, min, and max are only supported on slash commands")
+	}
+	if arg.Autocomplete && !HasSlashOptions(commandType) {
+		return fmt.Errorf("autocomplete is only supported on slash commands")
+	}
+	if err := validateArgAutocomplete(arg); err != nil {
+		return err
+	}
+	if len(arg.Choices) > 0 && (arg.Min != "" || arg.Max != "") {
+		return fmt.Errorf("an argument cannot have both choices and a min or max")
+	}
+	if err := ValidateArgChoices(arg.Type, arg.Choices); err != nil {
+		return err
+	}
+	// Discord only ever sends one of the choices, so a default outside them could never be picked
+	if arg.Default != "" && len(arg.Choices) > 0 && !slices.ContainsFunc(arg.Choices, func(choice ChoiceInfo) bool {
+		return sameArgValue(arg.Type, choice.Value, arg.Default)
+	}) {
+		return fmt.Errorf("default '%s' must be the value of one of the choices", arg.Default)
+	}
+	if err := ValidateArgRange(arg.Type, arg.Min, arg.Max); err != nil {
+		return err
+	}
+	return validateArgDefaultRange(arg)
+}
+
+// validateArgDefaultRange checks a default falls within the argument's min and max, the bounds of a str argument limit its length
+func validateArgDefaultRange(arg ArgInfo) error {
+	if arg.Default == "" || (arg.Min == "" && arg.Max == "") {
+		return nil
+	}
+	unit := ""
+	value, err := strconv.ParseFloat(arg.Default, 64)
+	if arg.Type == "str" {
+		unit = " characters long"
+		value, err = float64(utf8.RuneCountInString(arg.Default)), nil
+	}
+	// A default of the wrong type is already reported by ValidateArgDefault
+	if err != nil {
+		return nil
+	}
+	if low, err := strconv.ParseFloat(arg.Min, 64); err == nil && value < low {
+		return fmt.Errorf("default '%s' must be at least %s%s", arg.Default, arg.Min, unit)
+	}
+	if high, err := strconv.ParseFloat(arg.Max, 64); err == nil && value > high {
+		return fmt.Errorf("default '%s' must be at most %s%s", arg.Default, arg.Max, unit)
+	}
+	return nil
+}
+
+// sameArgValue reports whether two fixed values of an argument type are the same literal, numbers by value
+func sameArgValue(argType string, a, b string) bool {
+	if argType == "int" || argType == "float" {
+		x, errA := strconv.ParseFloat(a, 64)
+		y, errB := strconv.ParseFloat(b, 64)
+		return errA == nil && errB == nil && x == y
+	}
+	return a == b
+}
+
+// Discord lays a view out in at most five rows, a select menu fills a row and buttons share one five at a time
+const (
+	maxViewRows                = 5
+	maxButtonsPerRow           = 5
+	MaxSelectOptions           = 25
+	maxButtonLabelLength       = 80
+	maxSelectPlaceholderLength = 150
+	maxSelectOptionLength      = 100
+)
+
+// Component settings a component command can use
+var (
+	validComponentTypes = []string{"button", "select"}
+	validButtonStyles   = []string{"primary", "secondary", "success", "danger"}
+)
+
+// ValidateComponentName checks a component name, it names the item in the generated view
+func ValidateComponentName(s string, existing []ComponentInfo) error {
+	if s == "" {
+		return fmt.Errorf("component name cannot be empty")
+	}
+	for i, r := range s {
+		if !(r >= 'a' && r <= 'z' || r == '_' || i > 0 && r >= '0' && r <= '9') {
+			return fmt.Errorf("component name must be lowercase letters, numbers, or underscores and cannot start with a number")
+		}
+	}
+	for _, component := range existing {
+		if component.Name == s {
+			return fmt.Errorf("component '%s' already exists", s)
+		}
+	}
+	return nil
+}
+
+// ValidateComponentLabel checks a button label or select placeholder against Discord's limits
+func ValidateComponentLabel(componentType, s string) error {
+	limit := maxButtonLabelLength
+	if componentType == "select" {
+		limit = maxSelectPlaceholderLength
+	}
+	if s == "" && componentType == "button" {
+		return fmt.Errorf("button label cannot be empty")
+	}
+	if len(s) > limit {
+		return fmt.Errorf("label cannot be longer than %d characters", limit)
+	}
+	return nil
+}
+
+// ValidateComponentTimeout checks the seconds typed for a view timeout, empty keeps the default
+func ValidateComponentTimeout(s string) error {
+	if s == "" {
+		return nil
+	}
+	timeout, err := strconv.Atoi(s)
+	if err != nil || timeout < 0 {
+		return fmt.Errorf("timeout must be a whole number of seconds, 0 for no timeout")
+	}
+	return nil
+}
+
+// ValidateSelectOption checks one select option against the options already on the menu
+func ValidateSelectOption(option SelectOptionInfo, existing []SelectOptionInfo) error {
+	if option.Label == "" || option.Value == "" {
+		return fmt.Errorf("select options need a label and a value")
+	}
+	if len(option.Label) > maxSelectOptionLength || len(option.Value) > maxSelectOptionLength {
+		return fmt.Errorf("select option labels and values cannot be longer than %d characters", maxSelectOptionLength)
+	}
+	for _, other := range existing {
+		if other.Value == option.Value {
+			return fmt.Errorf("select option value '%s' is used more than once", option.Value)
+		}
+	}
+	if err := ValidateResponse(option.Response); err != nil {
+		return fmt.Errorf("select option '%s': %w", option.Label, err)
+	}
+	return nil
+}
+
+// viewRows counts the rows Discord needs to lay out the components
+func viewRows(items []ComponentInfo) int {
+	rows, buttons := 0, 0
+	for _, item := range items {
+		if item.Type == "select" {
+			rows++
+		} else {
+			buttons++
+		}
+	}
+	return rows + (buttons+maxButtonsPerRow-1)/maxButtonsPerRow
+}
+
+// ValidateComponents checks the view of a component command, every button and select option needs a response
+func ValidateComponents(components *ComponentsInfo) error {
+	if components == nil || len(components.Items) == 0 {
+		return fmt.Errorf("component commands need at least one button or select menu")
+	}
+	if components.Timeout < 0 {
+		return fmt.Errorf("timeout cannot be negative")
+	}
+	if viewRows(components.Items) > maxViewRows {
+		return fmt.Errorf("components need more than the %d rows Discord allows, a select menu takes a row and buttons fit %d to a row", maxViewRows, maxButtonsPerRow)
+	}
+	for i, item := range components.Items {
+		if err := ValidateComponentName(item.Name, components.Items[:i]); err != nil {
+			return err
+		}
+		if !contains(validComponentTypes, item.Type) {
+			return fmt.Errorf("component '%s': type must be one of %s", item.Name, strings.Join(validComponentTypes, ", "))
+		}
+		if err := ValidateComponentLabel(item.Type, item.Label); err != nil {
+			return fmt.Errorf("component '%s': %w", item.Name, err)
+		}
+		if item.Type == "button" {
+			if !contains(validButtonStyles, item.Style) {
+				return fmt.Errorf("component '%s': style must be one of %s", item.Name, strings.Join(validButtonStyles, ", "))
+			}
+			if len(item.Options) > 0 {
+				return fmt.Errorf("component '%s': buttons cannot have options", item.Name)
+			}
+			if err := ValidateResponse(item.Response); err != nil {
+				return fmt.Errorf("component '%s': %w", item.Name, err)
+			}
+			continue
+		}
+		if len(item.Options) == 0 || len(item.Options) > MaxSelectOptions {
+			return fmt.Errorf("component '%s': select menus need between 1 and %d options", item.Name, MaxSelectOptions)
+		}
+		for j, option := range item.Options {
+			if err := ValidateSelectOption(option, item.Options[:j]); err != nil {
+				return fmt.Errorf("component '%s': %w", item.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func fieldExists(fieldName string, fields []FieldInfo) bool {
+	for _, field := range fields {
+		if field.Name == fieldName {
+			return true
+		}
+	}
+	return false
+}
+
+func ValidateFieldName(s string, existing []FieldInfo) error {
+	if s == "" {
+		return fmt.Errorf("field name cannot be empty")
+	}
+	if strings.Contains(s, " ") {
+		return fmt.Errorf("field name cannot contain spaces")
+	}
+	if strings.Contains(s, "-") {
+		return fmt.Errorf("field name cannot contain dashes")
+	}
+	if fieldExists(s, existing) {
+		return fmt.Errorf("field name already exists")
+	}
+	return nil
+}
+
+func ValidateFieldLabel(s string) error {
+	if s == "" {
+		return fmt.Errorf("field label cannot be empty")
+	}
+	if len([]rune(s)) > maxFieldLabelLength {
+		return fmt.Errorf("field label must be at most %d characters", maxFieldLabelLength)
+	}
+	return nil
+}
+
+func ValidateFieldStyle(s string) error {
+	if s == "" {
+		return fmt.Errorf("field style cannot be empty")
+	}
+	if !contains(validFieldStyles, s) {
+		return fmt.Errorf("field style must be one of %s", strings.Join(validFieldStyles, ", "))
+	}
+	return nil
+}
+
+// validateFields runs the shared field checks used by single page modals and flow pages
+func validateFields(fields []FieldInfo) error {
+	if len(fields) == 0 {
+		return fmt.Errorf("modal commands need at least one field")
+	}
+	if len(fields) > MaxModalFields {
+		return fmt.Errorf("modal commands can have at most %d fields", MaxModalFields)
+	}
+	for i, field := range fields {
+		if err := ValidateFieldName(field.Name, fields[:i]); err != nil {
+			return fmt.Errorf("field '%s': %w", field.Name, err)
+		}
+		if err := ValidateFieldLabel(field.Label); err != nil {
+			return fmt.Errorf("field '%s': %w", field.Name, err)
+		}
+		if err := ValidateFieldStyle(field.Style); err != nil {
+			return fmt.Errorf("field '%s': %w", field.Name, err)
+		}
+	}
+	return nil
+}
+
+// ValidatePages checks a multi page modal flow, the first page in the slice starts the flow
+func ValidatePages(pages []PageInfo) error {
+	if len(pages) == 0 {
+		return fmt.Errorf("multi page modal commands need at least one page")
+	}
+	if len(pages) > MaxFlowPages {
+		return fmt.Errorf("multi page modal commands can have at most %d pages", MaxFlowPages)
+	}
+
+	// Collect every page name first so branches can point forward or backward
+	pageNames := make(map[string]bool)
+	for _, page := range pages {
+		if page.Name == "" {
+			return fmt.Errorf("page names cannot be empty")
+		}
+		if strings.Contains(page.Name, " ") {
+			return fmt.Errorf("page '%s': page names cannot contain spaces", page.Name)
+		}
+		if pageNames[page.Name] {
+			return fmt.Errorf("page name '%s' is used more than once", page.Name)
+		}
+		pageNames[page.Name] = true
+	}
+
+	for _, page := range pages {
+		if err := validateFields(page.Fields); err != nil {
+			return fmt.Errorf("page '%s': %w", page.Name, err)
+		}
+
+		// Branches can only test fields the user filled in on this page
+		for _, branch := range page.Branches {
+			if !fieldExists(branch.Field, page.Fields) {
+				return fmt.Errorf("page '%s': branch field '%s' is not a field on this page", page.Name, branch.Field)
+			}
+			if branch.Goto == "" {
+				return fmt.Errorf("page '%s': branch goto cannot be empty", page.Name)
+			}
+			if !pageNames[branch.Goto] {
+				return fmt.Errorf("page '%s': branch goto '%s' is not a page name", page.Name, branch.Goto)
+			}
+		}
+
+		// An empty next ends the flow, anything else has to be a real page
+		if page.Next != "" && !pageNames[page.Next] {
+			return fmt.Errorf("page '%s': next page '%s' is not a page name", page.Name, page.Next)
+		}
+	}
+
+	return nil
+}
+
+// ValidateResponses checks the expected responses a command declares
+func ValidateResponses(responses []ResponseInfo) error {
+	if len(responses) > MaxCommandResponses {
+		return fmt.Errorf("commands can have at most %d responses", MaxCommandResponses)
+	}
+	for i, response := range responses {
+		if err := ValidateResponse(response); err != nil {
+			return fmt.Errorf("response %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// ValidateResponse checks a single response and the settings its type needs
+func ValidateResponse(response ResponseInfo) error {
+	if !contains(validResponseTypes, response.Type) {
+		return fmt.Errorf("type must be one of %s", strings.Join(validResponseTypes, ", "))
+	}
+	// An embed can say everything through its title and fields, every other type sends the content
+	if response.Content == "" && response.Type != "embed" {
+		return fmt.Errorf("content cannot be empty")
+	}
+	if err := validateResponseText("content", response.Content, maxEmbedDescriptionLength); err != nil {
+		return err
+	}
+
+	if response.Type == "embed" {
+		if err := ValidateEmbed(response.Embed, response.Content); err != nil {
+			return err
+		}
+	} else if response.Embed != nil {
+		return fmt.Errorf("only embed responses can have an embed")
+	}
+
+	if response.Type == "channel" {
+		if err := ValidateChannelID(response.ChannelID); err != nil {
+			return err
+		}
+	} else if response.ChannelID != "" {
+		return fmt.Errorf("only channel responses can have a channel ID")
+	}
+
+	// Direct messages and channel posts are seen by whoever can read them, ephemeral only applies to replies
+	if response.Ephemeral && (response.Type == "dm" || response.Type == "channel") {
+		return fmt.Errorf("%s responses cannot be ephemeral", response.Type)
+	}
+	return nil
+}
+
+// validateResponseText checks a piece of response text that is rendered into a python string literal
+func validateResponseText(name string, s string, maxLength int) error {
+	if len(s) > maxLength {
+		return fmt.Errorf("%s cannot be longer than %d characters", name, maxLength)
+	}
+	// The text lands inside a python string literal so these characters would break the generated file
+	if strings.ContainsAny(s, "\"\\\n") {
+		return fmt.Errorf("%s cannot contain double quotes, backslashes, or newlines", name)
+	}
+	return nil
+}
+
+// ValidateEmbed checks the embed of an embed response, description is the response content
+func ValidateEmbed(embed *EmbedInfo, description string) error {
+	if embed == nil {
+		embed = &EmbedInfo{}
+	}
+	if embed.Title == "" && description == "" && len(embed.Fields) == 0 {
+		return fmt.Errorf("embeds need a title, a description, or at least one field")
+	}
+	if err := validateResponseText("embed title", embed.Title, maxEmbedTitleLength); err != nil {
+		return err
+	}
+	if embed.Color != "" && !embedColorRegex.MatchString(embed.Color) {
+		return fmt.Errorf("embed color must be a hex color like #5865F2")
+	}
+	if len(embed.Fields) > MaxEmbedFields {
+		return fmt.Errorf("embeds can have at most %d fields", MaxEmbedFields)
+	}
+	for _, field := range embed.Fields {
+		if field.Name == "" || field.Value == "" {
+			return fmt.Errorf("embed fields need a name and a value")
+		}
+		if err := validateResponseText("embed field name", field.Name, maxEmbedFieldNameLength); err != nil {
+			return err
+		}
+		if err := validateResponseText("embed field value", field.Value, maxEmbedFieldValueLength); err != nil {
+			return err
+		}
+	}
+	if err := validateResponseText("embed footer", embed.Footer, maxEmbedFooterLength); err != nil {
+		return err
+	}
+	return ValidateEmbedThumbnail(embed.Thumbnail)
+}
+
+// ValidateEmbedThumbnail checks that an embed thumbnail is empty or a plain http or https URL
+func ValidateEmbedThumbnail(s string) error {
+	if s == "" {
+		return nil
+	}
+	if !strings.HasPrefix(s, "https://") && !strings.HasPrefix(s, "http://") {
+		return fmt.Errorf("embed thumbnail must be an http or https URL")
+	}
+	if strings.ContainsAny(s, "\" \\\n") {
+		return fmt.Errorf("embed thumbnail cannot contain spaces, double quotes, or backslashes")
+	}
+	return nil
+}
+
+// ValidateChannelID checks that a channel response targets a Discord channel ID
+func ValidateChannelID(s string) error {
+	if !snowflakeRegex.MatchString(s) {
+		return fmt.Errorf("channel ID must be the 17 to 20 digit ID of a Discord channel")
+	}
+	return nil
+}
+
+// listenerEvent is a discord.py event a cog listener can handle.
+// Member is the expression holding the member a role action assigns to, empty when the event has none
+type listenerEvent struct {
+	Name   string
+	Params []string
+	Member string
+}
+
+// Events the generator knows the signatures of, in the order the TUI lists them
+var listenerEvents = []listenerEvent{
+	{Name: "on_ready"},
+	{Name: "on_member_join", Params: []string{"member: discord.Member"}, Member: "member"},
+	{Name: "on_member_remove", Params: []string{"member: discord.Member"}},
+	{Name: "on_member_update", Params: []string{"before: discord.Member", "after: discord.Member"}, Member: "after"},
+	{Name: "on_message", Params: []string{"message: discord.Message"}},
+	{Name: "on_message_edit", Params: []string{"before: discord.Message", "after: discord.Message"}},
+	{Name: "on_message_delete", Params: []string{"message: discord.Message"}},
+	{Name: "on_reaction_add", Params: []string{"reaction: discord.Reaction", "user: discord.abc.User"}},
+	{Name: "on_raw_reaction_add", Params: []string{"payload: discord.RawReactionActionEvent"}, Member: "payload.member"},
+	{Name: "on_raw_reaction_remove", Params: []string{"payload: discord.RawReactionActionEvent"}},
+	{Name: "on_voice_state_update", Params: []string{"member: discord.Member", "before: discord.VoiceState", "after: discord.VoiceState"}, Member: "member"},
+	{Name: "on_guild_join", Params: []string{"guild: discord.Guild"}},
+	{Name: "on_guild_remove", Params: []string{"guild: discord.Guild"}},
+}
+
+// Canned listener actions, an empty action generates an empty handler to fill in
+var validListenerActions = []string{"log", "message", "role"}
+
+// findListenerEvent looks up a supported listener event by name
+func findListenerEvent(name string) (listenerEvent, bool) {
+	for _, event := range listenerEvents {
+		if event.Name == name {
+			return event, true
+		}
+	}
+	return listenerEvent{}, false
+}
+
+// ListenerEventNames returns the supported listener events in display order
+func ListenerEventNames() []string {
+	names := make([]string, len(listenerEvents))
+	for i, event := range listenerEvents {
+		names[i] = event.Name
+	}
+	return names
+}
+
+// ValidateListenerEvent checks that an event is supported and not already handled by the cog,
+// a cog method can only be named after an event once
+func ValidateListenerEvent(s string, existing []ListenerInfo) error {
+	if _, ok := findListenerEvent(s); !ok {
+		return fmt.Errorf("listener event must be one of %s", strings.Join(ListenerEventNames(), ", "))
+	}
+	for _, listener := range existing {
+		if listener.Event == s {
+			return fmt.Errorf("the cog already listens to %s", s)
+		}
+	}
+	return nil
+}
+
+// ValidateRoleID checks that a role action targets a Discord role ID
+func ValidateRoleID(s string) error {
+	if !snowflakeRegex.MatchString(s) {
+		return fmt.Errorf("role ID must be the 17 to 20 digit ID of a Discord role")
+	}
+	return nil
+}
+
+// ValidateListener checks a listener definition against the listeners already on the cog
+func ValidateListener(listener ListenerInfo, existing []ListenerInfo) error {
+	if err := ValidateListenerEvent(listener.Event, existing); err != nil {
+		return err
+	}
+	if listener.Action != "" && !contains(validListenerActions, listener.Action) {
+		return fmt.Errorf("listener action must be empty or one of %s", strings.Join(validListenerActions, ", "))
+	}
+
+	if listener.Action == "message" {
+		if err := ValidateChannelID(listener.ChannelID); err != nil {
+			return err
+		}
+		if listener.Content == "" {
+			return fmt.Errorf("message actions need content")
+		}
+		if err := validateResponseText("content", listener.Content, maxEmbedDescriptionLength); err != nil {
+			return err
+		}
+	} else if listener.ChannelID != "" || listener.Content != "" {
+		return fmt.Errorf("only message actions can have a channel ID and content")
+	}
+
+	if listener.Action == "role" {
+		event, _ := findListenerEvent(listener.Event)
+		if event.Member == "" {
+			return fmt.Errorf("%s has no member to assign a role to", listener.Event)
+		}
+		if err := ValidateRoleID(listener.RoleID); err != nil {
+			return err
+		}
+	} else if listener.RoleID != "" {
+		return fmt.Errorf("only role actions can have a role ID")
+	}
+	return nil
+}
+
+// ValidateListeners checks every listener of a cog against the ones before it, and that the method named after
+// its event is not already a method of the cog's commands or tasks
+func ValidateListeners(listeners []ListenerInfo, commands []CommandInfo, tasks []TaskInfo) error {
+	for i, listener := range listeners {
+		if err := ValidateListener(listener, listeners[:i]); err != nil {
+			return fmt.Errorf("listener '%s': %w", listener.Event, err)
+		}
+		if owner := cogMethodOwner(listener.Event, commands, nil, tasks); owner != "" {
+			return fmt.Errorf("listener '%s': %s already defines the method %s", listener.Event, owner, listener.Event)
+		}
+	}
+	return nil
+}
+
+// Units a task interval can be given in, matching the tasks.loop keyword arguments
+var validTaskUnits = []string{"seconds", "minutes", "hours"}
+
+// Cog methods a task name would shadow
+var reservedTaskNames = []string{"bot", "cog_load", "cog_unload"}
+
+const maxTaskNameLength = 32
+
+// ValidateTaskName checks that a task name is a method name not already used by the cog's tasks
+func ValidateTaskName(s string, existing []TaskInfo) error {
+	if s == "" {
+		return fmt.Errorf("task name cannot be empty")
+	}
+	if len(s) > maxTaskNameLength {
+		return fmt.Errorf("task name cannot be longer than %d characters", maxTaskNameLength)
+	}
+	if !taskNameRegex.MatchString(s) {
+		return fmt.Errorf("task name can only contain lowercase letters, numbers, and underscores, and cannot start with a number")
+	}
+	if contains(reservedTaskNames, s) || strings.HasPrefix(s, "before_") {
+		return fmt.Errorf("task name '%s' is reserved", s)
+	}
+	for _, task := range existing {
+		if task.Name == s {
+			return fmt.Errorf("the cog already has a task named %s", s)
+		}
+	}
+	return nil
+}
+
+// ParseTaskTimes splits a comma separated list of HH:MM times, checking each one
+func ParseTaskTimes(s string) ([]string, error) {
+	var times []string
+	for item := range strings.SplitSeq(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if !taskTimeRegex.MatchString(item) {
+			return nil, fmt.Errorf("task time '%s' must be a 24 hour time like 09:00", item)
+		}
+		if contains(times, item) {
+			return nil, fmt.Errorf("task time '%s' is listed twice", item)
+		}
+		times = append(times, item)
+	}
+	return times, nil
+}
+
+// ValidateTimezone checks that a task timezone looks like an IANA zone name, empty means UTC
+func ValidateTimezone(s string) error {
+	if s != "" && !timezoneRegex.MatchString(s) {
+		return fmt.Errorf("timezone must be an IANA zone name like America/New_York")
+	}
+	return nil
+}
+
+// ValidateTask checks a task definition against the tasks already on the cog
+func ValidateTask(task TaskInfo, existing []TaskInfo) error {
+	if err := ValidateTaskName(task.Name, existing); err != nil {
+		return err
+	}
+
+	// A task runs on an interval or at times of day, never both
+	if len(task.Times) > 0 {
+		if task.Interval != 0 || task.Unit != "" {
+			return fmt.Errorf("a task runs on an interval or at times of day, not both")
+		}
+		if _, err := ParseTaskTimes(strings.Join(task.Times, ",")); err != nil {
+			return err
+		}
+		if err := ValidateTimezone(task.Timezone); err != nil {
+			return err
+		}
+	} else {
+		if task.Interval <= 0 {
+			return fmt.Errorf("a task needs a positive interval or at least one time of day")
+		}
+		if !contains(validTaskUnits, task.Unit) {
+			return fmt.Errorf("task unit must be one of %s", strings.Join(validTaskUnits, ", "))
+		}
+		if task.Timezone != "" {
+			return fmt.Errorf("only tasks that run at times of day can have a timezone")
+		}
+	}
+
+	if task.ChannelID != "" {
+		if err := ValidateChannelID(task.ChannelID); err != nil {
+			return err
+		}
+	}
+	if task.Content != "" {
+		if task.ChannelID == "" {
+			return fmt.Errorf("task content needs a channel ID to post to")
+		}
+		if err := validateResponseText("content", task.Content, maxEmbedDescriptionLength); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateTasks checks every task of a cog against the ones before it, and that its methods are not already
+// methods of the cog's commands or listeners
+func ValidateTasks(tasks []TaskInfo, commands []CommandInfo, listeners []ListenerInfo) error {
+	for i, task := range tasks {
+		if err := ValidateTask(task, tasks[:i]); err != nil {
+			return fmt.Errorf("task '%s': %w", task.Name, err)
+		}
+		if err := validateTaskMethods(task.Name, commands, listeners); err != nil {
+			return fmt.Errorf("task '%s': %w", task.Name, err)
+		}
+	}
+	return nil
+}
+
+// validateTaskMethods checks that the methods a task is rendered as, the loop and its before_ hook, are not
+// already methods of the cog's commands or listeners
+func validateTaskMethods(name string, commands []CommandInfo, listeners []ListenerInfo) error {
+	for _, method := range []string{underscoreName(name), "before_" + underscoreName(name)} {
+		if owner := cogMethodOwner(method, commands, listeners, nil); owner != "" {
+			return fmt.Errorf("%s already defines the method %s", owner, method)
+		}
+	}
+	return nil
+}
+
+// cogMethodOwner names what in a cog is rendered as the method, commands by their underscored path, listeners by
+// their event, and tasks by their name and before_ hook, empty when nothing is
+func cogMethodOwner(method string, commands []CommandInfo, listeners []ListenerInfo, tasks []TaskInfo) string {
+	for _, command := range commands {
+		if commandMethod(command) == method {
+			return fmt.Sprintf("command '%s'", CommandPath(command))
+		}
+	}
+	for _, listener := range listeners {
+		if listener.Event == method {
+			return fmt.Sprintf("the %s listener", listener.Event)
+		}
+	}
+	for _, task := range tasks {
+		if underscoreName(task.Name) == method || "before_"+underscoreName(task.Name) == method {
+			return fmt.Sprintf("task '%s'", task.Name)
+		}
+	}
+	return ""
+}
+
+// ValidateCommand checks a full command definition against the already accepted commands
+// validateHybridCommand checks the slash command limits a hybrid command has on top of the prefix ones
+func validateHybridCommand(command CommandInfo) error {
+	if len(command.Name) > maxSlashNameLength {
+		return fmt.Errorf("hybrid command names must be %d characters or less", maxSlashNameLength)
+	}
+	if !isSlashName(command.Name) {
+		return fmt.Errorf("hybrid command names can only use lowercase letters, numbers, dashes, and underscores")
+	}
+	if len(command.Description) > maxSlashDescriptionLength {
+		return fmt.Errorf("hybrid command descriptions must be %d characters or less", maxSlashDescriptionLength)
+	}
+	for _, arg := range command.Args {
+		if len(arg.Description) > maxSlashDescriptionLength {
+			return fmt.Errorf("argument '%s': description must be %d characters or less", arg.Name, maxSlashDescriptionLength)
+		}
+	}
+	return nil
+}
+
+// isSlashName reports whether Discord accepts s as a slash command or option name,
+// only lowercase names made of letters, numbers, dashes, and underscores are allowed
+func isSlashName(s string) bool {
+	for _, r := range s {
+		if unicode.IsUpper(r) || !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateLocale checks that a locale is one Discord translates commands into
+func ValidateLocale(s string) error {
+	if !contains(validLocales, s) {
+		return fmt.Errorf("unknown locale '%s', use a Discord locale like de, fr, or pt-BR", s)
+	}
+	return nil
+}
+
+func validateLocalizedName(s string) error {
+	if s == "" || len([]rune(s)) > maxSlashNameLength {
+		return fmt.Errorf("must be 1 to %d characters", maxSlashNameLength)
+	}
+	if !isSlashName(s) {
+		return fmt.Errorf("can only use lowercase letters, numbers, dashes, and underscores")
+	}
+	return nil
+}
+
+// Context menu names are shown as written, so they only share the length limit
+func validateLocalizedMenuName(s string) error {
+	if s == "" || len([]rune(s)) > maxSlashNameLength {
+		return fmt.Errorf("must be 1 to %d characters", maxSlashNameLength)
+	}
+	return nil
+}
+
+func validateLocalizedDescription(s string) error {
+	if s == "" || len([]rune(s)) > maxSlashDescriptionLength {
+		return fmt.Errorf("must be 1 to %d characters", maxSlashDescriptionLength)
+	}
+	return nil
+}
+
+func validateLocalizedLabel(s string) error {
+	if s == "" || len([]rune(s)) > maxFieldLabelLength {
+		return fmt.Errorf("must be 1 to %d characters", maxFieldLabelLength)
+	}
+	return nil
+}
+
+// validateTranslations runs check on the text of every locale, kind names the translated string in errors
+func validateTranslations(kind string, translations map[string]string, check func(string) error) error {
+	for _, locale := range slices.Sorted(maps.Keys(translations)) {
+		if err := ValidateLocale(locale); err != nil {
+			return fmt.Errorf("%s translation: %w", kind, err)
+		}
+		if err := check(translations[locale]); err != nil {
+			return fmt.Errorf("%s translation for %s %w", kind, locale, err)
+		}
+	}
+	return nil
+}
+
+// validateLocalizations checks a command's translations, only the strings Discord shows for app commands are translated
+func validateLocalizations(command CommandInfo) error {
+	if command.Type == "prefix" {
+		translated := len(command.NameLocalizations) > 0 || len(command.DescriptionLocalizations) > 0 ||
+			slices.ContainsFunc(command.Args, func(arg ArgInfo) bool {
+				return len(arg.NameLocalizations) > 0 || len(arg.DescriptionLocalizations) > 0
+			})
+		if translated {
+			return fmt.Errorf("prefix commands cannot be localized, only app commands are translated")
+		}
+		return nil
+	}
+
+	nameCheck := validateLocalizedName
+	if IsContextMenuType(command.Type) {
+		if len(command.DescriptionLocalizations) > 0 {
+			return fmt.Errorf("context menu commands have no description to localize")
+		}
+		nameCheck = validateLocalizedMenuName
+	}
+	if err := validateTranslations("name", command.NameLocalizations, nameCheck); err != nil {
+		return err
+	}
+	if err := validateTranslations("description", command.DescriptionLocalizations, validateLocalizedDescription); err != nil {
+		return err
+	}
+
+	for _, arg := range command.Args {
+		if err := validateTranslations("name", arg.NameLocalizations, validateLocalizedName); err != nil {
+			return fmt.Errorf("argument '%s': %w", arg.Name, err)
+		}
+		if err := validateTranslations("description", arg.DescriptionLocalizations, validateLocalizedDescription); err != nil {
+			return fmt.Errorf("argument '%s': %w", arg.Name, err)
+		}
+	}
+	for _, field := range command.Fields {
+		if err := validateTranslations("label", field.LabelLocalizations, validateLocalizedLabel); err != nil {
+			return fmt.Errorf("field '%s': %w", field.Name, err)
+		}
+	}
+	for _, page := range command.Pages {
+		for _, field := range page.Fields {
+			if err := validateTranslations("label", field.LabelLocalizations, validateLocalizedLabel); err != nil {
+				return fmt.Errorf("page '%s' field '%s': %w", page.Name, field.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validatePrefixOptions checks the aliases, hidden flag, and group settings only prefix commands have
+func validatePrefixOptions(command CommandInfo, existing []CommandInfo) error {
+	if command.Type != "prefix" {
+		if len(command.Aliases) > 0 || command.Hidden || command.InvokeWithoutCommand {
+			return fmt.Errorf("aliases, hidden, and invoke without command only apply to prefix commands")
+		}
+		return nil
+	}
+	// A prefix group is a command with its own description, there is no separate group to describe
+	if command.GroupDescription != "" {
+		return fmt.Errorf("prefix subcommands cannot set a group description")
+	}
+	for i, alias := range command.Aliases {
+		if alias == "" || strings.ContainsAny(alias, " \t") {
+			return fmt.Errorf("aliases cannot be empty or contain spaces")
+		}
+		if strings.ContainsAny(alias, "\"\\") {
+			return fmt.Errorf("alias '%s' cannot contain double quotes or backslashes", alias)
+		}
+		if alias == command.Name {
+			return fmt.Errorf("alias '%s' is already the command name", alias)
+		}
+		if contains(command.Aliases[:i], alias) {
+			return fmt.Errorf("alias '%s' is listed more than once", alias)
+		}
+	}
+	// Names and aliases share one lookup table among the commands of the same group
+	names := append([]string{command.Name}, command.Aliases...)
+	for _, other := range existing {
+		if other.Type != "prefix" || other.Group != command.Group {
+			continue
+		}
+		for _, name := range names {
+			if name == other.Name || contains(other.Aliases, name) {
+				return fmt.Errorf("'%s' is already used by prefix command '%s'", name, other.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidatePrefixGroups checks that every grouped prefix command sits under a prefix command of the same cog,
+// prefix groups are commands themselves so the whole set is needed to find them
+func ValidatePrefixGroups(commands []CommandInfo) error {
+	for _, command := range commands {
+		if command.Type != "prefix" || command.Group == "" {
+			continue
+		}
+		found := slices.ContainsFunc(commands, func(parent CommandInfo) bool {
+			return parent.Type == "prefix" && CommandPath(parent) == command.Group
+		})
+		if !found {
+			return fmt.Errorf("command '%s': group '%s' is not a prefix command in this cog", command.Name, command.Group)
+		}
+	}
+	return nil
+}
+
+func ValidateCommand(command CommandInfo, existing []CommandInfo) error {
+	if err := ValidateCommandType(command.Type); err != nil {
+		return err
+	}
+	if err := validateCommandNameFor(command.Type, command.Name, nil); err != nil {
+		return err
+	}
+	if err := validateCommandPath(command, existing); err != nil {
+		return err
+	}
+	if err := ValidateCommandScope(command.Scope); err != nil {
+		return err
+	}
+	if err := validateCommandDescriptionFor(command.Type, command.Description); err != nil {
+		return err
+	}
+	if err := ValidateReturnType(command.ReturnType); err != nil {
+		return err
+	}
+	if err := ValidateResponses(command.Responses); err != nil {
+		return err
+	}
+	if err := ValidateCommandGroup(command.Group); err != nil {
+		return err
+	}
+	if err := ValidateGroupDescription(command.GroupDescription); err != nil {
+		return err
+	}
+	if command.Group != "" && !CanBeGrouped(command.Type) && command.Type != "prefix" {
+		return fmt.Errorf("only slash, modal, and prefix commands can be in a group")
+	}
+	if err := validateGroupPlacement(command, existing); err != nil {
+		return err
+	}
+	if err := validateCommandAccess(command); err != nil {
+		return err
+	}
+	if command.Type == "hybrid" {
+		if err := validateHybridCommand(command); err != nil {
+			return err
+		}
+	}
+	if err := validatePrefixOptions(command, existing); err != nil {
+		return err
+	}
+	if err := validateLocalizations(command); err != nil {
+		return err
+	}
+	if command.Type == "component" {
+		if err := ValidateComponents(command.Components); err != nil {
+			return err
+		}
+		// The view rides on the reply itself, so it cannot go out as a direct message, channel post, or follow up
+		if len(command.Responses) > 0 && command.Responses[0].Type != "message" && command.Responses[0].Type != "embed" {
+			return fmt.Errorf("component commands can only reply with a message or an embed")
+		}
+	} else if command.Components != nil {
+		return fmt.Errorf("only component commands can have components")
+	}
+	if command.Type == "modal" {
+		if len(command.Args) > 0 {
+			return fmt.Errorf("modal commands cannot have arguments")
+		}
+		if len(command.Fields) > 0 && len(command.Pages) > 0 {
+			return fmt.Errorf("modal commands cannot have both fields and pages")
+		}
+		if len(command.Pages) > 0 {
 			return ValidatePages(command.Pages)
 		}
 		return validateFields(command.Fields)
 	}
+	if IsContextMenuType(command.Type) {
+		// Discord passes the clicked user or message as the only parameter
+		if len(command.Args) > 0 {
+			return fmt.Errorf("context menu commands cannot have arguments")
+		}
+		if len(command.Fields) > 0 || len(command.Pages) > 0 {
+			return fmt.Errorf("context menu commands cannot have fields or pages")
+		}
+		return nil
+	}
 	if len(command.Fields) > 0 {
 		return fmt.Errorf("only modal commands can have fields")
 	}
@@ -413,6 +1797,16 @@ func ValidateCommand(command CommandInfo, existing []CommandInfo) error {
 		if err := ValidateArgType(arg.Type); err != nil {
 			return fmt.Errorf("argument '%s': %w", arg.Name, err)
 		}
+		if err := validateArgOptions(arg, command.Type); err != nil {
+			return fmt.Errorf("argument '%s': %w", arg.Name, err)
+		}
+		if arg.Rest && i != len(command.Args)-1 {
+			return fmt.Errorf("only the last argument can take the rest of the message")
+		}
+		// Python and Discord both need every required argument before the optional ones
+		if !arg.Optional && i > 0 && command.Args[i-1].Optional {
+			return fmt.Errorf("required argument '%s' must come before optional arguments", arg.Name)
+		}
 	}
 	return nil
 }
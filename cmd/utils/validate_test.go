@@ -7,6 +7,7 @@ package utils
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -157,7 +158,7 @@ func TestValidateCommand(t *testing.T) {
 
 	badType := valid
 	badType.Name = "other"
-	badType.Type = "hybrid"
+	badType.Type = "macro"
 	if err := ValidateCommand(badType, nil); err == nil {
 		t.Error("unknown command type should fail")
 	}
@@ -180,6 +181,213 @@ func TestValidateCommand(t *testing.T) {
 	}
 }
 
+func TestValidateCommandHybrid(t *testing.T) {
+	base := CommandInfo{
+		Name:        "greet",
+		Scope:       "guild",
+		Type:        "hybrid",
+		Description: "Greets a user",
+		ReturnType:  "None",
+		Args:        []ArgInfo{{Name: "user", Type: "discord.Member", Description: "who to greet"}},
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(command *CommandInfo)
+		wantErr bool
+	}{
+		{"valid", func(c *CommandInfo) {}, false},
+		{"dashes and numbers", func(c *CommandInfo) { c.Name = "greet-2" }, false},
+		{"uppercase name", func(c *CommandInfo) { c.Name = "Greet" }, true},
+		{"name with a dot", func(c *CommandInfo) { c.Name = "greet.user" }, true},
+		{"long name", func(c *CommandInfo) { c.Name = strings.Repeat("a", 33) }, true},
+		{"long description", func(c *CommandInfo) { c.Description = strings.Repeat("a", 101) }, true},
+		{"long argument description", func(c *CommandInfo) { c.Args[0].Description = strings.Repeat("a", 101) }, true},
+		{"transform argument", func(c *CommandInfo) {
+			c.Args = []ArgInfo{{Name: "when", Type: "app_commands.Transform[str, Duration]", Description: "how long"}}
+		}, false},
+		{"access checks", func(c *CommandInfo) {
+			c.Permissions = []string{"manage_messages"}
+			c.GuildOnly = true
+			c.Cooldown = &CooldownInfo{Rate: 1, Per: 5, Bucket: "user"}
+		}, false},
+		{"group", func(c *CommandInfo) { c.Group = "admin" }, true},
+		{"allowed installs", func(c *CommandInfo) { c.AllowedInstalls = []string{"user"} }, true},
+		{"choices", func(c *CommandInfo) {
+			c.Args = []ArgInfo{{Name: "mood", Type: "str", Description: "how", Choices: []ChoiceInfo{{Name: "Happy", Value: "happy"}}}}
+		}, true},
+		{"range", func(c *CommandInfo) {
+			c.Args = []ArgInfo{{Name: "count", Type: "int", Description: "how many", Min: "1"}}
+		}, true},
+		{"autocomplete", func(c *CommandInfo) {
+			c.Args = []ArgInfo{{Name: "tag", Type: "str", Description: "which", Autocomplete: true, Suggestions: []string{"a"}}}
+		}, true},
+		{"fields", func(c *CommandInfo) { c.Fields = []FieldInfo{{Name: "reason", Label: "Reason", Style: "short"}} }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command := base
+			command.Args = slices.Clone(base.Args)
+			tt.modify(&command)
+			err := ValidateCommand(command, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCommand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// A hybrid command registers a top level slash command, so it cannot share a name with a group
+	grouped := CommandInfo{Name: "ban", Scope: "guild", Type: "slash", Description: "Bans", ReturnType: "None", Group: "greet"}
+	if err := ValidateCommand(base, []CommandInfo{grouped}); err == nil {
+		t.Error("hybrid command named after a group should fail")
+	}
+}
+
+func TestValidateCommandPrefixOptions(t *testing.T) {
+	base := CommandInfo{
+		Name:        "kick",
+		Scope:       "guild",
+		Type:        "prefix",
+		Description: "Kicks members",
+		ReturnType:  "None",
+		Args: []ArgInfo{
+			{Name: "members", Type: "discord.Member", Description: "who", Greedy: true},
+			{Name: "reason", Type: "str", Description: "why", Rest: true},
+		},
+	}
+	existing := []CommandInfo{
+		{Name: "ban", Scope: "guild", Type: "prefix", Description: "d", ReturnType: "None", Aliases: []string{"b"}},
+		{Name: "mute", Scope: "guild", Type: "prefix", Description: "d", ReturnType: "None", Group: "mod", Aliases: []string{"k"}},
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(command *CommandInfo)
+		wantErr bool
+	}{
+		{"greedy and rest", func(c *CommandInfo) {}, false},
+		{"aliases, hidden, and invoke", func(c *CommandInfo) {
+			c.Aliases = []string{"k", "boot"}
+			c.Hidden = true
+			c.InvokeWithoutCommand = true
+		}, false},
+		{"subcommand", func(c *CommandInfo) { c.Group = "mod" }, false},
+		{"subcommand alias clashing with a sibling", func(c *CommandInfo) {
+			c.Group = "mod"
+			c.Aliases = []string{"k"}
+		}, true},
+		{"alias clashing with a command", func(c *CommandInfo) { c.Aliases = []string{"ban"} }, true},
+		{"alias clashing with an alias", func(c *CommandInfo) { c.Aliases = []string{"b"} }, true},
+		{"alias equal to the name", func(c *CommandInfo) { c.Aliases = []string{"kick"} }, true},
+		{"repeated alias", func(c *CommandInfo) { c.Aliases = []string{"k", "k"} }, true},
+		{"alias with a space", func(c *CommandInfo) { c.Aliases = []string{"give boot"} }, true},
+		{"group description", func(c *CommandInfo) {
+			c.Group = "mod"
+			c.GroupDescription = "Moderation"
+		}, true},
+		{"greedy str", func(c *CommandInfo) { c.Args[0].Type = "str" }, true},
+		{"greedy rest", func(c *CommandInfo) { c.Args[1].Greedy = true }, true},
+		{"rest before the last argument", func(c *CommandInfo) {
+			c.Args[0].Greedy = false
+			c.Args[0].Rest = true
+		}, true},
+		{"greedy on a slash command", func(c *CommandInfo) {
+			c.Type = "slash"
+			c.Args = c.Args[:1]
+		}, true},
+		{"aliases on a slash command", func(c *CommandInfo) {
+			c.Type = "slash"
+			c.Args = nil
+			c.Aliases = []string{"k"}
+		}, true},
+		{"hidden on a hybrid command", func(c *CommandInfo) {
+			c.Type = "hybrid"
+			c.Args = nil
+			c.Hidden = true
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command := base
+			command.Args = slices.Clone(base.Args)
+			tt.modify(&command)
+			err := ValidateCommand(command, existing)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCommand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateCommandLocalizations(t *testing.T) {
+	tests := []struct {
+		name    string
+		command CommandInfo
+		wantErr bool
+	}{
+		{"slash translations", CommandInfo{
+			Type:                     "slash",
+			NameLocalizations:        map[string]string{"de": "grüßen", "pt-BR": "saudar"},
+			DescriptionLocalizations: map[string]string{"de": "Grüßt"},
+			Args:                     []ArgInfo{{Name: "user", Type: "discord.User", Description: "d", NameLocalizations: map[string]string{"fr": "membre"}}},
+		}, false},
+		{"unknown locale", CommandInfo{Type: "slash", NameLocalizations: map[string]string{"german": "gruessen"}}, true},
+		{"uppercase slash name", CommandInfo{Type: "slash", NameLocalizations: map[string]string{"de": "Gruessen"}}, true},
+		{"empty description", CommandInfo{Type: "slash", DescriptionLocalizations: map[string]string{"de": ""}}, true},
+		{"long argument description", CommandInfo{
+			Type: "slash",
+			Args: []ArgInfo{{Name: "user", Type: "str", Description: "d", DescriptionLocalizations: map[string]string{"de": strings.Repeat("a", 101)}}},
+		}, true},
+		{"context menu name with spaces", CommandInfo{Type: "user_context", NameLocalizations: map[string]string{"de": "Nutzer melden"}}, false},
+		{"context menu description", CommandInfo{Type: "user_context", DescriptionLocalizations: map[string]string{"de": "Meldet"}}, true},
+		{"prefix translations", CommandInfo{Type: "prefix", NameLocalizations: map[string]string{"de": "gruessen"}}, true},
+		{"modal label", CommandInfo{
+			Type:   "modal",
+			Fields: []FieldInfo{{Name: "summary", Label: "Summary", LabelLocalizations: map[string]string{"de": "Zusammenfassung"}}},
+		}, false},
+		{"long page label", CommandInfo{
+			Type:  "modal",
+			Pages: []PageInfo{{Name: "start", Fields: []FieldInfo{{Name: "summary", LabelLocalizations: map[string]string{"de": strings.Repeat("a", 46)}}}}},
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateLocalizations(tt.command)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateLocalizations() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidatePrefixGroups(t *testing.T) {
+	tag := CommandInfo{Name: "tag", Type: "prefix"}
+	admin := CommandInfo{Name: "admin", Type: "prefix", Group: "tag"}
+	purge := CommandInfo{Name: "purge", Type: "prefix", Group: "tag admin"}
+
+	tests := []struct {
+		name     string
+		commands []CommandInfo
+		wantErr  bool
+	}{
+		{"nested groups", []CommandInfo{tag, admin, purge}, false},
+		{"subcommand listed before its group", []CommandInfo{purge, admin, tag}, false},
+		{"missing group", []CommandInfo{tag, purge}, true},
+		{"slash command as the group", []CommandInfo{{Name: "tag", Type: "slash"}, admin}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePrefixGroups(tt.commands)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidatePrefixGroups() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestValidateFieldName(t *testing.T) {
 	existing := []FieldInfo{{Name: "summary", Label: "Summary", Style: "short"}}
 
@@ -291,6 +499,63 @@ func TestValidateCommandModal(t *testing.T) {
 	}
 }
 
+func TestValidateCommandContextMenu(t *testing.T) {
+	valid := CommandInfo{
+		Name:        "report",
+		Scope:       "guild",
+		Type:        "message_context",
+		Description: "Reports a message",
+		ReturnType:  "None",
+	}
+
+	if err := ValidateCommand(valid, nil); err != nil {
+		t.Errorf("valid message context command should pass, got %v", err)
+	}
+
+	user := valid
+	user.Name = "profile"
+	user.Type = "user_context"
+	if err := ValidateCommand(user, nil); err != nil {
+		t.Errorf("valid user context command should pass, got %v", err)
+	}
+
+	withArgs := valid
+	withArgs.Args = []ArgInfo{{Name: "reason", Type: "str", Description: "why"}}
+	if err := ValidateCommand(withArgs, nil); err == nil {
+		t.Error("context menu command with args should fail")
+	}
+
+	withFields := valid
+	withFields.Fields = []FieldInfo{{Name: "reason", Label: "Reason", Style: "short"}}
+	if err := ValidateCommand(withFields, nil); err == nil {
+		t.Error("context menu command with fields should fail")
+	}
+
+	// Context menus are named like Discord shows them and have no description Discord displays
+	spaced := valid
+	spaced.Name = "Report Message"
+	spaced.Description = ""
+	if err := ValidateCommand(spaced, nil); err != nil {
+		t.Errorf("context menu named with spaces and no description should pass, got %v", err)
+	}
+	slash := spaced
+	slash.Type = "slash"
+	if err := ValidateCommand(slash, nil); err == nil {
+		t.Error("slash command named with spaces should fail")
+	}
+	for _, name := range []string{"", " Report", strings.Repeat("a", 33), `Say "hi"`} {
+		menu := valid
+		menu.Name = name
+		if err := ValidateCommand(menu, nil); err == nil {
+			t.Errorf("context menu named %q should fail", name)
+		}
+	}
+
+	if !HasFixedReturnType("user_context") || !HasFixedReturnType("modal") || HasFixedReturnType("slash") {
+		t.Error("HasFixedReturnType should hold for modal and context menu types only")
+	}
+}
+
 func TestValidateCommandPages(t *testing.T) {
 	valid := CommandInfo{
 		Name:        "survey",
@@ -435,7 +700,7 @@ func TestValidateResponses(t *testing.T) {
 		t.Errorf("no responses should pass, got %v", err)
 	}
 
-	badType := []ResponseInfo{{Type: "embed", Content: "done"}}
+	badType := []ResponseInfo{{Type: "voice", Content: "done"}}
 	if err := ValidateResponses(badType); err == nil {
 		t.Error("unknown response type should fail")
 	}
@@ -459,6 +724,420 @@ func TestValidateResponses(t *testing.T) {
 	}
 }
 
+func TestValidateResponseTypes(t *testing.T) {
+	embed := &EmbedInfo{
+		Title:     "Server stats",
+		Color:     "#5865F2",
+		Fields:    []EmbedFieldInfo{{Name: "Members", Value: "{count}", Inline: true}},
+		Footer:    "Updated hourly",
+		Thumbnail: "https://example.com/icon.png",
+	}
+
+	tests := []struct {
+		name     string
+		response ResponseInfo
+		wantErr  bool
+	}{
+		{"embed", ResponseInfo{Type: "embed", Content: "Current numbers", Embed: embed}, false},
+		{"embed with only a title", ResponseInfo{Type: "embed", Embed: &EmbedInfo{Title: "Hi"}}, false},
+		{"empty embed", ResponseInfo{Type: "embed"}, true},
+		{"embed with a bad color", ResponseInfo{Type: "embed", Embed: &EmbedInfo{Title: "Hi", Color: "blurple"}}, true},
+		{"embed field without a value", ResponseInfo{Type: "embed", Embed: &EmbedInfo{Fields: []EmbedFieldInfo{{Name: "a"}}}}, true},
+		{"embed with a quoted footer", ResponseInfo{Type: "embed", Embed: &EmbedInfo{Title: "Hi", Footer: `"x"`}}, true},
+		{"embed with a relative thumbnail", ResponseInfo{Type: "embed", Embed: &EmbedInfo{Title: "Hi", Thumbnail: "icon.png"}}, true},
+		{"message with an embed", ResponseInfo{Type: "message", Content: "hi", Embed: embed}, true},
+		{"dm", ResponseInfo{Type: "dm", Content: "Check your inbox"}, false},
+		{"ephemeral dm", ResponseInfo{Type: "dm", Content: "hi", Ephemeral: true}, true},
+		{"channel", ResponseInfo{Type: "channel", Content: "New report", ChannelID: "123456789012345678"}, false},
+		{"channel without an ID", ResponseInfo{Type: "channel", Content: "New report"}, true},
+		{"channel with a name", ResponseInfo{Type: "channel", Content: "New report", ChannelID: "reports"}, true},
+		{"message with a channel ID", ResponseInfo{Type: "message", Content: "hi", ChannelID: "123456789012345678"}, true},
+		{"defer", ResponseInfo{Type: "defer", Content: "Finished the export", Ephemeral: true}, false},
+		{"defer without content", ResponseInfo{Type: "defer"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateResponse(tt.response)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateResponse error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateCommandGroup(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr bool
+	}{
+		{"", false},
+		{"ticket", false},
+		{"ticket admin", false},
+		{"ticket-tools", false},
+		{"ticket admin extra", true},
+		{"ticket  admin", true},
+		{" ticket", true},
+		{"Ticket", true},
+		{"ticket!", true},
+		{strings.Repeat("a", 33), true},
+	}
+	for _, tt := range tests {
+		err := ValidateCommandGroup(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateCommandGroup(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateArgType(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr bool
+	}{
+		{"str", false},
+		{"discord.User", false},
+		{"discord.TextChannel", false},
+		{"discord.VoiceChannel", false},
+		{"discord.abc.GuildChannel", false},
+		{"discord.Thread", false},
+		{"discord.Attachment", false},
+		{MentionableArgType, false},
+		{"app_commands.Transform[str, TagTransformer]", false},
+		{"app_commands.Transform[int, PointsTransformer]", false},
+		{"app_commands.Transform[bool, TagTransformer]", true},
+		{"app_commands.Transform[str, tag]", true},
+		{"app_commands.Transform[str]", true},
+		{"Union[discord.Member, discord.User]", true},
+		{"discord.Channel", true},
+		{"", true},
+	}
+	for _, tt := range tests {
+		err := ValidateArgType(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateArgType(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateCommandGroupPlacement(t *testing.T) {
+	open := CommandInfo{
+		Name:             "open",
+		Scope:            "guild",
+		Type:             "slash",
+		Description:      "Opens a ticket",
+		Group:            "ticket",
+		GroupDescription: "Ticket tools",
+		ReturnType:       "None",
+	}
+
+	if err := ValidateCommand(open, nil); err != nil {
+		t.Errorf("grouped slash command should pass, got %v", err)
+	}
+
+	closeTicket := open
+	closeTicket.Name = "close"
+	if err := ValidateCommand(closeTicket, []CommandInfo{open}); err != nil {
+		t.Errorf("second command in the group should pass, got %v", err)
+	}
+
+	otherScope := closeTicket
+	otherScope.Scope = "global"
+	if err := ValidateCommand(otherScope, []CommandInfo{open}); err == nil {
+		t.Error("commands in one group with different scopes should fail")
+	}
+
+	otherDescription := closeTicket
+	otherDescription.GroupDescription = "Something else"
+	if err := ValidateCommand(otherDescription, []CommandInfo{open}); err == nil {
+		t.Error("commands in one group with different group descriptions should fail")
+	}
+
+	nested := closeTicket
+	nested.Group = "ticket admin"
+	nested.GroupDescription = "Admin tools"
+	if err := ValidateCommand(nested, []CommandInfo{open}); err != nil {
+		t.Errorf("subgroup command should pass, got %v", err)
+	}
+
+	topLevel := open
+	topLevel.Name = "ticket"
+	topLevel.Group = ""
+	topLevel.GroupDescription = ""
+	if err := ValidateCommand(topLevel, []CommandInfo{open}); err == nil {
+		t.Error("top level command sharing a group name should fail")
+	}
+	if err := ValidateCommand(open, []CommandInfo{topLevel}); err == nil {
+		t.Error("group sharing a top level command name should fail")
+	}
+
+	otherGroup := open
+	otherGroup.Group = "tag"
+	otherGroup.GroupDescription = "Tag tools"
+	if err := ValidateCommand(otherGroup, []CommandInfo{open}); err != nil {
+		t.Errorf("same name in another group should pass, got %v", err)
+	}
+	if err := ValidateCommand(open, []CommandInfo{open}); err == nil {
+		t.Error("duplicate command path should fail")
+	}
+
+	clash := open
+	clash.Name = "ticket_open"
+	clash.Group = ""
+	clash.GroupDescription = ""
+	if err := ValidateCommand(clash, []CommandInfo{open}); err == nil {
+		t.Error("command sharing a method name with a grouped command should fail")
+	}
+
+	prefix := open
+	prefix.Type = "prefix"
+	if err := ValidateCommand(prefix, nil); err == nil {
+		t.Error("grouped prefix command should fail")
+	}
+
+	var full []CommandInfo
+	for i := 0; i < MaxGroupCommands; i++ {
+		command := open
+		command.Name = fmt.Sprintf("sub%d", i)
+		full = append(full, command)
+	}
+	if err := ValidateCommand(closeTicket, full); err == nil {
+		t.Error("a group past the command cap should fail")
+	}
+}
+
+func TestValidateCommandArgOptions(t *testing.T) {
+	base := CommandInfo{
+		Name:        "roll",
+		Scope:       "guild",
+		Type:        "slash",
+		Description: "Rolls dice",
+		ReturnType:  "None",
+	}
+	colors := []ChoiceInfo{{Name: "Red", Value: "red"}, {Name: "Blue", Value: "blue"}}
+
+	var tooManyChoices []ChoiceInfo
+	for i := 0; i <= MaxArgChoices; i++ {
+		tooManyChoices = append(tooManyChoices, ChoiceInfo{Name: fmt.Sprintf("c%d", i), Value: fmt.Sprintf("%d", i)})
+	}
+
+	tests := []struct {
+		name    string
+		cmdType string
+		args    []ArgInfo
+		wantErr bool
+	}{
+		{"optional with default", "slash", []ArgInfo{{Name: "sides", Type: "int", Description: "d", Optional: true, Default: "6"}}, false},
+		{"optional member defaults to None", "slash", []ArgInfo{{Name: "user", Type: "discord.Member", Description: "d", Optional: true}}, false},
+		{"bool default", "prefix", []ArgInfo{{Name: "loud", Type: "bool", Description: "d", Optional: true, Default: "true"}}, false},
+		{"default without optional", "slash", []ArgInfo{{Name: "sides", Type: "int", Description: "d", Default: "6"}}, true},
+		{"default of the wrong type", "slash", []ArgInfo{{Name: "sides", Type: "int", Description: "d", Optional: true, Default: "six"}}, true},
+		{"default on a member", "slash", []ArgInfo{{Name: "user", Type: "discord.Member", Description: "d", Optional: true, Default: "me"}}, true},
+		{"required after optional", "slash", []ArgInfo{
+			{Name: "sides", Type: "int", Description: "d", Optional: true},
+			{Name: "count", Type: "int", Description: "d"},
+		}, true},
+		{"required before optional", "slash", []ArgInfo{
+			{Name: "count", Type: "int", Description: "d"},
+			{Name: "sides", Type: "int", Description: "d", Optional: true},
+		}, false},
+		{"choices", "slash", []ArgInfo{{Name: "color", Type: "str", Description: "d", Choices: colors}}, false},
+		{"too many choices", "slash", []ArgInfo{{Name: "sides", Type: "int", Description: "d", Choices: tooManyChoices}}, true},
+		{"choice of the wrong type", "slash", []ArgInfo{{Name: "sides", Type: "int", Description: "d", Choices: colors}}, true},
+		{"default among the choices", "slash", []ArgInfo{{Name: "color", Type: "str", Description: "d", Choices: colors, Optional: true, Default: "blue"}}, false},
+		{"default outside the choices", "slash", []ArgInfo{{Name: "color", Type: "str", Description: "d", Choices: colors, Optional: true, Default: "green"}}, true},
+		{"numeric default matches a choice by value", "slash", []ArgInfo{{Name: "sides", Type: "float", Description: "d", Choices: []ChoiceInfo{{Name: "Half", Value: "0.5"}}, Optional: true, Default: ".50"}}, false},
+		{"choices on a bool", "slash", []ArgInfo{{Name: "loud", Type: "bool", Description: "d", Choices: []ChoiceInfo{{Name: "Yes", Value: "true"}}}}, true},
+		{"choices on a prefix command", "prefix", []ArgInfo{{Name: "color", Type: "str", Description: "d", Choices: colors}}, true},
+		{"default within the range", "slash", []ArgInfo{{Name: "sides", Type: "int", Description: "d", Min: "1", Max: "10", Optional: true, Default: "6"}}, false},
+		{"default above the max", "slash", []ArgInfo{{Name: "sides", Type: "int", Description: "d", Min: "1", Max: "10", Optional: true, Default: "50"}}, true},
+		{"default below the min", "slash", []ArgInfo{{Name: "scale", Type: "float", Description: "d", Min: "0.5", Optional: true, Default: "0.25"}}, true},
+		{"default within the length bounds", "slash", []ArgInfo{{Name: "note", Type: "str", Description: "d", Min: "2", Max: "5", Optional: true, Default: "héllo"}}, false},
+		{"default longer than the max length", "slash", []ArgInfo{{Name: "note", Type: "str", Description: "d", Max: "5", Optional: true, Default: "too long"}}, true},
+		{"default shorter than the min length", "slash", []ArgInfo{{Name: "note", Type: "str", Description: "d", Min: "3", Optional: true, Default: "a"}}, true},
+		{"int range", "slash", []ArgInfo{{Name: "sides", Type: "int", Description: "d", Min: "2", Max: "100"}}, false},
+		{"open ended float range", "slash", []ArgInfo{{Name: "scale", Type: "float", Description: "d", Min: "0.5"}}, false},
+		{"string length range", "slash", []ArgInfo{{Name: "note", Type: "str", Description: "d", Max: "200"}}, false},
+		{"string length past the cap", "slash", []ArgInfo{{Name: "note", Type: "str", Description: "d", Max: "7000"}}, true},
+		{"min above max", "slash", []ArgInfo{{Name: "sides", Type: "int", Description: "d", Min: "10", Max: "2"}}, true},
+		{"range with choices", "slash", []ArgInfo{{Name: "color", Type: "str", Description: "d", Choices: colors, Max: "10"}}, true},
+		{"autocomplete suggestions", "slash", []ArgInfo{{Name: "tag", Type: "str", Description: "d", Autocomplete: true, Suggestions: []string{"bug", "feature"}}}, false},
+		{"autocomplete source", "slash", []ArgInfo{{Name: "ticket", Type: "int", Description: "d", Autocomplete: true, AutocompleteSource: "open_tickets"}}, false},
+		{"autocomplete without a source", "slash", []ArgInfo{{Name: "tag", Type: "str", Description: "d", Autocomplete: true}}, true},
+		{"autocomplete with both", "slash", []ArgInfo{{Name: "tag", Type: "str", Description: "d", Autocomplete: true, Suggestions: []string{"bug"}, AutocompleteSource: "tags"}}, true},
+		{"autocomplete with choices", "slash", []ArgInfo{{Name: "color", Type: "str", Description: "d", Choices: colors, Autocomplete: true, Suggestions: []string{"red"}}}, true},
+		{"autocomplete suggestion of the wrong type", "slash", []ArgInfo{{Name: "ticket", Type: "int", Description: "d", Autocomplete: true, Suggestions: []string{"first"}}}, true},
+		{"autocomplete source with a bad name", "slash", []ArgInfo{{Name: "tag", Type: "str", Description: "d", Autocomplete: true, AutocompleteSource: "Tag-List"}}, true},
+		{"suggestions without autocomplete", "slash", []ArgInfo{{Name: "tag", Type: "str", Description: "d", Suggestions: []string{"bug"}}}, true},
+		{"autocomplete on a prefix command", "prefix", []ArgInfo{{Name: "tag", Type: "str", Description: "d", Autocomplete: true, Suggestions: []string{"bug"}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command := base
+			command.Type = tt.cmdType
+			command.Args = tt.args
+			err := ValidateCommand(command, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCommand error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateCommandAccess(t *testing.T) {
+	base := CommandInfo{
+		Name:        "purge",
+		Scope:       "guild",
+		Type:        "slash",
+		Description: "Deletes messages",
+		ReturnType:  "None",
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(command *CommandInfo)
+		wantErr bool
+	}{
+		{"permissions", func(c *CommandInfo) { c.Permissions = []string{"manage_messages", "kick_members"} }, false},
+		{"unknown permission", func(c *CommandInfo) { c.Permissions = []string{"manage_everything"} }, true},
+		{"repeated permission", func(c *CommandInfo) { c.Permissions = []string{"manage_messages", "manage_messages"} }, true},
+		{"role names and IDs", func(c *CommandInfo) { c.Roles = []string{"Moderator", "123456789012345678"} }, false},
+		{"role with a quote", func(c *CommandInfo) { c.Roles = []string{`The "Mods"`} }, true},
+		{"empty role", func(c *CommandInfo) { c.Roles = []string{""} }, true},
+		{"cooldown", func(c *CommandInfo) { c.Cooldown = &CooldownInfo{Rate: 3, Per: 60, Bucket: "guild"} }, false},
+		{"cooldown without a rate", func(c *CommandInfo) { c.Cooldown = &CooldownInfo{Per: 60, Bucket: "user"} }, true},
+		{"cooldown without a period", func(c *CommandInfo) { c.Cooldown = &CooldownInfo{Rate: 1, Bucket: "user"} }, true},
+		{"cooldown with an unknown bucket", func(c *CommandInfo) { c.Cooldown = &CooldownInfo{Rate: 1, Per: 5, Bucket: "role"} }, true},
+		{"installs and contexts", func(c *CommandInfo) {
+			c.AllowedInstalls = []string{"guild", "user"}
+			c.AllowedContexts = []string{"guild", "dm", "private_channel"}
+		}, false},
+		{"unknown install", func(c *CommandInfo) { c.AllowedInstalls = []string{"server"} }, true},
+		{"unknown context", func(c *CommandInfo) { c.AllowedContexts = []string{"voice"} }, true},
+		{"guild only", func(c *CommandInfo) { c.GuildOnly = true }, false},
+		{"guild only with contexts", func(c *CommandInfo) {
+			c.GuildOnly = true
+			c.AllowedContexts = []string{"dm"}
+		}, true},
+		{"prefix with permissions, roles, and cooldown", func(c *CommandInfo) {
+			c.Type = "prefix"
+			c.Scope = "global"
+			c.Permissions = []string{"manage_messages"}
+			c.Roles = []string{"Moderator"}
+			c.GuildOnly = true
+			c.Cooldown = &CooldownInfo{Rate: 1, Per: 2.5, Bucket: "channel"}
+		}, false},
+		{"prefix with installs", func(c *CommandInfo) {
+			c.Type = "prefix"
+			c.AllowedInstalls = []string{"user"}
+		}, true},
+		{"grouped with roles and cooldown", func(c *CommandInfo) {
+			c.Group = "mod"
+			c.Roles = []string{"Moderator"}
+			c.Cooldown = &CooldownInfo{Rate: 1, Per: 10, Bucket: "user"}
+		}, false},
+		{"grouped with permissions", func(c *CommandInfo) {
+			c.Group = "mod"
+			c.Permissions = []string{"manage_messages"}
+		}, true},
+		{"grouped guild only", func(c *CommandInfo) {
+			c.Group = "mod"
+			c.GuildOnly = true
+		}, true},
+		{"context menu with permissions", func(c *CommandInfo) {
+			c.Type = "message_context"
+			c.Permissions = []string{"manage_messages"}
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command := base
+			tt.modify(&command)
+			err := ValidateCommand(command, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCommand error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateCommandComponents(t *testing.T) {
+	button := ComponentInfo{Name: "confirm", Type: "button", Label: "Confirm", Style: "success", Response: ResponseInfo{Type: "message", Content: "Saved"}}
+	menu := ComponentInfo{Name: "topics", Type: "select", Label: "Pick a topic", Options: []SelectOptionInfo{
+		{Label: "News", Value: "news", Response: ResponseInfo{Type: "message", Content: "Subscribed"}},
+	}}
+	base := CommandInfo{
+		Name:        "roles",
+		Scope:       "guild",
+		Type:        "component",
+		Description: "Hands out roles",
+		ReturnType:  "None",
+		Components:  &ComponentsInfo{Timeout: 180, Items: []ComponentInfo{button, menu}},
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(command *CommandInfo)
+		wantErr bool
+	}{
+		{"button and select", func(c *CommandInfo) {}, false},
+		{"no components", func(c *CommandInfo) { c.Components = nil }, true},
+		{"empty view", func(c *CommandInfo) { c.Components = &ComponentsInfo{} }, true},
+		{"negative timeout", func(c *CommandInfo) { c.Components.Timeout = -1 }, true},
+		{"components on a slash command", func(c *CommandInfo) { c.Type = "slash" }, true},
+		{"duplicate name", func(c *CommandInfo) {
+			c.Components = &ComponentsInfo{Items: []ComponentInfo{button, button}}
+		}, true},
+		{"unknown style", func(c *CommandInfo) {
+			bad := button
+			bad.Style = "blurple"
+			c.Components = &ComponentsInfo{Items: []ComponentInfo{bad}}
+		}, true},
+		{"button without a response", func(c *CommandInfo) {
+			bad := button
+			bad.Response = ResponseInfo{}
+			c.Components = &ComponentsInfo{Items: []ComponentInfo{bad}}
+		}, true},
+		{"select without options", func(c *CommandInfo) {
+			bad := menu
+			bad.Options = nil
+			c.Components = &ComponentsInfo{Items: []ComponentInfo{bad}}
+		}, true},
+		{"duplicate option value", func(c *CommandInfo) {
+			bad := menu
+			bad.Options = []SelectOptionInfo{menu.Options[0], menu.Options[0]}
+			c.Components = &ComponentsInfo{Items: []ComponentInfo{bad}}
+		}, true},
+		{"too many rows", func(c *CommandInfo) {
+			items := []ComponentInfo{button}
+			for i := range 5 {
+				extra := menu
+				extra.Name = fmt.Sprintf("menu%d", i)
+				items = append(items, extra)
+			}
+			c.Components = &ComponentsInfo{Items: items}
+		}, true},
+		{"grouped component command", func(c *CommandInfo) { c.Group = "roles" }, false},
+		{"embed reply", func(c *CommandInfo) {
+			c.Responses = []ResponseInfo{{Type: "embed", Embed: &EmbedInfo{Title: "Roles"}}}
+		}, false},
+		{"direct message reply", func(c *CommandInfo) {
+			c.Responses = []ResponseInfo{{Type: "dm", Content: "Pick your roles"}}
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command := base
+			components := *base.Components
+			command.Components = &components
+			tt.modify(&command)
+			err := ValidateCommand(command, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCommand error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestValidateCommandResponses(t *testing.T) {
 	valid := CommandInfo{
 		Name:        "greet",
@@ -480,6 +1159,114 @@ func TestValidateCommandResponses(t *testing.T) {
 	}
 }
 
+func TestValidateListener(t *testing.T) {
+	existing := []ListenerInfo{{Event: "on_ready"}}
+	tests := []struct {
+		name     string
+		listener ListenerInfo
+		wantErr  bool
+	}{
+		{"empty action stub", ListenerInfo{Event: "on_message"}, false},
+		{"log action", ListenerInfo{Event: "on_guild_join", Action: "log"}, false},
+		{"message action", ListenerInfo{Event: "on_member_join", Action: "message", ChannelID: "123456789012345678", Content: "Welcome {member.mention}"}, false},
+		{"role action on member event", ListenerInfo{Event: "on_raw_reaction_add", Action: "role", RoleID: "123456789012345678"}, false},
+		{"unknown event", ListenerInfo{Event: "on_typing"}, true},
+		{"duplicate event", ListenerInfo{Event: "on_ready", Action: "log"}, true},
+		{"unknown action", ListenerInfo{Event: "on_message", Action: "ban"}, true},
+		{"message action without channel", ListenerInfo{Event: "on_member_join", Action: "message", Content: "hi"}, true},
+		{"message action without content", ListenerInfo{Event: "on_member_join", Action: "message", ChannelID: "123456789012345678"}, true},
+		{"role action without member", ListenerInfo{Event: "on_message", Action: "role", RoleID: "123456789012345678"}, true},
+		{"role action with bad role ID", ListenerInfo{Event: "on_member_join", Action: "role", RoleID: "moderators"}, true},
+		{"log action with a role ID", ListenerInfo{Event: "on_member_join", Action: "log", RoleID: "123456789012345678"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateListener(tt.listener, existing)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateListener(%+v) error = %v, wantErr %v", tt.listener, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateTask(t *testing.T) {
+	existing := []TaskInfo{{Name: "cleanup", Interval: 1, Unit: "hours"}}
+	tests := []struct {
+		name    string
+		task    TaskInfo
+		wantErr bool
+	}{
+		{"interval task", TaskInfo{Name: "heartbeat", Interval: 30, Unit: "seconds"}, false},
+		{"times of day in utc", TaskInfo{Name: "digest", Times: []string{"09:00", "21:30"}}, false},
+		{"times of day with timezone and channel", TaskInfo{Name: "digest", Times: []string{"08:00"}, Timezone: "America/New_York", ChannelID: "123456789012345678", Content: "Good morning"}, false},
+		{"channel without content", TaskInfo{Name: "digest", Interval: 1, Unit: "hours", ChannelID: "123456789012345678"}, false},
+		{"duplicate name", TaskInfo{Name: "cleanup", Interval: 2, Unit: "hours"}, true},
+		{"invalid name", TaskInfo{Name: "Daily-Digest", Interval: 1, Unit: "hours"}, true},
+		{"reserved name", TaskInfo{Name: "cog_load", Interval: 1, Unit: "hours"}, true},
+		{"before loop name", TaskInfo{Name: "before_cleanup", Interval: 1, Unit: "hours"}, true},
+		{"no schedule", TaskInfo{Name: "digest"}, true},
+		{"interval and times", TaskInfo{Name: "digest", Interval: 1, Unit: "hours", Times: []string{"09:00"}}, true},
+		{"bad unit", TaskInfo{Name: "digest", Interval: 1, Unit: "days"}, true},
+		{"bad time", TaskInfo{Name: "digest", Times: []string{"9am"}}, true},
+		{"duplicate time", TaskInfo{Name: "digest", Times: []string{"09:00", "09:00"}}, true},
+		{"timezone on an interval", TaskInfo{Name: "digest", Interval: 1, Unit: "hours", Timezone: "UTC"}, true},
+		{"bad timezone", TaskInfo{Name: "digest", Times: []string{"09:00"}, Timezone: "New York"}, true},
+		{"content without channel", TaskInfo{Name: "digest", Interval: 1, Unit: "hours", Content: "hi"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTask(tt.task, existing)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTask(%+v) error = %v, wantErr %v", tt.task, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateCogMethodNames(t *testing.T) {
+	commands := []CommandInfo{
+		{Name: "cleanup", Type: "slash"},
+		{Name: "list", Type: "slash", Group: "ticket"},
+		{Name: "on_message", Type: "prefix"},
+	}
+	listeners := []ListenerInfo{{Event: "on_ready"}}
+
+	tasks := []struct {
+		name    string
+		task    string
+		wantErr bool
+	}{
+		{"free name", "digest", false},
+		{"command method", "cleanup", true},
+		{"grouped command method", "ticket_list", true},
+		{"listener method", "on_ready", true},
+	}
+	for _, tt := range tasks {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTasks([]TaskInfo{{Name: tt.task, Interval: 1, Unit: "hours"}}, commands, listeners)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTasks(%s) error = %v, wantErr %v", tt.task, err, tt.wantErr)
+			}
+		})
+	}
+
+	// A task's before_ hook is a method too
+	hook := []CommandInfo{{Name: "before_digest", Type: "slash"}}
+	if err := ValidateTasks([]TaskInfo{{Name: "digest", Interval: 1, Unit: "hours"}}, hook, nil); err == nil {
+		t.Error("a task whose before_ hook is a command method should fail")
+	}
+
+	if err := ValidateListeners([]ListenerInfo{{Event: "on_ready"}}, commands, nil); err != nil {
+		t.Errorf("listener with a free method should pass, got %v", err)
+	}
+	if err := ValidateListeners([]ListenerInfo{{Event: "on_message"}}, commands, nil); err == nil {
+		t.Error("listener sharing a command method should fail")
+	}
+	if err := ValidateListeners([]ListenerInfo{{Event: "on_message"}}, nil, []TaskInfo{{Name: "on_message", Interval: 1, Unit: "hours"}}); err == nil {
+		t.Error("listener sharing a task method should fail")
+	}
+}
+
 func TestValidateCommandScopeAndReturnType(t *testing.T) {
 	if err := ValidateCommandScope("guild"); err != nil {
 		t.Errorf("guild should be valid, got %v", err)